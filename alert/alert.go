@@ -17,9 +17,12 @@ package alert
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/alert/channel"
 )
 
 const (
@@ -27,17 +30,51 @@ const (
 	FrequencyDaily        = "daily"
 	FrequencyWeekly       = "weekly"
 	FrequencyMonthly      = "monthly"
+	// FrequencyOnCondition alerts have no cadence: they are checked after every
+	// successful run and send only when one of their Conditions fires.
+	FrequencyOnCondition = "on_condition"
 )
 
 type Alert struct {
-	ID            uuid.UUID
-	PortfolioID   uuid.UUID
-	Frequency     string
-	Recipients    []string
-	LastSentAt    *time.Time
-	LastSentValue *float64
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID          uuid.UUID
+	PortfolioID uuid.UUID
+	Frequency   string
+	Recipients  []string
+	// VerifiedRecipients are the Recipients who followed their confirmation
+	// link. Only they are emailed.
+	VerifiedRecipients []string
+	// Locales maps a recipient to the locale tag their email is rendered
+	// in. Recipients without an entry get the server default.
+	Locales        map[string]string
+	LastSentAt     *time.Time
+	LastSentValue  *float64
+	Conditions     []Condition
+	ConditionState ConditionState
+	// Channels are the non-email delivery targets; Recipients stays the
+	// email path.
+	Channels  []channel.Target
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Verified reports whether recipient has confirmed they want a's emails.
+func (a Alert) Verified(recipient string) bool {
+	return slices.Contains(a.VerifiedRecipients, recipient)
+}
+
+// PendingRecipients returns the recipients still waiting to confirm.
+func (a Alert) PendingRecipients() []string {
+	return pendingRecipients(a.Recipients, a.VerifiedRecipients)
+}
+
+func pendingRecipients(recipients, verified []string) []string {
+	var out []string
+	for _, r := range recipients {
+		if !slices.Contains(verified, r) {
+			out = append(out, r)
+		}
+	}
+	return out
 }
 
 // Notifier is called by the backtest orchestrator after each run completes.
@@ -0,0 +1,240 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package channel delivers rendered alerts to destinations other than email:
+// signed generic webhooks, Slack and Discord incoming webhooks, Telegram bots
+// and ntfy topics.
+package channel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/penny-vault/pv-api/alert/email"
+)
+
+// Channel types accepted in Target.Type.
+const (
+	TypeWebhook  = "webhook"
+	TypeSlack    = "slack"
+	TypeDiscord  = "discord"
+	TypeTelegram = "telegram"
+	TypeNtfy     = "ntfy"
+)
+
+var (
+	// ErrInvalidTarget is returned by Validate for a malformed target.
+	ErrInvalidTarget = errors.New("invalid channel target")
+	// ErrUnknownType is returned by Dispatcher.Send for a type with no channel.
+	ErrUnknownType = errors.New("unknown channel type")
+	// ErrNotConfigured is returned when a channel needs server-side
+	// credentials (the Telegram bot token) that were not provided.
+	ErrNotConfigured = errors.New("channel not configured")
+	// ErrUnexpectedStatus is returned when a destination replies non-2xx.
+	ErrUnexpectedStatus = errors.New("channel: unexpected status")
+)
+
+// Target is one place an alert is delivered. Which fields apply depends on
+// Type: URL for webhook, slack and discord; Secret for webhook signing;
+// ChatID for telegram; Topic (and optionally URL as the server) for ntfy.
+type Target struct {
+	Type   string `json:"type"`
+	URL    string `json:"url,omitempty"`
+	Secret string `json:"secret,omitempty"`
+	ChatID string `json:"chatId,omitempty"`
+	Topic  string `json:"topic,omitempty"`
+}
+
+// Message is an alert rendered for delivery. Text is the same plaintext body
+// the email carries; Payload is the structured data behind it, sent verbatim
+// to generic webhooks.
+type Message struct {
+	AlertID string
+	Event   string
+	Subject string
+	Text    string
+	Payload email.Payload
+}
+
+// Channel sends a message to one target of its type.
+type Channel interface {
+	Send(ctx context.Context, t Target, m Message) error
+}
+
+// Config holds server-wide channel settings.
+type Config struct {
+	// TelegramBotToken authenticates the bot that posts to telegram targets.
+	// Empty disables telegram delivery.
+	TelegramBotToken string
+	// TelegramAPIURL defaults to https://api.telegram.org.
+	TelegramAPIURL string
+	// NtfyURL is the ntfy server used when a target names no server.
+	// Defaults to https://ntfy.sh.
+	NtfyURL string
+	// Client sends to user-supplied URLs: webhook, Slack and Discord URLs
+	// and a target's own ntfy server. Defaults to NewClient with a 10
+	// second timeout, which refuses non-public addresses and redirects.
+	Client *http.Client
+	// ServerClient sends to the operator-configured TelegramAPIURL and
+	// NtfyURL, which may sit on a private network. Defaults to a plain
+	// client with a 10 second timeout.
+	ServerClient *http.Client
+}
+
+// Dispatcher routes a target to the Channel registered for its type.
+type Dispatcher struct {
+	channels map[string]Channel
+}
+
+// New returns a Dispatcher with every built-in channel registered.
+func New(cfg Config) *Dispatcher {
+	client := cfg.Client
+	if client == nil {
+		client = NewClient(10 * time.Second)
+	}
+	serverClient := cfg.ServerClient
+	if serverClient == nil {
+		serverClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.TelegramAPIURL == "" {
+		cfg.TelegramAPIURL = "https://api.telegram.org"
+	}
+	if cfg.NtfyURL == "" {
+		cfg.NtfyURL = "https://ntfy.sh"
+	}
+	d := &Dispatcher{channels: map[string]Channel{}}
+	d.Register(TypeWebhook, Webhook{Client: client})
+	d.Register(TypeSlack, Slack{Client: client})
+	d.Register(TypeDiscord, Discord{Client: client})
+	d.Register(TypeTelegram, Telegram{Client: serverClient, Token: cfg.TelegramBotToken, APIURL: cfg.TelegramAPIURL})
+	d.Register(TypeNtfy, Ntfy{Client: client, ServerClient: serverClient, ServerURL: cfg.NtfyURL})
+	return d
+}
+
+// Register installs ch for targets of type typ, replacing any existing one.
+func (d *Dispatcher) Register(typ string, ch Channel) {
+	d.channels[typ] = ch
+}
+
+// Send delivers m to t.
+func (d *Dispatcher) Send(ctx context.Context, t Target, m Message) error {
+	ch, ok := d.channels[t.Type]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownType, t.Type)
+	}
+	return ch.Send(ctx, t, m)
+}
+
+// Validate checks that t has the fields its type needs. Slack and Discord
+// URLs must point at the services' incoming-webhook hosts; generic webhooks
+// and ntfy servers must use https and resolve only to public addresses.
+func Validate(t Target) error {
+	switch t.Type {
+	case TypeWebhook:
+		if err := requireHTTPS(t.URL); err != nil {
+			return err
+		}
+	case TypeSlack:
+		if !strings.HasPrefix(t.URL, "https://hooks.slack.com/") {
+			return fmt.Errorf("%w: slack url must start with https://hooks.slack.com/", ErrInvalidTarget)
+		}
+	case TypeDiscord:
+		if !strings.HasPrefix(t.URL, "https://discord.com/api/webhooks/") &&
+			!strings.HasPrefix(t.URL, "https://discordapp.com/api/webhooks/") {
+			return fmt.Errorf("%w: discord url must be a discord.com/api/webhooks/ url", ErrInvalidTarget)
+		}
+	case TypeTelegram:
+		if t.ChatID == "" {
+			return fmt.Errorf("%w: telegram needs a chatId", ErrInvalidTarget)
+		}
+	case TypeNtfy:
+		if t.Topic == "" || strings.ContainsAny(t.Topic, "/?# ") {
+			return fmt.Errorf("%w: ntfy needs a topic without slashes or spaces", ErrInvalidTarget)
+		}
+		if t.URL != "" {
+			if err := requireHTTPS(t.URL); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidTarget, t.Type)
+	}
+	if t.Secret != "" && t.Type != TypeWebhook {
+		return fmt.Errorf("%w: only webhook targets take a secret", ErrInvalidTarget)
+	}
+	return nil
+}
+
+// Label names where t delivers without revealing its credentials: the host
+// of a webhook-style URL, the Telegram chat ID or the ntfy topic. It is what
+// delivery history shows as the destination.
+func (t Target) Label() string {
+	switch t.Type {
+	case TypeTelegram:
+		return t.ChatID
+	case TypeNtfy:
+		return t.Topic
+	}
+	if u, err := url.Parse(t.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return t.Type
+}
+
+func requireHTTPS(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute https url", ErrInvalidTarget)
+	}
+	return checkHost(u.Hostname())
+}
+
+// post sends body to endpoint and maps a non-2xx reply to ErrUnexpectedStatus.
+func post(ctx context.Context, client *http.Client, endpoint, contentType string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+	return nil
+}
+
+// truncate clips s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,379 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/penny-vault/pv-api/alert/channel"
+	"github.com/penny-vault/pv-api/alert/email"
+)
+
+// capture records the last request an httptest server received.
+type capture struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newServer(t *testing.T, status int) (*httptest.Server, *capture) {
+	t.Helper()
+	got := &capture{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.header = r.Header.Clone()
+		got.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func message() channel.Message {
+	return channel.Message{
+		AlertID: "a1",
+		Event:   "portfolio.update",
+		Subject: "Portfolio Update: Growth",
+		Text:    "Growth — as of January 5, 2024\n\nPortfolio Value: $103,240\n",
+		Payload: email.Payload{
+			PortfolioName: "Growth",
+			Success:       true,
+			CurrentValue:  "$103,240",
+			LogoDataURL:   "data:image/jpeg;base64,AAAA",
+			Trades:        []email.TradeRow{{Ticker: "VTI", Action: "Buy", ActionColor: "#16a34a", Shares: "10", Value: "$2,000"}},
+		},
+	}
+}
+
+func TestWebhookSignsPayload(t *testing.T) {
+	srv, got := newServer(t, http.StatusNoContent)
+	d := channel.New(channel.Config{Client: srv.Client()})
+
+	err := d.Send(context.Background(), channel.Target{Type: channel.TypeWebhook, URL: srv.URL, Secret: "s3cret"}, message())
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	ts := got.header.Get(channel.HeaderTimestamp)
+	if want := channel.Sign("s3cret", ts, got.body); got.header.Get(channel.HeaderSignature) != want {
+		t.Errorf("signature %q does not match recomputed %q", got.header.Get(channel.HeaderSignature), want)
+	}
+	if got.header.Get(channel.HeaderEvent) != "portfolio.update" {
+		t.Errorf("event header = %q", got.header.Get(channel.HeaderEvent))
+	}
+
+	var body struct {
+		Event string         `json:"event"`
+		Data  map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(got.body, &body); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if body.Data["portfolioName"] != "Growth" || body.Data["currentValue"] != "$103,240" {
+		t.Errorf("payload missing portfolio data: %v", body.Data)
+	}
+	if strings.Contains(string(got.body), "base64") || strings.Contains(string(got.body), "#16a34a") {
+		t.Errorf("payload carries presentation-only fields: %s", got.body)
+	}
+}
+
+func TestWebhookUnsignedWithoutSecret(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK)
+	d := channel.New(channel.Config{Client: srv.Client()})
+
+	if err := d.Send(context.Background(), channel.Target{Type: channel.TypeWebhook, URL: srv.URL}, message()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.header.Get(channel.HeaderSignature) != "" {
+		t.Error("unsigned target should not carry a signature header")
+	}
+}
+
+func TestSlackAndDiscordBodies(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK)
+	d := channel.New(channel.Config{Client: srv.Client()})
+
+	if err := d.Send(context.Background(), channel.Target{Type: channel.TypeSlack, URL: srv.URL}, message()); err != nil {
+		t.Fatalf("slack: %v", err)
+	}
+	var slack map[string]string
+	_ = json.Unmarshal(got.body, &slack)
+	if !strings.HasPrefix(slack["text"], "*Portfolio Update: Growth*") || !strings.Contains(slack["text"], "$103,240") {
+		t.Errorf("unexpected slack text: %q", slack["text"])
+	}
+
+	if err := d.Send(context.Background(), channel.Target{Type: channel.TypeDiscord, URL: srv.URL}, message()); err != nil {
+		t.Fatalf("discord: %v", err)
+	}
+	var discord map[string]string
+	_ = json.Unmarshal(got.body, &discord)
+	if !strings.HasPrefix(discord["content"], "**Portfolio Update: Growth**") {
+		t.Errorf("unexpected discord content: %q", discord["content"])
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK)
+	target := channel.Target{Type: channel.TypeTelegram, ChatID: "-100123"}
+
+	d := channel.New(channel.Config{Client: srv.Client(), TelegramAPIURL: srv.URL})
+	if err := d.Send(context.Background(), target, message()); !errors.Is(err, channel.ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured without a bot token, got %v", err)
+	}
+
+	d = channel.New(channel.Config{Client: srv.Client(), TelegramAPIURL: srv.URL, TelegramBotToken: "123:abc"})
+	if err := d.Send(context.Background(), target, message()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.path != "/bot123:abc/sendMessage" {
+		t.Errorf("path = %q", got.path)
+	}
+	var body map[string]any
+	_ = json.Unmarshal(got.body, &body)
+	if body["chat_id"] != "-100123" {
+		t.Errorf("chat_id = %v", body["chat_id"])
+	}
+}
+
+func TestNtfy(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK)
+	d := channel.New(channel.Config{Client: srv.Client(), NtfyURL: srv.URL})
+
+	msg := message()
+	msg.Payload.PortfolioURL = "https://www.pennyvault.com/portfolios/growth"
+	if err := d.Send(context.Background(), channel.Target{Type: channel.TypeNtfy, Topic: "pv-growth"}, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.path != "/pv-growth" {
+		t.Errorf("path = %q", got.path)
+	}
+	if got.header.Get("Title") != "Portfolio Update: Growth" || got.header.Get("Click") != msg.Payload.PortfolioURL {
+		t.Errorf("unexpected headers: %v", got.header)
+	}
+	if string(got.body) != msg.Text {
+		t.Errorf("body = %q", got.body)
+	}
+}
+
+func TestSendErrors(t *testing.T) {
+	srv, _ := newServer(t, http.StatusInternalServerError)
+	d := channel.New(channel.Config{Client: srv.Client()})
+
+	err := d.Send(context.Background(), channel.Target{Type: channel.TypeSlack, URL: srv.URL}, message())
+	if !errors.Is(err, channel.ErrUnexpectedStatus) {
+		t.Errorf("expected ErrUnexpectedStatus, got %v", err)
+	}
+	err = d.Send(context.Background(), channel.Target{Type: "pager"}, message())
+	if !errors.Is(err, channel.ErrUnknownType) {
+		t.Errorf("expected ErrUnknownType, got %v", err)
+	}
+}
+
+// staticResolver answers lookups from a fixed table; unknown hosts fail.
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupNetIP(_ context.Context, _, host string) ([]netip.Addr, error) {
+	ips, ok := r[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	out := make([]netip.Addr, 0, len(ips))
+	for _, ip := range ips {
+		out = append(out, netip.MustParseAddr(ip))
+	}
+	return out, nil
+}
+
+// useResolver swaps channel.DefaultResolver for the duration of a test.
+func useResolver(t *testing.T, r channel.Resolver) {
+	t.Helper()
+	prev := channel.DefaultResolver
+	channel.DefaultResolver = r
+	t.Cleanup(func() { channel.DefaultResolver = prev })
+}
+
+func TestValidate(t *testing.T) {
+	useResolver(t, staticResolver{
+		"example.com":      {"93.184.215.14"},
+		"ntfy.example.com": {"2606:4700::6810:84e5"},
+	})
+	valid := []channel.Target{
+		{Type: channel.TypeWebhook, URL: "https://example.com/hook", Secret: "x"},
+		{Type: channel.TypeSlack, URL: "https://hooks.slack.com/services/T/B/X"},
+		{Type: channel.TypeDiscord, URL: "https://discord.com/api/webhooks/1/abc"},
+		{Type: channel.TypeTelegram, ChatID: "42"},
+		{Type: channel.TypeNtfy, Topic: "alerts"},
+		{Type: channel.TypeNtfy, Topic: "alerts", URL: "https://ntfy.example.com"},
+	}
+	for _, tgt := range valid {
+		if err := channel.Validate(tgt); err != nil {
+			t.Errorf("Validate(%+v) = %v", tgt, err)
+		}
+	}
+	invalid := []channel.Target{
+		{Type: channel.TypeWebhook, URL: "ftp://example.com"},
+		{Type: channel.TypeSlack, URL: "https://hooks.slack.com.evil.com/x"},
+		{Type: channel.TypeNtfy, Topic: "a/b"},
+		{Type: channel.TypeTelegram, ChatID: "1", Secret: "x"},
+	}
+	for _, tgt := range invalid {
+		if err := channel.Validate(tgt); !errors.Is(err, channel.ErrInvalidTarget) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidTarget", tgt, err)
+		}
+	}
+}
+
+func TestValidateBlocksNonPublicAddresses(t *testing.T) {
+	useResolver(t, staticResolver{
+		"localhost":            {"127.0.0.1", "::1"},
+		"db.internal":          {"10.0.0.5"},
+		"metadata.internal":    {"169.254.169.254"},
+		"mixed.example.com":    {"93.184.215.14", "192.168.1.10"},
+		"rebind.example.com":   {"0.0.0.0"},
+		"mapped.example.com":   {"::ffff:127.0.0.1"},
+		"private6.example.com": {"fd00::1"},
+	})
+	cases := map[string][]string{
+		"loopback": {"https://127.0.0.1/hook", "https://127.8.9.10/hook", "https://[::1]/hook",
+			"https://localhost/hook", "https://mapped.example.com/hook"},
+		"private": {"https://10.1.2.3/hook", "https://172.16.0.1/hook", "https://192.168.0.1/hook",
+			"https://[fd00::1]/hook", "https://db.internal/hook", "https://mixed.example.com/hook",
+			"https://private6.example.com/hook"},
+		"link-local": {"https://169.254.169.254/latest/meta-data", "https://[fe80::1]/hook",
+			"https://metadata.internal/hook"},
+		"unspecified":  {"https://0.0.0.0/hook", "https://[::]/hook", "https://rebind.example.com/hook"},
+		"unresolvable": {"https://nowhere.invalid/hook"},
+	}
+	for class, urls := range cases {
+		for _, u := range urls {
+			for _, tgt := range []channel.Target{
+				{Type: channel.TypeWebhook, URL: u},
+				{Type: channel.TypeNtfy, Topic: "alerts", URL: u},
+			} {
+				err := channel.Validate(tgt)
+				if !errors.Is(err, channel.ErrInvalidTarget) {
+					t.Errorf("%s: Validate(%+v) = %v, want ErrInvalidTarget", class, tgt, err)
+				}
+				if class != "unresolvable" && !errors.Is(err, channel.ErrBlockedAddress) {
+					t.Errorf("%s: Validate(%+v) = %v, want ErrBlockedAddress", class, tgt, err)
+				}
+			}
+		}
+	}
+}
+
+func TestValidateBlockedRanges(t *testing.T) {
+	cases := []struct {
+		name    string
+		host    string
+		blocked bool
+	}{
+		{"this network", "0.1.2.3", true},
+		{"carrier-grade NAT low", "100.64.0.1", true},
+		{"carrier-grade NAT high", "100.127.255.254", true},
+		{"just past carrier-grade NAT", "100.128.0.1", false},
+		{"reserved", "240.0.0.1", true},
+		{"broadcast", "255.255.255.255", true},
+		{"NAT64 of loopback", "[64:ff9b::7f00:1]", true},
+		{"NAT64 of a public address", "[64:ff9b::5db8:d70e]", true},
+		{"local-use NAT64", "[64:ff9b:1::a00:5]", true},
+		{"IPv4-mapped loopback", "[::ffff:127.0.0.1]", true},
+		{"IPv4-mapped private", "[::ffff:10.0.0.5]", true},
+		{"IPv4-mapped carrier-grade NAT", "[::ffff:100.64.0.1]", true},
+		{"IPv4-mapped public", "[::ffff:93.184.215.14]", false},
+		{"public IPv4", "93.184.215.14", false},
+		{"public IPv6", "[2606:4700::6810:84e5]", false},
+	}
+	for _, tc := range cases {
+		err := channel.Validate(channel.Target{Type: channel.TypeWebhook, URL: "https://" + tc.host + "/hook"})
+		if got := errors.Is(err, channel.ErrBlockedAddress); got != tc.blocked {
+			t.Errorf("%s (%s): blocked = %v, want %v (err %v)", tc.name, tc.host, got, tc.blocked, err)
+		}
+	}
+}
+
+func TestClientRefusesBlockedAddressAtDial(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK)
+	d := channel.New(channel.Config{Client: channel.NewClient(5 * time.Second)})
+	err := d.Send(context.Background(), channel.Target{Type: channel.TypeWebhook, URL: srv.URL}, message())
+	if !errors.Is(err, channel.ErrBlockedAddress) {
+		t.Fatalf("expected ErrBlockedAddress, got %v", err)
+	}
+	if got.body != nil {
+		t.Error("request reached the loopback server")
+	}
+}
+
+func TestOperatorServersMayBePrivate(t *testing.T) {
+	srv, got := newServer(t, http.StatusOK)
+	d := channel.New(channel.Config{TelegramAPIURL: srv.URL, TelegramBotToken: "123:abc", NtfyURL: srv.URL})
+
+	if err := d.Send(context.Background(), channel.Target{Type: channel.TypeTelegram, ChatID: "42"}, message()); err != nil {
+		t.Fatalf("telegram via a private API URL: %v", err)
+	}
+	if err := d.Send(context.Background(), channel.Target{Type: channel.TypeNtfy, Topic: "alerts"}, message()); err != nil {
+		t.Fatalf("ntfy via a private server URL: %v", err)
+	}
+	if got.path != "/alerts" {
+		t.Errorf("path = %q", got.path)
+	}
+
+	err := d.Send(context.Background(), channel.Target{Type: channel.TypeNtfy, Topic: "alerts", URL: srv.URL}, message())
+	if !errors.Is(err, channel.ErrBlockedAddress) {
+		t.Fatalf("a target's own ntfy URL should still be guarded, got %v", err)
+	}
+}
+
+func TestClientRefusesRedirects(t *testing.T) {
+	target, got := newServer(t, http.StatusOK)
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	t.Cleanup(redirect.Close)
+
+	// Keep the redirect policy but swap in a transport that may reach the
+	// loopback test servers.
+	client := channel.NewClient(5 * time.Second)
+	client.Transport = redirect.Client().Transport
+	d := channel.New(channel.Config{Client: client})
+	err := d.Send(context.Background(), channel.Target{Type: channel.TypeWebhook, URL: redirect.URL}, message())
+	if !errors.Is(err, channel.ErrRedirect) {
+		t.Fatalf("expected ErrRedirect, got %v", err)
+	}
+	if got.body != nil {
+		t.Error("redirect was followed")
+	}
+}
+
+func TestTargetLabel(t *testing.T) {
+	cases := map[string]channel.Target{
+		"hooks.slack.com": {Type: channel.TypeSlack, URL: "https://hooks.slack.com/services/T/B/X"},
+		"example.com":     {Type: channel.TypeWebhook, URL: "https://example.com/hook?key=secret"},
+		"42":              {Type: channel.TypeTelegram, ChatID: "42"},
+		"alerts":          {Type: channel.TypeNtfy, Topic: "alerts", URL: "https://ntfy.example.com"},
+	}
+	for want, tgt := range cases {
+		if got := tgt.Label(); got != want {
+			t.Errorf("Label(%+v) = %q, want %q", tgt, got, want)
+		}
+	}
+}
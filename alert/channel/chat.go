@@ -0,0 +1,99 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Message size limits imposed by the chat services.
+const (
+	slackTextLimit    = 40000
+	discordTextLimit  = 2000
+	telegramTextLimit = 4096
+)
+
+// chatText is the body shared by the chat channels: a bold-able subject line
+// followed by the plaintext rendering inside a code block, which keeps the
+// returns table aligned.
+func chatText(m Message, bold string) string {
+	return bold + m.Subject + bold + "\n```\n" + m.Text + "```"
+}
+
+// Slack posts to a Slack incoming webhook.
+type Slack struct {
+	Client *http.Client
+}
+
+func (s Slack) Send(ctx context.Context, t Target, m Message) error {
+	body, err := json.Marshal(map[string]string{
+		"text": truncate(chatText(m, "*"), slackTextLimit),
+	})
+	if err != nil {
+		return fmt.Errorf("slack: marshal: %w", err)
+	}
+	if err := post(ctx, s.Client, t.URL, "application/json", body, nil); err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
+
+// Discord posts to a Discord channel webhook.
+type Discord struct {
+	Client *http.Client
+}
+
+func (d Discord) Send(ctx context.Context, t Target, m Message) error {
+	body, err := json.Marshal(map[string]string{
+		"content": truncate(chatText(m, "**"), discordTextLimit),
+	})
+	if err != nil {
+		return fmt.Errorf("discord: marshal: %w", err)
+	}
+	if err := post(ctx, d.Client, t.URL, "application/json", body, nil); err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return nil
+}
+
+// Telegram sends through the Bot API as the bot whose token is configured.
+type Telegram struct {
+	Client *http.Client
+	Token  string
+	APIURL string
+}
+
+func (tg Telegram) Send(ctx context.Context, t Target, m Message) error {
+	if tg.Token == "" {
+		return fmt.Errorf("telegram: %w: no bot token", ErrNotConfigured)
+	}
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  t.ChatID,
+		"text":                     truncate(m.Subject+"\n\n"+m.Text, telegramTextLimit),
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("telegram: marshal: %w", err)
+	}
+	endpoint := tg.APIURL + "/bot" + tg.Token + "/sendMessage"
+	if err := post(ctx, tg.Client, endpoint, "application/json", body, nil); err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// resolveTimeout bounds the DNS lookup Validate makes for a target host.
+const resolveTimeout = 5 * time.Second
+
+var (
+	// ErrBlockedAddress is returned when a target resolves to, or a delivery
+	// would connect to, an address blockedAddr rejects. User-supplied URLs
+	// must not reach the server's own network.
+	ErrBlockedAddress = errors.New("channel: destination address is not public")
+	// ErrRedirect is returned when a destination answers with a redirect.
+	// Deliveries never follow redirects, so a public URL cannot bounce a
+	// request onto an internal one.
+	ErrRedirect = errors.New("channel: destination redirected; redirects are not followed")
+)
+
+// Resolver looks up the addresses of a host name. *net.Resolver
+// satisfies it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
+
+// DefaultResolver is the resolver Validate checks target hosts with.
+var DefaultResolver Resolver = net.DefaultResolver
+
+// blockedPrefixes are non-public ranges the netip predicates do not cover.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, maps onto any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+}
+
+// blockedAddr reports whether a is an address user-supplied URLs may not
+// reach: loopback, RFC 1918 / unique-local, link-local (which includes
+// cloud metadata endpoints such as 169.254.169.254), multicast,
+// unspecified, or one of blockedPrefixes. IPv4-mapped IPv6 addresses are
+// judged by the IPv4 address they carry.
+func blockedAddr(a netip.Addr) bool {
+	a = a.Unmap()
+	if !a.IsValid() || a.IsLoopback() || a.IsPrivate() ||
+		a.IsLinkLocalUnicast() || a.IsLinkLocalMulticast() || a.IsInterfaceLocalMulticast() ||
+		a.IsMulticast() || a.IsUnspecified() {
+		return true
+	}
+	for _, p := range blockedPrefixes {
+		if p.Contains(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHost rejects a URL host that is, or resolves to, a blocked address.
+// Validation catches obvious targets early; the dialer installed by
+// NewClient re-checks at connect time, which is what defeats DNS
+// rebinding.
+func checkHost(host string) error {
+	if a, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		if blockedAddr(a) {
+			return fmt.Errorf("%w: %w", ErrInvalidTarget, ErrBlockedAddress)
+		}
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	addrs, err := DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: host %q does not resolve", ErrInvalidTarget, host)
+	}
+	for _, a := range addrs {
+		if blockedAddr(a) {
+			return fmt.Errorf("%w: %w", ErrInvalidTarget, ErrBlockedAddress)
+		}
+	}
+	return nil
+}
+
+// dialControl runs after name resolution, immediately before connect, and
+// refuses blocked addresses.
+func dialControl(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	if blockedAddr(ap.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ap.Addr())
+	}
+	return nil
+}
+
+// NewClient returns the HTTP client deliveries use by default: it refuses
+// to connect to blocked addresses, checked per connection at dial time so
+// a host cannot rebind to an internal address after validation, ignores
+// proxy settings so the check sees the real destination, and never follows
+// redirects.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return ErrRedirect
+		},
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Ntfy publishes to an ntfy topic. The target's URL, when set, names a
+// self-hosted server reached through Client; otherwise ServerURL is used,
+// reached through ServerClient.
+type Ntfy struct {
+	Client       *http.Client
+	ServerClient *http.Client
+	ServerURL    string
+}
+
+func (n Ntfy) Send(ctx context.Context, t Target, m Message) error {
+	server, client := n.ServerURL, n.ServerClient
+	if t.URL != "" {
+		server, client = t.URL, n.Client
+	}
+	endpoint := strings.TrimRight(server, "/") + "/" + url.PathEscape(t.Topic)
+
+	header := http.Header{}
+	header.Set("Title", mime.QEncoding.Encode("utf-8", m.Subject))
+	if m.Payload.PortfolioURL != "" {
+		header.Set("Click", m.Payload.PortfolioURL)
+	}
+	if !m.Payload.Success || len(m.Payload.Triggers) > 0 {
+		header.Set("Priority", "high")
+	}
+	if err := post(ctx, client, endpoint, "text/plain; charset=utf-8", []byte(m.Text), header); err != nil {
+		return fmt.Errorf("ntfy: %w", err)
+	}
+	return nil
+}
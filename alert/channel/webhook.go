@@ -0,0 +1,87 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package channel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/penny-vault/pv-api/alert/email"
+)
+
+// Headers set on generic webhook deliveries. The signature is
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)); receivers
+// should recompute it and reject stale timestamps.
+const (
+	HeaderTimestamp = "X-PV-Timestamp"
+	HeaderSignature = "X-PV-Signature"
+	HeaderEvent     = "X-PV-Event"
+)
+
+// WebhookBody is the JSON document POSTed to generic webhooks.
+type WebhookBody struct {
+	Event   string        `json:"event"`
+	AlertID string        `json:"alertId"`
+	Subject string        `json:"subject"`
+	SentAt  time.Time     `json:"sentAt"`
+	Data    email.Payload `json:"data"`
+}
+
+// Webhook POSTs the alert payload as JSON, signed when the target has a secret.
+type Webhook struct {
+	Client *http.Client
+}
+
+func (w Webhook) Send(ctx context.Context, t Target, m Message) error {
+	sentAt := time.Now().UTC()
+	body, err := json.Marshal(WebhookBody{
+		Event:   m.Event,
+		AlertID: m.AlertID,
+		Subject: m.Subject,
+		SentAt:  sentAt,
+		Data:    m.Payload,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: marshal: %w", err)
+	}
+	header := http.Header{}
+	header.Set(HeaderEvent, m.Event)
+	if t.Secret != "" {
+		ts := strconv.FormatInt(sentAt.Unix(), 10)
+		header.Set(HeaderTimestamp, ts)
+		header.Set(HeaderSignature, Sign(t.Secret, ts, body))
+	}
+	if err := post(ctx, w.Client, t.URL, "application/json", body, header); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
+
+// Sign returns the X-PV-Signature value for body sent at timestamp ts.
+func Sign(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -28,12 +28,16 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog/log"
 
+	"github.com/penny-vault/pv-api/alert/channel"
 	"github.com/penny-vault/pv-api/alert/email"
+	"github.com/penny-vault/pv-api/notification"
+	"github.com/penny-vault/pv-api/openapi"
 	"github.com/penny-vault/pv-api/snapshot"
 )
 
-// ErrEmailNotConfigured is returned by SendSummary when no Mailgun API key is set.
-var ErrEmailNotConfigured = errors.New("email not configured: no Mailgun API key")
+// ErrEmailNotConfigured is returned by SendSummary when no email transport is
+// configured.
+var ErrEmailNotConfigured = errors.New("email not configured: no transport")
 
 type portfolioData struct {
 	Name             string
@@ -47,26 +51,74 @@ type portfolioData struct {
 	SnapshotPath     *string
 }
 
-// Checker implements Notifier using Postgres and Mailgun.
+// Checker implements Notifier using Postgres, an email transport (Mailgun or
+// SMTP) and, when configured, the non-email notification channels. Alert
+// messages go through the delivery outbox so failed sends are retried.
 type Checker struct {
 	pool              *pgxpool.Pool
 	store             *PoolStore
-	emailConfig       email.Config
+	transport         email.Transport
+	channels          *channel.Dispatcher
+	outbox            *Outbox
+	renderer          *email.Renderer
+	inbox             Inbox
 	appBaseURL        string
 	unsubscribeSecret string
 }
 
-// NewChecker creates a Checker. Sends are skipped if emailConfig.APIKey is empty.
-func NewChecker(pool *pgxpool.Pool, cfg email.Config, appBaseURL, unsubscribeSecret string) *Checker {
+// NewChecker creates a Checker. Email sends are skipped if transport is nil.
+func NewChecker(pool *pgxpool.Pool, transport email.Transport, appBaseURL, unsubscribeSecret string) *Checker {
+	store := NewPoolStore(pool)
 	return &Checker{
 		pool:              pool,
-		store:             NewPoolStore(pool),
-		emailConfig:       cfg,
+		store:             store,
+		transport:         transport,
+		outbox:            NewOutbox(store, transport, nil),
+		renderer:          email.DefaultRenderer(),
 		appBaseURL:        appBaseURL,
 		unsubscribeSecret: unsubscribeSecret,
 	}
 }
 
+// WithChannels enables delivery to each alert's channel targets. Without it
+// only email recipients are notified.
+func (c *Checker) WithChannels(d *channel.Dispatcher) *Checker {
+	c.channels = d
+	c.outbox = NewOutbox(c.store, c.transport, d)
+	return c
+}
+
+// WithRenderer renders emails with r's templates, branding and locales in
+// place of the built-in ones.
+func (c *Checker) WithRenderer(r *email.Renderer) *Checker {
+	c.renderer = r
+	return c
+}
+
+// Inbox records in-app notifications for portfolio owners.
+// notification.Service implements it.
+type Inbox interface {
+	Notify(ctx context.Context, n notification.Notification) error
+}
+
+// WithInbox also tells the portfolio owner, through their in-app
+// notifications, when one of their alert conditions fires.
+func (c *Checker) WithInbox(inbox Inbox) *Checker {
+	c.inbox = inbox
+	return c
+}
+
+// recipientLocale returns the locale recipient reads email in, given the
+// per-recipient tags stored on an alert or digest.
+func (c *Checker) recipientLocale(locales map[string]string, recipient string) *email.Locale {
+	return c.renderer.Locale(locales[recipient])
+}
+
+// RunOutbox retries queued deliveries every interval until ctx is cancelled.
+func (c *Checker) RunOutbox(ctx context.Context, interval time.Duration) {
+	c.outbox.Run(ctx, interval)
+}
+
 // NotifyRunComplete evaluates and dispatches alerts for portfolioID.
 func (c *Checker) NotifyRunComplete(ctx context.Context, portfolioID, _ uuid.UUID, success bool) error {
 	alerts, err := c.store.List(ctx, portfolioID)
@@ -84,43 +136,154 @@ func (c *Checker) NotifyRunComplete(ctx context.Context, portfolioID, _ uuid.UUI
 	}
 
 	for _, a := range alerts {
+		if a.Frequency == FrequencyOnCondition {
+			if success && port.SnapshotPath != nil {
+				c.checkConditions(ctx, a, port, now)
+			}
+			continue
+		}
 		if !isDue(a, port.StrategySchedule, now) {
 			continue
 		}
-		if sendErr := c.sendOne(ctx, a, port, now, success); sendErr != nil {
+		if sendErr := c.sendOne(ctx, a, port, now, success, nil); sendErr != nil {
 			log.Warn().Err(sendErr).Stringer("alert_id", a.ID).Msg("alert: send failed")
 		}
 	}
 	return nil
 }
 
+// checkConditions evaluates an on_condition alert against the fresh snapshot,
+// remembers what each condition saw, and sends only when something fired.
+func (c *Checker) checkConditions(ctx context.Context, a Alert, port portfolioData, now time.Time) {
+	r, err := snapshot.Open(*port.SnapshotPath)
+	if err != nil {
+		log.Warn().Err(err).Stringer("alert_id", a.ID).Msg("alert: open snapshot for conditions")
+		return
+	}
+	triggers, state := evaluateConditions(ctx, r, a.Conditions, a.ConditionState)
+	if err := r.Close(); err != nil {
+		log.Warn().Err(err).Msg("alert: snapshot close")
+	}
+
+	if err := c.store.SaveConditionState(ctx, a.ID, state); err != nil {
+		log.Warn().Err(err).Stringer("alert_id", a.ID).Msg("alert: save condition state")
+	}
+	if len(triggers) == 0 {
+		return
+	}
+	c.notifyTriggered(ctx, a, port, triggers)
+	if err := c.sendOne(ctx, a, port, now, true, triggers); err != nil {
+		log.Warn().Err(err).Stringer("alert_id", a.ID).Msg("alert: send failed")
+	}
+}
+
+// notifyTriggered records the fired conditions in the portfolio owner's
+// notifications, in the server's default locale.
+func (c *Checker) notifyTriggered(ctx context.Context, a Alert, port portfolioData, triggers []email.Message) {
+	if c.inbox == nil {
+		return
+	}
+	loc := c.renderer.DefaultLocale()
+	lines := make([]string, 0, len(triggers))
+	for _, t := range triggers {
+		lines = append(lines, loc.T(t.Key, t.Args...))
+	}
+	portfolioID := a.PortfolioID
+	n := notification.Notification{
+		Kind:        notification.KindAlertTriggered,
+		PortfolioID: &portfolioID,
+		Title:       loc.T("subject.condition", port.Name),
+		Body:        strings.Join(lines, "\n"),
+		Data:        map[string]any{"alertId": a.ID.String(), "triggers": lines},
+	}
+	if port.Slug != "" {
+		n.PortfolioSlug = &port.Slug
+	}
+	if err := c.inbox.Notify(ctx, n); err != nil {
+		log.Warn().Err(err).Stringer("alert_id", a.ID).Msg("alert: notify owner")
+	}
+}
+
 // SendSummary sends a one-off portfolio summary email to recipient.
-// Returns ErrEmailNotConfigured if no Mailgun API key is set.
+// Returns ErrEmailNotConfigured if no email transport is configured.
 func (c *Checker) SendSummary(ctx context.Context, portfolioID uuid.UUID, recipient string) error {
-	if c.emailConfig.APIKey == "" {
+	if c.transport == nil {
 		return ErrEmailNotConfigured
 	}
 	port, err := c.loadPortfolio(ctx, portfolioID)
 	if err != nil {
 		return fmt.Errorf("send summary: load portfolio: %w", err)
 	}
-	now := time.Now().UTC()
-	payload := c.buildPayload(ctx, Alert{}, port, now, port.Status == "ready")
+	loc := c.renderer.DefaultLocale()
+	payload := c.buildPayload(ctx, port, port.Status == "ready", nil, loc)
 	payload.Trades = nil // no trade context for an on-demand summary
-	htmlBody, textBody, err := email.Render(payload)
+	htmlBody, textBody, err := c.renderer.Render(payload, loc)
 	if err != nil {
 		return fmt.Errorf("send summary: render: %w", err)
 	}
-	subject := fmt.Sprintf("Portfolio Update: %s", port.Name)
+	subject := loc.T("subject.update", port.Name)
 	if port.Status != "ready" {
-		subject = fmt.Sprintf("Portfolio Error: %s", port.Name)
+		subject = loc.T("subject.error", port.Name)
 	}
-	if err := email.Send(ctx, c.emailConfig, []string{recipient}, subject, htmlBody, textBody); err != nil {
+	if _, err := c.transport.Send(ctx, []string{recipient}, subject, htmlBody, textBody); err != nil {
 		return fmt.Errorf("send summary: send: %w", err)
 	}
 	return nil
 }
 
+// ConfirmationsEnabled reports whether confirmation emails can be sent.
+// Confirmation links need the signing secret and the app base URL; without
+// them the handlers refuse new email recipients rather than mail anyone who
+// has not opted in.
+func (c *Checker) ConfirmationsEnabled() bool {
+	return c.unsubscribeSecret != "" && c.appBaseURL != ""
+}
+
+// SendConfirmations queues a confirmation email to each of recipients on a
+// and attempts delivery right away.
+func (c *Checker) SendConfirmations(ctx context.Context, a Alert, recipients []string) error {
+	if !c.ConfirmationsEnabled() || len(recipients) == 0 {
+		return nil
+	}
+	port, err := c.loadPortfolio(ctx, a.PortfolioID)
+	if err != nil {
+		return fmt.Errorf("send confirmations: load portfolio: %w", err)
+	}
+	expires := time.Now().Add(confirmTokenTTL)
+	deliveries := make([]Delivery, 0, len(recipients))
+	for _, recipient := range recipients {
+		tok, err := GenerateConfirmToken(c.unsubscribeSecret, a.ID, recipient, expires)
+		if err != nil {
+			return fmt.Errorf("send confirmations: %w", err)
+		}
+		loc := c.recipientLocale(a.Locales, recipient)
+		htmlBody, textBody, err := c.renderer.RenderConfirmation(email.Confirmation{
+			PortfolioName: port.Name,
+			Recipient:     recipient,
+			ConfirmURL:    c.appBaseURL + "/api/alerts/confirm?token=" + tok,
+			ExpiresOn:     loc.LongDate(expires.UTC()),
+		}, loc)
+		if err != nil {
+			return fmt.Errorf("send confirmations: %w", err)
+		}
+		deliveries = append(deliveries, Delivery{
+			Channel:     ChannelEmail,
+			Destination: recipient,
+			Event:       EventRecipientConfirm,
+			Subject:     loc.T("subject.confirm", port.Name),
+			HTMLBody:    htmlBody,
+			TextBody:    textBody,
+		})
+	}
+	if err := c.store.QueueDeliveries(ctx, a.ID, deliveries); err != nil {
+		return fmt.Errorf("send confirmations: %w", err)
+	}
+	if _, err := c.outbox.Tick(ctx); err != nil {
+		log.Warn().Err(err).Stringer("alert_id", a.ID).Msg("alert: outbox tick")
+	}
+	return nil
+}
+
 // loadPortfolio returns pgx.ErrNoRows if the portfolio does not exist. Callers
 // that reach this via SendSummary have already validated the portfolio by slug,
 // so a not-found here is a transient race and is acceptable as a 500.
@@ -150,33 +313,75 @@ func (c *Checker) loadPortfolio(ctx context.Context, id uuid.UUID) (portfolioDat
 	return p, nil
 }
 
-func (c *Checker) sendOne(ctx context.Context, a Alert, port portfolioData, now time.Time, success bool) error {
-	basePayload := c.buildPayload(ctx, a, port, now, success)
-
-	if c.appBaseURL != "" && port.Slug != "" {
-		basePayload.PortfolioURL = c.appBaseURL + "/portfolios/" + port.Slug
+func (c *Checker) sendOne(ctx context.Context, a Alert, port portfolioData, now time.Time, success bool, triggers []email.Message) error {
+	// The payload is formatted once per locale among the recipients, not once
+	// per recipient: building it reads the snapshot.
+	payloads := map[*email.Locale]email.Payload{}
+	payloadIn := func(loc *email.Locale) email.Payload {
+		if p, ok := payloads[loc]; ok {
+			return p
+		}
+		p := c.buildPayload(ctx, port, success, triggers, loc)
+		if c.appBaseURL != "" && port.Slug != "" {
+			p.PortfolioURL = c.appBaseURL + "/portfolios/" + port.Slug
+		}
+		payloads[loc] = p
+		return p
 	}
 
-	subject := fmt.Sprintf("Portfolio Update: %s", port.Name)
-	if !success {
-		subject = fmt.Sprintf("Portfolio Error: %s", port.Name)
+	subjectKey, event := "subject.update", "portfolio.update"
+	switch {
+	case !success:
+		subjectKey, event = "subject.error", "portfolio.error"
+	case len(triggers) > 0:
+		subjectKey, event = "subject.condition", "portfolio.condition"
 	}
 
-	for _, recipient := range a.Recipients {
-		p := basePayload
-		if c.unsubscribeSecret != "" && c.appBaseURL != "" {
-			tok, err := GenerateUnsubscribeToken(c.unsubscribeSecret, a.ID, recipient)
-			if err == nil {
-				p.UnsubscribeURL = c.appBaseURL + "/api/alerts/unsubscribe?token=" + tok
+	var deliveries []Delivery
+	if c.transport != nil {
+		for _, recipient := range a.Recipients {
+			if !a.Verified(recipient) {
+				continue
 			}
+			loc := c.recipientLocale(a.Locales, recipient)
+			p := payloadIn(loc)
+			if c.unsubscribeSecret != "" && c.appBaseURL != "" {
+				tok, err := GenerateUnsubscribeToken(c.unsubscribeSecret, a.ID, recipient)
+				if err == nil {
+					p.UnsubscribeURL = c.appBaseURL + "/api/alerts/unsubscribe?token=" + tok
+				}
+			}
+			htmlBody, textBody, err := c.renderer.Render(p, loc)
+			if err != nil {
+				log.Warn().Err(err).Str("recipient", recipient).Msg("alert: render failed")
+				continue
+			}
+			deliveries = append(deliveries, Delivery{
+				Channel:     ChannelEmail,
+				Destination: recipient,
+				Event:       event,
+				Subject:     loc.T(subjectKey, port.Name),
+				HTMLBody:    htmlBody,
+				TextBody:    textBody,
+			})
 		}
-		htmlBody, textBody, err := email.Render(p)
-		if err != nil {
-			log.Warn().Err(err).Str("recipient", recipient).Msg("alert: render failed")
-			continue
-		}
-		if err := email.Send(ctx, c.emailConfig, []string{recipient}, subject, htmlBody, textBody); err != nil {
-			log.Warn().Err(err).Str("recipient", recipient).Msg("alert: send failed")
+	}
+	if c.channels != nil && len(a.Channels) > 0 {
+		// Channels have no reader locale; they get the operator's default.
+		loc := c.renderer.DefaultLocale()
+		basePayload := payloadIn(loc)
+		subject := loc.T(subjectKey, port.Name)
+		text := c.renderer.Plaintext(basePayload, loc)
+		for _, t := range a.Channels {
+			deliveries = append(deliveries, Delivery{
+				Channel:     t.Type,
+				Destination: t.Label(),
+				Target:      &t,
+				Event:       event,
+				Subject:     subject,
+				TextBody:    text,
+				Payload:     basePayload,
+			})
 		}
 	}
 
@@ -184,18 +389,27 @@ func (c *Checker) sendOne(ctx context.Context, a Alert, port portfolioData, now
 	if port.CurrentValue != nil {
 		curVal = *port.CurrentValue
 	}
-	if markErr := c.store.MarkSent(ctx, a.ID, now, curVal); markErr != nil {
-		log.Warn().Err(markErr).Stringer("alert_id", a.ID).Msg("alert: mark sent failed")
+	if err := c.store.EnqueueDeliveries(ctx, a.ID, now, curVal, deliveries); err != nil {
+		return fmt.Errorf("enqueue: %w", err)
+	}
+	// Attempt the fresh deliveries right away; anything that fails is left
+	// for the background outbox worker to retry.
+	if _, err := c.outbox.Tick(ctx); err != nil {
+		log.Warn().Err(err).Stringer("alert_id", a.ID).Msg("alert: outbox tick")
 	}
 	return nil
 }
 
-func (c *Checker) buildPayload(ctx context.Context, _ Alert, port portfolioData, _ time.Time, success bool) email.Payload {
+// buildPayload formats port's latest run for an alert message in loc.
+// triggers are the conditions that fired, if any.
+func (c *Checker) buildPayload(ctx context.Context, port portfolioData, success bool, triggers []email.Message, loc *email.Locale) email.Payload {
 	p := email.Payload{
 		PortfolioName: port.Name,
 		StrategyCode:  port.StrategyCode,
 		Success:       success,
-		LogoDataURL:   email.LogoDataURL(),
+	}
+	for _, t := range triggers {
+		p.Triggers = append(p.Triggers, loc.Text(t))
 	}
 
 	if !success {
@@ -203,19 +417,19 @@ func (c *Checker) buildPayload(ctx context.Context, _ Alert, port portfolioData,
 			p.ErrorMessage = *port.LastError
 		}
 		if port.CurrentValue != nil {
-			p.LastKnownValue = "$" + email.FormatMoneyVal(*port.CurrentValue)
+			p.LastKnownValue = loc.Money(*port.CurrentValue)
 		}
 		return p
 	}
 
 	if port.SnapshotPath != nil {
 		if dc, err := snapshotDayChange(ctx, *port.SnapshotPath); err == nil {
-			p.RunDate = "as of " + dc.LatestDate.Format("January 2, 2006")
+			p.RunDate = loc.T("as_of", loc.LongDate(dc.LatestDate))
 			if port.CurrentValue != nil {
-				p.CurrentValue = "$" + email.FormatMoneyVal(*port.CurrentValue)
+				p.CurrentValue = loc.Money(*port.CurrentValue)
 			}
 			if dc.HasPrior {
-				pct, abs, color, since, hasDelta := email.FormatDelta(
+				pct, abs, color, since, hasDelta := loc.FormatDelta(
 					dc.LatestValue, dc.PriorValue,
 					dc.PriorDate, dc.LatestDate,
 				)
@@ -226,10 +440,10 @@ func (c *Checker) buildPayload(ctx context.Context, _ Alert, port portfolioData,
 				p.SinceLabel = since
 			}
 		}
-		c.fillReturns(ctx, &p, *port.SnapshotPath, port.Benchmark)
-		c.fillHoldingsAndTrades(ctx, &p, port)
+		c.fillReturns(ctx, &p, *port.SnapshotPath, port.Benchmark, loc)
+		c.fillHoldingsAndTrades(ctx, &p, port, loc)
 	} else if port.CurrentValue != nil {
-		p.CurrentValue = "$" + email.FormatMoneyVal(*port.CurrentValue)
+		p.CurrentValue = loc.Money(*port.CurrentValue)
 	}
 
 	return p
@@ -248,17 +462,17 @@ func snapshotDayChange(ctx context.Context, path string) (snapshot.DayChangeSumm
 
 // returnCell formats a fractional return into a grid cell, rendering an
 // em-dash for windows the snapshot did not emit (present == false).
-func returnCell(v float64, present bool) email.ReturnCell {
+func returnCell(v float64, present bool, loc *email.Locale) email.ReturnCell {
 	if !present {
 		return email.ReturnCell{Pct: "—", Color: "#94a3b8"}
 	}
-	pct, color := email.FormatReturnPct(v)
+	pct, color := loc.FormatReturnPct(v)
 	return email.ReturnCell{Pct: pct, Color: color}
 }
 
 // fillReturns builds the returns comparison grid: a Portfolio row always, and
 // a benchmark row when the portfolio has a benchmark with data in the snapshot.
-func (c *Checker) fillReturns(ctx context.Context, p *email.Payload, snapshotPath, benchmark string) {
+func (c *Checker) fillReturns(ctx context.Context, p *email.Payload, snapshotPath, benchmark string, loc *email.Locale) {
 	r, err := snapshot.Open(snapshotPath)
 	if err != nil {
 		return
@@ -269,34 +483,43 @@ func (c *Checker) fillReturns(ctx context.Context, p *email.Payload, snapshotPat
 		}
 	}()
 
-	short, err := r.ShortTermReturns(ctx)
-	if err != nil {
-		return
-	}
-	kpis, err := r.Kpis(ctx)
+	row, kpis, err := portfolioReturnsRow(ctx, r, loc.T("series.portfolio"), loc)
 	if err != nil {
 		return
 	}
+	p.Returns = append(p.Returns, row)
 
-	p.Returns = append(p.Returns, email.ReturnsRow{
-		Label:   "Portfolio",
-		Day:     returnCell(short.Day, true),
-		Wtd:     returnCell(short.WTD, true),
-		Mtd:     returnCell(short.MTD, true),
-		Ytd:     returnCell(deref(kpis.YtdReturn), kpis.YtdReturn != nil),
-		OneYear: returnCell(deref(kpis.OneYearReturn), kpis.OneYearReturn != nil),
-	})
-
-	if row, ok := benchmarkReturnsRow(ctx, r, benchmark, kpis); ok {
+	if row, ok := benchmarkReturnsRow(ctx, r, benchmark, kpis, loc); ok {
 		p.Returns = append(p.Returns, row)
 	}
 
-	fillPhoneReturns(p)
+	fillPhoneReturns(p, loc)
+}
+
+// portfolioReturnsRow builds the portfolio's row of the returns grid under
+// label, and returns the KPIs it read so the benchmark row can share them.
+func portfolioReturnsRow(ctx context.Context, r *snapshot.Reader, label string, loc *email.Locale) (email.ReturnsRow, snapshot.Kpis, error) {
+	short, err := r.ShortTermReturns(ctx)
+	if err != nil {
+		return email.ReturnsRow{}, snapshot.Kpis{}, err
+	}
+	kpis, err := r.Kpis(ctx)
+	if err != nil {
+		return email.ReturnsRow{}, snapshot.Kpis{}, err
+	}
+	return email.ReturnsRow{
+		Label:   label,
+		Day:     returnCell(short.Day, true, loc),
+		Wtd:     returnCell(short.WTD, true, loc),
+		Mtd:     returnCell(short.MTD, true, loc),
+		Ytd:     returnCell(deref(kpis.YtdReturn), kpis.YtdReturn != nil, loc),
+		OneYear: returnCell(deref(kpis.OneYearReturn), kpis.OneYearReturn != nil, loc),
+	}, kpis, nil
 }
 
 // benchmarkReturnsRow builds the benchmark row, or returns ok=false when the
 // portfolio has no benchmark or the snapshot carries no benchmark series.
-func benchmarkReturnsRow(ctx context.Context, r *snapshot.Reader, benchmark string, kpis snapshot.Kpis) (email.ReturnsRow, bool) {
+func benchmarkReturnsRow(ctx context.Context, r *snapshot.Reader, benchmark string, kpis snapshot.Kpis, loc *email.Locale) (email.ReturnsRow, bool) {
 	if benchmark == "" {
 		return email.ReturnsRow{}, false
 	}
@@ -309,34 +532,34 @@ func benchmarkReturnsRow(ctx context.Context, r *snapshot.Reader, benchmark stri
 		return email.ReturnsRow{}, false
 	}
 	return email.ReturnsRow{
-		Label:   fmt.Sprintf("Benchmark (%s)", benchmark),
-		Day:     returnCell(benchShort.Day, true),
-		Wtd:     returnCell(benchShort.WTD, true),
-		Mtd:     returnCell(benchShort.MTD, true),
-		Ytd:     returnCell(deref(kpis.BenchmarkYtdReturn), kpis.BenchmarkYtdReturn != nil),
-		OneYear: returnCell(deref(kpis.BenchmarkOneYearReturn), kpis.BenchmarkOneYearReturn != nil),
+		Label:   loc.T("series.benchmark", benchmark),
+		Day:     returnCell(benchShort.Day, true, loc),
+		Wtd:     returnCell(benchShort.WTD, true, loc),
+		Mtd:     returnCell(benchShort.MTD, true, loc),
+		Ytd:     returnCell(deref(kpis.BenchmarkYtdReturn), kpis.BenchmarkYtdReturn != nil, loc),
+		OneYear: returnCell(deref(kpis.BenchmarkOneYearReturn), kpis.BenchmarkOneYearReturn != nil, loc),
 	}, true
 }
 
 // fillPhoneReturns derives the flipped phone layout from the wide Returns rows:
 // the series labels become column headers, and each window (Day, WTD, …)
 // becomes a row whose cells line up with those columns.
-func fillPhoneReturns(p *email.Payload) {
+func fillPhoneReturns(p *email.Payload, loc *email.Locale) {
 	for _, row := range p.Returns {
 		p.SeriesLabels = append(p.SeriesLabels, row.Label)
 	}
 	windows := []struct {
-		label string
-		pick  func(email.ReturnsRow) email.ReturnCell
+		key  string
+		pick func(email.ReturnsRow) email.ReturnCell
 	}{
-		{"Day", func(r email.ReturnsRow) email.ReturnCell { return r.Day }},
-		{"WTD", func(r email.ReturnsRow) email.ReturnCell { return r.Wtd }},
-		{"MTD", func(r email.ReturnsRow) email.ReturnCell { return r.Mtd }},
-		{"YTD", func(r email.ReturnsRow) email.ReturnCell { return r.Ytd }},
-		{"1Y", func(r email.ReturnsRow) email.ReturnCell { return r.OneYear }},
+		{"window.day", func(r email.ReturnsRow) email.ReturnCell { return r.Day }},
+		{"window.wtd", func(r email.ReturnsRow) email.ReturnCell { return r.Wtd }},
+		{"window.mtd", func(r email.ReturnsRow) email.ReturnCell { return r.Mtd }},
+		{"window.ytd", func(r email.ReturnsRow) email.ReturnCell { return r.Ytd }},
+		{"window.1y", func(r email.ReturnsRow) email.ReturnCell { return r.OneYear }},
 	}
 	for _, w := range windows {
-		win := email.ReturnsWindow{Label: w.label}
+		win := email.ReturnsWindow{Label: loc.T(w.key)}
 		for _, row := range p.Returns {
 			win.Cells = append(win.Cells, w.pick(row))
 		}
@@ -351,7 +574,7 @@ func deref(v *float64) float64 {
 	return *v
 }
 
-func (c *Checker) fillHoldingsAndTrades(ctx context.Context, p *email.Payload, port portfolioData) {
+func (c *Checker) fillHoldingsAndTrades(ctx context.Context, p *email.Payload, port portfolioData, loc *email.Locale) {
 	r, err := snapshot.Open(*port.SnapshotPath)
 	if err != nil {
 		return
@@ -367,11 +590,25 @@ func (c *Checker) fillHoldingsAndTrades(ctx context.Context, p *email.Payload, p
 		return
 	}
 
+	p.Holdings = holdingRows(cur.Items, loc)
+
+	trades, err := r.LatestBatchTrades(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("alert: latest batch trades")
+		return
+	}
+	fillTrades(p, trades, loc)
+}
+
+// holdingRows formats items for the holdings table, weighting each by its
+// share of the items' total market value.
+func holdingRows(items []openapi.Holding, loc *email.Locale) []email.HoldingRow {
 	total := 0.0
-	for _, h := range cur.Items {
+	for _, h := range items {
 		total += h.MarketValue
 	}
-	for _, h := range cur.Items {
+	rows := make([]email.HoldingRow, 0, len(items))
+	for _, h := range items {
 		weight := 0.0
 		if total > 0 {
 			weight = h.MarketValue / total * 100
@@ -380,50 +617,56 @@ func (c *Checker) fillHoldingsAndTrades(ctx context.Context, p *email.Payload, p
 		if h.Ticker == "$CASH" {
 			shares = "—"
 		} else {
-			shares = email.FormatMoneyVal(h.Quantity)
+			shares = loc.Number(h.Quantity, 0)
 		}
 		tickerColor := "#0f172a"
 		if h.Ticker == "$CASH" {
 			tickerColor = "#94a3b8"
 		}
-		p.Holdings = append(p.Holdings, email.HoldingRow{
+		rows = append(rows, email.HoldingRow{
 			Ticker:      h.Ticker,
 			TickerColor: tickerColor,
 			Shares:      shares,
-			WeightPct:   fmt.Sprintf("%.1f", weight),
-			Value:       "$" + email.FormatMoneyVal(h.MarketValue),
+			WeightPct:   loc.Number(weight, 1),
+			Value:       loc.Money(h.MarketValue),
 		})
 	}
-
-	trades, err := r.LatestBatchTrades(ctx)
-	if err != nil {
-		log.Warn().Err(err).Msg("alert: latest batch trades")
-		return
-	}
-	fillTrades(p, trades)
+	return rows
 }
 
 // fillTrades populates the trade rows and the shared execution date on the
 // payload from the most recent rebalance batch.
-func fillTrades(p *email.Payload, trades []snapshot.LatestBatchTrade) {
+func fillTrades(p *email.Payload, trades []snapshot.LatestBatchTrade, loc *email.Locale) {
 	for _, tx := range trades {
 		if p.TradesDate == "" && tx.Date != "" {
 			if d, err := time.Parse("2006-01-02", tx.Date); err == nil {
-				p.TradesDate = d.Format("January 2, 2006")
+				p.TradesDate = loc.LongDate(d)
 			}
 		}
-		action := strings.ToUpper(tx.Type[:1]) + tx.Type[1:]
-		actionColor, actionBgColor := "#16a34a", "#dcfce7"
-		if tx.Type == "sell" {
-			actionColor, actionBgColor = "#ef4444", "#fee2e2"
-		}
-		p.Trades = append(p.Trades, email.TradeRow{
-			Ticker:        tx.Ticker,
-			Action:        action,
-			ActionColor:   actionColor,
-			ActionBgColor: actionBgColor,
-			Shares:        email.FormatMoneyVal(math.Abs(tx.Quantity)),
-			Value:         "$" + email.FormatMoneyVal(math.Abs(tx.Amount)),
-		})
+		p.Trades = append(p.Trades, tradeRow(tx.Type, tx.Ticker, tx.Quantity, tx.Amount, loc))
+	}
+}
+
+// tradeRow formats one buy or sell for the trades table.
+func tradeRow(typ, ticker string, quantity, amount float64, loc *email.Locale) email.TradeRow {
+	action := typ
+	switch typ {
+	case "buy", "sell":
+		action = loc.T("action." + typ)
+	case "":
+	default:
+		action = strings.ToUpper(typ[:1]) + typ[1:]
+	}
+	actionColor, actionBgColor := "#16a34a", "#dcfce7"
+	if typ == "sell" {
+		actionColor, actionBgColor = "#ef4444", "#fee2e2"
+	}
+	return email.TradeRow{
+		Ticker:        ticker,
+		Action:        action,
+		ActionColor:   actionColor,
+		ActionBgColor: actionBgColor,
+		Shares:        loc.Number(math.Abs(quantity), 0),
+		Value:         loc.Money(math.Abs(amount)),
 	}
 }
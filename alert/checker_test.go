@@ -18,16 +18,49 @@ package alert
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+
 	"github.com/penny-vault/pv-api/alert/email"
+	"github.com/penny-vault/pv-api/notification"
 )
 
 func TestSendSummaryNoAPIKey(t *testing.T) {
-	c := &Checker{emailConfig: email.Config{}}
+	c := &Checker{}
 	err := c.SendSummary(context.Background(), uuid.New(), "user@example.com")
 	if !errors.Is(err, ErrEmailNotConfigured) {
 		t.Fatalf("expected ErrEmailNotConfigured, got %v", err)
 	}
 }
+
+type recordingInbox struct {
+	sent []notification.Notification
+}
+
+func (r *recordingInbox) Notify(_ context.Context, n notification.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func TestNotifyTriggered(t *testing.T) {
+	inbox := &recordingInbox{}
+	c := NewChecker(nil, nil, "", "").WithInbox(inbox)
+	a := Alert{ID: uuid.New(), PortfolioID: uuid.New()}
+	port := portfolioData{Name: "Core", Slug: "core"}
+	c.notifyTriggered(context.Background(), a, port, []email.Message{
+		{Key: "trigger.drawdown", Args: []any{email.Percent(-0.12), email.Percent(0.1)}},
+	})
+
+	if len(inbox.sent) != 1 {
+		t.Fatalf("sent %d notifications, want 1", len(inbox.sent))
+	}
+	n := inbox.sent[0]
+	if n.Kind != notification.KindAlertTriggered || *n.PortfolioID != a.PortfolioID || *n.PortfolioSlug != "core" {
+		t.Fatalf("unexpected notification %+v", n)
+	}
+	if n.Title != "Portfolio Alert: Core" || !strings.Contains(n.Body, "12") {
+		t.Fatalf("title %q body %q", n.Title, n.Body)
+	}
+}
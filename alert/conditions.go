@@ -0,0 +1,342 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/alert/email"
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/snapshot"
+)
+
+// Condition types understood by an on_condition alert.
+const (
+	ConditionDrawdown                  = "drawdown"
+	ConditionDayChange                 = "day_change"
+	ConditionBenchmarkUnderperformance = "benchmark_underperformance"
+	ConditionPredictedTrade            = "predicted_trade"
+	ConditionHoldingChange             = "holding_change"
+)
+
+// ErrInvalidCondition is returned by ValidateConditions for a malformed rule.
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// Condition is one rule on an on_condition alert. Threshold is a positive
+// fraction (0.10 = 10%) used by drawdown, day_change and
+// benchmark_underperformance; Window is the lookback for
+// benchmark_underperformance.
+type Condition struct {
+	Type      string   `json:"type"`
+	Threshold *float64 `json:"threshold,omitempty"`
+	Window    string   `json:"window,omitempty"`
+}
+
+// ConditionState records what each condition last observed, keyed by
+// conditionKey. It lets a condition fire once when it becomes true rather than
+// on every run for as long as it stays true.
+type ConditionState map[string]string
+
+// conditionWindows are the benchmark_underperformance windows. Each has a
+// condition_window.<window> phrase in the email string catalog.
+var conditionWindows = []string{"1w", "1m", "3m", "6m", "ytd", "1y"}
+
+// stateActive marks a threshold condition that has fired and not yet cleared.
+const stateActive = "active"
+
+// ValidateConditions checks that every condition has a known type and carries
+// exactly the fields that type uses.
+func ValidateConditions(conds []Condition) error {
+	for i, c := range conds {
+		switch c.Type {
+		case ConditionDrawdown, ConditionDayChange:
+			if c.Threshold == nil || *c.Threshold <= 0 || *c.Threshold >= 1 {
+				return fmt.Errorf("%w: conditions[%d]: %s needs a threshold between 0 and 1", ErrInvalidCondition, i, c.Type)
+			}
+			if c.Window != "" {
+				return fmt.Errorf("%w: conditions[%d]: %s does not take a window", ErrInvalidCondition, i, c.Type)
+			}
+		case ConditionBenchmarkUnderperformance:
+			if c.Threshold == nil || *c.Threshold <= 0 {
+				return fmt.Errorf("%w: conditions[%d]: %s needs a positive threshold", ErrInvalidCondition, i, c.Type)
+			}
+			if !slices.Contains(conditionWindows, c.Window) {
+				return fmt.Errorf("%w: conditions[%d]: window must be one of 1w, 1m, 3m, 6m, ytd, 1y", ErrInvalidCondition, i)
+			}
+		case ConditionPredictedTrade, ConditionHoldingChange:
+			if c.Threshold != nil || c.Window != "" {
+				return fmt.Errorf("%w: conditions[%d]: %s takes no threshold or window", ErrInvalidCondition, i, c.Type)
+			}
+		default:
+			return fmt.Errorf("%w: conditions[%d]: unknown type %q", ErrInvalidCondition, i, c.Type)
+		}
+	}
+	return nil
+}
+
+// ConditionSource is the snapshot data conditions are evaluated against.
+// *snapshot.Reader satisfies it.
+type ConditionSource interface {
+	CurrentDrawdown(ctx context.Context) (float64, error)
+	DayChange(ctx context.Context) (snapshot.DayChangeSummary, error)
+	PortfolioValueAt(ctx context.Context, t time.Time) (float64, error)
+	BenchmarkValueAt(ctx context.Context, t time.Time) (float64, error)
+	Prediction(ctx context.Context) (*openapi.PredictionResponse, error)
+	CurrentHoldings(ctx context.Context) (*openapi.HoldingsResponse, error)
+}
+
+var _ ConditionSource = (*snapshot.Reader)(nil)
+
+func conditionKey(i int, c Condition) string { return fmt.Sprintf("%d:%s", i, c.Type) }
+
+// evaluateConditions runs every condition against src and returns a message
+// for each one that newly fired, plus the state to persist for the next run.
+// A condition whose data cannot be read keeps its previous state so a
+// transient snapshot error neither fires nor re-arms it.
+func evaluateConditions(ctx context.Context, src ConditionSource, conds []Condition, prev ConditionState) ([]email.Message, ConditionState) {
+	var triggers []email.Message
+	next := ConditionState{}
+	for i, c := range conds {
+		key := conditionKey(i, c)
+		before, had := prev[key]
+		msg, seen, err := evaluateCondition(ctx, src, c, before, had)
+		if err != nil {
+			log.Warn().Err(err).Str("condition", key).Msg("alert: evaluate condition")
+			if had {
+				next[key] = before
+			}
+			continue
+		}
+		// holding_change keeps an empty baseline too: a portfolio sitting in
+		// cash still has a ticker set to compare against next run.
+		if seen != "" || c.Type == ConditionHoldingChange {
+			next[key] = seen
+		}
+		if msg != nil {
+			triggers = append(triggers, *msg)
+		}
+	}
+	return triggers, next
+}
+
+// evaluateCondition returns the trigger message (nil when the condition did
+// not newly fire) and the state to remember for it (empty to clear).
+func evaluateCondition(ctx context.Context, src ConditionSource, c Condition, before string, had bool) (*email.Message, string, error) {
+	switch c.Type {
+	case ConditionDrawdown:
+		return evalDrawdown(ctx, src, *c.Threshold, before)
+	case ConditionDayChange:
+		return evalDayChange(ctx, src, *c.Threshold, before)
+	case ConditionBenchmarkUnderperformance:
+		return evalBenchmark(ctx, src, *c.Threshold, c.Window, before)
+	case ConditionPredictedTrade:
+		return evalPredictedTrade(ctx, src, before)
+	case ConditionHoldingChange:
+		return evalHoldingChange(ctx, src, before, had)
+	}
+	return nil, "", fmt.Errorf("%w: unknown type %q", ErrInvalidCondition, c.Type)
+}
+
+func evalDrawdown(ctx context.Context, src ConditionSource, threshold float64, before string) (*email.Message, string, error) {
+	dd, err := src.CurrentDrawdown(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if dd > -threshold {
+		return nil, "", nil
+	}
+	if before == stateActive {
+		return nil, stateActive, nil
+	}
+	return &email.Message{Key: "trigger.drawdown", Args: []any{email.Percent(-dd), email.Percent(threshold)}}, stateActive, nil
+}
+
+func evalDayChange(ctx context.Context, src ConditionSource, threshold float64, before string) (*email.Message, string, error) {
+	dc, err := src.DayChange(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if !dc.HasPrior || dc.PriorValue <= 0 {
+		return nil, "", nil
+	}
+	change := dc.LatestValue/dc.PriorValue - 1
+	if math.Abs(change) < threshold {
+		return nil, "", nil
+	}
+	day := dc.LatestDate.Format("2006-01-02")
+	if before == day {
+		return nil, day, nil
+	}
+	key := "trigger.day_change.rose"
+	if change < 0 {
+		key = "trigger.day_change.fell"
+	}
+	return &email.Message{Key: key, Args: []any{
+		email.Percent(math.Abs(change)), dc.LatestDate, email.Percent(threshold),
+	}}, day, nil
+}
+
+func evalBenchmark(ctx context.Context, src ConditionSource, threshold float64, window, before string) (*email.Message, string, error) {
+	dc, err := src.DayChange(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if dc.LatestDate.IsZero() {
+		return nil, "", nil
+	}
+	start := windowStart(window, dc.LatestDate)
+
+	var vals [4]float64
+	reads := []struct {
+		fn func(context.Context, time.Time) (float64, error)
+		t  time.Time
+	}{
+		{src.PortfolioValueAt, start},
+		{src.PortfolioValueAt, dc.LatestDate},
+		{src.BenchmarkValueAt, start},
+		{src.BenchmarkValueAt, dc.LatestDate},
+	}
+	for i, rd := range reads {
+		v, err := rd.fn(ctx, rd.t)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", nil // no benchmark series in this snapshot
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if v <= 0 {
+			return nil, "", nil
+		}
+		vals[i] = v
+	}
+	gap := (vals[3]/vals[2] - 1) - (vals[1]/vals[0] - 1)
+	if gap < threshold {
+		return nil, "", nil
+	}
+	if before == stateActive {
+		return nil, stateActive, nil
+	}
+	return &email.Message{Key: "trigger.benchmark", Args: []any{
+		email.Percent(gap), email.Message{Key: "condition_window." + window}, email.Percent(threshold),
+	}}, stateActive, nil
+}
+
+func evalPredictedTrade(ctx context.Context, src ConditionSource, before string) (*email.Message, string, error) {
+	pred, err := src.Prediction(ctx)
+	if errors.Is(err, snapshot.ErrNotFound) {
+		return nil, before, nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if len(pred.Transactions) == 0 {
+		return nil, before, nil
+	}
+	day := pred.Date.Format("2006-01-02")
+	if before == day {
+		return nil, day, nil
+	}
+	trades := email.List{Sep: ", "}
+	for _, tx := range pred.Transactions {
+		ticker := "?"
+		if tx.Ticker != nil {
+			ticker = *tx.Ticker
+		}
+		// Other transaction types keep their raw name as an argument, e.g.
+		// "dividend VTI"; it never becomes part of the format string.
+		trade := email.Message{Key: "trigger.trade.other", Args: []any{tx.Type, ticker}}
+		if tx.Type == "buy" || tx.Type == "sell" {
+			trade = email.Message{Key: "trigger.trade." + tx.Type, Args: []any{ticker}}
+		}
+		trades.Items = append(trades.Items, trade)
+	}
+	return &email.Message{Key: "trigger.predicted_trade", Args: []any{pred.Date.Time, trades}}, day, nil
+}
+
+// evalHoldingChange compares the current tickers (cash excluded) with the set
+// recorded last run. The first evaluation only records a baseline.
+func evalHoldingChange(ctx context.Context, src ConditionSource, before string, had bool) (*email.Message, string, error) {
+	cur, err := src.CurrentHoldings(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	var tickers []string
+	if cur != nil {
+		for _, h := range cur.Items {
+			if h.Ticker != "$CASH" && !slices.Contains(tickers, h.Ticker) {
+				tickers = append(tickers, h.Ticker)
+			}
+		}
+	}
+	slices.Sort(tickers)
+	seen := strings.Join(tickers, ",")
+	if !had || seen == before {
+		return nil, seen, nil
+	}
+
+	var prior []string
+	if before != "" {
+		prior = strings.Split(before, ",")
+	}
+	var added, removed []string
+	for _, t := range tickers {
+		if !slices.Contains(prior, t) {
+			added = append(added, t)
+		}
+	}
+	for _, t := range prior {
+		if !slices.Contains(tickers, t) {
+			removed = append(removed, t)
+		}
+	}
+	parts := email.List{Sep: "; "}
+	if len(added) > 0 {
+		parts.Items = append(parts.Items, email.Message{Key: "trigger.holding_added", Args: []any{strings.Join(added, ", ")}})
+	}
+	if len(removed) > 0 {
+		parts.Items = append(parts.Items, email.Message{Key: "trigger.holding_removed", Args: []any{strings.Join(removed, ", ")}})
+	}
+	return &email.Message{Key: "trigger.holding_change", Args: []any{parts}}, seen, nil
+}
+
+// windowStart returns the baseline date for a benchmark_underperformance
+// window ending at asOf. ytd uses the last day of the prior year so the
+// first trading day's return is included.
+func windowStart(window string, asOf time.Time) time.Time {
+	switch window {
+	case "1w":
+		return asOf.AddDate(0, 0, -7)
+	case "1m":
+		return asOf.AddDate(0, -1, 0)
+	case "3m":
+		return asOf.AddDate(0, -3, 0)
+	case "6m":
+		return asOf.AddDate(0, -6, 0)
+	case "ytd":
+		return time.Date(asOf.Year()-1, time.December, 31, 0, 0, 0, 0, asOf.Location())
+	default:
+		return asOf.AddDate(-1, 0, 0)
+	}
+}
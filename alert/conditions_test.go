@@ -0,0 +1,240 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"github.com/penny-vault/pv-api/alert/email"
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/snapshot"
+)
+
+// fakeSource is a ConditionSource backed by fixed values.
+type fakeSource struct {
+	drawdown   float64
+	day        snapshot.DayChangeSummary
+	portfolio  map[string]float64 // date -> value
+	benchmark  map[string]float64
+	prediction *openapi.PredictionResponse
+	holdings   []string
+	err        error
+}
+
+func (f *fakeSource) CurrentDrawdown(context.Context) (float64, error) { return f.drawdown, f.err }
+
+func (f *fakeSource) DayChange(context.Context) (snapshot.DayChangeSummary, error) {
+	return f.day, f.err
+}
+
+func (f *fakeSource) PortfolioValueAt(_ context.Context, t time.Time) (float64, error) {
+	return f.portfolio[t.Format("2006-01-02")], f.err
+}
+
+func (f *fakeSource) BenchmarkValueAt(_ context.Context, t time.Time) (float64, error) {
+	if f.benchmark == nil {
+		return 0, sql.ErrNoRows
+	}
+	return f.benchmark[t.Format("2006-01-02")], f.err
+}
+
+func (f *fakeSource) Prediction(context.Context) (*openapi.PredictionResponse, error) {
+	if f.prediction == nil {
+		return nil, snapshot.ErrNotFound
+	}
+	return f.prediction, f.err
+}
+
+func (f *fakeSource) CurrentHoldings(context.Context) (*openapi.HoldingsResponse, error) {
+	out := &openapi.HoldingsResponse{}
+	for _, t := range f.holdings {
+		out.Items = append(out.Items, openapi.Holding{Ticker: t})
+	}
+	return out, f.err
+}
+
+func threshold(v float64) *float64 { return &v }
+
+// evaluate runs evaluateConditions and renders the triggers in the default
+// en-US locale.
+func evaluate(ctx context.Context, src ConditionSource, conds []Condition, prev ConditionState) ([]string, ConditionState) {
+	msgs, state := evaluateConditions(ctx, src, conds, prev)
+	loc := email.DefaultRenderer().DefaultLocale()
+	var out []string
+	for _, m := range msgs {
+		out = append(out, loc.Text(m))
+	}
+	return out, state
+}
+
+func TestDrawdownConditionFiresOnce(t *testing.T) {
+	conds := []Condition{{Type: ConditionDrawdown, Threshold: threshold(0.10)}}
+	src := &fakeSource{drawdown: -0.05}
+	ctx := context.Background()
+
+	triggers, state := evaluate(ctx, src, conds, nil)
+	if len(triggers) != 0 {
+		t.Fatalf("5%% drawdown should not fire a 10%% rule: %v", triggers)
+	}
+
+	src.drawdown = -0.123
+	triggers, state = evaluate(ctx, src, conds, state)
+	if len(triggers) != 1 || !strings.Contains(triggers[0], "12.30%") {
+		t.Fatalf("expected one drawdown trigger, got %v", triggers)
+	}
+
+	src.drawdown = -0.15
+	triggers, state = evaluate(ctx, src, conds, state)
+	if len(triggers) != 0 {
+		t.Fatalf("a persisting drawdown should not fire again: %v", triggers)
+	}
+
+	src.drawdown = -0.02
+	_, state = evaluate(ctx, src, conds, state)
+	src.drawdown = -0.11
+	triggers, _ = evaluate(ctx, src, conds, state)
+	if len(triggers) != 1 {
+		t.Fatalf("drawdown should re-arm after recovering, got %v", triggers)
+	}
+}
+
+func TestDayChangeConditionOncePerDay(t *testing.T) {
+	conds := []Condition{{Type: ConditionDayChange, Threshold: threshold(0.02)}}
+	day := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	src := &fakeSource{day: snapshot.DayChangeSummary{
+		LatestDate: day, LatestValue: 97000, PriorValue: 100000, HasPrior: true,
+	}}
+	ctx := context.Background()
+
+	triggers, state := evaluate(ctx, src, conds, nil)
+	if len(triggers) != 1 || !strings.Contains(triggers[0], "fell 3.00%") {
+		t.Fatalf("expected a 3%% drop trigger, got %v", triggers)
+	}
+	triggers, _ = evaluate(ctx, src, conds, state)
+	if len(triggers) != 0 {
+		t.Fatalf("a rerun for the same day should not fire again: %v", triggers)
+	}
+}
+
+func TestBenchmarkUnderperformanceCondition(t *testing.T) {
+	conds := []Condition{{Type: ConditionBenchmarkUnderperformance, Threshold: threshold(0.05), Window: "1m"}}
+	day := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	src := &fakeSource{
+		day:       snapshot.DayChangeSummary{LatestDate: day},
+		portfolio: map[string]float64{"2024-02-15": 100, "2024-03-15": 101},
+		benchmark: map[string]float64{"2024-02-15": 100, "2024-03-15": 108},
+	}
+	triggers, _ := evaluate(context.Background(), src, conds, nil)
+	if len(triggers) != 1 || !strings.Contains(triggers[0], "7.00%") || !strings.Contains(triggers[0], "the last month") {
+		t.Fatalf("expected a 7%% underperformance trigger, got %v", triggers)
+	}
+
+	src.benchmark = nil
+	triggers, state := evaluate(context.Background(), src, conds, nil)
+	if len(triggers) != 0 || len(state) != 0 {
+		t.Fatalf("no benchmark series should be a quiet no-op, got %v %v", triggers, state)
+	}
+}
+
+func TestPredictedTradeCondition(t *testing.T) {
+	conds := []Condition{{Type: ConditionPredictedTrade}}
+	ticker := "VTI"
+	src := &fakeSource{prediction: &openapi.PredictionResponse{
+		Date:         openapi_types.Date{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		Transactions: []openapi.PredictedTransaction{{Type: "buy", Ticker: &ticker}},
+	}}
+	ctx := context.Background()
+
+	triggers, state := evaluate(ctx, src, conds, nil)
+	if len(triggers) != 1 || !strings.Contains(triggers[0], "buy VTI") {
+		t.Fatalf("expected a predicted trade trigger, got %v", triggers)
+	}
+	triggers, _ = evaluate(ctx, src, conds, state)
+	if len(triggers) != 0 {
+		t.Fatalf("the same prediction should not fire twice: %v", triggers)
+	}
+}
+
+func TestPredictedTradeOtherTypeIsAnArgument(t *testing.T) {
+	conds := []Condition{{Type: ConditionPredictedTrade}}
+	ticker := "VTI"
+	src := &fakeSource{prediction: &openapi.PredictionResponse{
+		Date:         openapi_types.Date{Time: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		Transactions: []openapi.PredictedTransaction{{Type: "split 100%d", Ticker: &ticker}},
+	}}
+
+	triggers, _ := evaluate(context.Background(), src, conds, nil)
+	if len(triggers) != 1 || !strings.Contains(triggers[0], "split 100%d VTI") {
+		t.Fatalf("a %% in the type should render verbatim, got %v", triggers)
+	}
+}
+
+func TestHoldingChangeCondition(t *testing.T) {
+	conds := []Condition{{Type: ConditionHoldingChange}}
+	src := &fakeSource{holdings: []string{"VTI", "BND", "$CASH"}}
+	ctx := context.Background()
+
+	triggers, state := evaluate(ctx, src, conds, nil)
+	if len(triggers) != 0 {
+		t.Fatalf("the first run should only record a baseline: %v", triggers)
+	}
+
+	src.holdings = []string{"VTI", "GLD"}
+	triggers, state = evaluate(ctx, src, conds, state)
+	if len(triggers) != 1 || triggers[0] != "Holdings changed: added GLD; removed BND" {
+		t.Fatalf("unexpected holding trigger: %v", triggers)
+	}
+
+	triggers, _ = evaluate(ctx, src, conds, state)
+	if len(triggers) != 0 {
+		t.Fatalf("unchanged holdings should not fire: %v", triggers)
+	}
+}
+
+func TestConditionErrorKeepsState(t *testing.T) {
+	conds := []Condition{{Type: ConditionDrawdown, Threshold: threshold(0.10)}}
+	prev := ConditionState{conditionKey(0, conds[0]): stateActive}
+	src := &fakeSource{err: errors.New("snapshot locked")}
+
+	triggers, state := evaluate(context.Background(), src, conds, prev)
+	if len(triggers) != 0 || state[conditionKey(0, conds[0])] != stateActive {
+		t.Fatalf("a read error should neither fire nor clear: %v %v", triggers, state)
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	ok := []Condition{
+		{Type: ConditionDrawdown, Threshold: threshold(0.2)},
+		{Type: ConditionDayChange, Threshold: threshold(0.03)},
+		{Type: ConditionBenchmarkUnderperformance, Threshold: threshold(0.05), Window: "ytd"},
+		{Type: ConditionPredictedTrade},
+		{Type: ConditionHoldingChange},
+	}
+	if err := ValidateConditions(ok); err != nil {
+		t.Fatalf("valid conditions rejected: %v", err)
+	}
+	bad := []Condition{{Type: ConditionDrawdown, Threshold: threshold(1.5)}}
+	if err := ValidateConditions(bad); !errors.Is(err, ErrInvalidCondition) {
+		t.Fatalf("expected ErrInvalidCondition, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidConfirmToken = errors.New("invalid or expired confirmation token")
+
+// confirmTokenTTL is how long a recipient confirmation link stays valid.
+const confirmTokenTTL = 7 * 24 * time.Hour
+
+// confirmPrefix keeps confirmation and unsubscribe tokens from being
+// interchangeable even though both are signed with the same secret.
+const confirmPrefix = "confirm:"
+
+// GenerateConfirmToken produces a URL-safe token that confirms recipient for
+// alertID until expires, signed with HMAC-SHA256 using secret.
+// Format: base64url(confirm:expiresUnix:alertID:recipient) + "." + base64url(hmac)
+func GenerateConfirmToken(secret string, alertID uuid.UUID, recipient string, expires time.Time) (string, error) {
+	payload := confirmPrefix + strconv.FormatInt(expires.Unix(), 10) + ":" + alertID.String() + ":" + recipient
+	tok, err := signToken(secret, payload)
+	if err != nil {
+		return "", fmt.Errorf("generate confirm token: %w", err)
+	}
+	return tok, nil
+}
+
+// VerifyConfirmToken validates the token against secret and now and returns
+// alertID and recipient.
+func VerifyConfirmToken(secret, token string, now time.Time) (uuid.UUID, string, error) {
+	payload, ok := openToken(secret, token)
+	if !ok {
+		return uuid.UUID{}, "", ErrInvalidConfirmToken
+	}
+	rest, ok := strings.CutPrefix(payload, confirmPrefix)
+	if !ok {
+		return uuid.UUID{}, "", ErrInvalidConfirmToken
+	}
+	parts := strings.SplitN(rest, ":", 3)
+	if len(parts) != 3 {
+		return uuid.UUID{}, "", ErrInvalidConfirmToken
+	}
+	exp, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || now.Unix() > exp {
+		return uuid.UUID{}, "", ErrInvalidConfirmToken
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return uuid.UUID{}, "", ErrInvalidConfirmToken
+	}
+	return id, parts[2], nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/penny-vault/pv-api/alert"
+)
+
+func TestConfirmTokenRoundtrip(t *testing.T) {
+	secret := "test-secret-32-bytes-long-enough!"
+	alertID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+
+	tok, err := alert.GenerateConfirmToken(secret, alertID, "user@example.com", now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	gotID, gotRecipient, err := alert.VerifyConfirmToken(secret, tok, now)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if gotID != alertID || gotRecipient != "user@example.com" {
+		t.Errorf("got %v %q", gotID, gotRecipient)
+	}
+	if _, _, err := alert.VerifyConfirmToken(secret, tok, now.Add(2*time.Hour)); !errors.Is(err, alert.ErrInvalidConfirmToken) {
+		t.Errorf("expected expired token to fail, got %v", err)
+	}
+}
+
+func TestConfirmAndUnsubscribeTokensDoNotMix(t *testing.T) {
+	secret := "s"
+	alertID := uuid.New()
+	unsub, _ := alert.GenerateUnsubscribeToken(secret, alertID, "user@example.com")
+	if _, _, err := alert.VerifyConfirmToken(secret, unsub, time.Now()); err == nil {
+		t.Error("an unsubscribe token must not confirm a recipient")
+	}
+	confirm, _ := alert.GenerateConfirmToken(secret, alertID, "user@example.com", time.Now().Add(time.Hour))
+	if _, _, err := alert.VerifyUnsubscribeToken(secret, confirm); err == nil {
+		t.Error("a confirmation token must not unsubscribe a recipient")
+	}
+}
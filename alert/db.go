@@ -17,6 +17,7 @@ package alert
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -24,21 +25,37 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/penny-vault/pv-api/alert/channel"
 )
 
 var ErrNotFound = errors.New("alert not found")
 
+// ErrStale is returned by a conditional Update or Delete when the alert
+// has changed, or gone, since the version the caller read.
+var ErrStale = errors.New("alert changed since it was read")
+
 type Store interface {
-	Create(ctx context.Context, portfolioID uuid.UUID, frequency string, recipients []string) (Alert, error)
+	// Create inserts an alert from a's PortfolioID, Frequency, Recipients,
+	// VerifiedRecipients, Locales, Conditions and Channels.
+	Create(ctx context.Context, a Alert) (Alert, error)
 	List(ctx context.Context, portfolioID uuid.UUID) ([]Alert, error)
 	Get(ctx context.Context, id uuid.UUID) (Alert, error)
-	Update(ctx context.Context, id uuid.UUID, frequency string, recipients []string) (Alert, error)
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Update replaces the same settings on the alert identified by a.ID.
+	// Update and Delete take the updated_at the caller last read, if any,
+	// and return ErrStale when the row no longer carries it.
+	Update(ctx context.Context, a Alert, ifUpdatedAt *time.Time) (Alert, error)
+	Delete(ctx context.Context, id uuid.UUID, ifUpdatedAt *time.Time) error
 	MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time, value float64) error
 	RemoveRecipient(ctx context.Context, id uuid.UUID, recipient string) error
+	SaveConditionState(ctx context.Context, id uuid.UUID, state ConditionState) error
+	// VerifyRecipient marks recipient as confirmed. It returns ErrNotFound
+	// when the alert is gone or no longer lists recipient.
+	VerifyRecipient(ctx context.Context, id uuid.UUID, recipient string) error
+	ListDeliveries(ctx context.Context, alertID uuid.UUID, limit int) ([]Delivery, error)
 }
 
-const alertColumns = `id, portfolio_id, frequency, recipients, last_sent_at, last_sent_value, created_at, updated_at`
+const alertColumns = `id, portfolio_id, frequency, recipients, verified_recipients, recipient_locales, last_sent_at, last_sent_value, conditions, condition_state, channels, created_at, updated_at`
 
 type PoolStore struct {
 	pool *pgxpool.Pool
@@ -49,21 +66,107 @@ func NewPoolStore(pool *pgxpool.Pool) *PoolStore {
 }
 
 func scanAlert(row pgx.Row) (Alert, error) {
-	var a Alert
-	err := row.Scan(&a.ID, &a.PortfolioID, &a.Frequency, &a.Recipients,
-		&a.LastSentAt, &a.LastSentValue, &a.CreatedAt, &a.UpdatedAt)
+	var (
+		a                                            Alert
+		localeJSON, condJSON, stateJSON, channelJSON []byte
+	)
+	err := row.Scan(&a.ID, &a.PortfolioID, &a.Frequency, &a.Recipients, &a.VerifiedRecipients, &localeJSON,
+		&a.LastSentAt, &a.LastSentValue, &condJSON, &stateJSON, &channelJSON, &a.CreatedAt, &a.UpdatedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return Alert{}, ErrNotFound
 	}
-	return a, err
+	if err != nil {
+		return Alert{}, err
+	}
+	if a.Locales, err = unmarshalLocales(localeJSON); err != nil {
+		return Alert{}, err
+	}
+	if len(condJSON) > 0 {
+		if err := json.Unmarshal(condJSON, &a.Conditions); err != nil {
+			return Alert{}, fmt.Errorf("unmarshaling conditions: %w", err)
+		}
+	}
+	if len(stateJSON) > 0 {
+		if err := json.Unmarshal(stateJSON, &a.ConditionState); err != nil {
+			return Alert{}, fmt.Errorf("unmarshaling condition state: %w", err)
+		}
+	}
+	if len(channelJSON) > 0 {
+		if err := json.Unmarshal(channelJSON, &a.Channels); err != nil {
+			return Alert{}, fmt.Errorf("unmarshaling channels: %w", err)
+		}
+	}
+	return a, nil
+}
+
+// marshalSettings encodes the alert's JSONB columns, writing empty arrays
+// rather than null when an alert has no conditions or channels.
+func marshalSettings(a Alert) (conditions, channels []byte, err error) {
+	conds := a.Conditions
+	if conds == nil {
+		conds = []Condition{}
+	}
+	if conditions, err = json.Marshal(conds); err != nil {
+		return nil, nil, fmt.Errorf("marshaling conditions: %w", err)
+	}
+	targets := a.Channels
+	if targets == nil {
+		targets = []channel.Target{}
+	}
+	if channels, err = json.Marshal(targets); err != nil {
+		return nil, nil, fmt.Errorf("marshaling channels: %w", err)
+	}
+	return conditions, channels, nil
+}
+
+// marshalLocales encodes a recipient_locales column, writing an empty object
+// rather than null.
+func marshalLocales(locales map[string]string) ([]byte, error) {
+	if locales == nil {
+		locales = map[string]string{}
+	}
+	b, err := json.Marshal(locales)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling recipient locales: %w", err)
+	}
+	return b, nil
+}
+
+func unmarshalLocales(b []byte) (map[string]string, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var out map[string]string
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, fmt.Errorf("unmarshaling recipient locales: %w", err)
+	}
+	return out, nil
 }
 
-func (s *PoolStore) Create(ctx context.Context, portfolioID uuid.UUID, frequency string, recipients []string) (Alert, error) {
+// recipientsOrEmpty keeps the NOT NULL recipients column satisfied for
+// channel-only alerts.
+func recipientsOrEmpty(r []string) []string {
+	if r == nil {
+		return []string{}
+	}
+	return r
+}
+
+func (s *PoolStore) Create(ctx context.Context, in Alert) (Alert, error) {
+	condJSON, channelJSON, err := marshalSettings(in)
+	if err != nil {
+		return Alert{}, fmt.Errorf("create alert: %w", err)
+	}
+	localeJSON, err := marshalLocales(in.Locales)
+	if err != nil {
+		return Alert{}, fmt.Errorf("create alert: %w", err)
+	}
 	row := s.pool.QueryRow(ctx, `
-		INSERT INTO portfolio_alerts (portfolio_id, frequency, recipients)
-		VALUES ($1, $2, $3)
+		INSERT INTO portfolio_alerts (portfolio_id, frequency, recipients, verified_recipients, conditions, channels, recipient_locales)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING `+alertColumns,
-		portfolioID, frequency, recipients,
+		in.PortfolioID, in.Frequency, recipientsOrEmpty(in.Recipients), recipientsOrEmpty(in.VerifiedRecipients),
+		condJSON, channelJSON, localeJSON,
 	)
 	a, err := scanAlert(row)
 	if err != nil {
@@ -102,30 +205,53 @@ func (s *PoolStore) Get(ctx context.Context, id uuid.UUID) (Alert, error) {
 	return a, nil
 }
 
-func (s *PoolStore) Update(ctx context.Context, id uuid.UUID, frequency string, recipients []string) (Alert, error) {
+// Update replaces the alert's settings. Changing the conditions resets the
+// remembered condition state, since it is keyed by condition position.
+func (s *PoolStore) Update(ctx context.Context, in Alert, ifUpdatedAt *time.Time) (Alert, error) {
+	condJSON, channelJSON, err := marshalSettings(in)
+	if err != nil {
+		return Alert{}, fmt.Errorf("update alert: %w", err)
+	}
+	localeJSON, err := marshalLocales(in.Locales)
+	if err != nil {
+		return Alert{}, fmt.Errorf("update alert: %w", err)
+	}
 	row := s.pool.QueryRow(ctx, `
 		UPDATE portfolio_alerts
-		   SET frequency=$2, recipients=$3, updated_at=now()
-		 WHERE id=$1
+		   SET frequency=$2, recipients=$3, verified_recipients=$4, conditions=$5, channels=$6,
+		       recipient_locales=$7,
+		       condition_state = CASE WHEN conditions = $5::jsonb THEN condition_state ELSE '{}'::jsonb END,
+		       updated_at=now()
+		 WHERE id=$1 AND ($8::timestamptz IS NULL OR updated_at=$8)
 		RETURNING `+alertColumns,
-		id, frequency, recipients,
+		in.ID, in.Frequency, recipientsOrEmpty(in.Recipients), recipientsOrEmpty(in.VerifiedRecipients),
+		condJSON, channelJSON, localeJSON, ifUpdatedAt,
 	)
 	a, err := scanAlert(row)
+	if errors.Is(err, ErrNotFound) && ifUpdatedAt != nil {
+		return Alert{}, ErrStale
+	}
 	if err != nil {
 		return Alert{}, fmt.Errorf("update alert: %w", err)
 	}
 	return a, nil
 }
 
-func (s *PoolStore) Delete(ctx context.Context, id uuid.UUID) error {
-	tag, err := s.pool.Exec(ctx, `DELETE FROM portfolio_alerts WHERE id=$1`, id)
+func (s *PoolStore) Delete(ctx context.Context, id uuid.UUID, ifUpdatedAt *time.Time) error {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM portfolio_alerts WHERE id=$1 AND ($2::timestamptz IS NULL OR updated_at=$2)`,
+		id, ifUpdatedAt)
 	if err != nil {
 		return fmt.Errorf("delete alert: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
+	switch {
+	case tag.RowsAffected() > 0:
+		return nil
+	case ifUpdatedAt != nil:
+		return ErrStale
+	default:
 		return ErrNotFound
 	}
-	return nil
 }
 
 func (s *PoolStore) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time, value float64) error {
@@ -138,12 +264,45 @@ func (s *PoolStore) MarkSent(ctx context.Context, id uuid.UUID, sentAt time.Time
 	return err
 }
 
-// RemoveRecipient removes one recipient from the alert. If recipients becomes
-// empty, the alert is deleted.
+// SaveConditionState records what an on_condition alert's conditions saw on
+// the latest run.
+func (s *PoolStore) SaveConditionState(ctx context.Context, id uuid.UUID, state ConditionState) error {
+	b, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshaling condition state: %w", err)
+	}
+	_, err = s.pool.Exec(ctx,
+		`UPDATE portfolio_alerts SET condition_state=$2 WHERE id=$1`, id, b)
+	return err
+}
+
+func (s *PoolStore) VerifyRecipient(ctx context.Context, id uuid.UUID, recipient string) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE portfolio_alerts
+		   SET verified_recipients = CASE WHEN $2 = ANY(verified_recipients) THEN verified_recipients
+		                                  ELSE array_append(verified_recipients, $2) END,
+		       updated_at = now()
+		 WHERE id = $1 AND $2 = ANY(recipients)`,
+		id, recipient,
+	)
+	if err != nil {
+		return fmt.Errorf("verify recipient: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RemoveRecipient removes one recipient from the alert. If that leaves the
+// alert with no recipients and no channels, the alert is deleted.
 func (s *PoolStore) RemoveRecipient(ctx context.Context, id uuid.UUID, recipient string) error {
 	tag, err := s.pool.Exec(ctx, `
 		UPDATE portfolio_alerts
-		   SET recipients = array_remove(recipients, $2), updated_at = now()
+		   SET recipients = array_remove(recipients, $2),
+		       verified_recipients = array_remove(verified_recipients, $2),
+		       recipient_locales = recipient_locales - $2::text,
+		       updated_at = now()
 		 WHERE id = $1`,
 		id, recipient,
 	)
@@ -155,8 +314,368 @@ func (s *PoolStore) RemoveRecipient(ctx context.Context, id uuid.UUID, recipient
 	}
 	_, err = s.pool.Exec(ctx, `
 		DELETE FROM portfolio_alerts
-		 WHERE id = $1 AND array_length(recipients, 1) IS NULL`,
+		 WHERE id = $1 AND array_length(recipients, 1) IS NULL AND channels = '[]'::jsonb`,
 		id,
 	)
 	return err
 }
+
+const deliveryColumns = `id, alert_id, digest_id, channel, destination, target, event, subject, html_body, text_body, payload,
+	status, attempts, last_error, provider_message_id, next_attempt_at, created_at, sent_at`
+
+func scanDelivery(row pgx.Row) (Delivery, error) {
+	var (
+		d                       Delivery
+		alertID, digestID       *uuid.UUID
+		targetJSON, payloadJSON []byte
+	)
+	err := row.Scan(&d.ID, &alertID, &digestID, &d.Channel, &d.Destination, &targetJSON, &d.Event, &d.Subject,
+		&d.HTMLBody, &d.TextBody, &payloadJSON, &d.Status, &d.Attempts, &d.LastError,
+		&d.ProviderMessageID, &d.NextAttemptAt, &d.CreatedAt, &d.SentAt)
+	if err != nil {
+		return Delivery{}, err
+	}
+	if alertID != nil {
+		d.AlertID = *alertID
+	}
+	if digestID != nil {
+		d.DigestID = *digestID
+	}
+	if len(targetJSON) > 0 {
+		d.Target = &channel.Target{}
+		if err := json.Unmarshal(targetJSON, d.Target); err != nil {
+			return Delivery{}, fmt.Errorf("unmarshaling target: %w", err)
+		}
+	}
+	if len(payloadJSON) > 0 {
+		if err := json.Unmarshal(payloadJSON, &d.Payload); err != nil {
+			return Delivery{}, fmt.Errorf("unmarshaling payload: %w", err)
+		}
+	}
+	return d, nil
+}
+
+func collectDeliveries(rows pgx.Rows) ([]Delivery, error) {
+	defer rows.Close()
+	var out []Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// EnqueueDeliveries queues deliveries for the outbox and records the alert as
+// sent in one transaction, so a crash can neither lose the messages nor send
+// them twice.
+func (s *PoolStore) EnqueueDeliveries(ctx context.Context, alertID uuid.UUID, sentAt time.Time, value float64, deliveries []Delivery) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("enqueue deliveries: begin: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) && err == nil {
+			err = rbErr
+		}
+	}()
+
+	if err = insertDeliveries(ctx, tx, &alertID, nil, deliveries); err != nil {
+		return fmt.Errorf("enqueue deliveries: %w", err)
+	}
+	if _, err = tx.Exec(ctx, `
+		UPDATE portfolio_alerts
+		   SET last_sent_at=$2, last_sent_value=$3, updated_at=now()
+		 WHERE id=$1`,
+		alertID, sentAt, value,
+	); err != nil {
+		return fmt.Errorf("enqueue deliveries: mark sent: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// QueueDeliveries queues deliveries that are not alert sends, such as
+// recipient confirmations, leaving the alert's last-sent state alone.
+func (s *PoolStore) QueueDeliveries(ctx context.Context, alertID uuid.UUID, deliveries []Delivery) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("queue deliveries: begin: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) && err == nil {
+			err = rbErr
+		}
+	}()
+	if err = insertDeliveries(ctx, tx, &alertID, nil, deliveries); err != nil {
+		return fmt.Errorf("queue deliveries: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// insertDeliveries queues deliveries owned by exactly one of alertID and
+// digestID; the other is nil.
+func insertDeliveries(ctx context.Context, tx pgx.Tx, alertID, digestID *uuid.UUID, deliveries []Delivery) error {
+	for _, d := range deliveries {
+		var targetJSON []byte
+		if d.Target != nil {
+			b, err := json.Marshal(d.Target)
+			if err != nil {
+				return fmt.Errorf("marshaling target: %w", err)
+			}
+			targetJSON = b
+		}
+		payloadJSON, err := json.Marshal(d.Payload)
+		if err != nil {
+			return fmt.Errorf("marshaling payload: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO alert_deliveries
+			       (alert_id, digest_id, channel, destination, target, event, subject, html_body, text_body, payload)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			alertID, digestID, d.Channel, d.Destination, targetJSON, d.Event, d.Subject, d.HTMLBody, d.TextBody, payloadJSON,
+		); err != nil {
+			return fmt.Errorf("insert: %w", err)
+		}
+	}
+	return nil
+}
+
+// ClaimDeliveries picks up pending deliveries and sending ones whose lease
+// has expired. SKIP LOCKED lets several API instances drain the outbox
+// without handing out the same row twice.
+func (s *PoolStore) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE alert_deliveries
+		   SET status='sending', attempts=attempts+1, next_attempt_at=now() + $2 * interval '1 second'
+		 WHERE id IN (
+		       SELECT id FROM alert_deliveries
+		        WHERE status IN ('pending','sending') AND next_attempt_at <= now()
+		        ORDER BY next_attempt_at
+		        LIMIT $1
+		          FOR UPDATE SKIP LOCKED)
+		RETURNING `+deliveryColumns,
+		limit, int(lease.Seconds()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim deliveries: %w", err)
+	}
+	return collectDeliveries(rows)
+}
+
+// MarkDelivered records a successful send. providerMessageID may be empty for
+// channels that do not return one.
+func (s *PoolStore) MarkDelivered(ctx context.Context, id uuid.UUID, providerMessageID string) error {
+	var pid *string
+	if providerMessageID != "" {
+		pid = &providerMessageID
+	}
+	_, err := s.pool.Exec(ctx, `
+		UPDATE alert_deliveries
+		   SET status='sent', sent_at=now(), provider_message_id=$2, last_error=NULL
+		 WHERE id=$1`,
+		id, pid,
+	)
+	return err
+}
+
+func (s *PoolStore) MarkDeliveryFailed(ctx context.Context, id uuid.UUID, errMsg string, next *time.Time) error {
+	if next == nil {
+		_, err := s.pool.Exec(ctx,
+			`UPDATE alert_deliveries SET status='dead', last_error=$2 WHERE id=$1`, id, errMsg)
+		return err
+	}
+	_, err := s.pool.Exec(ctx, `
+		UPDATE alert_deliveries
+		   SET status='pending', last_error=$2, next_attempt_at=$3
+		 WHERE id=$1`,
+		id, errMsg, *next,
+	)
+	return err
+}
+
+// PruneDeliveries deletes sent and dead deliveries created before before.
+func (s *PoolStore) PruneDeliveries(ctx context.Context, before time.Time) error {
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM alert_deliveries WHERE status IN ('sent','dead') AND created_at < $1`, before)
+	return err
+}
+
+// ListDeliveries returns the alert's most recent deliveries, newest first.
+func (s *PoolStore) ListDeliveries(ctx context.Context, alertID uuid.UUID, limit int) ([]Delivery, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+deliveryColumns+` FROM alert_deliveries WHERE alert_id=$1 ORDER BY created_at DESC, id LIMIT $2`,
+		alertID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list deliveries: %w", err)
+	}
+	return collectDeliveries(rows)
+}
+
+const digestColumns = `id, owner_sub, frequency, recipients, verified_recipients, recipient_locales, last_sent_at, created_at, updated_at`
+
+func scanDigest(row pgx.Row) (Digest, error) {
+	var (
+		d          Digest
+		localeJSON []byte
+	)
+	err := row.Scan(&d.ID, &d.OwnerSub, &d.Frequency, &d.Recipients, &d.VerifiedRecipients, &localeJSON,
+		&d.LastSentAt, &d.CreatedAt, &d.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Digest{}, ErrDigestNotFound
+	}
+	if err != nil {
+		return Digest{}, err
+	}
+	if d.Locales, err = unmarshalLocales(localeJSON); err != nil {
+		return Digest{}, err
+	}
+	return d, nil
+}
+
+func (s *PoolStore) GetDigest(ctx context.Context, ownerSub string) (Digest, error) {
+	d, err := scanDigest(s.pool.QueryRow(ctx,
+		`SELECT `+digestColumns+` FROM alert_digests WHERE owner_sub=$1`, ownerSub))
+	if err != nil {
+		return Digest{}, fmt.Errorf("get digest: %w", err)
+	}
+	return d, nil
+}
+
+func (s *PoolStore) SaveDigest(ctx context.Context, in Digest) (Digest, error) {
+	localeJSON, err := marshalLocales(in.Locales)
+	if err != nil {
+		return Digest{}, fmt.Errorf("save digest: %w", err)
+	}
+	d, err := scanDigest(s.pool.QueryRow(ctx, `
+		INSERT INTO alert_digests (owner_sub, frequency, recipients, verified_recipients, recipient_locales)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (owner_sub) DO UPDATE
+		   SET frequency=EXCLUDED.frequency, recipients=EXCLUDED.recipients,
+		       verified_recipients=EXCLUDED.verified_recipients,
+		       recipient_locales=EXCLUDED.recipient_locales, updated_at=now()
+		RETURNING `+digestColumns,
+		in.OwnerSub, in.Frequency, recipientsOrEmpty(in.Recipients), recipientsOrEmpty(in.VerifiedRecipients),
+		localeJSON,
+	))
+	if err != nil {
+		return Digest{}, fmt.Errorf("save digest: %w", err)
+	}
+	return d, nil
+}
+
+func (s *PoolStore) DeleteDigest(ctx context.Context, ownerSub string) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM alert_digests WHERE owner_sub=$1`, ownerSub)
+	if err != nil {
+		return fmt.Errorf("delete digest: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDigestNotFound
+	}
+	return nil
+}
+
+// ListDigests returns every digest subscription; the checker decides which
+// are due.
+func (s *PoolStore) ListDigests(ctx context.Context) ([]Digest, error) {
+	rows, err := s.pool.Query(ctx, `SELECT `+digestColumns+` FROM alert_digests ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list digests: %w", err)
+	}
+	defer rows.Close()
+	var out []Digest
+	for rows.Next() {
+		d, scanErr := scanDigest(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("list digests: %w", scanErr)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (s *PoolStore) VerifyDigestRecipient(ctx context.Context, id uuid.UUID, recipient string) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE alert_digests
+		   SET verified_recipients = CASE WHEN $2 = ANY(verified_recipients) THEN verified_recipients
+		                                  ELSE array_append(verified_recipients, $2) END,
+		       updated_at = now()
+		 WHERE id = $1 AND $2 = ANY(recipients)`,
+		id, recipient,
+	)
+	if err != nil {
+		return fmt.Errorf("verify digest recipient: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDigestNotFound
+	}
+	return nil
+}
+
+// RemoveDigestRecipient removes one recipient from the digest. If that leaves
+// the digest with no recipients, the digest is deleted.
+func (s *PoolStore) RemoveDigestRecipient(ctx context.Context, id uuid.UUID, recipient string) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE alert_digests
+		   SET recipients = array_remove(recipients, $2),
+		       verified_recipients = array_remove(verified_recipients, $2),
+		       recipient_locales = recipient_locales - $2::text,
+		       updated_at = now()
+		 WHERE id = $1`,
+		id, recipient,
+	)
+	if err != nil {
+		return fmt.Errorf("remove digest recipient: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrDigestNotFound
+	}
+	_, err = s.pool.Exec(ctx,
+		`DELETE FROM alert_digests WHERE id = $1 AND array_length(recipients, 1) IS NULL`, id)
+	return err
+}
+
+// EnqueueDigestDeliveries queues a digest's emails and records it as sent in
+// one transaction, like EnqueueDeliveries does for alerts. With no
+// deliveries it only records the period as sent.
+func (s *PoolStore) EnqueueDigestDeliveries(ctx context.Context, digestID uuid.UUID, sentAt time.Time, deliveries []Delivery) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("enqueue digest deliveries: begin: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) && err == nil {
+			err = rbErr
+		}
+	}()
+
+	if err = insertDeliveries(ctx, tx, nil, &digestID, deliveries); err != nil {
+		return fmt.Errorf("enqueue digest deliveries: %w", err)
+	}
+	if _, err = tx.Exec(ctx,
+		`UPDATE alert_digests SET last_sent_at=$2, updated_at=now() WHERE id=$1`, digestID, sentAt,
+	); err != nil {
+		return fmt.Errorf("enqueue digest deliveries: mark sent: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// QueueDigestDeliveries queues digest messages that are not digest sends,
+// such as recipient confirmations.
+func (s *PoolStore) QueueDigestDeliveries(ctx context.Context, digestID uuid.UUID, deliveries []Delivery) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("queue digest deliveries: begin: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) && err == nil {
+			err = rbErr
+		}
+	}()
+	if err = insertDeliveries(ctx, tx, nil, &digestID, deliveries); err != nil {
+		return fmt.Errorf("queue digest deliveries: %w", err)
+	}
+	return tx.Commit(ctx)
+}
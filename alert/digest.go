@@ -0,0 +1,356 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/alert/email"
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/snapshot"
+)
+
+var ErrDigestNotFound = errors.New("digest not found")
+
+// digestTopHoldings is how many of each portfolio's largest positions a
+// digest lists.
+const digestTopHoldings = 5
+
+// Digest is a user's subscription to a single email that summarizes every
+// portfolio they own. Frequency is daily, weekly or monthly.
+type Digest struct {
+	ID         uuid.UUID
+	OwnerSub   string
+	Frequency  string
+	Recipients []string
+	// VerifiedRecipients are the Recipients who followed their confirmation
+	// link. Only they are emailed.
+	VerifiedRecipients []string
+	// Locales maps a recipient to the locale tag their email is rendered in.
+	Locales    map[string]string
+	LastSentAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Verified reports whether recipient has confirmed they want d's emails.
+func (d Digest) Verified(recipient string) bool {
+	return slices.Contains(d.VerifiedRecipients, recipient)
+}
+
+// PendingRecipients returns the recipients still waiting to confirm.
+func (d Digest) PendingRecipients() []string {
+	return pendingRecipients(d.Recipients, d.VerifiedRecipients)
+}
+
+// DigestStore persists digest subscriptions. PoolStore implements it.
+type DigestStore interface {
+	// GetDigest returns ErrDigestNotFound when ownerSub has no digest.
+	GetDigest(ctx context.Context, ownerSub string) (Digest, error)
+	// SaveDigest creates d.OwnerSub's digest, or replaces its Frequency,
+	// Recipients and VerifiedRecipients.
+	SaveDigest(ctx context.Context, d Digest) (Digest, error)
+	DeleteDigest(ctx context.Context, ownerSub string) error
+	// VerifyDigestRecipient marks recipient as confirmed. It returns
+	// ErrDigestNotFound when the digest is gone or no longer lists recipient.
+	VerifyDigestRecipient(ctx context.Context, id uuid.UUID, recipient string) error
+	RemoveDigestRecipient(ctx context.Context, id uuid.UUID, recipient string) error
+}
+
+var _ DigestStore = (*PoolStore)(nil)
+
+func validDigestFrequency(f string) bool {
+	switch f {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+		return true
+	}
+	return false
+}
+
+// digestDue reports whether d should go out at now: its cadence fires on the
+// current ET day, the ET clock has reached sendHour, and it has not already
+// gone out today.
+func digestDue(d Digest, now time.Time, sendHour int) bool {
+	nowET := now.In(nyseLoc)
+	if nowET.Hour() < sendHour {
+		return false
+	}
+	return cadenceFires(d.Frequency, "", nowET) && !sentToday(d.LastSentAt, nowET)
+}
+
+func digestTitle(frequency string, loc *email.Locale) string {
+	switch frequency {
+	case FrequencyWeekly:
+		return loc.T("digest.weekly")
+	case FrequencyMonthly:
+		return loc.T("digest.monthly")
+	}
+	return loc.T("digest.daily")
+}
+
+func portfolioCount(n int, loc *email.Locale) string {
+	if n == 1 {
+		return loc.T("digest.count.one")
+	}
+	return loc.T("digest.count.other", n)
+}
+
+// RunDigests sends every due digest each interval until ctx is cancelled.
+// Digests go out once the ET clock reaches sendHour, after the evening runs
+// have finished.
+func (c *Checker) RunDigests(ctx context.Context, interval time.Duration, sendHour int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.SendDueDigests(ctx, time.Now(), sendHour); err != nil {
+			log.Warn().Err(err).Msg("alert: send digests")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDueDigests sends each digest that is due at now.
+func (c *Checker) SendDueDigests(ctx context.Context, now time.Time, sendHour int) error {
+	if c.transport == nil {
+		return nil
+	}
+	digests, err := c.store.ListDigests(ctx)
+	if err != nil {
+		return fmt.Errorf("list digests: %w", err)
+	}
+	for _, d := range digests {
+		if len(d.VerifiedRecipients) == 0 || !digestDue(d, now, sendHour) {
+			continue
+		}
+		if err := c.sendDigest(ctx, d, now); err != nil {
+			log.Warn().Err(err).Stringer("digest_id", d.ID).Msg("alert: digest send failed")
+		}
+	}
+	return nil
+}
+
+func (c *Checker) sendDigest(ctx context.Context, d Digest, now time.Time) error {
+	// The digest is built once per locale among the recipients.
+	type built struct {
+		payload email.DigestPayload
+		n       int
+	}
+	digests := map[*email.Locale]built{}
+
+	var deliveries []Delivery
+	for _, recipient := range d.VerifiedRecipients {
+		loc := c.recipientLocale(d.Locales, recipient)
+		b, ok := digests[loc]
+		if !ok {
+			payload, n, err := c.buildDigest(ctx, d, now, loc)
+			if err != nil {
+				return err
+			}
+			b = built{payload, n}
+			digests[loc] = b
+		}
+		if b.n == 0 {
+			// Nothing to report still uses up the period; without marking
+			// it sent the digest is rebuilt on every tick until tomorrow.
+			if err := c.store.EnqueueDigestDeliveries(ctx, d.ID, now, nil); err != nil {
+				return fmt.Errorf("mark sent: %w", err)
+			}
+			return nil
+		}
+		p := b.payload
+		if c.unsubscribeSecret != "" && c.appBaseURL != "" {
+			tok, err := GenerateUnsubscribeToken(c.unsubscribeSecret, d.ID, recipient)
+			if err == nil {
+				p.UnsubscribeURL = c.appBaseURL + "/api/alerts/unsubscribe?token=" + tok
+			}
+		}
+		htmlBody, textBody, err := c.renderer.RenderDigest(p, loc)
+		if err != nil {
+			log.Warn().Err(err).Str("recipient", recipient).Msg("alert: render digest failed")
+			continue
+		}
+		deliveries = append(deliveries, Delivery{
+			Channel:     ChannelEmail,
+			Destination: recipient,
+			Event:       EventDigest,
+			Subject:     fmt.Sprintf("%s: %s", p.Title, portfolioCount(b.n, loc)),
+			HTMLBody:    htmlBody,
+			TextBody:    textBody,
+		})
+	}
+	if err := c.store.EnqueueDigestDeliveries(ctx, d.ID, now, deliveries); err != nil {
+		return fmt.Errorf("enqueue: %w", err)
+	}
+	if _, err := c.outbox.Tick(ctx); err != nil {
+		log.Warn().Err(err).Stringer("digest_id", d.ID).Msg("alert: outbox tick")
+	}
+	return nil
+}
+
+// buildDigest summarizes every portfolio d's owner has, formatted in loc. It
+// also returns the number of portfolios, so a user with none is skipped.
+func (c *Checker) buildDigest(ctx context.Context, d Digest, now time.Time, loc *email.Locale) (email.DigestPayload, int, error) {
+	rows, err := c.pool.Query(ctx,
+		`SELECT id FROM portfolios WHERE owner_sub=$1 ORDER BY name`, d.OwnerSub)
+	if err != nil {
+		return email.DigestPayload{}, 0, fmt.Errorf("list portfolios: %w", err)
+	}
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return email.DigestPayload{}, 0, fmt.Errorf("list portfolios: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return email.DigestPayload{}, 0, fmt.Errorf("list portfolios: %w", err)
+	}
+
+	p := email.DigestPayload{
+		Title:    digestTitle(d.Frequency, loc),
+		Subtitle: portfolioCount(len(ids), loc) + " · " + loc.T("as_of", loc.LongDate(now.In(nyseLoc))),
+	}
+	if c.appBaseURL != "" {
+		p.PortfoliosURL = c.appBaseURL + "/portfolios"
+	}
+	total := 0.0
+	for _, id := range ids {
+		port, err := c.loadPortfolio(ctx, id)
+		if err != nil {
+			return email.DigestPayload{}, 0, fmt.Errorf("load portfolio: %w", err)
+		}
+		dp := email.DigestPortfolio{Name: port.Name, Value: "—"}
+		if port.CurrentValue != nil {
+			total += *port.CurrentValue
+			dp.Value = loc.Money(*port.CurrentValue)
+		}
+		if port.Status == "failed" {
+			msg := loc.T("digest.run_failed")
+			if port.LastError != nil {
+				msg = *port.LastError
+			}
+			p.Failures = append(p.Failures, email.DigestFailure{Name: port.Name, ErrorMessage: msg})
+		}
+		if port.SnapshotPath != nil {
+			c.fillDigestPortfolio(ctx, &p, &dp, port, loc)
+		}
+		p.Portfolios = append(p.Portfolios, dp)
+	}
+	p.TotalValue = loc.Money(total)
+	return p, len(ids), nil
+}
+
+// fillDigestPortfolio adds port's returns row to the digest and fills its
+// card with the predicted trades and largest holdings from the snapshot.
+func (c *Checker) fillDigestPortfolio(ctx context.Context, p *email.DigestPayload, dp *email.DigestPortfolio, port portfolioData, loc *email.Locale) {
+	r, err := snapshot.Open(*port.SnapshotPath)
+	if err != nil {
+		log.Warn().Err(err).Str("portfolio", port.Slug).Msg("alert: open snapshot for digest")
+		return
+	}
+	defer func() {
+		if err := r.Close(); err != nil {
+			log.Warn().Err(err).Msg("alert: snapshot close")
+		}
+	}()
+
+	if row, _, err := portfolioReturnsRow(ctx, r, port.Name, loc); err == nil {
+		p.Returns = append(p.Returns, row)
+	}
+
+	pred, err := r.Prediction(ctx)
+	switch {
+	case err == nil && len(pred.Transactions) > 0:
+		dp.TradesDate = loc.LongDate(pred.Date.Time)
+		for _, tx := range pred.Transactions {
+			ticker := "?"
+			if tx.Ticker != nil {
+				ticker = *tx.Ticker
+			}
+			dp.Trades = append(dp.Trades, tradeRow(tx.Type, ticker, deref(tx.Quantity), deref(tx.Amount), loc))
+		}
+	case err != nil && !errors.Is(err, snapshot.ErrNotFound):
+		log.Warn().Err(err).Str("portfolio", port.Slug).Msg("alert: read prediction for digest")
+	}
+
+	cur, err := r.CurrentHoldings(ctx)
+	if err != nil || cur == nil {
+		return
+	}
+	items := slices.Clone(cur.Items)
+	slices.SortFunc(items, func(a, b openapi.Holding) int { return cmp.Compare(b.MarketValue, a.MarketValue) })
+	dp.Holdings = holdingRows(items, loc)
+	if len(dp.Holdings) > digestTopHoldings {
+		dp.Holdings = dp.Holdings[:digestTopHoldings]
+	}
+}
+
+// SendDigestConfirmations queues a confirmation email to each of recipients
+// on d and attempts delivery right away.
+func (c *Checker) SendDigestConfirmations(ctx context.Context, d Digest, recipients []string) error {
+	if !c.ConfirmationsEnabled() || len(recipients) == 0 {
+		return nil
+	}
+	expires := time.Now().Add(confirmTokenTTL)
+	deliveries := make([]Delivery, 0, len(recipients))
+	for _, recipient := range recipients {
+		tok, err := GenerateConfirmToken(c.unsubscribeSecret, d.ID, recipient, expires)
+		if err != nil {
+			return fmt.Errorf("send digest confirmations: %w", err)
+		}
+		loc := c.recipientLocale(d.Locales, recipient)
+		title := digestTitle(d.Frequency, loc)
+		htmlBody, textBody, err := c.renderer.RenderConfirmation(email.Confirmation{
+			PortfolioName: title,
+			Recipient:     recipient,
+			ConfirmURL:    c.appBaseURL + "/api/alerts/confirm?token=" + tok,
+			ExpiresOn:     loc.LongDate(expires.UTC()),
+		}, loc)
+		if err != nil {
+			return fmt.Errorf("send digest confirmations: %w", err)
+		}
+		deliveries = append(deliveries, Delivery{
+			Channel:     ChannelEmail,
+			Destination: recipient,
+			Event:       EventRecipientConfirm,
+			Subject:     loc.T("subject.confirm", title),
+			HTMLBody:    htmlBody,
+			TextBody:    textBody,
+		})
+	}
+	if err := c.store.QueueDigestDeliveries(ctx, d.ID, deliveries); err != nil {
+		return fmt.Errorf("send digest confirmations: %w", err)
+	}
+	if _, err := c.outbox.Tick(ctx); err != nil {
+		log.Warn().Err(err).Stringer("digest_id", d.ID).Msg("alert: outbox tick")
+	}
+	return nil
+}
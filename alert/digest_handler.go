@@ -0,0 +1,176 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// DigestConfirmer sends the double opt-in emails for new digest recipients.
+type DigestConfirmer interface {
+	ConfirmationsEnabled() bool
+	SendDigestConfirmations(ctx context.Context, d Digest, recipients []string) error
+}
+
+// DigestHandler serves the caller's digest subscription under /me/digest.
+type DigestHandler struct {
+	digests   DigestStore
+	confirmer DigestConfirmer
+}
+
+// NewDigestHandler builds a DigestHandler. With a confirmer (the Checker),
+// new recipients must confirm before they are emailed; a nil confirmer trusts
+// recipients as entered.
+func NewDigestHandler(digests DigestStore, confirmer DigestConfirmer) *DigestHandler {
+	return &DigestHandler{digests: digests, confirmer: confirmer}
+}
+
+// Get implements GET /me/digest.
+func (h *DigestHandler) Get(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return err
+	}
+	d, err := h.digests.GetDigest(c.Context(), ownerSub)
+	if errors.Is(err, ErrDigestNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "no digest subscription")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.JSON(toDigestView(d))
+}
+
+// Put implements PUT /me/digest: it creates the caller's digest or replaces
+// its settings. Recipients already confirmed stay confirmed.
+func (h *DigestHandler) Put(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Frequency        string            `json:"frequency"`
+		Recipients       []string          `json:"recipients"`
+		RecipientLocales map[string]string `json:"recipientLocales"`
+	}
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid body", err.Error())
+	}
+	if !validDigestFrequency(body.Frequency) {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid frequency",
+			"frequency must be one of: daily, weekly, monthly")
+	}
+	if len(body.Recipients) == 0 {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "recipients required",
+			"at least one recipient is required")
+	}
+
+	existing, err := h.digests.GetDigest(c.Context(), ownerSub)
+	if err != nil && !errors.Is(err, ErrDigestNotFound) {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	locales, detail := recipientLocales(body.Recipients, body.RecipientLocales, existing.Locales)
+	if detail != "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid recipientLocales", detail)
+	}
+	var verified []string
+	for _, r := range body.Recipients {
+		if existing.Verified(r) {
+			verified = append(verified, r)
+		} else if !slices.Contains(existing.Recipients, r) && !h.confirmationsEnabled() {
+			return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable", confirmationsUnavailableDetail)
+		}
+	}
+	saved, err := h.digests.SaveDigest(c.Context(), Digest{
+		OwnerSub:           ownerSub,
+		Frequency:          body.Frequency,
+		Recipients:         body.Recipients,
+		VerifiedRecipients: verified,
+		Locales:            locales,
+	})
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	var added []string
+	for _, r := range saved.PendingRecipients() {
+		if !slices.Contains(existing.Recipients, r) {
+			added = append(added, r)
+		}
+	}
+	if h.confirmationsEnabled() && len(added) > 0 {
+		if err := h.confirmer.SendDigestConfirmations(c.Context(), saved, added); err != nil {
+			log.Warn().Err(err).Stringer("digest_id", saved.ID).Msg("alert: send digest confirmations")
+		}
+	}
+	return c.JSON(toDigestView(saved))
+}
+
+// Delete implements DELETE /me/digest.
+func (h *DigestHandler) Delete(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return err
+	}
+	err = h.digests.DeleteDigest(c.Context(), ownerSub)
+	if errors.Is(err, ErrDigestNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "no digest subscription")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *DigestHandler) confirmationsEnabled() bool {
+	return h.confirmer != nil && h.confirmer.ConfirmationsEnabled()
+}
+
+type digestView struct {
+	ID              uuid.UUID         `json:"id"`
+	Frequency       string            `json:"frequency"`
+	Recipients      []string          `json:"recipients"`
+	RecipientStatus []recipientStatus `json:"recipientStatus"`
+	LastSentAt      *string           `json:"lastSentAt"`
+}
+
+func toDigestView(d Digest) digestView {
+	v := digestView{
+		ID:         d.ID,
+		Frequency:  d.Frequency,
+		Recipients: d.Recipients,
+	}
+	v.RecipientStatus = make([]recipientStatus, 0, len(d.Recipients))
+	for _, r := range d.Recipients {
+		status := RecipientPending
+		if d.Verified(r) {
+			status = RecipientVerified
+		}
+		v.RecipientStatus = append(v.RecipientStatus, recipientStatus{Email: r, Status: status, Locale: d.Locales[r]})
+	}
+	if d.LastSentAt != nil {
+		s := d.LastSentAt.UTC().Format(time.RFC3339)
+		v.LastSentAt = &s
+	}
+	return v
+}
@@ -0,0 +1,190 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/alert"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// memDigestStore keeps at most one digest in memory.
+type memDigestStore struct {
+	digest *alert.Digest
+}
+
+func (s memDigestStore) GetDigest(_ context.Context, _ string) (alert.Digest, error) {
+	if s.digest.ID == uuid.Nil {
+		return alert.Digest{}, alert.ErrDigestNotFound
+	}
+	return *s.digest, nil
+}
+
+func (s memDigestStore) SaveDigest(_ context.Context, d alert.Digest) (alert.Digest, error) {
+	if s.digest.ID == uuid.Nil {
+		d.ID = uuid.New()
+	} else {
+		d.ID = s.digest.ID
+	}
+	*s.digest = d
+	return d, nil
+}
+
+func (s memDigestStore) DeleteDigest(_ context.Context, _ string) error {
+	if s.digest.ID == uuid.Nil {
+		return alert.ErrDigestNotFound
+	}
+	*s.digest = alert.Digest{}
+	return nil
+}
+
+func (s memDigestStore) VerifyDigestRecipient(_ context.Context, _ uuid.UUID, _ string) error {
+	panic("unexpected call")
+}
+
+func (s memDigestStore) RemoveDigestRecipient(_ context.Context, _ uuid.UUID, _ string) error {
+	panic("unexpected call")
+}
+
+// stubDigestConfirmer records who was sent a digest confirmation link.
+type stubDigestConfirmer struct{ sent *[]string }
+
+func (s stubDigestConfirmer) ConfirmationsEnabled() bool { return true }
+
+func (s stubDigestConfirmer) SendDigestConfirmations(_ context.Context, _ alert.Digest, recipients []string) error {
+	*s.sent = append(*s.sent, recipients...)
+	return nil
+}
+
+func newDigestApp(h *alert.DigestHandler) *fiber.App {
+	app := fiber.New(fiber.Config{})
+	app.Use(func(c fiber.Ctx) error {
+		c.Locals(types.AuthSubjectKey{}, "user-1")
+		return c.Next()
+	})
+	app.Get("/me/digest", h.Get)
+	app.Put("/me/digest", h.Put)
+	app.Delete("/me/digest", h.Delete)
+	return app
+}
+
+func putDigest(t *testing.T, app *fiber.App, body string) (int, []byte) {
+	t.Helper()
+	req := httptest.NewRequest("PUT", "/me/digest", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, raw
+}
+
+func TestDigestLifecycle(t *testing.T) {
+	var stored alert.Digest
+	var sent []string
+	app := newDigestApp(alert.NewDigestHandler(memDigestStore{digest: &stored}, stubDigestConfirmer{sent: &sent}))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/me/digest", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 before subscribing, got %d", resp.StatusCode)
+	}
+
+	status, raw := putDigest(t, app, `{"frequency":"daily","recipients":["a@b.com"]}`)
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", status, raw)
+	}
+	if stored.OwnerSub != "user-1" || len(stored.VerifiedRecipients) != 0 {
+		t.Errorf("expected a pending digest for user-1, got %+v", stored)
+	}
+	if len(sent) != 1 || sent[0] != "a@b.com" {
+		t.Errorf("expected a confirmation to a@b.com, got %v", sent)
+	}
+	if !bytes.Contains(raw, []byte(`{"email":"a@b.com","status":"pending"}`)) {
+		t.Errorf("response should report a@b.com as pending: %s", raw)
+	}
+
+	// Once confirmed, a@b.com stays verified across updates and only the
+	// newly added recipient is asked to confirm.
+	stored.VerifiedRecipients = []string{"a@b.com"}
+	sent = nil
+	status, raw = putDigest(t, app, `{"frequency":"weekly","recipients":["a@b.com","c@d.com"]}`)
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", status, raw)
+	}
+	if stored.Frequency != alert.FrequencyWeekly || !stored.Verified("a@b.com") || stored.Verified("c@d.com") {
+		t.Errorf("unexpected digest after update: %+v", stored)
+	}
+	if len(sent) != 1 || sent[0] != "c@d.com" {
+		t.Errorf("expected a confirmation to c@d.com only, got %v", sent)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("DELETE", "/me/digest", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Errorf("expected 204, got %d", resp.StatusCode)
+	}
+}
+
+func TestPutDigestValidation(t *testing.T) {
+	var stored alert.Digest
+	app := newDigestApp(alert.NewDigestHandler(memDigestStore{digest: &stored}, nil))
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"bad json", `{`},
+		{"scheduled_run", `{"frequency":"scheduled_run","recipients":["a@b.com"]}`},
+		{"no recipients", `{"frequency":"daily","recipients":[]}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if status, raw := putDigest(t, app, tc.body); status != fiber.StatusUnprocessableEntity {
+				t.Errorf("expected 422, got %d: %s", status, raw)
+			}
+		})
+	}
+}
+
+func TestDigestWithoutConfirmerRefusesRecipients(t *testing.T) {
+	var stored alert.Digest
+	app := newDigestApp(alert.NewDigestHandler(memDigestStore{digest: &stored}, nil))
+
+	status, raw := putDigest(t, app, `{"frequency":"daily","recipients":["a@b.com"]}`)
+	if status != fiber.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", status, raw)
+	}
+	if len(stored.Recipients) != 0 {
+		t.Errorf("no recipient should be stored without confirmations, got %+v", stored)
+	}
+}
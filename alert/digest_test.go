@@ -0,0 +1,56 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/penny-vault/pvbt/tradecron"
+)
+
+func TestDigestDue(t *testing.T) {
+	tradecron.SetMarketHolidays([]tradecron.MarketHoliday{
+		{Date: time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC)},
+	})
+
+	// 18:30 and 11:00 America/New_York on Tuesday, April 22 2025 (EDT).
+	evening := time.Date(2025, 4, 22, 22, 30, 0, 0, time.UTC)
+	morning := time.Date(2025, 4, 22, 15, 0, 0, 0, time.UTC)
+	saturday := time.Date(2025, 4, 26, 22, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		d    Digest
+		now  time.Time
+		want bool
+	}{
+		{"daily/after send hour", Digest{Frequency: FrequencyDaily}, evening, true},
+		{"daily/before send hour", Digest{Frequency: FrequencyDaily}, morning, false},
+		{"daily/sent today", Digest{Frequency: FrequencyDaily, LastSentAt: ptrTime(evening.Add(-time.Minute))}, evening, false},
+		{"daily/sent yesterday", Digest{Frequency: FrequencyDaily, LastSentAt: ptrTime(evening.AddDate(0, 0, -1))}, evening, true},
+		{"daily/weekend", Digest{Frequency: FrequencyDaily}, saturday, false},
+		{"weekly/mid-week", Digest{Frequency: FrequencyWeekly}, evening, false},
+		{"monthly/mid-month", Digest{Frequency: FrequencyMonthly}, evening, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := digestDue(tc.d, tc.now, 18); got != tc.want {
+				t.Errorf("digestDue = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package email
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -32,17 +33,38 @@ import (
 // error message produced by fmt.Errorf("...: %w", ErrUnexpectedStatus).
 var ErrUnexpectedStatus = errors.New("mailgun: unexpected status")
 
+// Transport delivers one rendered email and returns the provider's message
+// ID (empty when the provider assigns none). Mailgun and SMTP implement it.
+type Transport interface {
+	Send(ctx context.Context, recipients []string, subject, htmlBody, textBody string) (string, error)
+}
+
 type Config struct {
 	Domain      string
 	APIKey      string
 	FromAddress string
 }
 
+// Mailgun is the Transport for the Mailgun HTTP API.
+type Mailgun struct {
+	Config Config
+}
+
+func (m Mailgun) Send(ctx context.Context, recipients []string, subject, htmlBody, textBody string) (string, error) {
+	return sendMailgun(ctx, m.Config, recipients, subject, htmlBody, textBody)
+}
+
 // Send delivers an email via the Mailgun HTTP API.
 // Returns nil without sending if cfg.APIKey is empty.
 func Send(ctx context.Context, cfg Config, recipients []string, subject, htmlBody, textBody string) error {
+	_, err := sendMailgun(ctx, cfg, recipients, subject, htmlBody, textBody)
+	return err
+}
+
+// sendMailgun posts the message and returns the ID Mailgun queued it under.
+func sendMailgun(ctx context.Context, cfg Config, recipients []string, subject, htmlBody, textBody string) (string, error) {
 	if cfg.APIKey == "" {
-		return nil
+		return "", nil
 	}
 	endpoint := fmt.Sprintf("https://api.mailgun.net/v3/%s/messages", url.PathEscape(cfg.Domain))
 
@@ -57,14 +79,14 @@ func Send(ctx context.Context, cfg Config, recipients []string, subject, htmlBod
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
 	if err != nil {
-		return fmt.Errorf("mailgun: build request: %w", err)
+		return "", fmt.Errorf("mailgun: build request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("api:"+cfg.APIKey)))
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("mailgun: send: %w", err)
+		return "", fmt.Errorf("mailgun: send: %w", err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -73,7 +95,13 @@ func Send(ctx context.Context, cfg Config, recipients []string, subject, htmlBod
 	}()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+		return "", fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+	var queued struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&queued); err != nil {
+		log.Warn().Err(err).Msg("mailgun: decode response")
 	}
-	return nil
+	return queued.ID, nil
 }
@@ -64,3 +64,22 @@ func TestSendSkipsWhenNoAPIKey(t *testing.T) {
 		t.Errorf("expected 0 HTTP calls, got %d", count)
 	}
 }
+
+func TestMailgunTransportReturnsMessageID(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodPost,
+		"https://api.mailgun.net/v3/mg.pennyvault.com/messages",
+		httpmock.NewStringResponder(200, `{"id":"<abc@mg.pennyvault.com>","message":"Queued"}`),
+	)
+
+	tr := email.Mailgun{Config: email.Config{Domain: "mg.pennyvault.com", APIKey: "key-test"}}
+	id, err := tr.Send(context.Background(), []string{"user@example.com"}, "subj", "<p>h</p>", "h")
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if id != "<abc@mg.pennyvault.com>" {
+		t.Errorf("message id = %q", id)
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocaleTag is the locale used when neither the recipient nor the
+// operator picked one.
+const DefaultLocaleTag = "en-US"
+
+// ErrInvalidLocale is returned for a locale tag that is not a BCP 47 tag.
+var ErrInvalidLocale = errors.New("invalid locale")
+
+//go:embed locales/*.json
+var builtinLocales embed.FS
+
+// Locale formats numbers, money, percentages and dates for one language and
+// region, and translates the fixed strings in the email templates. Money is
+// always US dollars; only its presentation changes.
+type Locale struct {
+	tag  string
+	spec localeSpec
+}
+
+// localeSpec is the JSON form of a locale, as stored in locales/<tag>.json
+// and in an operator's <templates_dir>/locales/<tag>.json. Empty fields
+// inherit from the locale it is layered over.
+type localeSpec struct {
+	Group         string            `json:"group"`
+	Decimal       string            `json:"decimal"`
+	Money         string            `json:"money"`   // "{n}" is the formatted amount
+	Percent       string            `json:"percent"` // "{n}" is the formatted number
+	LongDate      string            `json:"longDate"`
+	ShortDate     string            `json:"shortDate"`
+	Months        []string          `json:"months"`
+	MonthsShort   []string          `json:"monthsShort"`
+	Weekdays      []string          `json:"weekdays"` // Sunday first
+	WeekdaysShort []string          `json:"weekdaysShort"`
+	Strings       map[string]string `json:"strings"`
+}
+
+// overlay returns s with every field set in o replaced.
+func (s localeSpec) overlay(o localeSpec) localeSpec {
+	pick := func(cur, next string) string {
+		if next != "" {
+			return next
+		}
+		return cur
+	}
+	pickList := func(cur, next []string) []string {
+		if len(next) > 0 {
+			return next
+		}
+		return cur
+	}
+	out := localeSpec{
+		Group:         pick(s.Group, o.Group),
+		Decimal:       pick(s.Decimal, o.Decimal),
+		Money:         pick(s.Money, o.Money),
+		Percent:       pick(s.Percent, o.Percent),
+		LongDate:      pick(s.LongDate, o.LongDate),
+		ShortDate:     pick(s.ShortDate, o.ShortDate),
+		Months:        pickList(s.Months, o.Months),
+		MonthsShort:   pickList(s.MonthsShort, o.MonthsShort),
+		Weekdays:      pickList(s.Weekdays, o.Weekdays),
+		WeekdaysShort: pickList(s.WeekdaysShort, o.WeekdaysShort),
+		Strings:       make(map[string]string, len(s.Strings)+len(o.Strings)),
+	}
+	for k, v := range s.Strings {
+		out.Strings[k] = v
+	}
+	for k, v := range o.Strings {
+		out.Strings[k] = v
+	}
+	return out
+}
+
+func (s localeSpec) validate() error {
+	if len(s.Months) != 12 || len(s.MonthsShort) != 12 {
+		return errors.New("months and monthsShort need 12 entries")
+	}
+	if len(s.Weekdays) != 7 || len(s.WeekdaysShort) != 7 {
+		return errors.New("weekdays and weekdaysShort need 7 entries")
+	}
+	return nil
+}
+
+// CanonicalLocale returns tag in canonical BCP 47 form ("de_de" becomes
+// "de-DE"), or ErrInvalidLocale.
+func CanonicalLocale(tag string) (string, error) {
+	t, err := language.Parse(strings.ReplaceAll(tag, "_", "-"))
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLocale, tag)
+	}
+	return t.String(), nil
+}
+
+// loadLocales reads the built-in locales and layers the JSON files in dir
+// (when set) over them. Every locale starts from en-US, then the built-in
+// locale for the same language, then its own file, so an operator file only
+// needs the fields it changes.
+func loadLocales(dir string) (map[string]*Locale, error) {
+	specs := map[string]localeSpec{}
+	if err := readLocaleSpecs(builtinLocales, "locales", specs); err != nil {
+		return nil, err
+	}
+	base, ok := specs[DefaultLocaleTag]
+	if !ok {
+		return nil, errors.New("email: built-in en-US locale missing")
+	}
+	builtinByLang := map[string]localeSpec{}
+	for tag, s := range specs {
+		lang := baseLanguage(tag)
+		if _, ok := builtinByLang[lang]; !ok || tag == DefaultLocaleTag {
+			builtinByLang[lang] = s
+		}
+	}
+	resolved := map[string]localeSpec{}
+	for tag, s := range specs {
+		resolved[tag] = base.overlay(s)
+	}
+
+	if dir != "" {
+		overrides := map[string]localeSpec{}
+		localesDir := filepath.Join(dir, "locales")
+		if _, err := os.Stat(localesDir); err == nil {
+			if err := readLocaleSpecs(os.DirFS(localesDir), ".", overrides); err != nil {
+				return nil, err
+			}
+		}
+		for tag, o := range overrides {
+			start, ok := resolved[tag]
+			if !ok {
+				start = base
+				if same, ok := builtinByLang[baseLanguage(tag)]; ok {
+					start = base.overlay(same)
+				}
+			}
+			resolved[tag] = start.overlay(o)
+		}
+	}
+
+	out := make(map[string]*Locale, len(resolved))
+	for tag, s := range resolved {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("email: locale %s: %w", tag, err)
+		}
+		out[tag] = &Locale{tag: tag, spec: s}
+	}
+	return out, nil
+}
+
+func readLocaleSpecs(fsys fs.FS, dir string, into map[string]localeSpec) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("email: read locales: %w", err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		tag, err := CanonicalLocale(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			return fmt.Errorf("email: locale file %s: %w", e.Name(), err)
+		}
+		raw, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("email: read locale %s: %w", e.Name(), err)
+		}
+		var s localeSpec
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return fmt.Errorf("email: parse locale %s: %w", e.Name(), err)
+		}
+		into[tag] = s
+	}
+	return nil
+}
+
+func baseLanguage(tag string) string {
+	lang, _, _ := strings.Cut(tag, "-")
+	return strings.ToLower(lang)
+}
+
+// Tag returns the locale's BCP 47 tag, e.g. "de-DE".
+func (l *Locale) Tag() string { return l.tag }
+
+// Message is a translatable sentence: a catalog key plus the values it
+// interpolates. It lets a sentence be composed once and rendered later in
+// each recipient's locale.
+type Message struct {
+	Key  string
+	Args []any
+}
+
+// List is a sequence of messages that T renders joined by Sep.
+type List struct {
+	Items []Message
+	Sep   string
+}
+
+// Percent is a fractional value (0.034 for 3.4%) that T formats as a
+// percentage with two decimals.
+type Percent float64
+
+// T returns the translation of key with args interpolated by fmt verbs. A
+// Percent, time.Time, float64, nested Message or List argument is formatted
+// for the locale first. A key missing from the catalog renders as the key itself.
+func (l *Locale) T(key string, args ...any) string {
+	format, ok := l.spec.Strings[key]
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	vals := make([]any, len(args))
+	for i, a := range args {
+		switch v := a.(type) {
+		case Percent:
+			vals[i] = l.Percent(float64(v)*100, 2)
+		case time.Time:
+			vals[i] = l.LongDate(v)
+		case float64:
+			vals[i] = l.Number(v, 2)
+		case Message:
+			vals[i] = l.Text(v)
+		case List:
+			parts := make([]string, len(v.Items))
+			for j, m := range v.Items {
+				parts[j] = l.Text(m)
+			}
+			vals[i] = strings.Join(parts, v.Sep)
+		default:
+			vals[i] = v
+		}
+	}
+	return fmt.Sprintf(format, vals...)
+}
+
+// Text renders m in this locale.
+func (l *Locale) Text(m Message) string { return l.T(m.Key, m.Args...) }
+
+// Number formats v with the locale's digit grouping and decimal separator.
+func (l *Locale) Number(v float64, decimals int) string {
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+	offset := len(intPart) % 3
+	if offset > 0 {
+		b.WriteString(intPart[:offset])
+	}
+	for i := offset; i < len(intPart); i += 3 {
+		if i > 0 {
+			b.WriteString(l.spec.Group)
+		}
+		b.WriteString(intPart[i : i+3])
+	}
+	if frac != "" {
+		b.WriteString(l.spec.Decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// Money formats a whole-dollar amount, e.g. "$1,234" in en-US and
+// "1.234 $" in de-DE. A negative amount gets a leading minus.
+func (l *Locale) Money(v float64) string {
+	sign := ""
+	if v < 0 && math.Round(v) != 0 {
+		sign = "-"
+	}
+	return sign + strings.ReplaceAll(l.spec.Money, "{n}", l.Number(math.Abs(v), 0))
+}
+
+// Percent formats v, already in percent units (3.4 for 3.4%), without a sign.
+func (l *Locale) Percent(v float64, decimals int) string {
+	return strings.ReplaceAll(l.spec.Percent, "{n}", l.Number(v, decimals))
+}
+
+// LongDate formats t as a full date, e.g. "April 21, 2026".
+func (l *Locale) LongDate(t time.Time) string { return l.date(l.spec.LongDate, t) }
+
+// ShortDate formats t as an abbreviated date with weekday, e.g. "Thu, Apr 16".
+func (l *Locale) ShortDate(t time.Time) string { return l.date(l.spec.ShortDate, t) }
+
+// Weekday returns t's full weekday name.
+func (l *Locale) Weekday(t time.Time) string { return l.spec.Weekdays[t.Weekday()] }
+
+func (l *Locale) date(pattern string, t time.Time) string {
+	return strings.NewReplacer(
+		"{yyyy}", strconv.Itoa(t.Year()),
+		"{MMMM}", l.spec.Months[t.Month()-1],
+		"{MMM}", l.spec.MonthsShort[t.Month()-1],
+		"{MM}", fmt.Sprintf("%02d", int(t.Month())),
+		"{EEEE}", l.spec.Weekdays[t.Weekday()],
+		"{EEE}", l.spec.WeekdaysShort[t.Weekday()],
+		"{dd}", fmt.Sprintf("%02d", t.Day()),
+		"{d}", strconv.Itoa(t.Day()),
+	).Replace(pattern)
+}
+
+// FormatDelta formats a value change since lastSentAt, relative to now. Both
+// times are interpreted in their own locations for the day-difference calc, so
+// callers should pass them already converted to the display timezone.
+func (l *Locale) FormatDelta(currentValue, previousValue float64, lastSentAt, now time.Time) (deltaPct, deltaAbs, color, since string, hasDelta bool) {
+	if previousValue <= 0 {
+		return "", "", "", "", false
+	}
+	diff := currentValue - previousValue
+	pct := diff / previousValue * 100
+	roundedPct := math.Round(pct*100) / 100
+	roundedDiff := math.Round(diff)
+	since = l.sinceLabel(lastSentAt, now)
+	if roundedPct == 0 && roundedDiff == 0 {
+		return l.Percent(0, 2), l.Money(0), "#94a3b8", since, true
+	}
+	sign := "+"
+	if diff < 0 {
+		sign = "-"
+		diff = -diff
+		pct = math.Abs(pct)
+	}
+	deltaPct = sign + l.Percent(pct, 2)
+	deltaAbs = sign + l.Money(diff)
+	if sign == "+" {
+		color = "#22c55e"
+	} else {
+		color = "#ef4444"
+	}
+	return deltaPct, deltaAbs, color, since, true
+}
+
+func (l *Locale) sinceLabel(lastSentAt, now time.Time) string {
+	sentDay := time.Date(lastSentAt.Year(), lastSentAt.Month(), lastSentAt.Day(), 0, 0, 0, 0, lastSentAt.Location())
+	nowDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	diffDays := int(nowDay.Sub(sentDay).Hours() / 24)
+	switch {
+	case diffDays <= 0:
+		return l.T("since.today")
+	case diffDays == 1:
+		return l.T("since.yesterday")
+	case diffDays >= 2 && diffDays <= 6 && lastSentAt.Weekday() != now.Weekday():
+		return l.Weekday(lastSentAt)
+	default:
+		return l.ShortDate(lastSentAt)
+	}
+}
+
+// FormatReturnPct formats a fractional return (e.g. 0.034) as "+3.40%" and
+// returns the appropriate color string for light-mode rendering. A value that
+// rounds to zero at two-decimal precision renders as "0.00%" with no sign and
+// a neutral color, so a tiny move isn't dressed up as a gain or a loss.
+func (l *Locale) FormatReturnPct(v float64) (pct, color string) {
+	rounded := math.Round(v*10000) / 100 // percent, two decimal places
+	if rounded == 0 {
+		return l.Percent(0, 2), "#94a3b8"
+	}
+	sign := "+"
+	if rounded < 0 {
+		sign = "-"
+		rounded = -rounded
+	}
+	pct = sign + l.Percent(rounded, 2)
+	if sign == "+" {
+		color = "#16a34a"
+	} else {
+		color = "#dc2626"
+	}
+	return
+}
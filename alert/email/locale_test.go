@@ -0,0 +1,131 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/penny-vault/pv-api/alert/email"
+)
+
+func TestLocaleFormatting(t *testing.T) {
+	r, err := email.NewRenderer(email.RendererConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	de := r.Locale("de-DE")
+	day := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct{ got, want string }{
+		{de.Money(1234567.4), "1.234.567 $"},
+		{de.Money(-1500), "-1.500 $"},
+		{de.Number(12.345, 1), "12,3"},
+		{de.Percent(3.4, 1), "3,4 %"},
+		{de.LongDate(day), "5. März 2026"},
+		{de.T("subject.update", "Core"), "Portfolio-Update: Core"},
+		{de.T("trigger.drawdown", email.Percent(0.123), email.Percent(0.1)), de.T("trigger.drawdown", "12,30 %", "10,00 %")},
+		{r.Locale("en-US").Money(1234567.4), "$1,234,567"},
+		{r.Locale("en-GB").LongDate(day), "5 March 2026"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("got %q, want %q", c.got, c.want)
+		}
+	}
+}
+
+func TestLocaleFallback(t *testing.T) {
+	r, err := email.NewRenderer(email.RendererConfig{DefaultLocale: "fr_FR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for tag, want := range map[string]string{
+		"de-AT": "de-DE",
+		"es":    "es-ES",
+		"ja-JP": "fr-FR",
+		"":      "fr-FR",
+		"!!":    "fr-FR",
+	} {
+		if got := r.Locale(tag).Tag(); got != want {
+			t.Errorf("Locale(%q) = %s, want %s", tag, got, want)
+		}
+	}
+	if _, err := email.NewRenderer(email.RendererConfig{DefaultLocale: "not a tag"}); err == nil {
+		t.Error("a malformed default locale should fail")
+	}
+}
+
+func TestRendererOverrides(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite := func(name, body string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite("confirm.html", `<p style="color:{{.Brand.AccentColor}}">{{.Brand.Name}}: {{t "confirm.button"}}</p>`)
+	mustWrite("de-DE/confirm.html", `<p lang="{{lang}}">{{t "confirm.button"}} {{.Recipient}}</p>`)
+	mustWrite("locales/en-US.json", `{"strings": {"confirm.button": "Yes, send them"}}`)
+	mustWrite("locales/nl-NL.json", `{"strings": {"confirm.button": "Bevestigen"}}`)
+
+	r, err := email.NewRenderer(email.RendererConfig{
+		TemplatesDir: dir,
+		Brand:        email.Brand{Name: "Acme <Wealth>", AccentColor: "#112233"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := email.Confirmation{PortfolioName: "Core", Recipient: "a@example.com", ConfirmURL: "https://x", ExpiresOn: "soon"}
+
+	html, _, err := r.RenderConfirmation(c, r.Locale("en-US"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != `<p style="color:#112233">Acme &lt;Wealth&gt;: Yes, send them</p>` {
+		t.Errorf("en-US override: %s", html)
+	}
+
+	html, _, err = r.RenderConfirmation(c, r.Locale("de-DE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(html, `<p lang="de-DE">`) || !strings.Contains(html, "a@example.com") {
+		t.Errorf("de-DE override: %s", html)
+	}
+
+	// nl-NL only overrides one string; the rest falls back to en-US.
+	nl := r.Locale("nl-NL")
+	if nl.Tag() != "nl-NL" || nl.T("confirm.button") != "Bevestigen" || nl.T("confirm.title") != r.Locale("en-US").T("confirm.title") {
+		t.Errorf("nl-NL locale: %s %q %q", nl.Tag(), nl.T("confirm.button"), nl.T("confirm.title"))
+	}
+}
+
+func TestRendererRejectsBadBrand(t *testing.T) {
+	if _, err := email.NewRenderer(email.RendererConfig{Brand: email.Brand{AccentColor: "red; x"}}); err == nil {
+		t.Error("a non-hex accent color should fail")
+	}
+	if _, err := email.NewRenderer(email.RendererConfig{Brand: email.Brand{Logo: filepath.Join(t.TempDir(), "missing.png")}}); err == nil {
+		t.Error("a missing logo file should fail")
+	}
+}
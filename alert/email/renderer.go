@@ -0,0 +1,295 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"golang.org/x/text/language"
+)
+
+// Brand is the operator's branding, applied to every email.
+type Brand struct {
+	// Name appears in the header, the footer and the confirmation text.
+	Name string
+	// Logo is an http(s) or data URL, or the path of an image file that is
+	// inlined as a data URL.
+	Logo string
+	// AccentColor is a hex color for the header rule, table headers and buttons.
+	AccentColor string
+	// Footer is an extra footer line, such as a postal address.
+	Footer string
+}
+
+const (
+	defaultBrandName   = "Penny Vault"
+	defaultAccentColor = "#0ea5e9"
+)
+
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// RendererConfig configures NewRenderer. The zero value renders the built-in
+// templates in en-US with Penny Vault branding.
+type RendererConfig struct {
+	// TemplatesDir overrides the built-in templates. <dir>/<locale>/<name>.html
+	// wins over <dir>/<name>.html, which wins over the built-in template, for
+	// each of success, failure, confirm and digest. Locale files in
+	// <dir>/locales/<locale>.json add locales or override built-in strings
+	// and formats.
+	TemplatesDir string
+	// DefaultLocale is used for recipients with no locale of their own.
+	DefaultLocale string
+	Brand         Brand
+}
+
+// templateNames are the templates a Renderer loads, by file name.
+var templateNames = []string{"success", "failure", "confirm", "digest"}
+
+// Renderer renders alert emails in each recipient's locale with the
+// operator's templates and branding.
+type Renderer struct {
+	brand     Brand // HTML-escaped, for the templates
+	textBrand Brand
+	locales   map[string]*Locale
+	tags      []string // sorted, for deterministic language fallback
+	def       *Locale
+	templates map[string]map[string]*template.Template // locale tag -> name
+}
+
+// NewRenderer loads locales and templates and checks that they parse, so a
+// broken override fails at startup rather than on the first alert.
+func NewRenderer(cfg RendererConfig) (*Renderer, error) {
+	brand, err := resolveBrand(cfg.Brand)
+	if err != nil {
+		return nil, err
+	}
+	locales, err := loadLocales(cfg.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	r := &Renderer{
+		brand: Brand{
+			Name:        template.HTMLEscapeString(brand.Name),
+			Logo:        brand.Logo,
+			AccentColor: brand.AccentColor,
+			Footer:      template.HTMLEscapeString(brand.Footer),
+		},
+		textBrand: brand,
+		locales:   locales,
+		templates: make(map[string]map[string]*template.Template, len(locales)),
+	}
+	for tag := range locales {
+		r.tags = append(r.tags, tag)
+	}
+	slices.Sort(r.tags)
+
+	defTag := cfg.DefaultLocale
+	if defTag == "" {
+		defTag = DefaultLocaleTag
+	}
+	canon, err := CanonicalLocale(defTag)
+	if err != nil {
+		return nil, fmt.Errorf("email: default locale: %w", err)
+	}
+	if r.def = r.match(canon); r.def == nil {
+		return nil, fmt.Errorf("email: default locale %s has no strings", canon)
+	}
+
+	for tag, loc := range locales {
+		set := make(map[string]*template.Template, len(templateNames))
+		for _, name := range templateNames {
+			src, err := templateSource(cfg.TemplatesDir, tag, name)
+			if err != nil {
+				return nil, err
+			}
+			tmpl, err := template.New(name).Funcs(template.FuncMap{
+				"t":    loc.T,
+				"lang": loc.Tag,
+			}).Parse(src)
+			if err != nil {
+				return nil, fmt.Errorf("email: parse %s template for %s: %w", name, tag, err)
+			}
+			set[name] = tmpl
+		}
+		r.templates[tag] = set
+	}
+	return r, nil
+}
+
+// resolveBrand fills defaults and inlines a logo given as a file path.
+func resolveBrand(b Brand) (Brand, error) {
+	if b.Name == "" {
+		b.Name = defaultBrandName
+	}
+	if b.AccentColor == "" {
+		b.AccentColor = defaultAccentColor
+	}
+	if !hexColor.MatchString(b.AccentColor) {
+		return b, fmt.Errorf("email: brand accent color %q is not a hex color", b.AccentColor)
+	}
+	switch {
+	case b.Logo == "":
+		b.Logo = logoDataURL
+	case strings.HasPrefix(b.Logo, "https://"), strings.HasPrefix(b.Logo, "http://"), strings.HasPrefix(b.Logo, "data:"):
+	default:
+		raw, err := os.ReadFile(b.Logo)
+		if err != nil {
+			return b, fmt.Errorf("email: brand logo: %w", err)
+		}
+		b.Logo = "data:" + http.DetectContentType(raw) + ";base64," + base64.StdEncoding.EncodeToString(raw)
+	}
+	return b, nil
+}
+
+// templateSource returns the most specific override of name for tag, or the
+// built-in template.
+func templateSource(dir, tag, name string) (string, error) {
+	if dir != "" {
+		for _, p := range []string{filepath.Join(dir, tag, name+".html"), filepath.Join(dir, name+".html")} {
+			raw, err := os.ReadFile(p)
+			if err == nil {
+				return string(raw), nil
+			}
+			if !errors.Is(err, os.ErrNotExist) {
+				return "", fmt.Errorf("email: read template %s: %w", p, err)
+			}
+		}
+	}
+	switch name {
+	case "success":
+		return successHTML, nil
+	case "failure":
+		return failureHTML, nil
+	case "confirm":
+		return confirmHTML, nil
+	default:
+		return digestHTML, nil
+	}
+}
+
+// Locale returns the locale for a recipient's tag: an exact match, then
+// another locale of the same language ("de-AT" falls back to "de-DE"), then
+// the default locale.
+func (r *Renderer) Locale(tag string) *Locale {
+	if tag == "" {
+		return r.def
+	}
+	canon, err := CanonicalLocale(tag)
+	if err != nil {
+		return r.def
+	}
+	if loc := r.match(canon); loc != nil {
+		return loc
+	}
+	return r.def
+}
+
+func (r *Renderer) match(canon string) *Locale {
+	if loc, ok := r.locales[canon]; ok {
+		return loc
+	}
+	want, _ := language.Make(canon).Base()
+	for _, tag := range r.tags {
+		if base, _ := language.Make(tag).Base(); base == want {
+			return r.locales[tag]
+		}
+	}
+	return nil
+}
+
+// DefaultLocale returns the locale used for recipients without one.
+func (r *Renderer) DefaultLocale() *Locale { return r.def }
+
+func (r *Renderer) execute(name string, loc *Locale, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := r.templates[loc.Tag()][name].Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// Render renders an alert message's HTML and text parts in loc. Triggers
+// carry values read from the snapshot, so they are escaped before they
+// reach the HTML.
+func (r *Renderer) Render(p Payload, loc *Locale) (string, string, error) {
+	name := "success"
+	if !p.Success {
+		name = "failure"
+	}
+	h := p
+	h.Triggers = make([]string, len(p.Triggers))
+	for i, t := range p.Triggers {
+		h.Triggers[i] = template.HTMLEscapeString(t)
+	}
+	h.Brand = r.brand
+	if h.LogoDataURL == "" {
+		h.LogoDataURL = r.brand.Logo
+	}
+	html, err := r.execute(name, loc, h)
+	if err != nil {
+		return "", "", err
+	}
+	return html, buildPlaintext(p, loc), nil
+}
+
+// Plaintext renders only the text part of p, for channels that cannot show HTML.
+func (r *Renderer) Plaintext(p Payload, loc *Locale) string { return buildPlaintext(p, loc) }
+
+// RenderConfirmation renders the confirmation email. The recipient address is
+// whatever the alert owner typed, so it and the portfolio name are escaped
+// before they reach the HTML.
+func (r *Renderer) RenderConfirmation(c Confirmation, loc *Locale) (string, string, error) {
+	h := c
+	h.PortfolioName = template.HTMLEscapeString(c.PortfolioName)
+	h.Recipient = template.HTMLEscapeString(c.Recipient)
+	h.ConfirmURL = template.HTMLEscapeString(c.ConfirmURL)
+	h.Brand = r.brand
+	if h.LogoDataURL == "" {
+		h.LogoDataURL = r.brand.Logo
+	}
+	html, err := r.execute("confirm", loc, h)
+	if err != nil {
+		return "", "", err
+	}
+	text := loc.T("confirm.intro", r.textBrand.Name, c.PortfolioName, c.Recipient) + "\n\n" +
+		loc.T("confirm.text_open") + "\n" + c.ConfirmURL + "\n\n" +
+		loc.T("confirm.text_expires", c.ExpiresOn) + "\n"
+	return html, text, nil
+}
+
+// RenderDigest renders the digest email's HTML and text parts in loc.
+func (r *Renderer) RenderDigest(p DigestPayload, loc *Locale) (string, string, error) {
+	h := p
+	h.Brand = r.brand
+	if h.LogoDataURL == "" {
+		h.LogoDataURL = r.brand.Logo
+	}
+	html, err := r.execute("digest", loc, h)
+	if err != nil {
+		return "", "", err
+	}
+	return html, buildDigestPlaintext(p, loc), nil
+}
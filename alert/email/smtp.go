@@ -0,0 +1,226 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP TLS modes.
+const (
+	// SMTPStartTLS connects in plaintext and upgrades with STARTTLS, failing
+	// if the server does not offer it. The usual mode on port 587.
+	SMTPStartTLS = "starttls"
+	// SMTPImplicitTLS speaks TLS from the first byte. The usual mode on port 465.
+	SMTPImplicitTLS = "tls"
+	// SMTPNoTLS never encrypts. Only for local sinks such as MailHog.
+	SMTPNoTLS = "none"
+)
+
+// ErrNoStartTLS is returned when STARTTLS is required but not advertised.
+var ErrNoStartTLS = errors.New("smtp: server does not support STARTTLS")
+
+// smtpTimeout bounds a send when the caller's context has no deadline.
+const smtpTimeout = time.Minute
+
+// SMTP is the Transport for a plain SMTP relay.
+type SMTP struct {
+	Host        string
+	Port        int
+	Username    string // empty skips AUTH
+	Password    string
+	FromAddress string
+	TLS         string // SMTPStartTLS (default), SMTPImplicitTLS or SMTPNoTLS
+	// TLSConfig overrides the TLS settings; ServerName defaults to Host.
+	TLSConfig *tls.Config
+}
+
+// ValidateTLSMode reports whether mode is a known SMTP TLS mode.
+func ValidateTLSMode(mode string) error {
+	switch mode {
+	case "", SMTPStartTLS, SMTPImplicitTLS, SMTPNoTLS:
+		return nil
+	}
+	return fmt.Errorf("smtp: unknown tls mode %q (want starttls, tls or none)", mode)
+}
+
+// Send returns the Message-ID header it generated, since SMTP relays do not
+// report one of their own.
+func (s SMTP) Send(ctx context.Context, recipients []string, subject, htmlBody, textBody string) (string, error) {
+	if err := ValidateTLSMode(s.TLS); err != nil {
+		return "", err
+	}
+	from, err := mail.ParseAddress(s.FromAddress)
+	if err != nil {
+		return "", fmt.Errorf("smtp: from address: %w", err)
+	}
+	msg, msgID, err := buildMessage(s.FromAddress, recipients, subject, htmlBody, textBody, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = client.Close() }()
+
+	if s.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return "", fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return "", fmt.Errorf("smtp: MAIL FROM: %w", err)
+	}
+	for _, r := range recipients {
+		if err := client.Rcpt(r); err != nil {
+			return "", fmt.Errorf("smtp: RCPT TO %s: %w", r, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return "", fmt.Errorf("smtp: DATA: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return "", fmt.Errorf("smtp: write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("smtp: end message: %w", err)
+	}
+	if err := client.Quit(); err != nil {
+		return "", fmt.Errorf("smtp: QUIT: %w", err)
+	}
+	return msgID, nil
+}
+
+// dial connects, applies the TLS mode, and returns a client ready for AUTH.
+func (s SMTP) dial(ctx context.Context) (*smtp.Client, error) {
+	tlsConf := s.TLSConfig
+	if tlsConf == nil {
+		tlsConf = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if tlsConf.ServerName == "" {
+		tlsConf = tlsConf.Clone()
+		tlsConf.ServerName = s.Host
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp: dial %s: %w", addr, err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if s.TLS == SMTPImplicitTLS {
+		conn = tls.Client(conn, tlsConf)
+	}
+	client, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("smtp: greeting: %w", err)
+	}
+	if s.TLS == "" || s.TLS == SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			_ = client.Close()
+			return nil, ErrNoStartTLS
+		}
+		if err := client.StartTLS(tlsConf); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("smtp: STARTTLS: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// buildMessage assembles a multipart/alternative message carrying both the
+// plaintext and HTML bodies, quoted-printable encoded, and returns it with
+// its Message-ID.
+func buildMessage(from string, to []string, subject, htmlBody, textBody string, now time.Time) ([]byte, string, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", textBody},
+		{"text/html; charset=utf-8", htmlBody},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, "", fmt.Errorf("smtp: build message: %w", err)
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, "", fmt.Errorf("smtp: build message: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, "", fmt.Errorf("smtp: build message: %w", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", fmt.Errorf("smtp: build message: %w", err)
+	}
+
+	var id [12]byte
+	_, _ = rand.Read(id[:])
+	domain := "pvapi"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndexByte(addr.Address, '@'); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+
+	msgID := "<" + hex.EncodeToString(id[:]) + "@" + domain + ">"
+
+	var msg bytes.Buffer
+	headers := [][2]string{
+		{"From", from},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", msgID},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + mw.Boundary()},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), msgID, nil
+}
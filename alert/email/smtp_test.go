@@ -0,0 +1,234 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package email_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/penny-vault/pv-api/alert/email"
+)
+
+// smtpSink is a minimal SMTP server that accepts one message per connection
+// and records the envelope and DATA it received.
+type smtpSink struct {
+	ln       net.Listener
+	starttls bool
+	from     string
+	rcpts    []string
+	data     string
+	done     chan struct{}
+}
+
+func newSMTPSink(t *testing.T, ln net.Listener) *smtpSink {
+	t.Helper()
+	s := &smtpSink{ln: ln, done: make(chan struct{})}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpSink) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	reply("220 sink ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+		switch {
+		case strings.HasPrefix(upper, "EHLO"):
+			if s.starttls {
+				reply("250-sink")
+				reply("250 STARTTLS")
+			} else {
+				reply("250 sink")
+			}
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 ok")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.rcpts = append(s.rcpts, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 ok")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data = b.String()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func sinkPort(t *testing.T, ln net.Listener) int {
+	t.Helper()
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestSMTPSendsMultipartMessage(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := newSMTPSink(t, ln)
+
+	tr := email.SMTP{
+		Host:        "127.0.0.1",
+		Port:        sinkPort(t, ln),
+		FromAddress: "Penny Vault <no-reply@example.com>",
+		TLS:         email.SMTPNoTLS,
+	}
+	msgID, err := tr.Send(context.Background(), []string{"user@example.com"},
+		"Portfolio Update: Growth", "<p>hello</p>", "hello")
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	<-sink.done
+
+	if sink.from != "no-reply@example.com" {
+		t.Errorf("MAIL FROM = %q", sink.from)
+	}
+	if len(sink.rcpts) != 1 || sink.rcpts[0] != "user@example.com" {
+		t.Errorf("RCPT TO = %v", sink.rcpts)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(sink.data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	if msg.Header.Get("Message-Id") != msgID || !strings.HasSuffix(msgID, "@example.com>") {
+		t.Errorf("Message-ID header %q, returned %q", msg.Header.Get("Message-Id"), msgID)
+	}
+	if msg.Header.Get("Subject") != "Portfolio Update: Growth" {
+		t.Errorf("Subject = %q", msg.Header.Get("Subject"))
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q", msg.Header.Get("Content-Type"))
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, part.Header.Get("Content-Type"))
+	}
+	if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Errorf("parts = %v", types)
+	}
+}
+
+func TestSMTPImplicitTLS(t *testing.T) {
+	// Borrow httptest's self-signed certificate for the sink.
+	ts := httptest.NewUnstartedServer(http.NotFoundHandler())
+	ts.StartTLS()
+	defer ts.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(ts.Certificate())
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: ts.TLS.Certificates})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := newSMTPSink(t, ln)
+
+	tr := email.SMTP{
+		Host:        "127.0.0.1",
+		Port:        sinkPort(t, ln),
+		FromAddress: "no-reply@example.com",
+		TLS:         email.SMTPImplicitTLS,
+		TLSConfig:   &tls.Config{RootCAs: pool, ServerName: "example.com", MinVersion: tls.VersionTLS12},
+	}
+	if _, err := tr.Send(context.Background(), []string{"user@example.com"}, "s", "<p>h</p>", "h"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	<-sink.done
+	if len(sink.rcpts) != 1 {
+		t.Errorf("RCPT TO = %v", sink.rcpts)
+	}
+}
+
+func TestSMTPRequiresStartTLS(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSMTPSink(t, ln)
+
+	tr := email.SMTP{Host: "127.0.0.1", Port: sinkPort(t, ln), FromAddress: "no-reply@example.com"}
+	_, err = tr.Send(context.Background(), []string{"user@example.com"}, "s", "<p>h</p>", "h")
+	if !errors.Is(err, email.ErrNoStartTLS) {
+		t.Fatalf("expected ErrNoStartTLS, got %v", err)
+	}
+}
+
+func TestValidateTLSMode(t *testing.T) {
+	for _, mode := range []string{"", "starttls", "tls", "none"} {
+		if err := email.ValidateTLSMode(mode); err != nil {
+			t.Errorf("ValidateTLSMode(%q) = %v", mode, err)
+		}
+	}
+	if err := email.ValidateTLSMode("ssl"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -16,24 +16,17 @@
 package email
 
 import (
-	"bytes"
 	_ "embed"
 	"encoding/base64"
 	"fmt"
-	"math"
 	"strings"
-	"text/template"
 	"time"
 )
 
 //go:embed assets/logo-80.jpg
 var logoBytes []byte
 
-var logoDataURL string
-
-func init() {
-	logoDataURL = "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(logoBytes)
-}
+var logoDataURL = "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(logoBytes)
 
 //go:embed templates/success.html
 var successHTML string
@@ -41,45 +34,60 @@ var successHTML string
 //go:embed templates/failure.html
 var failureHTML string
 
-var (
-	successTmpl = template.Must(template.New("success").Parse(successHTML))
-	failureTmpl = template.Must(template.New("failure").Parse(failureHTML))
-)
+//go:embed templates/confirm.html
+var confirmHTML string
+
+//go:embed templates/digest.html
+var digestHTML string
+
+// defaultRenderer backs the package-level helpers: built-in templates, en-US,
+// Penny Vault branding.
+var defaultRenderer = func() *Renderer {
+	r, err := NewRenderer(RendererConfig{})
+	if err != nil {
+		panic(err)
+	}
+	return r
+}()
+
+// DefaultRenderer returns the renderer for the built-in templates and
+// branding in en-US.
+func DefaultRenderer() *Renderer { return defaultRenderer }
 
 type TradeRow struct {
-	Ticker        string
-	Action        string
-	ActionColor   string
-	ActionBgColor string
-	Shares        string
-	Value         string
+	Ticker        string `json:"ticker"`
+	Action        string `json:"action"`
+	ActionColor   string `json:"-"`
+	ActionBgColor string `json:"-"`
+	Shares        string `json:"shares"`
+	Value         string `json:"value"`
 }
 
 type HoldingRow struct {
-	Ticker      string
-	TickerColor string
-	Shares      string // formatted with commas; "—" for $CASH
-	WeightPct   string
-	Value       string
+	Ticker      string `json:"ticker"`
+	TickerColor string `json:"-"`
+	Shares      string `json:"shares"` // formatted with commas; "—" for $CASH
+	WeightPct   string `json:"weightPct"`
+	Value       string `json:"value"`
 }
 
 // ReturnCell is one formatted return percentage and the color it renders in.
 // Pct is "—" when the snapshot has no value for that window.
 type ReturnCell struct {
-	Pct   string
-	Color string
+	Pct   string `json:"pct"`
+	Color string `json:"-"`
 }
 
 // ReturnsRow is one labeled line of the returns comparison grid: the
 // portfolio's returns, or the benchmark's, across the standard windows.
 // It drives the wide (desktop) layout, where each row is a series.
 type ReturnsRow struct {
-	Label   string
-	Day     ReturnCell
-	Wtd     ReturnCell
-	Mtd     ReturnCell
-	Ytd     ReturnCell
-	OneYear ReturnCell
+	Label   string     `json:"label"`
+	Day     ReturnCell `json:"day"`
+	Wtd     ReturnCell `json:"wtd"`
+	Mtd     ReturnCell `json:"mtd"`
+	Ytd     ReturnCell `json:"ytd"`
+	OneYear ReturnCell `json:"oneYear"`
 }
 
 // ReturnsWindow is one row of the narrow (phone) layout, which flips the grid
@@ -90,201 +98,236 @@ type ReturnsWindow struct {
 	Cells []ReturnCell
 }
 
+// Payload is everything an alert message shows. Its JSON form is the data
+// document generic webhooks receive; colors, the logo and the phone layout
+// are presentation-only and left out.
 type Payload struct {
-	PortfolioName string
-	StrategyCode  string
-	RunDate       string
-	Success       bool
+	PortfolioName string `json:"portfolioName"`
+	StrategyCode  string `json:"strategyCode"`
+	RunDate       string `json:"runDate"`
+	Success       bool   `json:"success"`
 
-	LogoDataURL string
+	LogoDataURL string `json:"-"`
+	Brand       Brand  `json:"-"` // set by the Renderer
+
+	CurrentValue string `json:"currentValue"`
+	HasDelta     bool   `json:"hasDelta"`
+	DeltaPct     string `json:"deltaPct"`
+	DeltaAbs     string `json:"deltaAbs"`
+	SinceLabel   string `json:"sinceLabel"`
+	DeltaColor   string `json:"-"`
 
-	CurrentValue string
-	HasDelta     bool
-	DeltaPct     string
-	DeltaAbs     string
-	SinceLabel   string
-	DeltaColor   string
+	// Triggers lists the conditions that fired for an on_condition alert, one
+	// human-readable sentence each. Empty for cadence alerts.
+	Triggers []string `json:"triggers,omitempty"`
 
 	// TradesDate is the formatted date the trades should be executed on
 	// (the rebalance date shared by every row in Trades). Empty when there
 	// are no trades.
-	TradesDate string
+	TradesDate string `json:"tradesDate"`
 
 	// Returns drives the wide (desktop) returns grid: row 0 is the portfolio,
 	// row 1 (when present) is the benchmark, each spanning Day/WTD/MTD/YTD/1Y.
-	Returns []ReturnsRow
+	Returns []ReturnsRow `json:"returns"`
 	// SeriesLabels and ReturnWindows drive the narrow (phone) layout, which
 	// flips the grid: a row per window, a column per series. SeriesLabels are
 	// the column headers ("Portfolio", "Benchmark (SPY)"); each ReturnsWindow's
 	// Cells line up with them.
-	SeriesLabels  []string
-	ReturnWindows []ReturnsWindow
+	SeriesLabels  []string        `json:"-"`
+	ReturnWindows []ReturnsWindow `json:"-"`
 
-	Trades   []TradeRow
-	Holdings []HoldingRow
+	Trades   []TradeRow   `json:"trades"`
+	Holdings []HoldingRow `json:"holdings"`
 
-	PortfolioURL   string
-	UnsubscribeURL string
+	PortfolioURL   string `json:"portfolioUrl,omitempty"`
+	UnsubscribeURL string `json:"-"`
 
-	ErrorMessage   string
-	LastKnownValue string
+	ErrorMessage   string `json:"errorMessage,omitempty"`
+	LastKnownValue string `json:"lastKnownValue,omitempty"`
 }
 
+// Render renders p with the default renderer in en-US.
 func Render(p Payload) (string, string, error) {
-	tmpl := successTmpl
-	if !p.Success {
-		tmpl = failureTmpl
-	}
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, p); err != nil {
-		return "", "", fmt.Errorf("render email template: %w", err)
-	}
-	return buf.String(), buildPlaintext(p), nil
+	return defaultRenderer.Render(p, defaultRenderer.DefaultLocale())
 }
 
-// FormatDelta formats a value change since lastSentAt, relative to now. Both
-// times are interpreted in their own locations for the day-difference calc, so
-// callers should pass them already converted to the display timezone.
-func FormatDelta(currentValue, previousValue float64, lastSentAt, now time.Time) (deltaPct, deltaAbs, color, since string, hasDelta bool) {
-	if previousValue <= 0 {
-		return "", "", "", "", false
-	}
-	diff := currentValue - previousValue
-	pct := diff / previousValue * 100
-	roundedPct := math.Round(pct*100) / 100
-	roundedDiff := math.Round(diff)
-	since = formatSinceLabel(lastSentAt, now)
-	if roundedPct == 0 && roundedDiff == 0 {
-		return "0.00%", "$0", "#94a3b8", since, true
-	}
-	sign := "+"
-	if diff < 0 {
-		sign = "-"
-		diff = -diff
-		pct = math.Abs(pct)
-	}
-	deltaPct = fmt.Sprintf("%s%.2f%%", sign, pct)
-	deltaAbs = fmt.Sprintf("%s$%s", sign, FormatMoneyVal(diff))
-	if sign == "+" {
-		color = "#22c55e"
-	} else {
-		color = "#ef4444"
-	}
-	return deltaPct, deltaAbs, color, since, true
+// Confirmation is the data behind the email that asks a new recipient to
+// confirm they want a portfolio's alerts.
+type Confirmation struct {
+	PortfolioName string
+	Recipient     string
+	ConfirmURL    string
+	ExpiresOn     string
+	LogoDataURL   string
+	Brand         Brand // set by the Renderer
 }
 
-func formatSinceLabel(lastSentAt, now time.Time) string {
-	sentDay := time.Date(lastSentAt.Year(), lastSentAt.Month(), lastSentAt.Day(), 0, 0, 0, 0, lastSentAt.Location())
-	nowDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	diffDays := int(nowDay.Sub(sentDay).Hours() / 24)
-	switch {
-	case diffDays <= 0:
-		return "earlier today"
-	case diffDays == 1:
-		return "yesterday"
-	case diffDays >= 2 && diffDays <= 6 && lastSentAt.Weekday() != now.Weekday():
-		return lastSentAt.Format("Monday")
-	default:
-		return lastSentAt.Format("Mon, Jan 2")
-	}
+// RenderConfirmation renders c with the default renderer in en-US.
+func RenderConfirmation(c Confirmation) (string, string, error) {
+	return defaultRenderer.RenderConfirmation(c, defaultRenderer.DefaultLocale())
 }
 
-func FormatMoneyVal(v float64) string {
-	sign := ""
-	if v < 0 {
-		sign = "-"
-		v = -v
-	}
-	s := fmt.Sprintf("%.0f", v)
-	if len(s) <= 3 {
-		return sign + s
-	}
-	var result strings.Builder
-	result.WriteString(sign)
-	offset := len(s) % 3
-	if offset > 0 {
-		result.WriteString(s[:offset])
-	}
-	for i := offset; i < len(s); i += 3 {
-		if i > 0 {
-			result.WriteByte(',')
-		}
-		result.WriteString(s[i : i+3])
-	}
-	return result.String()
+// DigestPortfolio is one portfolio's card in a digest: its value, the trades
+// its strategy predicts next, and its largest holdings.
+type DigestPortfolio struct {
+	Name       string
+	Value      string
+	TradesDate string
+	Trades     []TradeRow
+	Holdings   []HoldingRow
+}
+
+// DigestFailure names a portfolio whose latest run failed.
+type DigestFailure struct {
+	Name         string
+	ErrorMessage string
+}
+
+// DigestPayload is everything a digest email shows. Returns carries one row
+// per portfolio, labeled with the portfolio's name.
+type DigestPayload struct {
+	Title       string
+	Subtitle    string
+	LogoDataURL string
+	Brand       Brand // set by the Renderer
+
+	TotalValue string
+	Failures   []DigestFailure
+	Returns    []ReturnsRow
+	Portfolios []DigestPortfolio
+
+	PortfoliosURL  string
+	UnsubscribeURL string
 }
 
-// FormatReturnPct formats a fractional return (e.g. 0.034) as "+3.40%" and
-// returns the appropriate color string for light-mode rendering. A value that
-// rounds to zero at two-decimal precision renders as "0.00%" with no sign and
-// a neutral color, so a tiny move isn't dressed up as a gain or a loss.
+// RenderDigest renders p with the default renderer in en-US.
+func RenderDigest(p DigestPayload) (string, string, error) {
+	return defaultRenderer.RenderDigest(p, defaultRenderer.DefaultLocale())
+}
+
+// Plaintext renders only the text part of p in en-US, for channels that
+// cannot show HTML.
+func Plaintext(p Payload) string { return buildPlaintext(p, defaultRenderer.DefaultLocale()) }
+
+// FormatDelta formats a value change since lastSentAt, relative to now, in
+// en-US. See Locale.FormatDelta.
+func FormatDelta(currentValue, previousValue float64, lastSentAt, now time.Time) (deltaPct, deltaAbs, color, since string, hasDelta bool) {
+	return defaultRenderer.DefaultLocale().FormatDelta(currentValue, previousValue, lastSentAt, now)
+}
+
+// FormatMoneyVal formats a whole-dollar amount with en-US digit grouping and
+// no currency symbol.
+func FormatMoneyVal(v float64) string { return defaultRenderer.DefaultLocale().Number(v, 0) }
+
+// FormatReturnPct formats a fractional return in en-US. See
+// Locale.FormatReturnPct.
 func FormatReturnPct(v float64) (pct, color string) {
-	rounded := math.Round(v*10000) / 100 // percent, two decimal places
-	if rounded == 0 {
-		return "0.00%", "#94a3b8"
-	}
-	sign := "+"
-	if rounded < 0 {
-		sign = "-"
-		rounded = -rounded
-	}
-	pct = fmt.Sprintf("%s%.2f%%", sign, rounded)
-	if sign == "+" {
-		color = "#16a34a"
-	} else {
-		color = "#dc2626"
-	}
-	return
+	return defaultRenderer.DefaultLocale().FormatReturnPct(v)
 }
 
 // LogoDataURL returns the embedded logo as a data URL string.
 func LogoDataURL() string { return logoDataURL }
 
-func buildPlaintext(p Payload) string {
+func buildPlaintext(p Payload, loc *Locale) string {
 	var b strings.Builder
 	b.WriteString(p.PortfolioName + " — " + p.RunDate + "\n\n")
 	if !p.Success {
-		b.WriteString("ERROR: " + p.ErrorMessage + "\n")
+		b.WriteString(loc.T("text.error", p.ErrorMessage) + "\n")
 		return b.String()
 	}
-	b.WriteString("Portfolio Value: " + p.CurrentValue + "\n")
+	if len(p.Triggers) > 0 {
+		b.WriteString(loc.T("what_happened") + ":\n")
+		for _, t := range p.Triggers {
+			b.WriteString("  - " + t + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(loc.T("portfolio_value") + ": " + p.CurrentValue + "\n")
 	if p.HasDelta {
-		fmt.Fprintf(&b, "Change: %s (%s) since %s\n", p.DeltaPct, p.DeltaAbs, p.SinceLabel)
+		b.WriteString(loc.T("text.change", p.DeltaPct, p.DeltaAbs, p.SinceLabel) + "\n")
 	}
 	b.WriteString("\n")
 	if len(p.Returns) > 0 {
-		fmt.Fprintf(&b, "%-12s %7s %7s %7s %7s %7s\n", "", "DAY", "WTD", "MTD", "YTD", "1Y")
-		for _, row := range p.Returns {
-			fmt.Fprintf(&b, "%-12s %7s %7s %7s %7s %7s\n",
-				row.Label, row.Day.Pct, row.Wtd.Pct, row.Mtd.Pct, row.Ytd.Pct, row.OneYear.Pct)
-		}
-		b.WriteString("\n")
+		writeReturnsText(&b, p.Returns, 12, loc)
 	}
 	if len(p.Trades) == 0 {
-		b.WriteString("No trades required.\n\n")
+		b.WriteString(loc.T("trades.none") + ".\n\n")
 	} else {
 		if p.TradesDate != "" {
-			b.WriteString("Trades to Execute (" + p.TradesDate + "):\n")
+			b.WriteString(loc.T("trades.title") + " (" + p.TradesDate + "):\n")
 		} else {
-			b.WriteString("Trades to Execute:\n")
+			b.WriteString(loc.T("trades.title") + ":\n")
 		}
 		for _, tr := range p.Trades {
-			fmt.Fprintf(&b, "  %s %s %s shares (~%s)\n", tr.Action, tr.Ticker, tr.Shares, tr.Value)
+			b.WriteString("  " + loc.T("text.trade", tr.Action, tr.Ticker, tr.Shares, tr.Value) + "\n")
 		}
 		b.WriteString("\n")
 	}
 	if len(p.Holdings) > 0 {
-		b.WriteString("Current Holdings:\n")
+		b.WriteString(loc.T("holdings.title") + ":\n")
 		for _, h := range p.Holdings {
-			fmt.Fprintf(&b, "  %s  %s shares  %s  %s%%\n", h.Ticker, h.Shares, h.Value, h.WeightPct)
+			b.WriteString("  " + loc.T("text.holding", h.Ticker, h.Shares, h.Value, h.WeightPct) + "\n")
 		}
 		b.WriteString("\n")
 	}
 	if p.PortfolioURL != "" {
-		b.WriteString("View portfolio: " + p.PortfolioURL + "\n")
+		b.WriteString(loc.T("view_portfolio") + ": " + p.PortfolioURL + "\n")
+	}
+	if p.UnsubscribeURL != "" {
+		b.WriteString(loc.T("unsubscribe") + ": " + p.UnsubscribeURL + "\n")
+	}
+	return b.String()
+}
+
+// writeReturnsText writes the returns grid as fixed-width text, with the row
+// labels padded to labelWidth.
+func writeReturnsText(b *strings.Builder, rows []ReturnsRow, labelWidth int, loc *Locale) {
+	fmt.Fprintf(b, "%-*s %7s %7s %7s %7s %7s\n", labelWidth, "",
+		strings.ToUpper(loc.T("window.day")), strings.ToUpper(loc.T("window.wtd")),
+		strings.ToUpper(loc.T("window.mtd")), strings.ToUpper(loc.T("window.ytd")),
+		strings.ToUpper(loc.T("window.1y")))
+	for _, row := range rows {
+		fmt.Fprintf(b, "%-*s %7s %7s %7s %7s %7s\n", labelWidth,
+			row.Label, row.Day.Pct, row.Wtd.Pct, row.Mtd.Pct, row.Ytd.Pct, row.OneYear.Pct)
+	}
+	b.WriteString("\n")
+}
+
+func buildDigestPlaintext(p DigestPayload, loc *Locale) string {
+	var b strings.Builder
+	b.WriteString(p.Title + " — " + p.Subtitle + "\n\n")
+	b.WriteString(loc.T("digest.total_value") + ": " + p.TotalValue + "\n\n")
+	if len(p.Failures) > 0 {
+		b.WriteString(loc.T("digest.failed_runs") + ":\n")
+		for _, f := range p.Failures {
+			b.WriteString("  - " + f.Name + ": " + f.ErrorMessage + "\n")
+		}
+		b.WriteString("\n")
+	}
+	if len(p.Returns) > 0 {
+		writeReturnsText(&b, p.Returns, 24, loc)
+	}
+	for _, port := range p.Portfolios {
+		b.WriteString(port.Name + ": " + port.Value + "\n")
+		if len(port.Trades) > 0 {
+			b.WriteString("  " + loc.T("digest.upcoming_trades") + " (" + port.TradesDate + "):\n")
+			for _, tr := range port.Trades {
+				b.WriteString("    " + loc.T("text.trade", tr.Action, tr.Ticker, tr.Shares, tr.Value) + "\n")
+			}
+		}
+		if len(port.Holdings) > 0 {
+			b.WriteString("  " + loc.T("digest.top_holdings") + ":\n")
+			for _, h := range port.Holdings {
+				b.WriteString("    " + loc.T("text.holding", h.Ticker, h.Shares, h.Value, h.WeightPct) + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	if p.PortfoliosURL != "" {
+		b.WriteString(loc.T("view_portfolios") + ": " + p.PortfoliosURL + "\n")
 	}
 	if p.UnsubscribeURL != "" {
-		b.WriteString("Unsubscribe: " + p.UnsubscribeURL + "\n")
+		b.WriteString(loc.T("unsubscribe") + ": " + p.UnsubscribeURL + "\n")
 	}
 	return b.String()
 }
@@ -104,6 +104,46 @@ func TestRenderSuccess(t *testing.T) {
 	}
 }
 
+func TestRenderSuccessTriggers(t *testing.T) {
+	p := successPayload()
+	html, text, err := email.Render(p)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if strings.Contains(html, "What happened") || strings.Contains(text, "What happened") {
+		t.Error("cadence email should not render a What happened section")
+	}
+
+	p.Triggers = []string{"Drawdown reached 12.30% (alert at 10.00%)"}
+	html, text, err = email.Render(p)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	for _, want := range []string{"What happened", "Drawdown reached 12.30% (alert at 10.00%)"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("triggered HTML missing %q", want)
+		}
+		if !strings.Contains(text, want) {
+			t.Errorf("triggered text missing %q", want)
+		}
+	}
+}
+
+func TestRenderEscapesTriggersInHTML(t *testing.T) {
+	p := successPayload()
+	p.Triggers = []string{"New trades predicted: <b>split</b> VTI"}
+	html, text, err := email.Render(p)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if strings.Contains(html, "<b>split</b>") || !strings.Contains(html, "&lt;b&gt;split&lt;/b&gt;") {
+		t.Error("trigger text should be escaped in the HTML part")
+	}
+	if !strings.Contains(text, "<b>split</b>") {
+		t.Error("the text part should carry the trigger verbatim")
+	}
+}
+
 func TestRenderFailure(t *testing.T) {
 	p := email.Payload{
 		PortfolioName:  "My Portfolio",
@@ -127,6 +167,76 @@ func TestRenderFailure(t *testing.T) {
 	}
 }
 
+func TestRenderConfirmation(t *testing.T) {
+	html, text, err := email.RenderConfirmation(email.Confirmation{
+		PortfolioName: "Growth & Income",
+		Recipient:     "<script>@example.com",
+		ConfirmURL:    "https://www.pennyvault.com/api/alerts/confirm?token=abc",
+		ExpiresOn:     "January 12, 2026",
+	})
+	if err != nil {
+		t.Fatalf("RenderConfirmation: %v", err)
+	}
+	for _, want := range []string{"Growth &amp; Income", "&lt;script&gt;@example.com", "confirm?token=abc", "January 12, 2026"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("confirmation HTML missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("confirmation HTML did not escape the recipient")
+	}
+	if !strings.Contains(text, "https://www.pennyvault.com/api/alerts/confirm?token=abc") {
+		t.Errorf("confirmation plaintext missing link: %s", text)
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	p := email.DigestPayload{
+		Title:      "Daily Digest",
+		Subtitle:   "2 portfolios · as of October 16, 2026",
+		TotalValue: "$250,000",
+		Failures:   []email.DigestFailure{{Name: "Momentum", ErrorMessage: "strategy binary exited with code 1"}},
+		Returns: []email.ReturnsRow{{
+			Label: "Growth",
+			Day:   email.ReturnCell{Pct: "+1.20%", Color: "#16a34a"},
+			Wtd:   email.ReturnCell{Pct: "+3.30%", Color: "#16a34a"},
+			Mtd:   email.ReturnCell{Pct: "-0.70%", Color: "#dc2626"},
+		}},
+		Portfolios: []email.DigestPortfolio{
+			{
+				Name:       "Growth",
+				Value:      "$150,000",
+				TradesDate: "October 30, 2026",
+				Trades:     []email.TradeRow{{Ticker: "VTI", Action: "Buy", Shares: "12", Value: "$3,400"}},
+				Holdings:   []email.HoldingRow{{Ticker: "QQQ", Shares: "100", WeightPct: "60.0", Value: "$90,000"}},
+			},
+			{Name: "Momentum", Value: "$100,000"},
+		},
+		PortfoliosURL:  "https://www.pennyvault.com/portfolios",
+		UnsubscribeURL: "https://www.pennyvault.com/api/alerts/unsubscribe?token=abc",
+	}
+	html, text, err := email.RenderDigest(p)
+	if err != nil {
+		t.Fatalf("RenderDigest: %v", err)
+	}
+	for _, want := range []string{
+		"Daily Digest", "$250,000", "Momentum: strategy binary exited with code 1",
+		"+1.20%", "October 30, 2026", "VTI", "QQQ", "View Portfolios", "unsubscribe?token=abc",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("digest HTML missing %q", want)
+		}
+	}
+	if strings.Count(html, "Upcoming trades") != 1 {
+		t.Error("digest HTML should show trades only for the portfolio that has them")
+	}
+	for _, want := range []string{"Total Value: $250,000", "Buy VTI 12 shares", "Momentum: $100,000"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("digest plaintext missing %q:\n%s", want, text)
+		}
+	}
+}
+
 func TestFormatDelta(t *testing.T) {
 	lastSent := time.Date(2026, 4, 14, 0, 0, 0, 0, time.UTC)
 	now := time.Date(2026, 4, 21, 0, 0, 0, 0, time.UTC)
@@ -102,33 +102,32 @@ func strategyFiresOn(spec string, t time.Time) bool {
 // alert has already gone out for the current ET day.
 func isDue(a Alert, strategySchedule string, now time.Time) bool {
 	nowET := now.In(nyseLoc)
+	return cadenceFires(a.Frequency, strategySchedule, nowET) && !sentToday(a.LastSentAt, nowET)
+}
 
-	switch a.Frequency {
+// cadenceFires reports whether frequency calls for a send on the ET day of
+// nowET.
+func cadenceFires(frequency, strategySchedule string, nowET time.Time) bool {
+	switch frequency {
 	case FrequencyScheduledRun:
-		if !strategyFiresOn(strategySchedule, nowET) {
-			return false
-		}
+		return strategyFiresOn(strategySchedule, nowET)
 	case FrequencyDaily:
-		if !isTradingDay(nowET) {
-			return false
-		}
+		return isTradingDay(nowET)
 	case FrequencyWeekly:
-		if !isLastTradingDayOfWeek(nowET) {
-			return false
-		}
+		return isLastTradingDayOfWeek(nowET)
 	case FrequencyMonthly:
-		if !isLastTradingDayOfMonth(nowET) {
-			return false
-		}
-	default:
-		return false
+		return isLastTradingDayOfMonth(nowET)
 	}
+	return false
+}
 
-	if a.LastSentAt == nil {
-		return true
+// sentToday reports whether lastSentAt falls on the same ET day as nowET.
+func sentToday(lastSentAt *time.Time, nowET time.Time) bool {
+	if lastSentAt == nil {
+		return false
 	}
 	today := time.Date(nowET.Year(), nowET.Month(), nowET.Day(), 0, 0, 0, 0, nyseLoc)
-	last := a.LastSentAt.In(nyseLoc)
+	last := lastSentAt.In(nyseLoc)
 	lastDay := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, nyseLoc)
-	return today.After(lastDay)
+	return !today.After(lastDay)
 }
@@ -19,13 +19,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html/template"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v3"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 
+	"github.com/penny-vault/pv-api/alert/channel"
+	"github.com/penny-vault/pv-api/alert/email"
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/etag"
 	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/quota"
 	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
 )
 
 // PortfolioReader is the subset of portfolio.Store the alert package needs.
@@ -38,10 +50,30 @@ type EmailSummarizer interface {
 	SendSummary(ctx context.Context, portfolioID uuid.UUID, recipient string) error
 }
 
+// RecipientConfirmer sends the double opt-in emails for new alert
+// recipients.
+type RecipientConfirmer interface {
+	ConfirmationsEnabled() bool
+	SendConfirmations(ctx context.Context, a Alert, recipients []string) error
+}
+
+// confirmationsUnavailableDetail explains a 503 for new email recipients on
+// a server that cannot send the confirmation that opts them in.
+const confirmationsUnavailableDetail = "email recipients must confirm before they are mailed, and this server " +
+	"cannot send confirmation emails (unsubscribe_secret or app_base_url unset); use channels instead"
+
+// staleETagDetail explains a 412 from an If-Match that no longer matches.
+const staleETagDetail = "the alert has changed since it was read; fetch it again and retry"
+
 type AlertHandler struct {
 	portfolios        PortfolioReader
 	alerts            Store
 	summarizer        EmailSummarizer
+	confirmer         RecipientConfirmer
+	digests           DigestStore
+	workspaces        workspace.Roles
+	limits            quota.Limits
+	audit             audit.Recorder
 	unsubscribeSecret string
 }
 
@@ -49,48 +81,115 @@ func NewAlertHandler(portfolios PortfolioReader, alerts Store) *AlertHandler {
 	return &AlertHandler{portfolios: portfolios, alerts: alerts}
 }
 
+// NewAlertHandlerWithChecker builds a handler that can email summaries. When
+// summarizer also implements RecipientConfirmer (the Checker does), new
+// recipients must confirm before they are emailed.
 func NewAlertHandlerWithChecker(portfolios PortfolioReader, alerts Store, summarizer EmailSummarizer, unsubscribeSecret string) *AlertHandler {
-	return &AlertHandler{
+	h := &AlertHandler{
 		portfolios:        portfolios,
 		alerts:            alerts,
 		summarizer:        summarizer,
 		unsubscribeSecret: unsubscribeSecret,
 	}
+	if rc, ok := summarizer.(RecipientConfirmer); ok {
+		h.confirmer = rc
+	}
+	return h
+}
+
+// WithWorkspaces lets requests manage the alerts of a workspace's
+// portfolios, subject to the caller's role there.
+func (h *AlertHandler) WithWorkspaces(r workspace.Roles) *AlertHandler {
+	h.workspaces = r
+	return h
+}
+
+// WithQuotas caps the recipients on one alert.
+func (h *AlertHandler) WithQuotas(limits quota.Limits) *AlertHandler {
+	h.limits = limits
+	return h
+}
+
+// WithAudit records alert creates, updates, deletes and unsubscribes in
+// the audit trail.
+func (h *AlertHandler) WithAudit(r audit.Recorder) *AlertHandler {
+	h.audit = r
+	return h
+}
+
+// WithDigests lets the unsubscribe and confirmation links in digest emails,
+// which carry a digest ID in place of an alert ID, resolve against digests.
+func (h *AlertHandler) WithDigests(digests DigestStore) *AlertHandler {
+	h.digests = digests
+	return h
 }
 
 func (h *AlertHandler) Create(c fiber.Ctx) error {
-	ownerSub, p, err := h.resolvePortfolio(c)
-	if err != nil {
+	p, ok, err := h.resolvePortfolio(c, workspace.RoleEditor)
+	if !ok {
 		return err
 	}
-	_ = ownerSub
 
 	var body struct {
-		Frequency  string   `json:"frequency"`
-		Recipients []string `json:"recipients"`
+		Frequency        string            `json:"frequency"`
+		Recipients       []string          `json:"recipients"`
+		RecipientLocales map[string]string `json:"recipientLocales"`
+		Conditions       []Condition       `json:"conditions"`
+		Channels         []channel.Target  `json:"channels"`
 	}
 	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
 		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid body", err.Error())
 	}
 	if !validFrequency(body.Frequency) {
 		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid frequency",
-			"frequency must be one of: scheduled_run, daily, weekly, monthly")
+			"frequency must be one of: scheduled_run, daily, weekly, monthly, on_condition")
 	}
-	if len(body.Recipients) == 0 {
+	if len(body.Recipients) == 0 && len(body.Channels) == 0 {
 		return writeProblem(c, fiber.StatusUnprocessableEntity, "recipients required",
-			"at least one recipient is required")
+			"at least one recipient or channel is required")
+	}
+	if err := h.limits.CheckRecipients(len(body.Recipients)); err != nil {
+		status := quota.Status(err)
+		return writeProblem(c, status, http.StatusText(status), err.Error())
+	}
+	if detail := conditionsProblem(body.Frequency, body.Conditions); detail != "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid conditions", detail)
+	}
+	if detail := channelsProblem(body.Channels); detail != "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid channels", detail)
+	}
+	locales, detail := recipientLocales(body.Recipients, body.RecipientLocales, nil)
+	if detail != "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid recipientLocales", detail)
 	}
 
-	a, err := h.alerts.Create(c.Context(), p.ID, body.Frequency, body.Recipients)
+	// Recipients are never trusted as entered: each one opts in through a
+	// confirmation email, so without a way to send one they are refused.
+	if len(body.Recipients) > 0 && !h.confirmationsEnabled() {
+		return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable", confirmationsUnavailableDetail)
+	}
+	a, err := h.alerts.Create(c.Context(), Alert{
+		PortfolioID: p.ID,
+		Frequency:   body.Frequency,
+		Recipients:  body.Recipients,
+		Locales:     locales,
+		Conditions:  body.Conditions,
+		Channels:    body.Channels,
+	})
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
+	h.sendConfirmations(c.Context(), a, a.PendingRecipients())
+	e := audit.FromRequest(c, audit.ActionAlertCreate)
+	e.Changes = audit.Diff(nil, auditState(a))
+	h.record(c.Context(), e, p, a)
+	c.Set(fiber.HeaderETag, etag.FromTime(a.UpdatedAt))
 	return c.Status(fiber.StatusCreated).JSON(toView(a))
 }
 
 func (h *AlertHandler) List(c fiber.Ctx) error {
-	_, p, err := h.resolvePortfolio(c)
-	if err != nil {
+	p, ok, err := h.resolvePortfolio(c, workspace.RoleViewer)
+	if !ok {
 		return err
 	}
 	alerts, err := h.alerts.List(c.Context(), p.ID)
@@ -104,29 +203,39 @@ func (h *AlertHandler) List(c fiber.Ctx) error {
 	return c.JSON(out)
 }
 
-func (h *AlertHandler) Update(c fiber.Ctx) error {
-	_, p, err := h.resolvePortfolio(c)
-	if err != nil {
+// Get implements GET /portfolios/:slug/alerts/:alertId. Its ETag guards
+// later updates and deletes through If-Match.
+func (h *AlertHandler) Get(c fiber.Ctx) error {
+	_, a, ok, err := h.resolveAlert(c, workspace.RoleViewer)
+	if !ok {
 		return err
 	}
-	alertID, err := uuid.Parse(c.Params("alertId"))
-	if err != nil {
-		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid alertId")
+	if etag.NotModified(c, etag.FromTime(a.UpdatedAt)) {
+		return c.SendStatus(fiber.StatusNotModified)
 	}
-	existing, err := h.alerts.Get(c.Context(), alertID)
-	if errors.Is(err, ErrNotFound) {
-		return writeProblem(c, fiber.StatusNotFound, "Not Found", "alert not found")
+	return c.JSON(toView(a))
+}
+
+func (h *AlertHandler) Update(c fiber.Ctx) error {
+	p, existing, ok, err := h.resolveAlert(c, workspace.RoleEditor)
+	if !ok {
+		return err
 	}
-	if err != nil {
-		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	alertID := existing.ID
+	if !etag.Match(c, etag.FromTime(existing.UpdatedAt)) {
+		return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", staleETagDetail)
 	}
-	if existing.PortfolioID != p.ID {
-		return writeProblem(c, fiber.StatusNotFound, "Not Found", "alert not found")
+	var guard *time.Time
+	if etag.Conditional(c) {
+		guard = &existing.UpdatedAt
 	}
 
 	var body struct {
-		Frequency  string   `json:"frequency"`
-		Recipients []string `json:"recipients"`
+		Frequency        string            `json:"frequency"`
+		Recipients       *[]string         `json:"recipients"`
+		RecipientLocales map[string]string `json:"recipientLocales"`
+		Conditions       *[]Condition      `json:"conditions"`
+		Channels         *[]channel.Target `json:"channels"`
 	}
 	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
 		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid body", err.Error())
@@ -136,25 +245,167 @@ func (h *AlertHandler) Update(c fiber.Ctx) error {
 	if body.Frequency != "" {
 		if !validFrequency(body.Frequency) {
 			return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid frequency",
-				"frequency must be one of: scheduled_run, daily, weekly, monthly")
+				"frequency must be one of: scheduled_run, daily, weekly, monthly, on_condition")
 		}
 		freq = body.Frequency
 	}
 	recips := existing.Recipients
-	if len(body.Recipients) > 0 {
-		recips = body.Recipients
+	if body.Recipients != nil {
+		recips = *body.Recipients
+	}
+	targets := existing.Channels
+	if body.Channels != nil {
+		targets = keepSecrets(*body.Channels, existing.Channels)
+	}
+	if len(recips) == 0 && len(targets) == 0 {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "recipients required",
+			"at least one recipient or channel is required")
+	}
+	if err := h.limits.CheckRecipients(len(recips)); err != nil {
+		status := quota.Status(err)
+		return writeProblem(c, status, http.StatusText(status), err.Error())
+	}
+	if detail := channelsProblem(targets); detail != "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid channels", detail)
+	}
+	locales, detail := recipientLocales(recips, body.RecipientLocales, existing.Locales)
+	if detail != "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid recipientLocales", detail)
+	}
+	// Conditions carry over only while the alert stays on_condition; moving to
+	// a cadence without sending conditions drops them.
+	conds := existing.Conditions
+	if body.Conditions != nil {
+		conds = *body.Conditions
+	} else if freq != FrequencyOnCondition {
+		conds = nil
+	}
+	if detail := conditionsProblem(freq, conds); detail != "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid conditions", detail)
 	}
 
-	updated, err := h.alerts.Update(c.Context(), alertID, freq, recips)
+	// Confirmations survive an update for recipients that stay on the alert;
+	// anyone added has to confirm, which needs confirmation emails.
+	var verified []string
+	for _, r := range recips {
+		if existing.Verified(r) {
+			verified = append(verified, r)
+		} else if !slices.Contains(existing.Recipients, r) && !h.confirmationsEnabled() {
+			return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable", confirmationsUnavailableDetail)
+		}
+	}
+	updated, err := h.alerts.Update(c.Context(), Alert{
+		ID:                 alertID,
+		Frequency:          freq,
+		Recipients:         recips,
+		VerifiedRecipients: verified,
+		Locales:            locales,
+		Conditions:         conds,
+		Channels:           targets,
+	}, guard)
+	if errors.Is(err, ErrStale) {
+		return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", staleETagDetail)
+	}
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
+	var added []string
+	for _, r := range updated.PendingRecipients() {
+		if !slices.Contains(existing.Recipients, r) {
+			added = append(added, r)
+		}
+	}
+	h.sendConfirmations(c.Context(), updated, added)
+	c.Set(fiber.HeaderETag, etag.FromTime(updated.UpdatedAt))
+	if changes := audit.Diff(auditState(existing), auditState(updated)); len(changes) > 0 {
+		e := audit.FromRequest(c, audit.ActionAlertUpdate)
+		e.Changes = changes
+		h.record(c.Context(), e, p, updated)
+	}
 	return c.JSON(toView(updated))
 }
 
 func (h *AlertHandler) Delete(c fiber.Ctx) error {
-	_, p, err := h.resolvePortfolio(c)
+	p, existing, ok, err := h.resolveAlert(c, workspace.RoleEditor)
+	if !ok {
+		return err
+	}
+	alertID := existing.ID
+	if !etag.Match(c, etag.FromTime(existing.UpdatedAt)) {
+		return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", staleETagDetail)
+	}
+	var guard *time.Time
+	if etag.Conditional(c) {
+		guard = &existing.UpdatedAt
+	}
+	if err := h.alerts.Delete(c.Context(), alertID, guard); err != nil {
+		if errors.Is(err, ErrStale) {
+			return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", staleETagDetail)
+		}
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	e := audit.FromRequest(c, audit.ActionAlertDelete)
+	e.Changes = audit.Diff(auditState(existing), nil)
+	h.record(c.Context(), e, p, existing)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+const (
+	defaultDeliveryLimit = 50
+	maxDeliveryLimit     = 200
+)
+
+// Deliveries implements GET /portfolios/:slug/alerts/:alertId/deliveries:
+// the alert's recent delivery attempts, newest first.
+func (h *AlertHandler) Deliveries(c fiber.Ctx) error {
+	p, ok, err := h.resolvePortfolio(c, workspace.RoleViewer)
+	if !ok {
+		return err
+	}
+	alertID, err := uuid.Parse(c.Params("alertId"))
+	if err != nil {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid alertId")
+	}
+	limit := defaultDeliveryLimit
+	if v := c.Query("limit"); v != "" {
+		n, convErr := strconv.Atoi(v)
+		if convErr != nil || n < 1 || n > maxDeliveryLimit {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid limit",
+				fmt.Sprintf("limit must be an integer between 1 and %d", maxDeliveryLimit))
+		}
+		limit = n
+	}
+	existing, err := h.alerts.Get(c.Context(), alertID)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "alert not found")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if existing.PortfolioID != p.ID {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "alert not found")
+	}
+
+	deliveries, err := h.alerts.ListDeliveries(c.Context(), alertID, limit)
 	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	out := make([]deliveryView, 0, len(deliveries))
+	for _, d := range deliveries {
+		out = append(out, toDeliveryView(d))
+	}
+	return c.JSON(out)
+}
+
+// ResendConfirmations implements POST /portfolios/:slug/alerts/:alertId/confirmations:
+// it emails a fresh confirmation link to every recipient still pending.
+func (h *AlertHandler) ResendConfirmations(c fiber.Ctx) error {
+	if !h.confirmationsEnabled() {
+		return writeProblem(c, fiber.StatusServiceUnavailable, "confirmations not configured",
+			"recipient confirmation is not configured on this server")
+	}
+	p, ok, err := h.resolvePortfolio(c, workspace.RoleEditor)
+	if !ok {
 		return err
 	}
 	alertID, err := uuid.Parse(c.Params("alertId"))
@@ -171,23 +422,23 @@ func (h *AlertHandler) Delete(c fiber.Ctx) error {
 	if existing.PortfolioID != p.ID {
 		return writeProblem(c, fiber.StatusNotFound, "Not Found", "alert not found")
 	}
-	if err := h.alerts.Delete(c.Context(), alertID); err != nil {
+	if err := h.confirmer.SendConfirmations(c.Context(), existing, existing.PendingRecipients()); err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
-	return c.SendStatus(fiber.StatusNoContent)
+	return c.Status(fiber.StatusAccepted).JSON(toView(existing))
 }
 
 // SendSummary implements POST /portfolios/:slug/email-summary.
 func (h *AlertHandler) SendSummary(c fiber.Ctx) error {
 	// nil summarizer: handler built without email support (e.g. tests via NewAlertHandler).
-	// ErrEmailNotConfigured: non-nil summarizer but no Mailgun API key set at runtime.
+	// ErrEmailNotConfigured: non-nil summarizer but no email transport configured.
 	// Both result in 503.
 	if h.summarizer == nil {
 		return writeProblem(c, fiber.StatusServiceUnavailable, "email not configured",
 			"email sending is not configured on this server")
 	}
-	_, p, err := h.resolvePortfolio(c)
-	if err != nil {
+	p, ok, err := h.resolvePortfolio(c, workspace.RoleEditor)
+	if !ok {
 		return err
 	}
 	var body struct {
@@ -202,7 +453,7 @@ func (h *AlertHandler) SendSummary(c fiber.Ctx) error {
 	}
 	if sendErr := h.summarizer.SendSummary(c.Context(), p.ID, body.Recipient); errors.Is(sendErr, ErrEmailNotConfigured) {
 		return writeProblem(c, fiber.StatusServiceUnavailable, "email not configured",
-			"Mailgun API key is not set")
+			"no email transport is configured")
 	} else if sendErr != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", sendErr.Error())
 	}
@@ -220,8 +471,14 @@ func (h *AlertHandler) Unsubscribe(c fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).SendString("Invalid or expired unsubscribe link.")
 	}
-	_, err = h.alerts.Get(c.Context(), alertID)
+	a, err := h.alerts.Get(c.Context(), alertID)
 	if errors.Is(err, ErrNotFound) {
+		if h.digests != nil {
+			rmErr := h.digests.RemoveDigestRecipient(c.Context(), alertID, recipient)
+			if rmErr != nil && !errors.Is(rmErr, ErrDigestNotFound) {
+				return c.Status(fiber.StatusInternalServerError).SendString("Something went wrong.")
+			}
+		}
 		return c.Status(fiber.StatusOK).Type("html").
 			SendString("<html><body><p>You have been unsubscribed.</p></body></html>")
 	}
@@ -231,6 +488,23 @@ func (h *AlertHandler) Unsubscribe(c fiber.Ctx) error {
 	if err := h.alerts.RemoveRecipient(c.Context(), alertID, recipient); err != nil && !errors.Is(err, ErrNotFound) {
 		return c.Status(fiber.StatusInternalServerError).SendString("Something went wrong.")
 	}
+	if slices.Contains(a.Recipients, recipient) {
+		portfolioID := a.PortfolioID
+		rid, _ := c.Locals(types.RequestIDKey{}).(string)
+		actor := "email:" + recipient
+		remaining := slices.DeleteFunc(slices.Clone(a.Recipients), func(r string) bool { return r == recipient })
+		audit.Record(c.Context(), h.audit, audit.Entry{
+			Action:      audit.ActionAlertUnsubscribe,
+			Actor:       actor,
+			Subject:     actor,
+			PortfolioID: &portfolioID,
+			Target:      alertID.String(),
+			RequestID:   rid,
+			Changes: map[string]audit.Change{
+				"recipients": {Before: a.Recipients, After: remaining},
+			},
+		})
+	}
 	html := fmt.Sprintf(`<!DOCTYPE html>
 <html><head><meta charset="UTF-8"><title>Unsubscribed</title>
 <style>body{font-family:-apple-system,sans-serif;max-width:480px;margin:80px auto;padding:0 24px;color:#0f172a}
@@ -241,20 +515,158 @@ h1{color:#0ea5e9}p{color:#64748b}</style></head>
 	return c.Status(fiber.StatusOK).Type("html").SendString(html)
 }
 
-func (h *AlertHandler) resolvePortfolio(c fiber.Ctx) (string, portfolio.Portfolio, error) {
+// Confirm handles GET /api/alerts/confirm?token=<token>. It only renders a
+// page whose button POSTs the token back: mail scanners and link
+// prefetchers follow GET links, so a GET must not opt anyone in.
+// Unauthenticated — the HMAC token is the credential.
+func (h *AlertHandler) Confirm(c fiber.Ctx) error {
+	if h.unsubscribeSecret == "" {
+		return c.Status(fiber.StatusNotFound).SendString("Confirmation is not configured.")
+	}
+	token := string([]byte(c.Query("token")))
+	_, recipient, err := VerifyConfirmToken(h.unsubscribeSecret, token, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid or expired confirmation link.")
+	}
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html><head><meta charset="UTF-8"><title>Confirm</title>
+<style>body{font-family:-apple-system,sans-serif;max-width:480px;margin:80px auto;padding:0 24px;color:#0f172a}
+h1{color:#0ea5e9}p{color:#64748b}button{background:#0ea5e9;color:#fff;border:0;border-radius:6px;padding:10px 18px;font-size:15px}</style></head>
+<body><h1>Penny Vault</h1>
+<p>Send portfolio alerts to <strong>%s</strong>?</p>
+<form method="POST" action="confirm"><input type="hidden" name="token" value="%s">
+<button type="submit">Confirm</button></form>
+</body></html>`, template.HTMLEscapeString(recipient), template.HTMLEscapeString(token))
+	return c.Status(fiber.StatusOK).Type("html").SendString(html)
+}
+
+// ConfirmSubmit handles POST /api/alerts/confirm with a form-encoded token,
+// the submission of the page Confirm renders, and marks the recipient
+// confirmed. Unauthenticated — the HMAC token is the credential.
+func (h *AlertHandler) ConfirmSubmit(c fiber.Ctx) error {
+	if h.unsubscribeSecret == "" {
+		return c.Status(fiber.StatusNotFound).SendString("Confirmation is not configured.")
+	}
+	token := string([]byte(c.FormValue("token")))
+	alertID, recipient, err := VerifyConfirmToken(h.unsubscribeSecret, token, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).SendString("Invalid or expired confirmation link.")
+	}
+	err = h.alerts.VerifyRecipient(c.Context(), alertID, recipient)
+	if errors.Is(err, ErrNotFound) && h.digests != nil {
+		if err = h.digests.VerifyDigestRecipient(c.Context(), alertID, recipient); errors.Is(err, ErrDigestNotFound) {
+			err = ErrNotFound
+		}
+	}
+	if errors.Is(err, ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).
+			SendString("This alert no longer sends to this address.")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString("Something went wrong.")
+	}
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html><head><meta charset="UTF-8"><title>Confirmed</title>
+<style>body{font-family:-apple-system,sans-serif;max-width:480px;margin:80px auto;padding:0 24px;color:#0f172a}
+h1{color:#0ea5e9}p{color:#64748b}</style></head>
+<body><h1>Penny Vault</h1>
+<p><strong>%s</strong> will now receive portfolio alerts for alert <strong>%s</strong>.</p>
+</body></html>`, template.HTMLEscapeString(recipient), alertID)
+	return c.Status(fiber.StatusOK).Type("html").SendString(html)
+}
+
+// auditState is the part of an alert the audit trail follows. Channel
+// secrets never leave the alerts table.
+func auditState(a Alert) map[string]any {
+	v := toView(a)
+	return map[string]any{
+		"frequency":        a.Frequency,
+		"recipients":       a.Recipients,
+		"recipientLocales": a.Locales,
+		"conditions":       v.Conditions,
+		"channels":         v.Channels,
+	}
+}
+
+// record adds e, an action on alert a of portfolio p, to the audit trail.
+func (h *AlertHandler) record(ctx context.Context, e audit.Entry, p portfolio.Portfolio, a Alert) {
+	id := p.ID
+	e.OwnerSub = p.OwnerSub
+	e.PortfolioID = &id
+	e.PortfolioSlug = p.Slug
+	e.Target = a.ID.String()
+	audit.Record(ctx, h.audit, e)
+}
+
+// confirmationsEnabled reports whether new recipients start out pending.
+func (h *AlertHandler) confirmationsEnabled() bool {
+	return h.confirmer != nil && h.confirmer.ConfirmationsEnabled()
+}
+
+// sendConfirmations emails recipients their confirmation links. A failure is
+// logged rather than failing the request: the alert is saved and the owner
+// can resend.
+func (h *AlertHandler) sendConfirmations(ctx context.Context, a Alert, recipients []string) {
+	if !h.confirmationsEnabled() || len(recipients) == 0 {
+		return
+	}
+	if err := h.confirmer.SendConfirmations(ctx, a, recipients); err != nil {
+		log.Warn().Err(err).Stringer("alert_id", a.ID).Msg("alert: send confirmations")
+	}
+}
+
+// subject returns the authenticated caller, writing a 401 when there is none.
+func subject(c fiber.Ctx) (string, error) {
 	ownerSub, ok := c.Locals(types.AuthSubjectKey{}).(string)
 	if !ok || ownerSub == "" {
-		return "", portfolio.Portfolio{}, writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", "missing subject")
+		return "", writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", "missing subject")
+	}
+	return ownerSub, nil
+}
+
+// resolveAlert looks up the :alertId alert of the :slug portfolio, writing
+// the error response itself when ok is false.
+func (h *AlertHandler) resolveAlert(c fiber.Ctx, need workspace.Role) (portfolio.Portfolio, Alert, bool, error) {
+	p, ok, err := h.resolvePortfolio(c, need)
+	if !ok {
+		return portfolio.Portfolio{}, Alert{}, false, err
+	}
+	alertID, err := uuid.Parse(c.Params("alertId"))
+	if err != nil {
+		return portfolio.Portfolio{}, Alert{}, false, writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid alertId")
+	}
+	a, err := h.alerts.Get(c.Context(), alertID)
+	if errors.Is(err, ErrNotFound) || (err == nil && a.PortfolioID != p.ID) {
+		return portfolio.Portfolio{}, Alert{}, false, writeProblem(c, fiber.StatusNotFound, "Not Found", "alert not found")
+	}
+	if err != nil {
+		return portfolio.Portfolio{}, Alert{}, false, writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return p, a, true, nil
+}
+
+// resolvePortfolio loads the :slug portfolio, which the caller must be able
+// to act on with need. On failure it writes the problem response and
+// returns ok=false.
+func (h *AlertHandler) resolvePortfolio(c fiber.Ctx, need workspace.Role) (portfolio.Portfolio, bool, error) {
+	sub, err := subject(c)
+	if sub == "" {
+		return portfolio.Portfolio{}, false, err
+	}
+	ownerSub, err := workspace.Resolve(c, h.workspaces, sub, need)
+	if err != nil {
+		status := workspace.Status(err)
+		return portfolio.Portfolio{}, false, writeProblem(c, status, http.StatusText(status), err.Error())
 	}
 	slug := string([]byte(c.Params("slug")))
 	p, err := h.portfolios.Get(c.Context(), ownerSub, slug)
 	if errors.Is(err, portfolio.ErrNotFound) {
-		return "", portfolio.Portfolio{}, writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+		return portfolio.Portfolio{}, false, writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
 	}
 	if err != nil {
-		return "", portfolio.Portfolio{}, writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		return portfolio.Portfolio{}, false, writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
-	return ownerSub, p, nil
+	return p, true, nil
 }
 
 type alertView struct {
@@ -262,7 +674,33 @@ type alertView struct {
 	PortfolioID uuid.UUID `json:"portfolioId"`
 	Frequency   string    `json:"frequency"`
 	Recipients  []string  `json:"recipients"`
-	LastSentAt  *string   `json:"lastSentAt"`
+	// RecipientStatus reports, per recipient, whether they have confirmed.
+	RecipientStatus []recipientStatus `json:"recipientStatus"`
+	LastSentAt      *string           `json:"lastSentAt"`
+	Conditions      []Condition       `json:"conditions"`
+	Channels        []channelView     `json:"channels"`
+}
+
+type recipientStatus struct {
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	Locale string `json:"locale,omitempty"`
+}
+
+// Recipient statuses reported in alertView.RecipientStatus.
+const (
+	RecipientVerified = "verified"
+	RecipientPending  = "pending"
+)
+
+// channelView is a channel target as the API returns it: the webhook signing
+// secret is never echoed back, only whether one is set.
+type channelView struct {
+	Type   string `json:"type"`
+	URL    string `json:"url,omitempty"`
+	ChatID string `json:"chatId,omitempty"`
+	Topic  string `json:"topic,omitempty"`
+	Signed bool   `json:"signed,omitempty"`
 }
 
 func toView(a Alert) alertView {
@@ -271,6 +709,24 @@ func toView(a Alert) alertView {
 		PortfolioID: a.PortfolioID,
 		Frequency:   a.Frequency,
 		Recipients:  a.Recipients,
+		Conditions:  a.Conditions,
+	}
+	if v.Conditions == nil {
+		v.Conditions = []Condition{}
+	}
+	v.RecipientStatus = make([]recipientStatus, 0, len(a.Recipients))
+	for _, r := range a.Recipients {
+		status := RecipientPending
+		if a.Verified(r) {
+			status = RecipientVerified
+		}
+		v.RecipientStatus = append(v.RecipientStatus, recipientStatus{Email: r, Status: status, Locale: a.Locales[r]})
+	}
+	v.Channels = make([]channelView, 0, len(a.Channels))
+	for _, t := range a.Channels {
+		v.Channels = append(v.Channels, channelView{
+			Type: t.Type, URL: t.URL, ChatID: t.ChatID, Topic: t.Topic, Signed: t.Secret != "",
+		})
 	}
 	if a.LastSentAt != nil {
 		s := a.LastSentAt.Format("2006-01-02T15:04:05Z")
@@ -279,14 +735,131 @@ func toView(a Alert) alertView {
 	return v
 }
 
+// deliveryView is one outbox row as support and users see it. Message
+// bodies and channel credentials stay server-side.
+type deliveryView struct {
+	ID                uuid.UUID `json:"id"`
+	Channel           string    `json:"channel"`
+	Destination       string    `json:"destination"`
+	Event             string    `json:"event"`
+	Subject           string    `json:"subject"`
+	Status            string    `json:"status"`
+	Attempts          int       `json:"attempts"`
+	LastError         *string   `json:"lastError"`
+	ProviderMessageID *string   `json:"providerMessageId"`
+	CreatedAt         string    `json:"createdAt"`
+	NextAttemptAt     *string   `json:"nextAttemptAt"`
+	SentAt            *string   `json:"sentAt"`
+}
+
+func toDeliveryView(d Delivery) deliveryView {
+	v := deliveryView{
+		ID:                d.ID,
+		Channel:           d.Channel,
+		Destination:       d.Destination,
+		Event:             d.Event,
+		Subject:           d.Subject,
+		Status:            d.Status,
+		Attempts:          d.Attempts,
+		LastError:         d.LastError,
+		ProviderMessageID: d.ProviderMessageID,
+		CreatedAt:         d.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if d.Status == DeliveryPending || d.Status == DeliverySending {
+		s := d.NextAttemptAt.UTC().Format(time.RFC3339)
+		v.NextAttemptAt = &s
+	}
+	if d.SentAt != nil {
+		s := d.SentAt.UTC().Format(time.RFC3339)
+		v.SentAt = &s
+	}
+	return v
+}
+
 func validFrequency(f string) bool {
 	switch f {
-	case FrequencyScheduledRun, FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+	case FrequencyScheduledRun, FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyOnCondition:
 		return true
 	}
 	return false
 }
 
+// conditionsProblem returns why conds are unacceptable for an alert with
+// frequency freq, or "" when they are fine. on_condition alerts need at least
+// one condition; cadence alerts take none.
+func conditionsProblem(freq string, conds []Condition) string {
+	if freq != FrequencyOnCondition {
+		if len(conds) > 0 {
+			return "conditions are only allowed when frequency is on_condition"
+		}
+		return ""
+	}
+	if len(conds) == 0 {
+		return "an on_condition alert needs at least one condition"
+	}
+	if err := ValidateConditions(conds); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// recipientLocales merges the locales a request sets onto those prev already
+// held for recipients still listed, canonicalizing each tag. An empty tag
+// clears a recipient's locale. The string is the problem detail when a key
+// is not a recipient or a tag is malformed.
+func recipientLocales(recipients []string, given, prev map[string]string) (map[string]string, string) {
+	out := map[string]string{}
+	for r, tag := range prev {
+		if slices.Contains(recipients, r) {
+			out[r] = tag
+		}
+	}
+	for r, tag := range given {
+		if !slices.Contains(recipients, r) {
+			return nil, fmt.Sprintf("%s is not a recipient", r)
+		}
+		if tag == "" {
+			delete(out, r)
+			continue
+		}
+		canon, err := email.CanonicalLocale(tag)
+		if err != nil {
+			return nil, fmt.Sprintf("%s: %v", r, err)
+		}
+		out[r] = canon
+	}
+	return out, ""
+}
+
+// channelsProblem returns why targets are unacceptable, or "" when they are
+// all well-formed.
+func channelsProblem(targets []channel.Target) string {
+	for i, t := range targets {
+		if err := channel.Validate(t); err != nil {
+			return fmt.Sprintf("channels[%d]: %v", i, err)
+		}
+	}
+	return ""
+}
+
+// keepSecrets carries a webhook's signing secret over from prev when the
+// client resubmits the same URL without one. The API never returns secrets,
+// so a client editing other settings cannot send them back.
+func keepSecrets(next, prev []channel.Target) []channel.Target {
+	for i, t := range next {
+		if t.Type != channel.TypeWebhook || t.Secret != "" {
+			continue
+		}
+		for _, old := range prev {
+			if old.Type == channel.TypeWebhook && old.URL == t.URL {
+				next[i].Secret = old.Secret
+				break
+			}
+		}
+	}
+	return next
+}
+
 func writeProblem(c fiber.Ctx, status int, title, detail string) error {
 	return c.Status(status).JSON(fiber.Map{"title": title, "detail": detail})
 }
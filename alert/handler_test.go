@@ -18,7 +18,15 @@ package alert_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,10 +34,28 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/penny-vault/pv-api/alert"
+	"github.com/penny-vault/pv-api/alert/channel"
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/etag"
 	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/quota"
 	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
 )
 
+// publicResolver resolves every host to a public address so channel
+// validation in these tests never touches DNS.
+type publicResolver struct{}
+
+func (publicResolver) LookupNetIP(context.Context, string, string) ([]netip.Addr, error) {
+	return []netip.Addr{netip.MustParseAddr("93.184.215.14")}, nil
+}
+
+func TestMain(m *testing.M) {
+	channel.DefaultResolver = publicResolver{}
+	os.Exit(m.Run())
+}
+
 // stubPortfolio implements alert.PortfolioReader for tests.
 type stubPortfolio struct {
 	p   portfolio.Portfolio
@@ -43,7 +69,7 @@ func (s stubPortfolio) Get(_ context.Context, _, _ string) (portfolio.Portfolio,
 // stubAlertStore implements alert.Store with panics for unused methods.
 type stubAlertStore struct{}
 
-func (s stubAlertStore) Create(_ context.Context, _ uuid.UUID, _ string, _ []string) (alert.Alert, error) {
+func (s stubAlertStore) Create(_ context.Context, _ alert.Alert) (alert.Alert, error) {
 	panic("unexpected call")
 }
 func (s stubAlertStore) List(_ context.Context, _ uuid.UUID) ([]alert.Alert, error) {
@@ -52,16 +78,39 @@ func (s stubAlertStore) List(_ context.Context, _ uuid.UUID) ([]alert.Alert, err
 func (s stubAlertStore) Get(_ context.Context, _ uuid.UUID) (alert.Alert, error) {
 	panic("unexpected call")
 }
-func (s stubAlertStore) Update(_ context.Context, _ uuid.UUID, _ string, _ []string) (alert.Alert, error) {
+func (s stubAlertStore) Update(_ context.Context, _ alert.Alert, _ *time.Time) (alert.Alert, error) {
+	panic("unexpected call")
+}
+func (s stubAlertStore) Delete(_ context.Context, _ uuid.UUID, _ *time.Time) error {
 	panic("unexpected call")
 }
-func (s stubAlertStore) Delete(_ context.Context, _ uuid.UUID) error { panic("unexpected call") }
 func (s stubAlertStore) MarkSent(_ context.Context, _ uuid.UUID, _ time.Time, _ float64) error {
 	panic("unexpected call")
 }
 func (s stubAlertStore) RemoveRecipient(_ context.Context, _ uuid.UUID, _ string) error {
 	panic("unexpected call")
 }
+func (s stubAlertStore) SaveConditionState(_ context.Context, _ uuid.UUID, _ alert.ConditionState) error {
+	panic("unexpected call")
+}
+func (s stubAlertStore) VerifyRecipient(_ context.Context, _ uuid.UUID, _ string) error {
+	panic("unexpected call")
+}
+func (s stubAlertStore) ListDeliveries(_ context.Context, _ uuid.UUID, _ int) ([]alert.Delivery, error) {
+	panic("unexpected call")
+}
+
+// createStore records the alert passed to Create.
+type createStore struct {
+	stubAlertStore
+	created *alert.Alert
+}
+
+func (s createStore) Create(_ context.Context, a alert.Alert) (alert.Alert, error) {
+	a.ID = uuid.New()
+	*s.created = a
+	return a, nil
+}
 
 // stubSummarizer implements alert.EmailSummarizer for tests.
 type stubSummarizer struct{ err error }
@@ -76,6 +125,13 @@ func newTestApp(h *alert.AlertHandler) *fiber.App {
 		return c.Next()
 	})
 	app.Post("/portfolios/:slug/email-summary", h.SendSummary)
+	app.Post("/portfolios/:slug/alerts", h.Create)
+	app.Get("/portfolios/:slug/alerts/:alertId", h.Get)
+	app.Patch("/portfolios/:slug/alerts/:alertId", h.Update)
+	app.Delete("/portfolios/:slug/alerts/:alertId", h.Delete)
+	app.Get("/portfolios/:slug/alerts/:alertId/deliveries", h.Deliveries)
+	app.Get("/api/alerts/confirm", h.Confirm)
+	app.Post("/api/alerts/confirm", h.ConfirmSubmit)
 	return app
 }
 
@@ -167,3 +223,722 @@ func TestSendSummaryEmailNotConfigured(t *testing.T) {
 		t.Errorf("expected 503, got %d", resp.StatusCode)
 	}
 }
+
+func TestCreateAlertConditionValidation(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	cases := map[string]string{
+		"on_condition without conditions": `{"frequency":"on_condition","recipients":["a@b.com"]}`,
+		"conditions on a cadence alert":   `{"frequency":"daily","recipients":["a@b.com"],"conditions":[{"type":"drawdown","threshold":0.1}]}`,
+		"unknown type":                    `{"frequency":"on_condition","recipients":["a@b.com"],"conditions":[{"type":"moon_phase"}]}`,
+		"drawdown without threshold":      `{"frequency":"on_condition","recipients":["a@b.com"],"conditions":[{"type":"drawdown"}]}`,
+		"benchmark without window":        `{"frequency":"on_condition","recipients":["a@b.com"],"conditions":[{"type":"benchmark_underperformance","threshold":0.05}]}`,
+		"predicted_trade with threshold":  `{"frequency":"on_condition","recipients":["a@b.com"],"conditions":[{"type":"predicted_trade","threshold":0.05}]}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := alert.NewAlertHandler(stubPortfolio{p: port}, stubAlertStore{})
+			app := newTestApp(h)
+			req := httptest.NewRequest("POST", "/portfolios/my-port/alerts", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != fiber.StatusUnprocessableEntity {
+				t.Errorf("expected 422, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestCreateAlertWithConditions(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	var created alert.Alert
+	h := alert.NewAlertHandlerWithChecker(stubPortfolio{p: port}, createStore{created: &created},
+		stubConfirmer{sent: new([]string)}, "secret")
+	app := newTestApp(h)
+
+	req := httptest.NewRequest("POST", "/portfolios/my-port/alerts", bytes.NewBufferString(
+		`{"frequency":"on_condition","recipients":["a@b.com"],"conditions":[`+
+			`{"type":"drawdown","threshold":0.1},`+
+			`{"type":"benchmark_underperformance","threshold":0.05,"window":"3m"},`+
+			`{"type":"holding_change"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if created.Frequency != alert.FrequencyOnCondition || len(created.Conditions) != 3 {
+		t.Fatalf("unexpected alert stored: %+v", created)
+	}
+	if c := created.Conditions[1]; c.Type != alert.ConditionBenchmarkUnderperformance || c.Window != "3m" || *c.Threshold != 0.05 {
+		t.Errorf("unexpected benchmark condition: %+v", c)
+	}
+}
+
+// workspaceRoles is a fixed workspace.Roles for one workspace.
+type workspaceRoles struct {
+	id    uuid.UUID
+	roles map[string]workspace.Role
+}
+
+func (w workspaceRoles) Role(_ context.Context, id uuid.UUID, sub string) (workspace.Role, error) {
+	if r, ok := w.roles[sub]; ok && id == w.id {
+		return r, nil
+	}
+	return "", workspace.ErrNotFound
+}
+
+// ownedPortfolio implements alert.PortfolioReader for one owner.
+type ownedPortfolio struct{ p portfolio.Portfolio }
+
+func (o ownedPortfolio) Get(_ context.Context, ownerSub, slug string) (portfolio.Portfolio, error) {
+	if ownerSub != o.p.OwnerSub || slug != o.p.Slug {
+		return portfolio.Portfolio{}, portfolio.ErrNotFound
+	}
+	return o.p, nil
+}
+
+func TestCreateAlertWorkspaceRoles(t *testing.T) {
+	wsID := uuid.New()
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: workspace.OwnerSub(wsID), Slug: "my-port", Status: portfolio.StatusReady}
+	for role, want := range map[workspace.Role]int{
+		workspace.RoleViewer: fiber.StatusForbidden,
+		workspace.RoleEditor: fiber.StatusCreated,
+	} {
+		var created alert.Alert
+		h := alert.NewAlertHandlerWithChecker(ownedPortfolio{p: port}, createStore{created: &created},
+			stubConfirmer{sent: new([]string)}, "secret").
+			WithWorkspaces(workspaceRoles{id: wsID, roles: map[string]workspace.Role{"user-1": role}})
+		app := newTestApp(h)
+
+		req := httptest.NewRequest("POST", "/portfolios/my-port/alerts",
+			bytes.NewBufferString(`{"frequency":"daily","recipients":["a@b.com"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(workspace.HeaderWorkspace, wsID.String())
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: expected %d, got %d", role, want, resp.StatusCode)
+		}
+		if want == fiber.StatusCreated && created.PortfolioID != port.ID {
+			t.Errorf("%s: alert not created on the workspace portfolio", role)
+		}
+	}
+}
+
+func TestCreateAlertRecipientQuota(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	for body, want := range map[string]int{
+		`{"frequency":"daily","recipients":["a@b.com","c@d.com"]}`:           fiber.StatusCreated,
+		`{"frequency":"daily","recipients":["a@b.com","c@d.com","e@f.com"]}`: fiber.StatusForbidden,
+	} {
+		var created alert.Alert
+		h := alert.NewAlertHandlerWithChecker(stubPortfolio{p: port}, createStore{created: &created},
+			stubConfirmer{sent: new([]string)}, "secret").
+			WithQuotas(quota.Limits{AlertRecipients: 2})
+		app := newTestApp(h)
+		req := httptest.NewRequest("POST", "/portfolios/my-port/alerts", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("%s: expected %d, got %d", body, want, resp.StatusCode)
+		}
+	}
+}
+
+// auditLog is an audit.Recorder that keeps entries in memory.
+type auditLog []audit.Entry
+
+func (l *auditLog) Record(_ context.Context, e audit.Entry) error {
+	*l = append(*l, e)
+	return nil
+}
+
+func TestCreateAlertAudited(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	var created alert.Alert
+	log := &auditLog{}
+	h := alert.NewAlertHandler(stubPortfolio{p: port}, createStore{created: &created}).WithAudit(log)
+	app := newTestApp(h)
+	body := `{"frequency":"daily","channels":[{"type":"webhook","url":"https://example.com/hook","secret":"s3cret"}]}`
+	req := httptest.NewRequest("POST", "/portfolios/my-port/alerts", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(*log) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(*log))
+	}
+	e := (*log)[0]
+	if e.Action != audit.ActionAlertCreate || e.OwnerSub != "user-1" || e.PortfolioSlug != "my-port" || e.Target != created.ID.String() {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	raw, _ := json.Marshal(e.Changes)
+	if bytes.Contains(raw, []byte("s3cret")) {
+		t.Errorf("audit entry leaks the channel secret: %s", raw)
+	}
+}
+
+func TestCreateAlertChannelValidation(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	cases := map[string]string{
+		"no recipients or channels": `{"frequency":"daily","recipients":[],"channels":[]}`,
+		"plain-http webhook":        `{"frequency":"daily","channels":[{"type":"webhook","url":"http://example.com/hook"}]}`,
+		"slack url on another host": `{"frequency":"daily","channels":[{"type":"slack","url":"https://example.com/hook"}]}`,
+		"telegram without chat":     `{"frequency":"daily","channels":[{"type":"telegram"}]}`,
+		"unknown channel type":      `{"frequency":"daily","channels":[{"type":"pager"}]}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := alert.NewAlertHandler(stubPortfolio{p: port}, stubAlertStore{})
+			app := newTestApp(h)
+			req := httptest.NewRequest("POST", "/portfolios/my-port/alerts", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != fiber.StatusUnprocessableEntity {
+				t.Errorf("expected 422, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestCreateAlertChannelOnlyHidesSecret(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	var created alert.Alert
+	h := alert.NewAlertHandler(stubPortfolio{p: port}, createStore{created: &created})
+	app := newTestApp(h)
+
+	req := httptest.NewRequest("POST", "/portfolios/my-port/alerts", bytes.NewBufferString(
+		`{"frequency":"daily","channels":[`+
+			`{"type":"webhook","url":"https://example.com/hook","secret":"s3cret"},`+
+			`{"type":"ntfy","topic":"my-alerts"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if len(created.Channels) != 2 || created.Channels[0].Secret != "s3cret" {
+		t.Fatalf("unexpected channels stored: %+v", created.Channels)
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("s3cret")) {
+		t.Errorf("response leaked the webhook secret: %s", raw)
+	}
+	if !bytes.Contains(raw, []byte(`"signed":true`)) {
+		t.Errorf("response should mark the webhook as signed: %s", raw)
+	}
+}
+
+// deliveryStore serves one alert and its deliveries.
+type deliveryStore struct {
+	stubAlertStore
+	alert      alert.Alert
+	deliveries []alert.Delivery
+	limit      *int
+}
+
+func (s deliveryStore) Get(_ context.Context, id uuid.UUID) (alert.Alert, error) {
+	if id != s.alert.ID {
+		return alert.Alert{}, alert.ErrNotFound
+	}
+	return s.alert, nil
+}
+
+func (s deliveryStore) ListDeliveries(_ context.Context, _ uuid.UUID, limit int) ([]alert.Delivery, error) {
+	*s.limit = limit
+	return s.deliveries, nil
+}
+
+func TestListDeliveries(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	a := alert.Alert{ID: uuid.New(), PortfolioID: port.ID}
+	msgID := "<20260105.1@mg.example.com>"
+	lastErr := "mailgun: unexpected status 503"
+	sentAt := time.Date(2026, 1, 5, 21, 1, 0, 0, time.UTC)
+	var limit int
+	store := deliveryStore{alert: a, limit: &limit, deliveries: []alert.Delivery{
+		{ID: uuid.New(), AlertID: a.ID, Channel: alert.ChannelEmail, Destination: "a@b.com",
+			Status: alert.DeliverySent, Attempts: 2, LastError: nil, ProviderMessageID: &msgID,
+			HTMLBody: "<html>secret body</html>", CreatedAt: sentAt.Add(-time.Minute), SentAt: &sentAt},
+		{ID: uuid.New(), AlertID: a.ID, Channel: "webhook", Destination: "example.com",
+			Target: &channel.Target{Type: "webhook", URL: "https://example.com/h", Secret: "s3cret"},
+			Status: alert.DeliveryPending, Attempts: 1, LastError: &lastErr, NextAttemptAt: sentAt, CreatedAt: sentAt},
+	}}
+	app := newTestApp(alert.NewAlertHandler(stubPortfolio{p: port}, store))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/portfolios/my-port/alerts/"+a.ID.String()+"/deliveries?limit=10", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if limit != 10 {
+		t.Errorf("expected limit 10 passed to store, got %d", limit)
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0]["providerMessageId"] != msgID || got[0]["status"] != "sent" || got[0]["nextAttemptAt"] != nil {
+		t.Errorf("unexpected first delivery: %v", got)
+	}
+	if got[1]["lastError"] != lastErr || got[1]["nextAttemptAt"] != "2026-01-05T21:01:00Z" {
+		t.Errorf("unexpected second delivery: %v", got[1])
+	}
+	for _, leak := range []string{"s3cret", "secret body"} {
+		if bytes.Contains(raw, []byte(leak)) {
+			t.Errorf("response leaked %q: %s", leak, raw)
+		}
+	}
+}
+
+func TestListDeliveriesErrors(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	mine := alert.Alert{ID: uuid.New(), PortfolioID: port.ID}
+	var limit int
+	app := newTestApp(alert.NewAlertHandler(stubPortfolio{p: port}, deliveryStore{alert: mine, limit: &limit}))
+
+	cases := map[string]struct {
+		path string
+		want int
+	}{
+		"unknown alert": {"/portfolios/my-port/alerts/" + uuid.NewString() + "/deliveries", fiber.StatusNotFound},
+		"bad alert id":  {"/portfolios/my-port/alerts/nope/deliveries", fiber.StatusBadRequest},
+		"limit too big": {"/portfolios/my-port/alerts/" + mine.ID.String() + "/deliveries?limit=500", fiber.StatusUnprocessableEntity},
+		"limit not int": {"/portfolios/my-port/alerts/" + mine.ID.String() + "/deliveries?limit=ten", fiber.StatusUnprocessableEntity},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.path, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.want {
+				t.Errorf("expected %d, got %d", tc.want, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// stubConfirmer implements alert.EmailSummarizer and alert.RecipientConfirmer,
+// recording who was sent a confirmation link.
+type stubConfirmer struct {
+	stubSummarizer
+	sent *[]string
+}
+
+func (s stubConfirmer) ConfirmationsEnabled() bool { return true }
+
+func (s stubConfirmer) SendConfirmations(_ context.Context, _ alert.Alert, recipients []string) error {
+	*s.sent = append(*s.sent, recipients...)
+	return nil
+}
+
+// verifyStore serves one alert through Get/Update and records confirmations.
+type verifyStore struct {
+	stubAlertStore
+	alert    *alert.Alert
+	verified *[]string
+}
+
+func (s verifyStore) Create(_ context.Context, a alert.Alert) (alert.Alert, error) {
+	a.ID = uuid.New()
+	*s.alert = a
+	return a, nil
+}
+
+func (s verifyStore) Get(_ context.Context, id uuid.UUID) (alert.Alert, error) {
+	if id != s.alert.ID {
+		return alert.Alert{}, alert.ErrNotFound
+	}
+	return *s.alert, nil
+}
+
+func (s verifyStore) Update(_ context.Context, a alert.Alert, ifUpdatedAt *time.Time) (alert.Alert, error) {
+	if ifUpdatedAt != nil && !s.alert.UpdatedAt.Equal(*ifUpdatedAt) {
+		return alert.Alert{}, alert.ErrStale
+	}
+	a.PortfolioID = s.alert.PortfolioID
+	a.UpdatedAt = s.alert.UpdatedAt.Add(time.Second)
+	*s.alert = a
+	return a, nil
+}
+
+func (s verifyStore) VerifyRecipient(_ context.Context, id uuid.UUID, recipient string) error {
+	if id != s.alert.ID {
+		return alert.ErrNotFound
+	}
+	*s.verified = append(*s.verified, recipient)
+	return nil
+}
+
+func TestCreateAlertHoldsRecipientsPending(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	var (
+		stored    alert.Alert
+		confirmed []string
+		sent      []string
+	)
+	store := verifyStore{alert: &stored, verified: &confirmed}
+	h := alert.NewAlertHandlerWithChecker(stubPortfolio{p: port}, store, stubConfirmer{sent: &sent}, "secret")
+	app := newTestApp(h)
+
+	req := httptest.NewRequest("POST", "/portfolios/my-port/alerts",
+		bytes.NewBufferString(`{"frequency":"daily","recipients":["a@b.com","c@d.com"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if len(stored.VerifiedRecipients) != 0 {
+		t.Errorf("new recipients should start unverified, got %v", stored.VerifiedRecipients)
+	}
+	if len(sent) != 2 {
+		t.Errorf("expected confirmations to both recipients, got %v", sent)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	if !bytes.Contains(raw, []byte(`{"email":"a@b.com","status":"pending"}`)) {
+		t.Errorf("response should report a@b.com as pending: %s", raw)
+	}
+}
+
+func TestCreateAlertWithoutConfirmerRefusesRecipients(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	var created alert.Alert
+	h := alert.NewAlertHandler(stubPortfolio{p: port}, createStore{created: &created})
+	app := newTestApp(h)
+
+	req := httptest.NewRequest("POST", "/portfolios/my-port/alerts",
+		bytes.NewBufferString(`{"frequency":"daily","recipients":["a@b.com"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("expected 503, got %d", resp.StatusCode)
+	}
+	if created.Verified("a@b.com") || len(created.Recipients) != 0 {
+		t.Errorf("no recipient should be stored without confirmations, got %+v", created)
+	}
+}
+
+func TestUpdateAlertWithoutConfirmerRefusesNewRecipients(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	stored := alert.Alert{
+		ID: uuid.New(), PortfolioID: port.ID, Frequency: alert.FrequencyDaily,
+		Recipients:         []string{"old@b.com"},
+		VerifiedRecipients: []string{"old@b.com"},
+	}
+	var confirmed []string
+	app := newTestApp(alert.NewAlertHandler(stubPortfolio{p: port}, verifyStore{alert: &stored, verified: &confirmed}))
+
+	patch := func(body string) int {
+		req := httptest.NewRequest("PATCH", "/portfolios/my-port/alerts/"+stored.ID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	if status := patch(`{"recipients":["old@b.com","new@b.com"]}`); status != fiber.StatusServiceUnavailable {
+		t.Errorf("adding a recipient: expected 503, got %d", status)
+	}
+	if slices.Contains(stored.Recipients, "new@b.com") {
+		t.Errorf("new@b.com should not be stored, got %v", stored.Recipients)
+	}
+	if status := patch(`{"frequency":"weekly"}`); status != fiber.StatusOK {
+		t.Errorf("keeping confirmed recipients: expected 200, got %d", status)
+	}
+	if !stored.Verified("old@b.com") {
+		t.Errorf("old@b.com should stay verified, got %v", stored.VerifiedRecipients)
+	}
+}
+
+func TestUpdateAlertConfirmsOnlyNewRecipients(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	stored := alert.Alert{
+		ID: uuid.New(), PortfolioID: port.ID, Frequency: alert.FrequencyDaily,
+		Recipients:         []string{"old@b.com", "gone@b.com"},
+		VerifiedRecipients: []string{"old@b.com", "gone@b.com"},
+	}
+	var confirmed, sent []string
+	h := alert.NewAlertHandlerWithChecker(stubPortfolio{p: port},
+		verifyStore{alert: &stored, verified: &confirmed}, stubConfirmer{sent: &sent}, "secret")
+	app := newTestApp(h)
+
+	req := httptest.NewRequest("PATCH", "/portfolios/my-port/alerts/"+stored.ID.String(),
+		bytes.NewBufferString(`{"recipients":["old@b.com","new@b.com"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if len(stored.VerifiedRecipients) != 1 || stored.VerifiedRecipients[0] != "old@b.com" {
+		t.Errorf("expected only old@b.com to stay verified, got %v", stored.VerifiedRecipients)
+	}
+	if len(sent) != 1 || sent[0] != "new@b.com" {
+		t.Errorf("expected a confirmation to new@b.com only, got %v", sent)
+	}
+}
+
+func TestAlertRecipientLocales(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	stored := alert.Alert{
+		ID: uuid.New(), PortfolioID: port.ID, Frequency: alert.FrequencyDaily,
+		Recipients:         []string{"a@b.com", "c@d.com"},
+		VerifiedRecipients: []string{"a@b.com", "c@d.com"},
+		Locales:            map[string]string{"a@b.com": "de-DE"},
+	}
+	var confirmed []string
+	app := newTestApp(alert.NewAlertHandler(stubPortfolio{p: port}, verifyStore{alert: &stored, verified: &confirmed}))
+
+	patch := func(body string) (int, []byte) {
+		req := httptest.NewRequest("PATCH", "/portfolios/my-port/alerts/"+stored.ID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, raw
+	}
+
+	for _, body := range []string{
+		`{"recipientLocales":{"x@y.com":"fr-FR"}}`,
+		`{"recipientLocales":{"c@d.com":"not a tag"}}`,
+	} {
+		if status, raw := patch(body); status != fiber.StatusUnprocessableEntity {
+			t.Errorf("%s: expected 422, got %d: %s", body, status, raw)
+		}
+	}
+
+	status, raw := patch(`{"recipientLocales":{"c@d.com":"fr_fr"}}`)
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", status, raw)
+	}
+	if stored.Locales["a@b.com"] != "de-DE" || stored.Locales["c@d.com"] != "fr-FR" {
+		t.Errorf("expected de-DE kept and fr-FR canonicalized, got %v", stored.Locales)
+	}
+	if !bytes.Contains(raw, []byte(`{"email":"c@d.com","status":"verified","locale":"fr-FR"}`)) {
+		t.Errorf("response should report c@d.com's locale: %s", raw)
+	}
+
+	if status, raw := patch(`{"recipients":["c@d.com"]}`); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", status, raw)
+	}
+	if _, ok := stored.Locales["a@b.com"]; ok {
+		t.Errorf("a removed recipient's locale should be dropped, got %v", stored.Locales)
+	}
+}
+
+func TestConfirmRecipient(t *testing.T) {
+	const secret = "secret"
+	stored := alert.Alert{ID: uuid.New(), Recipients: []string{"a@b.com"}}
+	var confirmed, sent []string
+	h := alert.NewAlertHandlerWithChecker(stubPortfolio{},
+		verifyStore{alert: &stored, verified: &confirmed}, stubConfirmer{sent: &sent}, secret)
+	app := newTestApp(h)
+
+	tok, err := alert.GenerateConfirmToken(secret, stored.ID, "a@b.com", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A GET, as a mail scanner or prefetcher would send, only renders the
+	// form; it must not confirm anyone.
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/alerts/confirm?token="+tok, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(page), `method="POST"`) || !strings.Contains(string(page), tok) {
+		t.Errorf("expected a POST form carrying the token, got %s", page)
+	}
+	if len(confirmed) != 0 {
+		t.Fatalf("GET must leave the recipient unconfirmed, got %v", confirmed)
+	}
+
+	post := func(token string) *http.Response {
+		req := httptest.NewRequest("POST", "/api/alerts/confirm", strings.NewReader(url.Values{"token": {token}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	if resp := post(tok); resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if len(confirmed) != 1 || confirmed[0] != "a@b.com" {
+		t.Errorf("expected a@b.com confirmed, got %v", confirmed)
+	}
+
+	expired, _ := alert.GenerateConfirmToken(secret, stored.ID, "a@b.com", time.Now().Add(-time.Hour))
+	resp, err = app.Test(httptest.NewRequest("GET", "/api/alerts/confirm?token="+expired, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("expected 400 for an expired link, got %d", resp.StatusCode)
+	}
+	if resp := post(expired); resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("expected 400 for an expired token, got %d", resp.StatusCode)
+	}
+}
+
+func TestUpdateAlertIfMatch(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	stored := alert.Alert{
+		ID: uuid.New(), PortfolioID: port.ID, Frequency: "daily", Recipients: []string{"a@b.com"},
+		UpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	var verified []string
+	h := alert.NewAlertHandler(stubPortfolio{p: port}, verifyStore{alert: &stored, verified: &verified})
+	app := newTestApp(h)
+	target := "/portfolios/my-port/alerts/" + stored.ID.String()
+
+	resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	tag := resp.Header.Get("ETag")
+	if resp.StatusCode != fiber.StatusOK || tag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d %q", resp.StatusCode, tag)
+	}
+
+	req := httptest.NewRequest("GET", target, nil)
+	req.Header.Set("If-None-Match", tag)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != fiber.StatusNotModified {
+		t.Errorf("expected 304 for a current If-None-Match, got %d", resp.StatusCode)
+	}
+
+	// The successful update changes the alert's version, so it goes last.
+	for _, tc := range []struct {
+		ifMatch string
+		want    int
+	}{
+		{`"stale"`, fiber.StatusPreconditionFailed},
+		{`"x", ` + tag, fiber.StatusOK},
+	} {
+		req := httptest.NewRequest("PATCH", target, bytes.NewBufferString(`{"frequency":"weekly"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", tc.ifMatch)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("If-Match %s: expected %d, got %d", tc.ifMatch, tc.want, resp.StatusCode)
+		}
+	}
+}
+
+// raceStore reads an alert as it was before a concurrent write landed.
+type raceStore struct {
+	verifyStore
+	read alert.Alert
+}
+
+func (s raceStore) Get(_ context.Context, _ uuid.UUID) (alert.Alert, error) { return s.read, nil }
+
+func (s raceStore) Delete(_ context.Context, _ uuid.UUID, ifUpdatedAt *time.Time) error {
+	if ifUpdatedAt != nil && !s.alert.UpdatedAt.Equal(*ifUpdatedAt) {
+		return alert.ErrStale
+	}
+	return nil
+}
+
+func TestAlertIfMatchLosesRaceWithConcurrentWrite(t *testing.T) {
+	port := portfolio.Portfolio{ID: uuid.New(), OwnerSub: "user-1", Slug: "my-port", Status: portfolio.StatusReady}
+	read := alert.Alert{
+		ID: uuid.New(), PortfolioID: port.ID, Frequency: "daily", Recipients: []string{"a@b.com"},
+		UpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+	stored := read
+	stored.Frequency = "monthly"
+	stored.UpdatedAt = read.UpdatedAt.Add(time.Second)
+	var verified []string
+	h := alert.NewAlertHandler(stubPortfolio{p: port},
+		raceStore{verifyStore: verifyStore{alert: &stored, verified: &verified}, read: read})
+	app := newTestApp(h)
+	target := "/portfolios/my-port/alerts/" + read.ID.String()
+
+	for _, tc := range []struct{ method, body string }{
+		{"PATCH", `{"frequency":"weekly"}`},
+		{"DELETE", ""},
+	} {
+		req := httptest.NewRequest(tc.method, target, bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", etag.FromTime(read.UpdatedAt))
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != fiber.StatusPreconditionFailed {
+			t.Errorf("%s: expected 412 once the alert changed after it was read, got %d", tc.method, resp.StatusCode)
+		}
+	}
+	if stored.Frequency != "monthly" {
+		t.Errorf("the concurrent write was overwritten: frequency %q", stored.Frequency)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/alert/channel"
+	"github.com/penny-vault/pv-api/alert/email"
+)
+
+// Delivery statuses. A delivery is pending until the outbox claims it, sending
+// while an attempt is in flight, and ends sent or dead once it succeeds or
+// exhausts its attempts.
+const (
+	DeliveryPending = "pending"
+	DeliverySending = "sending"
+	DeliverySent    = "sent"
+	DeliveryDead    = "dead"
+)
+
+// EventRecipientConfirm is the Delivery.Event of a recipient confirmation
+// email. Alert sends use portfolio.update, portfolio.error and
+// portfolio.condition.
+const EventRecipientConfirm = "recipient.confirm"
+
+// EventDigest is the Delivery.Event of a digest email.
+const EventDigest = "portfolio.digest"
+
+// ChannelEmail is the Delivery.Channel of an email recipient; every other
+// delivery carries its channel target's type.
+const ChannelEmail = "email"
+
+const (
+	maxDeliveryAttempts = 8
+	deliveryBatchSize   = 50
+	// deliveryLease is how long a claimed delivery stays invisible to other
+	// workers. A worker that dies mid-send releases it when the lease ends.
+	deliveryLease = 5 * time.Minute
+	// deliveryRetention is how long finished deliveries stay in the history.
+	deliveryRetention = 90 * 24 * time.Hour
+)
+
+var errTransportNotConfigured = errors.New("no delivery transport configured")
+
+// Delivery is one rendered alert or digest message addressed to one email
+// recipient or channel target, together with its delivery history.
+type Delivery struct {
+	ID uuid.UUID
+	// AlertID or DigestID names what the delivery was sent for; the other is
+	// uuid.Nil.
+	AlertID  uuid.UUID
+	DigestID uuid.UUID
+	Channel  string
+	// Destination is the recipient address, or the target's Label for
+	// channel deliveries.
+	Destination string
+	// Target is the full channel target, including any signing secret; nil
+	// for email.
+	Target            *channel.Target
+	Event             string
+	Subject           string
+	HTMLBody          string
+	TextBody          string
+	Payload           email.Payload
+	Status            string
+	Attempts          int
+	LastError         *string
+	ProviderMessageID *string
+	NextAttemptAt     time.Time
+	CreatedAt         time.Time
+	SentAt            *time.Time
+}
+
+// OutboxStore is the persistence the Outbox needs. PoolStore implements it.
+type OutboxStore interface {
+	// ClaimDeliveries marks up to limit due deliveries as sending, counts the
+	// attempt and hides them from other workers for lease.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, providerMessageID string) error
+	// MarkDeliveryFailed records errMsg and schedules the next attempt at
+	// next, or dead-letters the delivery when next is nil.
+	MarkDeliveryFailed(ctx context.Context, id uuid.UUID, errMsg string, next *time.Time) error
+	PruneDeliveries(ctx context.Context, before time.Time) error
+}
+
+var _ OutboxStore = (*PoolStore)(nil)
+
+// Outbox delivers queued alert messages, retrying failures with exponential
+// backoff until they succeed or reach maxDeliveryAttempts.
+type Outbox struct {
+	store     OutboxStore
+	transport email.Transport
+	channels  *channel.Dispatcher
+}
+
+// NewOutbox creates an Outbox. A nil transport or dispatcher fails the
+// deliveries that need it, so they retry once the server is configured.
+func NewOutbox(store OutboxStore, transport email.Transport, channels *channel.Dispatcher) *Outbox {
+	return &Outbox{store: store, transport: transport, channels: channels}
+}
+
+// Tick claims due deliveries and attempts each once. It returns the number
+// attempted.
+func (o *Outbox) Tick(ctx context.Context) (int, error) {
+	batch, err := o.store.ClaimDeliveries(ctx, deliveryBatchSize, deliveryLease)
+	if err != nil {
+		return 0, fmt.Errorf("claim deliveries: %w", err)
+	}
+	for _, d := range batch {
+		o.attempt(ctx, d)
+	}
+	return len(batch), nil
+}
+
+// Run ticks every interval until ctx is cancelled, and prunes finished
+// deliveries older than the retention window once a day.
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var lastPrune time.Time
+	for {
+		if _, err := o.Tick(ctx); err != nil {
+			log.Warn().Err(err).Msg("alert outbox: tick")
+		}
+		if time.Since(lastPrune) > 24*time.Hour {
+			if err := o.store.PruneDeliveries(ctx, time.Now().Add(-deliveryRetention)); err != nil {
+				log.Warn().Err(err).Msg("alert outbox: prune")
+			}
+			lastPrune = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *Outbox) attempt(ctx context.Context, d Delivery) {
+	providerID, err := o.send(ctx, d)
+	if err == nil {
+		if markErr := o.store.MarkDelivered(ctx, d.ID, providerID); markErr != nil {
+			log.Warn().Err(markErr).Stringer("delivery_id", d.ID).Msg("alert outbox: mark delivered")
+		}
+		return
+	}
+
+	var next *time.Time
+	if d.Attempts < maxDeliveryAttempts {
+		at := time.Now().Add(deliveryBackoff(d.Attempts))
+		next = &at
+	}
+	log.Warn().Err(err).Stringer("delivery_id", d.ID).Stringer("alert_id", d.AlertID).
+		Str("channel", d.Channel).Int("attempts", d.Attempts).Bool("dead", next == nil).
+		Msg("alert outbox: delivery failed")
+	if markErr := o.store.MarkDeliveryFailed(ctx, d.ID, err.Error(), next); markErr != nil {
+		log.Warn().Err(markErr).Stringer("delivery_id", d.ID).Msg("alert outbox: mark failed")
+	}
+}
+
+func (o *Outbox) send(ctx context.Context, d Delivery) (string, error) {
+	if d.Channel == ChannelEmail {
+		if o.transport == nil {
+			return "", fmt.Errorf("email: %w", errTransportNotConfigured)
+		}
+		return o.transport.Send(ctx, []string{d.Destination}, d.Subject, d.HTMLBody, d.TextBody)
+	}
+	if o.channels == nil || d.Target == nil {
+		return "", fmt.Errorf("%s: %w", d.Channel, errTransportNotConfigured)
+	}
+	return "", o.channels.Send(ctx, *d.Target, channel.Message{
+		AlertID: d.AlertID.String(),
+		Event:   d.Event,
+		Subject: d.Subject,
+		Text:    d.TextBody,
+		Payload: d.Payload,
+	})
+}
+
+// deliveryBackoff is the wait after the given number of failed attempts:
+// 1m, 4m, 16m, 64m, ... capped at 6h.
+func deliveryBackoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts; i++ {
+		d *= 4
+		if d >= 6*time.Hour {
+			return 6 * time.Hour
+		}
+	}
+	return d
+}
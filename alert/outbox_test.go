@@ -0,0 +1,140 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alert
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/alert/channel"
+)
+
+// fakeOutboxStore hands out its queue once and records the outcome of each
+// attempt.
+type fakeOutboxStore struct {
+	queue     []Delivery
+	delivered map[uuid.UUID]string
+	failed    map[uuid.UUID]*time.Time
+}
+
+func newFakeOutboxStore(queue ...Delivery) *fakeOutboxStore {
+	return &fakeOutboxStore{queue: queue, delivered: map[uuid.UUID]string{}, failed: map[uuid.UUID]*time.Time{}}
+}
+
+func (s *fakeOutboxStore) ClaimDeliveries(_ context.Context, limit int, _ time.Duration) ([]Delivery, error) {
+	n := min(limit, len(s.queue))
+	out := s.queue[:n]
+	s.queue = s.queue[n:]
+	return out, nil
+}
+
+func (s *fakeOutboxStore) MarkDelivered(_ context.Context, id uuid.UUID, providerMessageID string) error {
+	s.delivered[id] = providerMessageID
+	return nil
+}
+
+func (s *fakeOutboxStore) MarkDeliveryFailed(_ context.Context, id uuid.UUID, _ string, next *time.Time) error {
+	s.failed[id] = next
+	return nil
+}
+
+func (s *fakeOutboxStore) PruneDeliveries(context.Context, time.Time) error { return nil }
+
+// fakeTransport fails while err is set and otherwise returns a message ID.
+type fakeTransport struct {
+	err  error
+	sent []string
+}
+
+func (t *fakeTransport) Send(_ context.Context, to []string, _, _, _ string) (string, error) {
+	if t.err != nil {
+		return "", t.err
+	}
+	t.sent = append(t.sent, to...)
+	return "<msg-1@example.com>", nil
+}
+
+func TestOutboxDeliversEmail(t *testing.T) {
+	d := Delivery{ID: uuid.New(), Channel: ChannelEmail, Destination: "a@b.com", Attempts: 1}
+	store := newFakeOutboxStore(d)
+	tr := &fakeTransport{}
+
+	n, err := NewOutbox(store, tr, nil).Tick(context.Background())
+	if err != nil || n != 1 {
+		t.Fatalf("Tick = %d, %v", n, err)
+	}
+	if got := store.delivered[d.ID]; got != "<msg-1@example.com>" {
+		t.Errorf("expected provider message id recorded, got %q", got)
+	}
+	if len(tr.sent) != 1 || tr.sent[0] != "a@b.com" {
+		t.Errorf("unexpected sends: %v", tr.sent)
+	}
+}
+
+func TestOutboxRetriesThenDeadLetters(t *testing.T) {
+	retry := Delivery{ID: uuid.New(), Channel: ChannelEmail, Destination: "a@b.com", Attempts: 2}
+	last := Delivery{ID: uuid.New(), Channel: ChannelEmail, Destination: "a@b.com", Attempts: maxDeliveryAttempts}
+	store := newFakeOutboxStore(retry, last)
+	tr := &fakeTransport{err: errors.New("mailgun: unexpected status 503")}
+
+	before := time.Now()
+	if _, err := NewOutbox(store, tr, nil).Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	next, ok := store.failed[retry.ID]
+	if !ok || next == nil {
+		t.Fatalf("expected a retry to be scheduled, got %v", next)
+	}
+	if wait := next.Sub(before); wait < 4*time.Minute || wait > 5*time.Minute {
+		t.Errorf("expected ~4m backoff after two attempts, got %s", wait)
+	}
+	if next, ok := store.failed[last.ID]; !ok || next != nil {
+		t.Errorf("expected the final attempt to be dead-lettered, got %v", next)
+	}
+}
+
+func TestOutboxChannelWithoutDispatcherFails(t *testing.T) {
+	d := Delivery{ID: uuid.New(), Channel: channel.TypeNtfy, Target: &channel.Target{Type: channel.TypeNtfy, Topic: "x"}, Attempts: 1}
+	store := newFakeOutboxStore(d)
+
+	if _, err := NewOutbox(store, nil, nil).Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.failed[d.ID]; !ok {
+		t.Error("expected the delivery to fail without a dispatcher")
+	}
+}
+
+func TestDeliveryBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1: time.Minute,
+		2: 4 * time.Minute,
+		3: 16 * time.Minute,
+		4: 64 * time.Minute,
+		5: 256 * time.Minute,
+		6: 6 * time.Hour,
+		8: 6 * time.Hour,
+	}
+	for attempts, want := range cases {
+		if got := deliveryBackoff(attempts); got != want {
+			t.Errorf("deliveryBackoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
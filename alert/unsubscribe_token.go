@@ -32,46 +32,61 @@ var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
 // recipient, signed with HMAC-SHA256 using secret.
 // Format: base64url(alertID:recipient) + "." + base64url(hmac)
 func GenerateUnsubscribeToken(secret string, alertID uuid.UUID, recipient string) (string, error) {
-	payload := alertID.String() + ":" + recipient
-	mac := hmac.New(sha256.New, []byte(secret))
-	if _, err := mac.Write([]byte(payload)); err != nil {
+	tok, err := signToken(secret, alertID.String()+":"+recipient)
+	if err != nil {
 		return "", fmt.Errorf("generate unsubscribe token: %w", err)
 	}
-	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
-	body := base64.RawURLEncoding.EncodeToString([]byte(payload))
-	return body + "." + sig, nil
+	return tok, nil
 }
 
 // VerifyUnsubscribeToken validates the token and returns alertID and recipient.
 func VerifyUnsubscribeToken(secret, token string) (uuid.UUID, string, error) {
-	parts := strings.SplitN(token, ".", 2)
-	if len(parts) != 2 {
+	payload, ok := openToken(secret, token)
+	if !ok {
 		return uuid.UUID{}, "", ErrInvalidUnsubscribeToken
 	}
-	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
-	if err != nil {
+	idx := strings.Index(payload, ":")
+	if idx < 0 {
 		return uuid.UUID{}, "", ErrInvalidUnsubscribeToken
 	}
-	sigBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	id, err := uuid.Parse(payload[:idx])
 	if err != nil {
 		return uuid.UUID{}, "", ErrInvalidUnsubscribeToken
 	}
-	payload := string(payloadBytes)
+	return id, payload[idx+1:], nil
+}
+
+// signToken returns base64url(payload) + "." + base64url(HMAC-SHA256(payload)).
+func signToken(secret, payload string) (string, error) {
 	mac := hmac.New(sha256.New, []byte(secret))
 	if _, err := mac.Write([]byte(payload)); err != nil {
-		return uuid.UUID{}, "", ErrInvalidUnsubscribeToken
+		return "", err
 	}
-	expected := mac.Sum(nil)
-	if !hmac.Equal(sigBytes, expected) {
-		return uuid.UUID{}, "", ErrInvalidUnsubscribeToken
+	sig := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	body := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return body + "." + sig, nil
+}
+
+// openToken checks a token made by signToken and returns its payload.
+func openToken(secret, token string) (string, bool) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", false
 	}
-	idx := strings.Index(payload, ":")
-	if idx < 0 {
-		return uuid.UUID{}, "", ErrInvalidUnsubscribeToken
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", false
 	}
-	id, err := uuid.Parse(payload[:idx])
+	sigBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return uuid.UUID{}, "", ErrInvalidUnsubscribeToken
+		return "", false
 	}
-	return id, payload[idx+1:], nil
+	mac := hmac.New(sha256.New, []byte(secret))
+	if _, err := mac.Write(payloadBytes); err != nil {
+		return "", false
+	}
+	if !hmac.Equal(sigBytes, mac.Sum(nil)) {
+		return "", false
+	}
+	return string(payloadBytes), true
 }
@@ -0,0 +1,49 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/penny-vault/pv-api/strategy"
+)
+
+// RegisterAdminRoutes mounts stub admin endpoints behind the admin scope
+// check. Used when no DB pool is configured.
+func RegisterAdminRoutes(r fiber.Router, adminScope string) {
+	admin := r.Group("/admin", RequireScope(adminScope))
+	admin.Post("/strategies/sync", stubAdmin)
+	admin.Post("/strategies/:shortCode/reinstall", stubAdmin)
+	admin.Delete("/strategies/:shortCode/install-error", stubAdmin)
+	admin.Post("/strategies/:shortCode/hide", stubAdmin)
+	admin.Post("/strategies/:shortCode/unhide", stubAdmin)
+	admin.Post("/strategies/:shortCode/stats", stubAdmin)
+}
+
+// RegisterAdminRoutesWith mounts the strategy-registry admin endpoints
+// under /admin. Every route requires adminScope; an empty adminScope
+// leaves them mounted but answering 403.
+func RegisterAdminRoutesWith(r fiber.Router, adminScope string, h *strategy.AdminHandler) {
+	admin := r.Group("/admin", RequireScope(adminScope))
+	admin.Post("/strategies/sync", h.Sync)
+	admin.Post("/strategies/:shortCode/reinstall", h.Reinstall)
+	admin.Delete("/strategies/:shortCode/install-error", h.ClearInstallError)
+	admin.Post("/strategies/:shortCode/hide", h.Hide)
+	admin.Post("/strategies/:shortCode/unhide", h.Unhide)
+	admin.Post("/strategies/:shortCode/stats", h.RefreshStats)
+}
+
+func stubAdmin(c fiber.Ctx) error { return WriteProblem(c, ErrNotImplemented) }
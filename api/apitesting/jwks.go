@@ -109,13 +109,26 @@ func (j *JWKS) Mint(subject string, ttl time.Duration) (string, error) {
 // MintWith lets tests override audience / issuer to exercise failure cases.
 // A negative ttl produces an already-expired token.
 func (j *JWKS) MintWith(subject, audience, issuer string, ttl time.Duration) (string, error) {
-	tok, err := jwt.NewBuilder().
+	return j.mint(subject, audience, issuer, ttl, nil)
+}
+
+// MintWithClaims produces a default-audience/issuer token carrying extra
+// private claims (e.g. "scope" or "permissions").
+func (j *JWKS) MintWithClaims(subject string, claims map[string]any, ttl time.Duration) (string, error) {
+	return j.mint(subject, Audience, Issuer, ttl, claims)
+}
+
+func (j *JWKS) mint(subject, audience, issuer string, ttl time.Duration, claims map[string]any) (string, error) {
+	b := jwt.NewBuilder().
 		Issuer(issuer).
 		Audience([]string{audience}).
 		Subject(subject).
 		IssuedAt(time.Now()).
-		Expiration(time.Now().Add(ttl)).
-		Build()
+		Expiration(time.Now().Add(ttl))
+	for k, v := range claims {
+		b = b.Claim(k, v)
+	}
+	tok, err := b.Build()
 	if err != nil {
 		return "", fmt.Errorf("building token: %w", err)
 	}
@@ -0,0 +1,45 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/penny-vault/pv-api/audit"
+)
+
+// AuditAdminActions returns a handler that records every successful
+// change made under /admin in the audit trail, naming the method and
+// path as the target. Reads and failed requests are not recorded.
+func AuditAdminActions(r audit.Recorder) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		err := c.Next()
+		if isSafeMethod(c.Method()) || err != nil || c.Response().StatusCode() >= fiber.StatusBadRequest {
+			return err
+		}
+		e := audit.FromRequest(c, audit.ActionAdmin)
+		e.Target = c.Method() + " " + c.Path()
+		audit.Record(c.Context(), r, e)
+		return nil
+	}
+}
+
+// RegisterAuditRoutesWith mounts the audit trail: the caller's own, one
+// portfolio's, and under /admin everyone's, which requires adminScope.
+func RegisterAuditRoutesWith(r fiber.Router, adminScope string, h *audit.Handler) {
+	r.Get("/me/audit", h.Mine)
+	r.Get("/portfolios/:slug/audit", h.Portfolio)
+	r.Get("/admin/audit", RequireScope(adminScope), h.Admin)
+}
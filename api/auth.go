@@ -19,13 +19,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/lestrrat-go/httprc/v3"
 	"github.com/lestrrat-go/jwx/v3/jwk"
 	"github.com/lestrrat-go/jwx/v3/jwt"
+	"github.com/rs/zerolog/log"
 
+	"github.com/penny-vault/pv-api/apikey"
 	"github.com/penny-vault/pv-api/types"
 )
 
@@ -34,8 +37,37 @@ type AuthConfig struct {
 	JWKSURL  string
 	Audience string
 	Issuer   string
+	// RolesClaim names an extra claim (e.g. an Auth0 namespaced
+	// "https://pennyvault.com/roles" claim) whose values are granted
+	// alongside the standard `scope` and `permissions` claims. Optional.
+	RolesClaim string
+	// AdminScope is the scope, permission, or role that unlocks the
+	// /admin routes. Empty disables admin access entirely.
+	AdminScope string
+	// RequireScopes makes every /api/v3 route require a
+	// "<resource>:read" or "<resource>:write" grant (see
+	// RequireRouteScopes). Off, any valid token can use every route for
+	// the portfolios its subject owns.
+	RequireScopes bool
+	// APIKeys verifies personal API keys presented in place of a JWT.
+	// Nil accepts JWTs only.
+	APIKeys KeyVerifier
 }
 
+// KeyVerifier resolves a personal API key to its record.
+type KeyVerifier interface {
+	Verify(ctx context.Context, secret string) (apikey.Key, error)
+}
+
+// HeaderActAs names the subject an admin is acting for. The request then
+// runs as that subject, and the admin's own subject is kept on
+// types.AuthActorKey.
+const HeaderActAs = "X-Act-As"
+
+// ErrForbidden is returned when an authenticated caller lacks the scope a
+// route requires. WriteProblem maps it to 403.
+var ErrForbidden = errors.New("insufficient scope")
+
 // ErrInvalidToken is returned when a JWT is missing, malformed, or fails
 // verification. The middleware converts it to a 401 problem+json via
 // WriteProblem.
@@ -47,7 +79,12 @@ var ErrAuthConfigIncomplete = errors.New("auth config incomplete")
 
 // NewAuthMiddleware builds a Fiber v3 handler that verifies the
 // Authorization: Bearer <jwt> header on every request and stores the
-// subject on types.AuthSubjectKey. ctx controls the JWK cache lifecycle.
+// subject on types.AuthSubjectKey and the caller's grants on
+// types.AuthScopesKey. A bearer token that is a personal API key is checked
+// with conf.APIKeys instead and authenticates as the key's owner, with the
+// route scopes its access allows. Callers holding conf.AdminScope may send
+// X-Act-As to act as another subject. ctx controls the JWK cache
+// lifecycle.
 func NewAuthMiddleware(ctx context.Context, conf AuthConfig) (fiber.Handler, error) {
 	if conf.JWKSURL == "" {
 		return nil, fmt.Errorf("%w: JWKSURL must not be empty", ErrAuthConfigIncomplete)
@@ -72,6 +109,9 @@ func NewAuthMiddleware(ctx context.Context, conf AuthConfig) (fiber.Handler, err
 		if token == "" {
 			return WriteProblem(c, fmt.Errorf("missing bearer token: %w", ErrInvalidToken))
 		}
+		if apikey.IsKey(token) {
+			return apiKeyAuth(c, conf.APIKeys, token)
+		}
 
 		keyset, err := cache.Lookup(c.Context(), conf.JWKSURL)
 		if err != nil {
@@ -93,11 +133,103 @@ func NewAuthMiddleware(ctx context.Context, conf AuthConfig) (fiber.Handler, err
 			return WriteProblem(c, fmt.Errorf("missing sub claim: %w", ErrInvalidToken))
 		}
 
+		grants := tokenGrants(parsed, conf.RolesClaim)
+		if target := c.Get(HeaderActAs); target != "" {
+			if conf.AdminScope == "" || !slices.Contains(grants, conf.AdminScope) {
+				return WriteProblem(c, fmt.Errorf("%w: %s requires %q", ErrForbidden, HeaderActAs, conf.AdminScope))
+			}
+			log.Info().Str("actor", sub).Str("subject", target).Str("method", c.Method()).
+				Str("path", c.Path()).Msg("admin acting as another subject")
+			c.Locals(types.AuthActorKey{}, sub)
+			sub = target
+		}
+
 		c.Locals(types.AuthSubjectKey{}, sub)
+		c.Locals(types.AuthScopesKey{}, grants)
 		return c.Next()
 	}, nil
 }
 
+// apiKeyAuth authenticates a request made with a personal API key.
+// Read-only keys are limited to safe methods.
+func apiKeyAuth(c fiber.Ctx, keys KeyVerifier, token string) error {
+	if keys == nil {
+		return WriteProblem(c, fmt.Errorf("api keys are not enabled: %w", ErrInvalidToken))
+	}
+	k, err := keys.Verify(c.Context(), token)
+	if errors.Is(err, apikey.ErrInvalid) {
+		return WriteProblem(c, fmt.Errorf("%w: %w", ErrInvalidToken, err))
+	}
+	if err != nil {
+		return WriteProblem(c, err)
+	}
+	if c.Get(HeaderActAs) != "" {
+		return WriteProblem(c, fmt.Errorf("%w: API keys cannot use %s", ErrForbidden, HeaderActAs))
+	}
+	if k.ReadOnly && !isSafeMethod(c.Method()) {
+		return WriteProblem(c, fmt.Errorf("%w: this API key is read-only", ErrForbidden))
+	}
+	c.Locals(types.AuthSubjectKey{}, k.OwnerSub)
+	c.Locals(types.AuthScopesKey{}, apiKeyGrants(k.ReadOnly))
+	c.Locals(types.AuthAPIKeyKey{}, k.ID.String())
+	return c.Next()
+}
+
+func isSafeMethod(m string) bool {
+	return m == fiber.MethodGet || m == fiber.MethodHead || m == fiber.MethodOptions
+}
+
+// RequireScope returns a handler that 403s unless the authenticated caller
+// was granted scope. It must run after the auth middleware. An empty scope
+// denies everyone, so an unconfigured admin scope fails closed.
+func RequireScope(scope string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if scope != "" && HasScope(c, scope) {
+			return c.Next()
+		}
+		return WriteProblem(c, fmt.Errorf("%w: requires %q", ErrForbidden, scope))
+	}
+}
+
+// HasScope reports whether the authenticated caller was granted scope.
+func HasScope(c fiber.Ctx, scope string) bool {
+	grants, _ := c.Locals(types.AuthScopesKey{}).([]string)
+	for _, g := range grants {
+		if g == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenGrants merges the space-delimited OAuth `scope` claim, Auth0's
+// `permissions` array, and the optional roles claim into one list.
+func tokenGrants(tok jwt.Token, rolesClaim string) []string {
+	var grants []string
+	for _, name := range []string{"scope", "permissions", rolesClaim} {
+		if name == "" {
+			continue
+		}
+		var v any
+		if err := tok.Get(name, &v); err != nil {
+			continue
+		}
+		switch vv := v.(type) {
+		case string:
+			grants = append(grants, strings.Fields(vv)...)
+		case []string:
+			grants = append(grants, vv...)
+		case []any:
+			for _, e := range vv {
+				if s, ok := e.(string); ok {
+					grants = append(grants, s)
+				}
+			}
+		}
+	}
+	return grants
+}
+
 // bearerToken returns the JWT from an `Authorization: Bearer <...>` header,
 // or "" if the header is absent or not a bearer scheme.
 func bearerToken(c fiber.Ctx) string {
@@ -20,12 +20,15 @@ import (
 	"net/http/httptest"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/gofiber/fiber/v3"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/penny-vault/pv-api/api"
 	"github.com/penny-vault/pv-api/api/apitesting"
+	"github.com/penny-vault/pv-api/apikey"
 	"github.com/penny-vault/pv-api/types"
 )
 
@@ -110,3 +113,277 @@ var _ = Describe("JWT middleware", func() {
 		Expect(resp.StatusCode).To(Equal(fiber.StatusOK))
 	})
 })
+
+var _ = Describe("RequireScope", func() {
+	var app *fiber.App
+
+	BeforeEach(func() {
+		ctx := context.Background()
+		mw, err := api.NewAuthMiddleware(ctx, api.AuthConfig{
+			JWKSURL:    testJWKS.URL,
+			Audience:   apitesting.Audience,
+			Issuer:     apitesting.Issuer,
+			RolesClaim: "https://pennyvault.com/roles",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		app = fiber.New()
+		app.Use(mw)
+		app.Get("/admin", api.RequireScope("pvapi:admin"), func(c fiber.Ctx) error {
+			return c.SendString("ok")
+		})
+		app.Get("/locked", api.RequireScope(""), func(c fiber.Ctx) error {
+			return c.SendString("ok")
+		})
+	})
+
+	get := func(path string, claims map[string]any) int {
+		tok, err := testJWKS.MintWithClaims("user-1", claims, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	It("forbids a token without the scope", func() {
+		Expect(get("/admin", nil)).To(Equal(fiber.StatusForbidden))
+		Expect(get("/admin", map[string]any{"scope": "read:portfolios"})).To(Equal(fiber.StatusForbidden))
+	})
+
+	It("accepts the scope in the space-delimited scope claim", func() {
+		Expect(get("/admin", map[string]any{"scope": "openid pvapi:admin"})).To(Equal(fiber.StatusOK))
+	})
+
+	It("accepts the scope in the permissions array", func() {
+		Expect(get("/admin", map[string]any{"permissions": []string{"pvapi:admin"}})).To(Equal(fiber.StatusOK))
+	})
+
+	It("accepts the scope in the configured roles claim", func() {
+		Expect(get("/admin", map[string]any{"https://pennyvault.com/roles": []string{"pvapi:admin"}})).To(Equal(fiber.StatusOK))
+	})
+
+	It("forbids everyone when the required scope is empty", func() {
+		Expect(get("/locked", map[string]any{"scope": "pvapi:admin"})).To(Equal(fiber.StatusForbidden))
+	})
+})
+
+type fakeKeys map[string]apikey.Key
+
+func (f fakeKeys) Verify(_ context.Context, secret string) (apikey.Key, error) {
+	k, ok := f[secret]
+	if !ok {
+		return apikey.Key{}, apikey.ErrInvalid
+	}
+	return k, nil
+}
+
+var _ = Describe("API key authentication", func() {
+	const (
+		readKey  = apikey.Prefix + "read"
+		writeKey = apikey.Prefix + "write"
+	)
+	var app *fiber.App
+
+	BeforeEach(func() {
+		mw, err := api.NewAuthMiddleware(context.Background(), api.AuthConfig{
+			JWKSURL:    testJWKS.URL,
+			Audience:   apitesting.Audience,
+			Issuer:     apitesting.Issuer,
+			AdminScope: "pvapi:admin",
+			APIKeys: fakeKeys{
+				readKey:  {ID: uuid.New(), OwnerSub: "user-7", ReadOnly: true},
+				writeKey: {ID: uuid.New(), OwnerSub: "user-7"},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		app = fiber.New()
+		app.Use(mw)
+		hello := func(c fiber.Ctx) error {
+			sub, _ := c.Locals(types.AuthSubjectKey{}).(string)
+			return c.SendString("hello " + sub)
+		}
+		app.Get("/secure", hello)
+		app.Post("/secure", hello)
+		app.Get("/admin", api.RequireScope("pvapi:admin"), hello)
+	})
+
+	do := func(method, path, key string) (int, string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		buf := make([]byte, 64)
+		n, _ := resp.Body.Read(buf)
+		return resp.StatusCode, string(buf[:n])
+	}
+
+	It("authenticates as the key's owner", func() {
+		status, body := do("GET", "/secure", readKey)
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(Equal("hello user-7"))
+	})
+
+	It("rejects an unknown key", func() {
+		status, _ := do("GET", "/secure", apikey.Prefix+"nope")
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+	})
+
+	It("limits read-only keys to safe methods", func() {
+		status, _ := do("POST", "/secure", readKey)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+		status, _ = do("POST", "/secure", writeKey)
+		Expect(status).To(Equal(fiber.StatusOK))
+	})
+
+	It("grants no scopes", func() {
+		status, _ := do("GET", "/admin", writeKey)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+	})
+
+	It("rejects keys when none are configured", func() {
+		mw, err := api.NewAuthMiddleware(context.Background(), api.AuthConfig{
+			JWKSURL:  testJWKS.URL,
+			Audience: apitesting.Audience,
+			Issuer:   apitesting.Issuer,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		app = fiber.New()
+		app.Use(mw)
+		app.Get("/secure", func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		status, _ := do("GET", "/secure", readKey)
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+	})
+})
+
+var _ = Describe("RequireRouteScopes", func() {
+	var app *fiber.App
+
+	BeforeEach(func() {
+		mw, err := api.NewAuthMiddleware(context.Background(), api.AuthConfig{
+			JWKSURL:    testJWKS.URL,
+			Audience:   apitesting.Audience,
+			Issuer:     apitesting.Issuer,
+			AdminScope: "pvapi:admin",
+			APIKeys: fakeKeys{
+				apikey.Prefix + "read": {ID: uuid.New(), OwnerSub: "user-7", ReadOnly: true},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		app = fiber.New()
+		v3 := app.Group("/api/v3", mw, api.RequireRouteScopes("/api/v3", "pvapi:admin"))
+		ok := func(c fiber.Ctx) error { return c.SendString("ok") }
+		v3.Get("/portfolios", ok)
+		v3.Post("/portfolios", ok)
+		v3.Get("/portfolios/:slug/alerts", ok)
+		v3.Get("/me/digest", ok)
+		v3.Get("/unscoped", ok)
+		v3.Post("/graphql", ok)
+	})
+
+	do := func(method, path string, claims map[string]any) int {
+		tok, err := testJWKS.MintWithClaims("user-1", claims, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	It("needs the read scope to read and the write scope to write", func() {
+		read := map[string]any{"scope": "portfolios:read"}
+		Expect(do("GET", "/api/v3/portfolios", nil)).To(Equal(fiber.StatusForbidden))
+		Expect(do("GET", "/api/v3/portfolios", read)).To(Equal(fiber.StatusOK))
+		Expect(do("POST", "/api/v3/portfolios", read)).To(Equal(fiber.StatusForbidden))
+		Expect(do("POST", "/api/v3/portfolios", map[string]any{"scope": "portfolios:write"})).To(Equal(fiber.StatusOK))
+	})
+
+	It("lets a write scope read", func() {
+		Expect(do("GET", "/api/v3/portfolios", map[string]any{"permissions": []string{"portfolios:write"}})).
+			To(Equal(fiber.StatusOK))
+	})
+
+	It("treats alerts and the account as their own resources", func() {
+		Expect(do("GET", "/api/v3/portfolios/p/alerts", map[string]any{"scope": "portfolios:read"})).
+			To(Equal(fiber.StatusForbidden))
+		Expect(do("GET", "/api/v3/portfolios/p/alerts", map[string]any{"scope": "alerts:read"})).
+			To(Equal(fiber.StatusOK))
+		Expect(do("GET", "/api/v3/me/digest", map[string]any{"scope": "account:read"})).
+			To(Equal(fiber.StatusOK))
+	})
+
+	It("lets GraphQL through with read on portfolios and strategies", func() {
+		Expect(do("POST", "/api/v3/graphql", map[string]any{"scope": "portfolios:read"})).
+			To(Equal(fiber.StatusForbidden))
+		Expect(do("POST", "/api/v3/graphql", map[string]any{"scope": "portfolios:read strategies:write"})).
+			To(Equal(fiber.StatusOK))
+	})
+
+	It("locks routes without a resource to admins", func() {
+		Expect(do("GET", "/api/v3/unscoped", map[string]any{"scope": "portfolios:write"})).To(Equal(fiber.StatusForbidden))
+		Expect(do("GET", "/api/v3/unscoped", map[string]any{"scope": "pvapi:admin"})).To(Equal(fiber.StatusOK))
+	})
+
+	It("gives API keys read scopes", func() {
+		req := httptest.NewRequest("GET", "/api/v3/portfolios", nil)
+		req.Header.Set("Authorization", "Bearer "+apikey.Prefix+"read")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(fiber.StatusOK))
+	})
+})
+
+var _ = Describe("X-Act-As", func() {
+	var app *fiber.App
+
+	BeforeEach(func() {
+		mw, err := api.NewAuthMiddleware(context.Background(), api.AuthConfig{
+			JWKSURL:    testJWKS.URL,
+			Audience:   apitesting.Audience,
+			Issuer:     apitesting.Issuer,
+			AdminScope: "pvapi:admin",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		app = fiber.New()
+		app.Use(mw)
+		app.Get("/whoami", func(c fiber.Ctx) error {
+			sub, _ := c.Locals(types.AuthSubjectKey{}).(string)
+			actor, _ := c.Locals(types.AuthActorKey{}).(string)
+			return c.SendString(sub + " by " + actor)
+		})
+	})
+
+	do := func(claims map[string]any) (int, string) {
+		tok, err := testJWKS.MintWithClaims("support-1", claims, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		req := httptest.NewRequest("GET", "/whoami", nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		req.Header.Set(api.HeaderActAs, "auth0|customer")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		buf := make([]byte, 64)
+		n, _ := resp.Body.Read(buf)
+		return resp.StatusCode, string(buf[:n])
+	}
+
+	It("lets admins act as another subject", func() {
+		status, body := do(map[string]any{"scope": "pvapi:admin"})
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(Equal("auth0|customer by support-1"))
+	})
+
+	It("forbids everyone else", func() {
+		status, _ := do(map[string]any{"scope": "portfolios:write"})
+		Expect(status).To(Equal(fiber.StatusForbidden))
+	})
+})
@@ -21,6 +21,9 @@ import (
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v3"
 	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/idempotency"
+	"github.com/penny-vault/pv-api/quota"
 )
 
 // Sentinel domain errors. Handlers return these; WriteProblem maps them
@@ -89,11 +92,15 @@ func classify(err error) (int, string) {
 	switch {
 	case errors.Is(err, ErrInvalidToken):
 		return fiber.StatusUnauthorized, "Unauthorized"
+	case errors.Is(err, ErrForbidden), errors.Is(err, quota.ErrExceeded):
+		return fiber.StatusForbidden, "Forbidden"
+	case errors.Is(err, quota.ErrRateLimited):
+		return fiber.StatusTooManyRequests, "Too Many Requests"
 	case errors.Is(err, ErrNotFound):
 		return fiber.StatusNotFound, "Not Found"
-	case errors.Is(err, ErrConflict):
+	case errors.Is(err, ErrConflict), errors.Is(err, idempotency.ErrInProgress):
 		return fiber.StatusConflict, "Conflict"
-	case errors.Is(err, ErrInvalidParams):
+	case errors.Is(err, ErrInvalidParams), errors.Is(err, idempotency.ErrMismatch):
 		return fiber.StatusUnprocessableEntity, "Unprocessable Entity"
 	case errors.Is(err, ErrNotImplemented):
 		return fiber.StatusNotImplemented, "Not Implemented"
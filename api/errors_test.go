@@ -59,6 +59,14 @@ var _ = Describe("WriteProblem", func() {
 		Expect(p.Title).To(Equal("Not Found"))
 	})
 
+	It("returns 403 for ErrForbidden", func() {
+		p := run(func(c fiber.Ctx) error {
+			return api.WriteProblem(c, api.ErrForbidden)
+		})
+		Expect(p.Status).To(Equal(403))
+		Expect(p.Title).To(Equal("Forbidden"))
+	})
+
 	It("returns 409 for ErrConflict", func() {
 		p := run(func(c fiber.Ctx) error {
 			return api.WriteProblem(c, api.ErrConflict)
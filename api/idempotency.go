@@ -0,0 +1,100 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/idempotency"
+	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// idempotentRoutes are the POST routes that honour Idempotency-Key: the
+// ones a retry would otherwise repeat.
+var idempotentRoutes = []string{
+	"/portfolios",
+	"/portfolios/:slug/run",
+	"/portfolios/:slug/upgrade",
+	"/portfolios/:slug/alerts",
+}
+
+// RegisterIdempotencyWith puts Idempotency in front of the routes that
+// honour Idempotency-Key. It must be called before those routes are
+// mounted.
+func RegisterIdempotencyWith(r fiber.Router, store idempotency.Store, ttl time.Duration) {
+	mw := Idempotency(store, ttl)
+	for _, path := range idempotentRoutes {
+		r.Post(path, mw)
+	}
+}
+
+// Idempotency returns a handler that replays the stored response when a
+// request repeats an Idempotency-Key its subject already used within ttl.
+// Requests without the header pass straight through. Server errors and
+// 429s are not kept, so retrying them runs the request again. It must run
+// after the auth middleware.
+func Idempotency(store idempotency.Store, ttl time.Duration) fiber.Handler {
+	if ttl <= 0 {
+		ttl = idempotency.DefaultTTL
+	}
+	return func(c fiber.Ctx) error {
+		key := c.Get(idempotency.Header)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > idempotency.MaxKeyLength {
+			return WriteProblem(c, fmt.Errorf("%w: %s must be at most %d characters",
+				ErrInvalidParams, idempotency.Header, idempotency.MaxKeyLength))
+		}
+		key = string([]byte(key))
+		sub, _ := c.Locals(types.AuthSubjectKey{}).(string)
+		fp := idempotency.Fingerprint(c.Method(), c.OriginalURL(), c.Get(workspace.HeaderWorkspace), c.Body())
+
+		saved, err := store.Reserve(c.Context(), sub, key, fp, ttl)
+		if err != nil {
+			return WriteProblem(c, err)
+		}
+		if saved != nil {
+			c.Set(idempotency.ReplayedHeader, "true")
+			if saved.ContentType != "" {
+				c.Set(fiber.HeaderContentType, saved.ContentType)
+			}
+			return c.Status(saved.Status).Send(saved.Body)
+		}
+
+		err = c.Next()
+		status := c.Response().StatusCode()
+		if err != nil || status >= fiber.StatusInternalServerError || status == fiber.StatusTooManyRequests {
+			if relErr := store.Release(c.Context(), sub, key); relErr != nil {
+				log.Warn().Err(relErr).Str("subject", sub).Msg("idempotency: release key")
+			}
+			return err
+		}
+		resp := idempotency.Response{
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}
+		if err := store.Complete(c.Context(), sub, key, resp); err != nil {
+			log.Warn().Err(err).Str("subject", sub).Msg("idempotency: store response")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/api"
+	"github.com/penny-vault/pv-api/idempotency"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// memIdempotency is an in-memory idempotency.Store.
+type memIdempotency struct {
+	mu   sync.Mutex
+	rows map[string]*memIdempotencyRow
+}
+
+type memIdempotencyRow struct {
+	fingerprint []byte
+	resp        *idempotency.Response
+}
+
+func (m *memIdempotency) Reserve(_ context.Context, subject, key string, fp []byte, _ time.Duration) (*idempotency.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	row, ok := m.rows[subject+"/"+key]
+	switch {
+	case !ok:
+		m.rows[subject+"/"+key] = &memIdempotencyRow{fingerprint: fp}
+		return nil, nil
+	case !bytes.Equal(row.fingerprint, fp):
+		return nil, idempotency.ErrMismatch
+	case row.resp == nil:
+		return nil, idempotency.ErrInProgress
+	}
+	return row.resp, nil
+}
+
+func (m *memIdempotency) Complete(_ context.Context, subject, key string, r idempotency.Response) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows[subject+"/"+key].resp = &r
+	return nil
+}
+
+func (m *memIdempotency) Release(_ context.Context, subject, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.rows, subject+"/"+key)
+	return nil
+}
+
+var _ = Describe("Idempotency", func() {
+	var (
+		app     *fiber.App
+		store   *memIdempotency
+		creates int
+		failing bool
+	)
+
+	BeforeEach(func() {
+		store = &memIdempotency{rows: map[string]*memIdempotencyRow{}}
+		creates = 0
+		failing = false
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, c.Get("X-Test-Sub"))
+			return c.Next()
+		})
+		api.RegisterIdempotencyWith(app, store, time.Hour)
+		app.Post("/portfolios", func(c fiber.Ctx) error {
+			if failing {
+				return c.SendStatus(fiber.StatusServiceUnavailable)
+			}
+			creates++
+			c.Set(fiber.HeaderContentType, "application/json")
+			return c.Status(fiber.StatusCreated).SendString(`{"slug":"p-` + strconv.Itoa(creates) + `"}`)
+		})
+	})
+
+	post := func(sub, key, body string) (int, string, string) {
+		req := httptest.NewRequest("POST", "/portfolios", bytes.NewBufferString(body))
+		req.Header.Set("X-Test-Sub", sub)
+		if key != "" {
+			req.Header.Set(idempotency.Header, key)
+		}
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		out, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(out), resp.Header.Get(idempotency.ReplayedHeader)
+	}
+
+	It("replays the first response to a retry", func() {
+		status, body, replayed := post("alice", "k1", `{"name":"a"}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+		Expect(replayed).To(BeEmpty())
+
+		status2, body2, replayed := post("alice", "k1", `{"name":"a"}`)
+		Expect(status2).To(Equal(fiber.StatusCreated))
+		Expect(body2).To(Equal(body))
+		Expect(replayed).To(Equal("true"))
+		Expect(creates).To(Equal(1))
+	})
+
+	It("runs every request without a key", func() {
+		post("alice", "", `{}`)
+		post("alice", "", `{}`)
+		Expect(creates).To(Equal(2))
+	})
+
+	It("keeps each subject's keys apart", func() {
+		post("alice", "k1", `{}`)
+		_, _, replayed := post("bob", "k1", `{}`)
+		Expect(replayed).To(BeEmpty())
+		Expect(creates).To(Equal(2))
+	})
+
+	It("refuses a key reused for a different request", func() {
+		post("alice", "k1", `{"name":"a"}`)
+		status, _, _ := post("alice", "k1", `{"name":"b"}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		Expect(creates).To(Equal(1))
+	})
+
+	It("lets a request that failed on the server run again", func() {
+		failing = true
+		status, _, _ := post("alice", "k1", `{}`)
+		Expect(status).To(Equal(fiber.StatusServiceUnavailable))
+		failing = false
+		status, _, replayed := post("alice", "k1", `{}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+		Expect(replayed).To(BeEmpty())
+	})
+
+	It("answers 409 while the first request is running", func() {
+		store.rows["alice/k1"] = &memIdempotencyRow{fingerprint: idempotency.Fingerprint("POST", "/portfolios", "", []byte(`{}`))}
+		status, _, _ := post("alice", "k1", `{}`)
+		Expect(status).To(Equal(fiber.StatusConflict))
+	})
+})
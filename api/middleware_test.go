@@ -28,6 +28,8 @@ import (
 
 	"github.com/penny-vault/pv-api/api"
 	"github.com/penny-vault/pv-api/api/apitesting"
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/types"
 )
 
 var _ = Describe("Middleware", func() {
@@ -106,3 +108,66 @@ var _ = Describe("Middleware", func() {
 		Expect(buf.String()).To(ContainSubstring(`"path":"/v3/strategies"`))
 	})
 })
+
+var _ = Describe("RateLimit", func() {
+	It("answers 429 once a subject uses up its minute", func() {
+		app := fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, c.Get("X-Test-Sub"))
+			return c.Next()
+		})
+		app.Use(api.RateLimit(2))
+		app.Get("/x", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+		get := func(sub string) int {
+			req := httptest.NewRequest("GET", "/x", nil)
+			req.Header.Set("X-Test-Sub", sub)
+			resp, err := app.Test(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+		Expect(get("alice")).To(Equal(fiber.StatusOK))
+		Expect(get("alice")).To(Equal(fiber.StatusOK))
+		Expect(get("alice")).To(Equal(fiber.StatusTooManyRequests))
+		Expect(get("bob")).To(Equal(fiber.StatusOK))
+	})
+})
+
+// recordedEntries collects what AuditAdminActions records.
+type recordedEntries []audit.Entry
+
+func (r *recordedEntries) Record(_ context.Context, e audit.Entry) error {
+	*r = append(*r, e)
+	return nil
+}
+
+var _ = Describe("AuditAdminActions", func() {
+	It("records successful admin changes with the acting admin", func() {
+		rec := &recordedEntries{}
+		app := fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|admin")
+			return c.Next()
+		})
+		app.Use("/admin", api.AuditAdminActions(rec))
+		app.Get("/admin/things", func(c fiber.Ctx) error { return c.SendString("ok") })
+		app.Post("/admin/things/:id/hide", func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+		app.Post("/admin/things/:id/fail", func(c fiber.Ctx) error { return c.SendStatus(fiber.StatusNotFound) })
+
+		for _, r := range []struct{ method, target string }{
+			{"GET", "/admin/things"},
+			{"POST", "/admin/things/adm/hide"},
+			{"POST", "/admin/things/adm/fail"},
+		} {
+			resp, err := app.Test(httptest.NewRequest(r.method, r.target, nil))
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+		}
+
+		Expect(*rec).To(HaveLen(1))
+		Expect((*rec)[0].Action).To(Equal(audit.ActionAdmin))
+		Expect((*rec)[0].Actor).To(Equal("auth0|admin"))
+		Expect((*rec)[0].Target).To(Equal("POST /admin/things/adm/hide"))
+	})
+})
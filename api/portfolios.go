@@ -19,7 +19,12 @@ import (
 	"github.com/gofiber/fiber/v3"
 
 	"github.com/penny-vault/pv-api/alert"
+	"github.com/penny-vault/pv-api/apikey"
+	"github.com/penny-vault/pv-api/gql"
+	"github.com/penny-vault/pv-api/notification"
 	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/quota"
+	"github.com/penny-vault/pv-api/workspace"
 )
 
 // RegisterPortfolioRoutes mounts every portfolio endpoint as stubs (501).
@@ -42,6 +47,19 @@ func RegisterPortfolioRoutes(r fiber.Router) {
 	r.Get("/portfolios/:slug/holdings-impact", stubPortfolio)
 	r.Get("/portfolios/:slug/holdings/:date", stubPortfolio)
 	r.Get("/portfolios/:slug/prediction", stubPortfolio)
+	r.Get("/portfolios/:slug/calendar.ics", stubPortfolio)
+	r.Get("/me/calendar", stubPortfolio)
+	r.Post("/me/calendar", stubPortfolio)
+	r.Delete("/me/calendar", stubPortfolio)
+	r.Get("/portfolios/:slug/checklist", stubPortfolio)
+	r.Get("/portfolios/:slug/fills", stubPortfolio)
+	r.Post("/portfolios/:slug/fills", stubPortfolio)
+	r.Delete("/portfolios/:slug/fills/:fillId", stubPortfolio)
+	r.Get("/portfolios/:slug/tracking", stubPortfolio)
+	r.Get("/portfolios/:slug/share", stubPortfolio)
+	r.Post("/portfolios/:slug/transfer", stubPortfolio)
+	r.Put("/portfolios/:slug/share", stubPortfolio)
+	r.Delete("/portfolios/:slug/share", stubPortfolio)
 	r.Post("/portfolios/:slug/upgrade", stubPortfolio)
 	r.Post("/portfolios/:slug/run", stubPortfolio)
 	r.Post("/portfolios/:slug/email-summary", stubPortfolio) // real path: RegisterAlertRoutesWith
@@ -67,6 +85,19 @@ func RegisterPortfolioRoutesWith(r fiber.Router, h *portfolio.Handler) {
 	r.Get("/portfolios/:slug/holdings/:date", h.HoldingsAsOf)
 	r.Get("/portfolios/:slug/holdings-impact", h.HoldingsImpact)
 	r.Get("/portfolios/:slug/prediction", h.Prediction)
+	r.Get("/portfolios/:slug/calendar.ics", h.Calendar)
+	r.Get("/me/calendar", h.CalendarFeed)
+	r.Post("/me/calendar", h.IssueCalendarFeed)
+	r.Delete("/me/calendar", h.RevokeCalendarFeed)
+	r.Get("/portfolios/:slug/checklist", h.Checklist)
+	r.Get("/portfolios/:slug/fills", h.ListFills)
+	r.Post("/portfolios/:slug/fills", h.CreateFill)
+	r.Delete("/portfolios/:slug/fills/:fillId", h.DeleteFill)
+	r.Get("/portfolios/:slug/tracking", h.Tracking)
+	r.Get("/portfolios/:slug/share", h.GetShare)
+	r.Post("/portfolios/:slug/transfer", h.Transfer)
+	r.Put("/portfolios/:slug/share", h.PutShare)
+	r.Delete("/portfolios/:slug/share", h.DeleteShare)
 	r.Get("/portfolios/:slug/performance", h.Performance)
 	r.Get("/portfolios/:slug/transactions", h.Transactions)
 	r.Post("/portfolios/:slug/upgrade", h.Upgrade)
@@ -80,15 +111,85 @@ func RegisterPortfolioRoutesWith(r fiber.Router, h *portfolio.Handler) {
 func RegisterAlertRoutesWith(r fiber.Router, h *alert.AlertHandler) {
 	r.Post("/portfolios/:slug/alerts", h.Create)
 	r.Get("/portfolios/:slug/alerts", h.List)
+	r.Get("/portfolios/:slug/alerts/:alertId", h.Get)
 	r.Patch("/portfolios/:slug/alerts/:alertId", h.Update)
 	r.Delete("/portfolios/:slug/alerts/:alertId", h.Delete)
+	r.Get("/portfolios/:slug/alerts/:alertId/deliveries", h.Deliveries)
+	r.Post("/portfolios/:slug/alerts/:alertId/confirmations", h.ResendConfirmations)
 	r.Post("/portfolios/:slug/email-summary", h.SendSummary)
 }
 
+// RegisterDigestRoutesWith mounts the caller's digest subscription endpoints
+// backed by h.
+func RegisterDigestRoutesWith(r fiber.Router, h *alert.DigestHandler) {
+	r.Get("/me/digest", h.Get)
+	r.Put("/me/digest", h.Put)
+	r.Delete("/me/digest", h.Delete)
+}
+
+// RegisterNotificationRoutesWith mounts the caller's notification inbox and
+// its event stream backed by h.
+func RegisterNotificationRoutesWith(r fiber.Router, h *notification.Handler) {
+	r.Get("/notifications", h.List)
+	r.Get("/notifications/stream", h.Stream)
+	r.Post("/notifications/read", h.MarkAllRead)
+	r.Post("/notifications/:id/read", h.MarkRead)
+}
+
+// RegisterWorkspaceRoutesWith mounts workspace and membership endpoints
+// backed by h.
+func RegisterWorkspaceRoutesWith(r fiber.Router, h *workspace.Handler) {
+	r.Get("/workspaces", h.List)
+	r.Post("/workspaces", h.Create)
+	r.Get("/workspaces/:id", h.Get)
+	r.Patch("/workspaces/:id", h.Patch)
+	r.Delete("/workspaces/:id", h.Delete)
+	r.Put("/workspaces/:id/members/:sub", h.PutMember)
+	r.Delete("/workspaces/:id/members/:sub", h.DeleteMember)
+}
+
+// RegisterAPIKeyRoutesWith mounts the personal API key endpoints.
+func RegisterAPIKeyRoutesWith(r fiber.Router, h *apikey.Handler) {
+	r.Get("/api-keys", h.List)
+	r.Post("/api-keys", h.Create)
+	r.Delete("/api-keys/:id", h.Delete)
+}
+
+// RegisterUsageRoutesWith mounts the quota usage endpoint.
+func RegisterUsageRoutesWith(r fiber.Router, h *quota.Handler) {
+	r.Get("/me/usage", h.Usage)
+}
+
+// RegisterGraphQLRoutesWith mounts the read-only GraphQL gateway.
+func RegisterGraphQLRoutesWith(r fiber.Router, h *gql.Handler) {
+	r.Post("/graphql", h.Query)
+}
+
 // RegisterPublicAlertRoutesWith mounts unauthenticated alert endpoints on the
 // root router. These routes must be registered before the auth middleware group.
 func RegisterPublicAlertRoutesWith(r fiber.Router, h *alert.AlertHandler) {
 	r.Get("/api/alerts/unsubscribe", h.Unsubscribe)
+	r.Get("/api/alerts/confirm", h.Confirm)
+	r.Post("/api/alerts/confirm", h.ConfirmSubmit)
+}
+
+// RegisterPublicCalendarRoutesWith mounts the per-user calendar feed on the
+// root router. Calendar apps cannot send a bearer token, so the feed is
+// authorized by the signed token in its URL instead.
+func RegisterPublicCalendarRoutesWith(r fiber.Router, h *portfolio.Handler) {
+	r.Get("/api/calendar.ics", h.PublicCalendar)
+}
+
+// RegisterPublicShareRoutesWith mounts the read-only views of shared
+// portfolios on the root router. The share token or handle in the path is
+// the only credential.
+func RegisterPublicShareRoutesWith(r fiber.Router, h *portfolio.Handler) {
+	r.Get("/api/shared/:ref", h.SharedPortfolio)
+	r.Get("/api/shared/:ref/summary", h.SharedSummary)
+	r.Get("/api/shared/:ref/performance", h.SharedPerformance)
+	r.Get("/api/shared/:ref/drawdowns", h.SharedDrawdowns)
+	r.Get("/api/shared/:ref/trailing-returns", h.SharedTrailingReturns)
+	r.Get("/api/shared/:ref/holdings", h.SharedHoldings)
 }
 
 func stubPortfolio(c fiber.Ctx) error { return WriteProblem(c, ErrNotImplemented) }
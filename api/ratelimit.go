@@ -0,0 +1,46 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/limiter"
+
+	"github.com/penny-vault/pv-api/quota"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// RateLimit returns a handler that allows each authenticated subject
+// perMinute requests in any sliding minute and answers the rest 429. It
+// must run after the auth middleware. Counts are kept in memory, so each
+// server enforces the limit on its own.
+func RateLimit(perMinute int) fiber.Handler {
+	return limiter.New(limiter.Config{
+		Max:               perMinute,
+		Expiration:        time.Minute,
+		LimiterMiddleware: limiter.SlidingWindow{},
+		KeyGenerator: func(c fiber.Ctx) string {
+			sub, _ := c.Locals(types.AuthSubjectKey{}).(string)
+			return sub
+		},
+		LimitReached: func(c fiber.Ctx) error {
+			return WriteProblem(c, fmt.Errorf("%w: at most %d requests per minute", quota.ErrRateLimited, perMinute))
+		},
+	})
+}
@@ -0,0 +1,136 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Resources that route scopes name. A route's resource is the first
+// segment of its path under /api/v3, except that alerts, which live under
+// a portfolio, are their own resource and /me and /api-keys are "account".
+const (
+	ResourcePortfolios    = "portfolios"
+	ResourceAlerts        = "alerts"
+	ResourceStrategies    = "strategies"
+	ResourceWorkspaces    = "workspaces"
+	ResourceNotifications = "notifications"
+	ResourceAccount       = "account"
+)
+
+var resources = []string{
+	ResourcePortfolios, ResourceAlerts, ResourceStrategies,
+	ResourceWorkspaces, ResourceNotifications, ResourceAccount,
+}
+
+// Scope names the grant for reading or writing resource, e.g.
+// "portfolios:write".
+func Scope(resource string, write bool) string {
+	if write {
+		return resource + ":write"
+	}
+	return resource + ":read"
+}
+
+// RequireRouteScopes returns a handler that 403s unless the caller holds
+// the scope for the route's resource: "<resource>:read" for GET and HEAD,
+// "<resource>:write" for everything else. A write scope also grants read,
+// and adminScope grants everything. prefix is stripped from the path
+// before the resource is worked out. Paths with no known resource need
+// adminScope, so a new route is locked until it is given one; /admin is
+// left to its own RequireScope check. /graphql needs read on every
+// resource it exposes.
+func RequireRouteScopes(prefix, adminScope string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if adminScope != "" && HasScope(c, adminScope) {
+			return c.Next()
+		}
+		path := strings.TrimPrefix(c.Path(), prefix)
+		if path == "/admin" || strings.HasPrefix(path, "/admin/") {
+			return c.Next()
+		}
+		if path == "/graphql" {
+			return requireGraphQLScopes(c)
+		}
+		resource := routeResource(path)
+		if resource == "" {
+			return WriteProblem(c, fmt.Errorf("%w: requires %q", ErrForbidden, adminScope))
+		}
+		write := Scope(resource, true)
+		if HasScope(c, write) {
+			return c.Next()
+		}
+		if isSafeMethod(c.Method()) {
+			read := Scope(resource, false)
+			if HasScope(c, read) {
+				return c.Next()
+			}
+			return WriteProblem(c, fmt.Errorf("%w: requires %q", ErrForbidden, read))
+		}
+		return WriteProblem(c, fmt.Errorf("%w: requires %q", ErrForbidden, write))
+	}
+}
+
+// graphQLResources are what the GraphQL gateway reads. It only reads,
+// although it is called with POST, so a read scope on each is enough.
+var graphQLResources = []string{ResourcePortfolios, ResourceStrategies}
+
+func requireGraphQLScopes(c fiber.Ctx) error {
+	for _, r := range graphQLResources {
+		if !HasScope(c, Scope(r, true)) && !HasScope(c, Scope(r, false)) {
+			return WriteProblem(c, fmt.Errorf("%w: requires %q", ErrForbidden, Scope(r, false)))
+		}
+	}
+	return c.Next()
+}
+
+// routeResource returns the resource a path under /api/v3 belongs to, or
+// "" if it belongs to none.
+func routeResource(path string) string {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	switch segs[0] {
+	case "portfolios":
+		if len(segs) >= 3 && segs[2] == "alerts" {
+			return ResourceAlerts
+		}
+		return ResourcePortfolios
+	case "strategies":
+		return ResourceStrategies
+	case "workspaces":
+		return ResourceWorkspaces
+	case "notifications":
+		return ResourceNotifications
+	case "me", "api-keys":
+		return ResourceAccount
+	}
+	return ""
+}
+
+// apiKeyGrants are the route scopes a personal API key carries: read on
+// every resource, plus write unless the key is read-only.
+func apiKeyGrants(readOnly bool) []string {
+	grants := make([]string, 0, 2*len(resources))
+	for _, r := range resources {
+		grants = append(grants, Scope(r, false))
+		if !readOnly {
+			grants = append(grants, Scope(r, true))
+		}
+	}
+	return grants
+}
@@ -29,11 +29,18 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/penny-vault/pv-api/alert"
+	"github.com/penny-vault/pv-api/apikey"
+	"github.com/penny-vault/pv-api/audit"
 	"github.com/penny-vault/pv-api/backtest"
+	"github.com/penny-vault/pv-api/gql"
+	"github.com/penny-vault/pv-api/idempotency"
+	"github.com/penny-vault/pv-api/notification"
 	"github.com/penny-vault/pv-api/portfolio"
 	"github.com/penny-vault/pv-api/progress"
+	"github.com/penny-vault/pv-api/quota"
 	"github.com/penny-vault/pv-api/snapshot"
 	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/workspace"
 )
 
 // Config-validation errors for startRegistrySync.
@@ -61,9 +68,18 @@ type Config struct {
 	SnapshotOpener    portfolio.SnapshotOpener
 	SnapshotsDir      string // optional: enables snapshot dir cleanup on portfolio delete
 	ProgressHub       *progress.Hub
+	NotificationHub   *notification.Hub     // optional: if nil, a private hub serves only this app's events
 	AlertChecker      alert.EmailSummarizer // optional: if nil, email-summary returns 503
 	UnsubscribeSecret string                // optional: HMAC secret for unsubscribe tokens
+	CalendarSecret    string                // optional: keys stored calendar feed token hashes; empty disables the per-user feed
 	Ephemeral         EphemeralConfig
+	// ValidateSandbox runs POST /strategies/validate. It is only set in
+	// docker runner mode; nil makes the endpoint answer 503.
+	ValidateSandbox *strategy.ValidateSandbox
+	Quotas          quota.Limits // zero limits are unlimited
+	// IdempotencyTTL is how long Idempotency-Key responses are replayed;
+	// zero means idempotency.DefaultTTL.
+	IdempotencyTTL time.Duration
 }
 
 // RegistryConfig configures the strategy registry sync and its install
@@ -81,6 +97,8 @@ type RegistryConfig struct {
 	StatsRefreshTime  string        // US Eastern "HH:MM"; default "17:00"
 	StatsStartDate    time.Time     // backtest start; default 2010-01-01
 	StatsTickInterval time.Duration // ticker cadence; default 5m
+	// WebhookSecret signs the strategy-sync webhooks; empty leaves them unmounted.
+	WebhookSecret string
 }
 
 // NewApp builds a Fiber v3 app with pvapi's middleware stack and routes.
@@ -113,11 +131,20 @@ func NewApp(ctx context.Context, conf Config) (*fiber.App, error) {
 	app.Get("/healthz", Healthz)
 	RegisterDocsRoutes(app)
 
+	if conf.Pool != nil && conf.Auth.APIKeys == nil {
+		conf.Auth.APIKeys = apikey.NewPoolStore(conf.Pool)
+	}
 	auth, err := NewAuthMiddleware(ctx, conf.Auth)
 	if err != nil {
 		return nil, fmt.Errorf("build auth middleware: %w", err)
 	}
 	protected := app.Group("/api/v3", auth)
+	if conf.Auth.RequireScopes {
+		protected.Use(RequireRouteScopes("/api/v3", conf.Auth.AdminScope))
+	}
+	if conf.Quotas.RequestsPerMinute > 0 {
+		protected.Use(RateLimit(conf.Quotas.RequestsPerMinute))
+	}
 
 	if conf.Pool != nil {
 		portfolioStore := portfolio.NewPoolStore(conf.Pool)
@@ -142,25 +169,73 @@ func NewApp(ctx context.Context, conf Config) (*fiber.App, error) {
 		if conf.SnapshotsDir != "" {
 			portfolioHandler.WithSnapshotsDir(conf.SnapshotsDir)
 		}
+		portfolioHandler.WithCalendarTokens(portfolio.NewPoolCalendarTokenStore(conf.Pool), conf.CalendarSecret)
+		portfolioHandler.WithFills(portfolio.NewPoolFillStore(conf.Pool))
+		portfolioHandler.WithShares(portfolio.NewPoolShareStore(conf.Pool))
+		workspaceStore := workspace.NewPoolStore(conf.Pool)
+		portfolioHandler.WithWorkspaces(workspaceStore)
+		quotaCounter := quota.NewPoolCounter(conf.Pool)
+		portfolioHandler.WithQuotas(conf.Quotas, quotaCounter)
+		auditStore := audit.NewPoolStore(conf.Pool)
+		portfolioHandler.WithAudit(auditStore)
+		RegisterIdempotencyWith(protected, idempotency.NewPoolStore(conf.Pool), conf.IdempotencyTTL)
 		RegisterPortfolioRoutesWith(protected, portfolioHandler)
+		RegisterPublicCalendarRoutesWith(app, portfolioHandler)
+		RegisterPublicShareRoutesWith(app, portfolioHandler)
 		alertStore := alert.NewPoolStore(conf.Pool)
-		alertHandler := alert.NewAlertHandlerWithChecker(portfolioStore, alertStore, conf.AlertChecker, conf.UnsubscribeSecret)
+		alertHandler := alert.NewAlertHandlerWithChecker(portfolioStore, alertStore, conf.AlertChecker, conf.UnsubscribeSecret).
+			WithDigests(alertStore).
+			WithWorkspaces(workspaceStore).
+			WithQuotas(conf.Quotas).
+			WithAudit(auditStore)
 		RegisterAlertRoutesWith(protected, alertHandler)
+		RegisterAuditRoutesWith(protected, conf.Auth.AdminScope, audit.NewHandler(auditStore).WithWorkspaces(workspaceStore))
+		RegisterUsageRoutesWith(protected, quota.NewHandler(conf.Quotas, quotaCounter).WithWorkspaces(workspaceStore))
+		RegisterGraphQLRoutesWith(protected, gql.NewHandler(portfolioStore, strategyStore, opener).WithWorkspaces(workspaceStore))
+		RegisterWorkspaceRoutesWith(protected, workspace.NewHandler(workspaceStore).WithCreateLimit(conf.Quotas.Workspaces))
+		RegisterAPIKeyRoutesWith(protected, apikey.NewHandler(apikey.NewPoolStore(conf.Pool)))
+		digestConfirmer, _ := conf.AlertChecker.(alert.DigestConfirmer)
+		RegisterDigestRoutesWith(protected, alert.NewDigestHandler(alertStore, digestConfirmer))
 		RegisterPublicAlertRoutesWith(app, alertHandler)
 		RegisterStrategyRoutesWith(protected, NewStrategyHandler(
 			strategyStore,
 			strategy.EphemeralBuild,
 			strategy.ValidateCloneURL,
 			ephOpts,
+			conf.ValidateSandbox,
 		))
 
-		autoUpgrader := portfolio.NewAutoUpgrader(portfolioHandler, strategyStore)
-		if err := startRegistrySync(ctx, strategyStore, strategyStore, autoUpgrader, conf.Registry); err != nil {
+		notificationHub := conf.NotificationHub
+		if notificationHub == nil {
+			notificationHub = notification.NewHub()
+		}
+		notificationStore := notification.NewPoolStore(conf.Pool)
+		RegisterNotificationRoutesWith(protected, notification.NewHandler(notificationStore, notificationHub))
+
+		autoUpgrader := portfolio.NewAutoUpgrader(portfolioHandler, strategyStore).
+			WithInbox(notification.NewService(notificationStore, notificationHub).WithMembers(workspaceStore))
+		syncer, statsRefresher, err := startRegistrySync(ctx, strategyStore, strategyStore, autoUpgrader, conf.Registry)
+		if err != nil {
 			return nil, fmt.Errorf("start registry sync: %w", err)
 		}
+		protected.Use("/admin", AuditAdminActions(auditStore))
+		RegisterAdminRoutesWith(protected, conf.Auth.AdminScope, &strategy.AdminHandler{
+			Store:  strategyStore,
+			Syncer: syncer,
+			Stats:  statsRefresher,
+		})
+		if conf.Registry.WebhookSecret != "" {
+			RegisterWebhookRoutesWith(app, &strategy.WebhookHandler{
+				Secret:     []byte(conf.Registry.WebhookSecret),
+				Strategies: strategyStore,
+				Deliveries: strategyStore,
+				Sync:       syncer.SyncOne,
+			})
+		}
 	} else {
 		RegisterPortfolioRoutes(protected)
 		RegisterStrategyRoutes(protected)
+		RegisterAdminRoutes(protected, conf.Auth.AdminScope)
 	}
 
 	return app, nil
@@ -178,6 +253,40 @@ func (h hostStatRunner) Run(ctx context.Context, req strategy.StatRunRequest) er
 	})
 }
 
+// DockerStatRunner adapts backtest.DockerRunner to strategy.StatRunner.
+// Artifact is an image reference and OutPath must sit under the runner's
+// SnapshotsDir so the container can write it.
+type DockerStatRunner struct {
+	Runner *backtest.DockerRunner
+}
+
+func (d DockerStatRunner) Run(ctx context.Context, req strategy.StatRunRequest) error {
+	return d.Runner.Run(ctx, backtest.RunRequest{
+		Artifact:     req.Artifact,
+		ArtifactKind: backtest.ArtifactImage,
+		Args:         req.Args,
+		OutPath:      req.OutPath,
+	})
+}
+
+// snapshotSchemaIssues adapts snapshot.CheckSchema to strategy.SnapshotCheckFunc.
+func snapshotSchemaIssues(ctx context.Context, path string) ([]strategy.ValidationIssue, error) {
+	issues, err := snapshot.CheckSchema(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("check snapshot schema: %w", err)
+	}
+	out := make([]strategy.ValidationIssue, 0, len(issues))
+	for _, is := range issues {
+		out = append(out, strategy.ValidationIssue{
+			Table:   is.Table,
+			Column:  is.Column,
+			Key:     is.Key,
+			Message: is.Message,
+		})
+	}
+	return out, nil
+}
+
 // snapshotKpis reads KPI metrics from a snapshot file at the given path.
 func snapshotKpis(ctx context.Context, path string) (strategy.StatKpis, error) {
 	reader, err := snapshot.Open(path)
@@ -207,18 +316,19 @@ func snapshotKpis(ctx context.Context, path string) (strategy.StatKpis, error) {
 
 // startRegistrySync spins off a goroutine that runs the strategy.Syncer
 // on conf.SyncInterval. Runs independently of the HTTP server; errors are
-// logged but never propagated.
+// logged but never propagated. The Syncer and StatsRefresher are returned
+// so webhooks and the admin routes can trigger work between ticks.
 func startRegistrySync(ctx context.Context, store strategy.Store, statsStore strategy.StatsStore,
 	autoUpgrader strategy.PortfolioAutoUpgrader, conf RegistryConfig,
-) error {
+) (*strategy.Syncer, *strategy.StatsRefresher, error) {
 	if conf.SyncInterval <= 0 {
-		return ErrRegistrySyncInterval
+		return nil, nil, ErrRegistrySyncInterval
 	}
 	if conf.OfficialDir == "" {
-		return ErrRegistryOfficialDir
+		return nil, nil, ErrRegistryOfficialDir
 	}
 	if conf.GitHubOwner == "" {
-		return ErrRegistryGitHubOwner
+		return nil, nil, ErrRegistryGitHubOwner
 	}
 	cacheDir := conf.CacheDir
 	if cacheDir == "" {
@@ -244,7 +354,7 @@ func startRegistrySync(ctx context.Context, store strategy.Store, statsStore str
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("building stats refresher: %w", err)
+		return nil, nil, fmt.Errorf("building stats refresher: %w", err)
 	}
 
 	syncer := strategy.NewSyncer(store, strategy.SyncerOptions{
@@ -261,5 +371,5 @@ func startRegistrySync(ctx context.Context, store strategy.Store, statsStore str
 	})
 	go func() { _ = syncer.Run(ctx) }()
 	go func() { statsRefresher.Run(ctx) }()
-	return nil
+	return syncer, statsRefresher, nil
 }
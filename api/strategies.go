@@ -22,20 +22,25 @@ import (
 )
 
 // StrategyHandler is the real-handler shim owned by api/. It delegates
-// to strategy.Handler for GET list/get endpoints, and to
-// strategy.DescribeHandler for the describe endpoint.
+// to strategy.Handler for GET list/get endpoints, to
+// strategy.DescribeHandler for the describe endpoint, and to
+// strategy.ValidateHandler for the validate endpoint.
 type StrategyHandler struct {
 	inner    *strategy.Handler
 	describe *strategy.DescribeHandler
+	validate *strategy.ValidateHandler
 }
 
 // NewStrategyHandler builds a StrategyHandler backed by the given read store,
-// builder, URL validator, and ephemeral options.
+// builder, URL validator, and ephemeral options. The validate endpoint runs
+// the caller's repo only inside sandbox and checks the result with
+// snapshot.CheckSchema; a nil sandbox makes it answer 503.
 func NewStrategyHandler(
-	store strategy.ReadStore,
+	store strategy.ListStore,
 	builder strategy.BuilderFunc,
 	validator strategy.URLValidatorFunc,
 	opts strategy.EphemeralOptions,
+	sandbox *strategy.ValidateSandbox,
 ) *StrategyHandler {
 	return &StrategyHandler{
 		inner: strategy.NewHandler(store),
@@ -44,6 +49,12 @@ func NewStrategyHandler(
 			URLValidator:  validator,
 			EphemeralOpts: opts,
 		},
+		validate: &strategy.ValidateHandler{
+			URLValidator:  validator,
+			Sandbox:       sandbox,
+			CheckSnapshot: snapshotSchemaIssues,
+			EphemeralOpts: opts,
+		},
 	}
 }
 
@@ -53,6 +64,7 @@ func NewStrategyHandler(
 func RegisterStrategyRoutes(r fiber.Router) {
 	r.Get("/strategies", stubListStrategies)
 	r.Get("/strategies/describe", stubDescribeStrategy)
+	r.Post("/strategies/validate", stubValidateStrategy)
 	r.Get("/strategies/:shortCode", stubGetStrategy)
 }
 
@@ -61,9 +73,19 @@ func RegisterStrategyRoutes(r fiber.Router) {
 func RegisterStrategyRoutesWith(r fiber.Router, h *StrategyHandler) {
 	r.Get("/strategies", h.inner.List)
 	r.Get("/strategies/describe", h.describe.Describe)
+	r.Post("/strategies/validate", h.validate.Validate)
 	r.Get("/strategies/:shortCode", h.inner.Get)
 }
 
+// RegisterWebhookRoutesWith mounts the strategy-sync webhooks. They sit
+// outside /api/v3 because senders authenticate with an HMAC signature, not
+// a bearer token.
+func RegisterWebhookRoutesWith(r fiber.Router, h *strategy.WebhookHandler) {
+	r.Post("/api/webhooks/github", h.GitHub)
+	r.Post("/api/webhooks/git", h.GitPush)
+}
+
 func stubListStrategies(c fiber.Ctx) error   { return WriteProblem(c, ErrNotImplemented) }
 func stubDescribeStrategy(c fiber.Ctx) error { return WriteProblem(c, ErrNotImplemented) }
+func stubValidateStrategy(c fiber.Ctx) error { return WriteProblem(c, ErrNotImplemented) }
 func stubGetStrategy(c fiber.Ctx) error      { return WriteProblem(c, ErrNotImplemented) }
@@ -44,6 +44,7 @@ var _ = Describe("Strategy handlers", func() {
 		},
 		Entry("list strategies", "GET", "/strategies"),
 		Entry("describe strategy", "GET", "/strategies/describe"),
+		Entry("validate strategy", "POST", "/strategies/validate"),
 		Entry("get strategy", "GET", "/strategies/adm"),
 	)
 })
@@ -0,0 +1,179 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apikey issues personal API keys for scripts and notebooks that
+// cannot log in interactively. A key authenticates as the user who created
+// it. Only its SHA-256 hash is stored, so a lost key cannot be recovered,
+// only revoked and replaced.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Prefix starts every API key, which is how the auth middleware tells keys
+// from JWTs.
+const Prefix = "pvk_"
+
+// displayLen is how much of a key is kept in the clear to identify it.
+const displayLen = len(Prefix) + 6
+
+// touchInterval limits how often last_used_at is written for a busy key.
+const touchInterval = time.Minute
+
+var (
+	// ErrNotFound is returned when a key does not exist or belongs to
+	// someone else.
+	ErrNotFound = errors.New("api key not found")
+	// ErrInvalid is returned by Verify for unknown and expired keys.
+	ErrInvalid = errors.New("invalid or expired api key")
+)
+
+// Key is an API key's record. The secret itself is never stored.
+type Key struct {
+	ID       uuid.UUID
+	OwnerSub string
+	Name     string
+	// Prefix is the start of the key, shown so users can tell keys apart.
+	Prefix string
+	// ReadOnly keys may only make GET and HEAD requests.
+	ReadOnly   bool
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// IsKey reports whether a bearer token looks like an API key.
+func IsKey(token string) bool { return strings.HasPrefix(token, Prefix) }
+
+// Generate returns a new random API key.
+func Generate() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("api key: %w", err)
+	}
+	return Prefix + base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// hash is what is stored for a key. Keys carry 256 bits of entropy, so a
+// plain SHA-256 is enough; a slow password hash would only slow requests.
+func hash(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
+
+// Store persists API keys.
+type Store interface {
+	// Create stores k under secret and returns it with ID, Prefix and
+	// CreatedAt filled in.
+	Create(ctx context.Context, k Key, secret string) (Key, error)
+	List(ctx context.Context, ownerSub string) ([]Key, error)
+	// Delete revokes a key. ErrNotFound if ownerSub does not own it.
+	Delete(ctx context.Context, ownerSub string, id uuid.UUID) error
+	// Verify returns the key secret belongs to and records its use.
+	// ErrInvalid if there is none or it has expired.
+	Verify(ctx context.Context, secret string) (Key, error)
+}
+
+// PoolStore is the pgxpool-backed Store.
+type PoolStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolStore(pool *pgxpool.Pool) *PoolStore { return &PoolStore{pool: pool} }
+
+const keyColumns = `id, owner_sub, name, prefix, read_only, expires_at, last_used_at, created_at`
+
+func scanKey(row pgx.Row) (Key, error) {
+	var k Key
+	err := row.Scan(&k.ID, &k.OwnerSub, &k.Name, &k.Prefix, &k.ReadOnly,
+		&k.ExpiresAt, &k.LastUsedAt, &k.CreatedAt)
+	return k, err
+}
+
+func (s *PoolStore) Create(ctx context.Context, k Key, secret string) (Key, error) {
+	k, err := scanKey(s.pool.QueryRow(ctx, `
+		INSERT INTO api_keys (owner_sub, name, prefix, key_hash, read_only, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+keyColumns,
+		k.OwnerSub, k.Name, secret[:displayLen], hash(secret), k.ReadOnly, k.ExpiresAt))
+	if err != nil {
+		return Key{}, fmt.Errorf("insert api key: %w", err)
+	}
+	return k, nil
+}
+
+func (s *PoolStore) List(ctx context.Context, ownerSub string) ([]Key, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+keyColumns+` FROM api_keys WHERE owner_sub = $1 ORDER BY created_at`, ownerSub)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer rows.Close()
+	var out []Key
+	for rows.Next() {
+		k, err := scanKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+func (s *PoolStore) Delete(ctx context.Context, ownerSub string, id uuid.UUID) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM api_keys WHERE id = $1 AND owner_sub = $2`, id, ownerSub)
+	if err != nil {
+		return fmt.Errorf("delete api key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *PoolStore) Verify(ctx context.Context, secret string) (Key, error) {
+	k, err := scanKey(s.pool.QueryRow(ctx,
+		`SELECT `+keyColumns+` FROM api_keys WHERE key_hash = $1`, hash(secret)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Key{}, ErrInvalid
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("look up api key: %w", err)
+	}
+	now := time.Now()
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return Key{}, ErrInvalid
+	}
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= touchInterval {
+		if _, err := s.pool.Exec(ctx,
+			`UPDATE api_keys SET last_used_at = now() WHERE id = $1`, k.ID); err != nil {
+			return Key{}, fmt.Errorf("touch api key: %w", err)
+		}
+		k.LastUsedAt = &now
+	}
+	return k, nil
+}
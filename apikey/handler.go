@@ -0,0 +1,206 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/types"
+)
+
+const maxNameLength = 100
+
+// Scopes a key can be created with.
+const (
+	ScopeRead      = "read"
+	ScopeReadWrite = "read-write"
+)
+
+var errNoSubject = errors.New("missing authenticated subject")
+
+// Handler serves /api-keys.
+type Handler struct {
+	store Store
+}
+
+func NewHandler(store Store) *Handler { return &Handler{store: store} }
+
+// List implements GET /api-keys: the caller's keys, without their secrets.
+func (h *Handler) List(c fiber.Ctx) error {
+	sub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	keys, err := h.store.List(c.Context(), sub)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	out := make([]keyView, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, toKeyView(k))
+	}
+	return writeJSON(c, fiber.StatusOK, out)
+}
+
+type createBody struct {
+	Name      string     `json:"name"`
+	Scope     string     `json:"scope"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// Create implements POST /api-keys. The response is the only time the key
+// itself is shown. Keys cannot mint other keys, so a leaked key cannot be
+// used to outlive its own revocation.
+func (h *Handler) Create(c fiber.Ctx) error {
+	sub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	if _, viaKey := c.Locals(types.AuthAPIKeyKey{}).(string); viaKey {
+		return writeProblem(c, fiber.StatusForbidden, "Forbidden", "API keys cannot create API keys")
+	}
+	var body createBody
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	name := strings.TrimSpace(body.Name)
+	if name == "" || len(name) > maxNameLength {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "name must be 1-100 characters")
+	}
+	readOnly := true
+	switch body.Scope {
+	case "", ScopeRead:
+	case ScopeReadWrite:
+		readOnly = false
+	default:
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity",
+			`scope must be "read" or "read-write"`)
+	}
+	if body.ExpiresAt != nil && !body.ExpiresAt.After(time.Now()) {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "expiresAt must be in the future")
+	}
+
+	secret, err := Generate()
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	k, err := h.store.Create(c.Context(), Key{
+		OwnerSub:  sub,
+		Name:      name,
+		ReadOnly:  readOnly,
+		ExpiresAt: body.ExpiresAt,
+	}, secret)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	v := toKeyView(k)
+	v.Key = secret
+	return writeJSON(c, fiber.StatusCreated, v)
+}
+
+// Delete implements DELETE /api-keys/{id}, revoking the key at once.
+func (h *Handler) Delete(c fiber.Ctx) error {
+	sub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "api key not found")
+	}
+	err = h.store.Delete(c.Context(), sub, id)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", err.Error())
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+type keyView struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Prefix     string  `json:"prefix"`
+	Scope      string  `json:"scope"`
+	ExpiresAt  *string `json:"expiresAt,omitempty"`
+	LastUsedAt *string `json:"lastUsedAt,omitempty"`
+	CreatedAt  string  `json:"createdAt"`
+	// Key is the secret; set only in the create response.
+	Key string `json:"key,omitempty"`
+}
+
+func toKeyView(k Key) keyView {
+	v := keyView{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scope:      ScopeReadWrite,
+		ExpiresAt:  formatTime(k.ExpiresAt),
+		LastUsedAt: formatTime(k.LastUsedAt),
+		CreatedAt:  k.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if k.ReadOnly {
+		v.Scope = ScopeRead
+	}
+	return v
+}
+
+func formatTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
+
+func subject(c fiber.Ctx) (string, error) {
+	sub, ok := c.Locals(types.AuthSubjectKey{}).(string)
+	if !ok || sub == "" {
+		return "", errNoSubject
+	}
+	return string([]byte(sub)), nil
+}
+
+func writeJSON(c fiber.Ctx, status int, v any) error {
+	body, err := sonic.Marshal(v)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/json")
+	return c.Status(status).Send(body)
+}
+
+func writeProblem(c fiber.Ctx, status int, title, detail string) error {
+	type problem struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+	body, _ := sonic.Marshal(problem{
+		Type: "about:blank", Title: title, Status: status, Detail: detail, Instance: c.Path(),
+	})
+	c.Set(fiber.HeaderContentType, "application/problem+json")
+	return c.Status(status).Send(body)
+}
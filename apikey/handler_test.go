@@ -0,0 +1,182 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/apikey"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// fakeStore is an in-memory apikey.Store keyed by secret.
+type fakeStore struct {
+	mu   sync.Mutex
+	keys map[string]apikey.Key
+}
+
+func (f *fakeStore) Create(_ context.Context, k apikey.Key, secret string) (apikey.Key, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	k.ID = uuid.Must(uuid.NewV7())
+	k.Prefix = secret[:10]
+	k.CreatedAt = time.Now()
+	f.keys[secret] = k
+	return k, nil
+}
+
+func (f *fakeStore) List(_ context.Context, ownerSub string) ([]apikey.Key, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []apikey.Key
+	for _, k := range f.keys {
+		if k.OwnerSub == ownerSub {
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, ownerSub string, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for s, k := range f.keys {
+		if k.ID == id && k.OwnerSub == ownerSub {
+			delete(f.keys, s)
+			return nil
+		}
+	}
+	return apikey.ErrNotFound
+}
+
+func (f *fakeStore) Verify(_ context.Context, secret string) (apikey.Key, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	k, ok := f.keys[secret]
+	if !ok {
+		return apikey.Key{}, apikey.ErrInvalid
+	}
+	return k, nil
+}
+
+var _ = Describe("API keys", func() {
+	var (
+		app    *fiber.App
+		store  *fakeStore
+		caller string
+		viaKey bool
+	)
+
+	do := func(method, target, body string) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		out, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(out)
+	}
+
+	BeforeEach(func() {
+		store = &fakeStore{keys: map[string]apikey.Key{}}
+		caller = "auth0|alice"
+		viaKey = false
+		h := apikey.NewHandler(store)
+		app = fiber.New(fiber.Config{JSONEncoder: sonic.Marshal, JSONDecoder: sonic.Unmarshal})
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, caller)
+			if viaKey {
+				c.Locals(types.AuthAPIKeyKey{}, "some-key")
+			}
+			return c.Next()
+		})
+		app.Get("/api-keys", h.List)
+		app.Post("/api-keys", h.Create)
+		app.Delete("/api-keys/:id", h.Delete)
+	})
+
+	It("shows a new key once and lists it without the secret", func() {
+		status, body := do("POST", "/api-keys", `{"name":"notebook","scope":"read-write"}`)
+		Expect(status).To(Equal(fiber.StatusCreated), body)
+		var created struct {
+			ID    string `json:"id"`
+			Key   string `json:"key"`
+			Scope string `json:"scope"`
+		}
+		Expect(sonic.Unmarshal([]byte(body), &created)).To(Succeed())
+		Expect(created.Key).To(HavePrefix(apikey.Prefix))
+		Expect(created.Scope).To(Equal(apikey.ScopeReadWrite))
+
+		k, err := store.Verify(context.Background(), created.Key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k.OwnerSub).To(Equal("auth0|alice"))
+		Expect(k.ReadOnly).To(BeFalse())
+
+		status, body = do("GET", "/api-keys", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(ContainSubstring(`"name":"notebook"`))
+		Expect(body).NotTo(ContainSubstring(created.Key))
+	})
+
+	It("defaults to read-only", func() {
+		status, body := do("POST", "/api-keys", `{"name":"cron"}`)
+		Expect(status).To(Equal(fiber.StatusCreated), body)
+		Expect(body).To(ContainSubstring(`"scope":"read"`))
+	})
+
+	It("validates the request", func() {
+		status, _ := do("POST", "/api-keys", `{"name":""}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		status, _ = do("POST", "/api-keys", `{"name":"x","scope":"admin"}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		status, _ = do("POST", "/api-keys", `{"name":"x","expiresAt":"2001-01-01T00:00:00Z"}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+	})
+
+	It("does not let a key create keys", func() {
+		viaKey = true
+		status, _ := do("POST", "/api-keys", `{"name":"x"}`)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+	})
+
+	It("revokes only the caller's keys", func() {
+		status, body := do("POST", "/api-keys", `{"name":"cron"}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+		var created struct {
+			ID string `json:"id"`
+		}
+		Expect(sonic.Unmarshal([]byte(body), &created)).To(Succeed())
+
+		caller = "auth0|mallory"
+		status, _ = do("DELETE", "/api-keys/"+created.ID, "")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+
+		caller = "auth0|alice"
+		status, _ = do("DELETE", "/api-keys/"+created.ID, "")
+		Expect(status).To(Equal(fiber.StatusNoContent))
+		Expect(store.keys).To(BeEmpty())
+	})
+})
@@ -0,0 +1,259 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit keeps an append-only trail of changes: portfolio creates,
+// edits, deletes, transfers and upgrades, run triggers, alert changes,
+// unsubscribes and admin actions. Each entry says who acted, on whose
+// behalf, in which request, and how each changed field went from before
+// to after. Recording never fails the change it describes; a failed write
+// is logged and dropped.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/types"
+)
+
+// Actions.
+const (
+	ActionPortfolioCreate      = "portfolio.create"
+	ActionPortfolioUpdate      = "portfolio.update"
+	ActionPortfolioDelete      = "portfolio.delete"
+	ActionPortfolioTransfer    = "portfolio.transfer"
+	ActionPortfolioUpgrade     = "portfolio.upgrade"
+	ActionPortfolioAutoUpgrade = "portfolio.auto_upgrade"
+	ActionRunTrigger           = "run.trigger"
+	ActionAlertCreate          = "alert.create"
+	ActionAlertUpdate          = "alert.update"
+	ActionAlertDelete          = "alert.delete"
+	ActionAlertUnsubscribe     = "alert.unsubscribe"
+	ActionAdmin                = "admin"
+)
+
+// ActorSystem is the actor of changes pvapi makes on its own, such as
+// automatic upgrades.
+const ActorSystem = "system"
+
+// ErrNotFound is returned when the portfolio whose trail is asked for does
+// not exist.
+var ErrNotFound = errors.New("portfolio not found")
+
+// Change is one field's value before and after an action. Before is nil
+// for creates and After is nil for deletes.
+type Change struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// Entry is one audit record.
+type Entry struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Action    string
+	// Actor is who acted. Subject is whose authority they acted with; it
+	// differs from Actor only when an admin acts as a user.
+	Actor   string
+	Subject string
+	// OwnerSub owns what changed; empty for admin actions on shared state.
+	OwnerSub      string
+	PortfolioID   *uuid.UUID
+	PortfolioSlug string
+	// Target names what changed below the portfolio, such as an alert ID,
+	// or the route of an admin action.
+	Target    string
+	RequestID string
+	Changes   map[string]Change
+}
+
+// FromRequest starts an entry for action with the actor, subject and
+// request ID of c.
+func FromRequest(c fiber.Ctx, action string) Entry {
+	sub, _ := c.Locals(types.AuthSubjectKey{}).(string)
+	actor, _ := c.Locals(types.AuthActorKey{}).(string)
+	if actor == "" {
+		actor = sub
+	}
+	rid, _ := c.Locals(types.RequestIDKey{}).(string)
+	return Entry{Action: action, Actor: actor, Subject: sub, RequestID: rid}
+}
+
+// System starts an entry for an action pvapi takes on its own.
+func System(action string) Entry {
+	return Entry{Action: action, Actor: ActorSystem, Subject: ActorSystem}
+}
+
+// Diff returns the fields whose values differ between before and after.
+// Either may be nil. Values are compared by their JSON encoding, so
+// pointers and the values they point to compare equal.
+func Diff(before, after map[string]any) map[string]Change {
+	out := map[string]Change{}
+	for k, b := range before {
+		a, ok := after[k]
+		if !ok || !sameJSON(a, b) {
+			out[k] = Change{Before: b, After: a}
+		}
+	}
+	for k, a := range after {
+		if _, ok := before[k]; !ok && !sameJSON(a, nil) {
+			out[k] = Change{After: a}
+		}
+	}
+	return out
+}
+
+func sameJSON(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// Recorder appends entries to the trail.
+type Recorder interface {
+	Record(ctx context.Context, e Entry) error
+}
+
+// Record writes e with r, logging instead of failing. A nil r records
+// nothing.
+func Record(ctx context.Context, r Recorder, e Entry) {
+	if r == nil {
+		return
+	}
+	if err := r.Record(ctx, e); err != nil {
+		log.Warn().Err(err).Str("action", e.Action).Str("actor", e.Actor).Msg("audit: record entry")
+	}
+}
+
+// Query selects entries, newest first. Empty fields do not filter.
+type Query struct {
+	OwnerSub    string
+	PortfolioID *uuid.UUID
+	// PortfolioSlug matches the slug the portfolio had when the entry was
+	// written, so the trail of a deleted portfolio stays reachable.
+	PortfolioSlug string
+	// Action matches exactly, or as a prefix up to a dot: "alert" matches
+	// "alert.create".
+	Action string
+	Actor  string
+	Since  *time.Time
+	Until  *time.Time
+	Limit  int
+	// Before is the id of the last entry on the previous page.
+	Before *uuid.UUID
+}
+
+// Store persists the trail.
+type Store interface {
+	Recorder
+	List(ctx context.Context, q Query) ([]Entry, error)
+	// PortfolioID resolves a live portfolio for the per-portfolio trail.
+	PortfolioID(ctx context.Context, ownerSub, slug string) (uuid.UUID, error)
+}
+
+// PoolStore is the pgxpool-backed Store.
+type PoolStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolStore(pool *pgxpool.Pool) *PoolStore { return &PoolStore{pool: pool} }
+
+const columns = `id, created_at, action, actor, subject, COALESCE(owner_sub, ''), portfolio_id,
+	COALESCE(portfolio_slug, ''), COALESCE(target, ''), COALESCE(request_id, ''), changes`
+
+// Record appends e. An entry naming a portfolio but no owner, such as an
+// unsubscribe made through an emailed link, takes the portfolio's owner
+// and slug.
+func (s *PoolStore) Record(ctx context.Context, e Entry) error {
+	changes := e.Changes
+	if changes == nil {
+		changes = map[string]Change{}
+	}
+	raw, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("encode audit changes: %w", err)
+	}
+	_, err = s.pool.Exec(ctx, `
+		INSERT INTO audit_log (action, actor, subject, owner_sub, portfolio_id, portfolio_slug, target, request_id, changes)
+		VALUES ($1, $2, $3,
+		        COALESCE(NULLIF($4, ''), (SELECT owner_sub FROM portfolios WHERE id = $5)),
+		        $5,
+		        COALESCE(NULLIF($6, ''), (SELECT slug FROM portfolios WHERE id = $5)),
+		        NULLIF($7, ''), NULLIF($8, ''), $9)`,
+		e.Action, e.Actor, e.Subject, e.OwnerSub, e.PortfolioID, e.PortfolioSlug, e.Target, e.RequestID, raw)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *PoolStore) List(ctx context.Context, q Query) ([]Entry, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+columns+` FROM audit_log
+		 WHERE ($1 = '' OR owner_sub = $1)
+		   AND ($2::uuid IS NULL OR portfolio_id = $2)
+		   AND ($3 = '' OR portfolio_slug = $3)
+		   AND ($4 = '' OR action = $4 OR action LIKE $4 || '.%')
+		   AND ($5 = '' OR actor = $5)
+		   AND ($6::timestamptz IS NULL OR created_at >= $6)
+		   AND ($7::timestamptz IS NULL OR created_at < $7)
+		   AND ($8::uuid IS NULL OR id < $8)
+		 ORDER BY id DESC
+		 LIMIT $9`,
+		q.OwnerSub, q.PortfolioID, q.PortfolioSlug, q.Action, q.Actor, q.Since, q.Until, q.Before, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+	out := []Entry{}
+	for rows.Next() {
+		var (
+			e   Entry
+			raw []byte
+		)
+		if err := rows.Scan(&e.ID, &e.CreatedAt, &e.Action, &e.Actor, &e.Subject, &e.OwnerSub,
+			&e.PortfolioID, &e.PortfolioSlug, &e.Target, &e.RequestID, &raw); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		if err := json.Unmarshal(raw, &e.Changes); err != nil {
+			return nil, fmt.Errorf("decode audit changes: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// PortfolioID returns the id of ownerSub's portfolio slug, or ErrNotFound.
+func (s *PoolStore) PortfolioID(ctx context.Context, ownerSub, slug string) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := s.pool.QueryRow(ctx,
+		`SELECT id FROM portfolios WHERE owner_sub = $1 AND slug = $2`, ownerSub, slug).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, ErrNotFound
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("look up portfolio: %w", err)
+	}
+	return id, nil
+}
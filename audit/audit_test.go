@@ -0,0 +1,210 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package audit_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// fakeStore is an in-memory audit.Store. It honours the filters the
+// handler sets, newest entry first.
+type fakeStore struct {
+	mu         sync.Mutex
+	entries    []audit.Entry
+	portfolios map[string]uuid.UUID // ownerSub + "/" + slug
+}
+
+func (f *fakeStore) Record(_ context.Context, e audit.Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e.ID = uuid.Must(uuid.NewV7())
+	f.entries = append(f.entries, e)
+	return nil
+}
+
+func (f *fakeStore) List(_ context.Context, q audit.Query) ([]audit.Entry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := []audit.Entry{}
+	for _, e := range slices.Backward(f.entries) {
+		switch {
+		case q.OwnerSub != "" && e.OwnerSub != q.OwnerSub,
+			q.PortfolioID != nil && (e.PortfolioID == nil || *e.PortfolioID != *q.PortfolioID),
+			q.PortfolioSlug != "" && e.PortfolioSlug != q.PortfolioSlug,
+			q.Action != "" && e.Action != q.Action && !strings.HasPrefix(e.Action, q.Action+"."),
+			q.Actor != "" && e.Actor != q.Actor,
+			q.Before != nil && e.ID.String() >= q.Before.String():
+			continue
+		}
+		if len(out) == q.Limit {
+			break
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (f *fakeStore) PortfolioID(_ context.Context, ownerSub, slug string) (uuid.UUID, error) {
+	id, ok := f.portfolios[ownerSub+"/"+slug]
+	if !ok {
+		return uuid.Nil, audit.ErrNotFound
+	}
+	return id, nil
+}
+
+var _ = Describe("Diff", func() {
+	It("keeps only the fields that changed", func() {
+		name := "old"
+		changes := audit.Diff(
+			map[string]any{"name": &name, "benchmark": "SPY", "runRetention": 2},
+			map[string]any{"name": "new", "benchmark": "SPY", "runRetention": 5},
+		)
+		Expect(changes).To(HaveLen(2))
+		Expect(changes["name"]).To(Equal(audit.Change{Before: &name, After: "new"}))
+		Expect(changes).NotTo(HaveKey("benchmark"))
+	})
+
+	It("treats a missing side as nil", func() {
+		Expect(audit.Diff(nil, map[string]any{"name": "x", "endDate": nil})).
+			To(Equal(map[string]audit.Change{"name": {After: "x"}}))
+		Expect(audit.Diff(map[string]any{"name": "x"}, nil)).
+			To(Equal(map[string]audit.Change{"name": {Before: "x"}}))
+	})
+})
+
+var _ = Describe("FromRequest", func() {
+	It("separates the acting admin from the user acted as", func() {
+		app := fiber.New()
+		var e audit.Entry
+		app.Get("/", func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|user")
+			c.Locals(types.AuthActorKey{}, "auth0|admin")
+			c.Locals(types.RequestIDKey{}, "req-1")
+			e = audit.FromRequest(c, audit.ActionPortfolioUpdate)
+			return nil
+		})
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(e.Actor).To(Equal("auth0|admin"))
+		Expect(e.Subject).To(Equal("auth0|user"))
+		Expect(e.RequestID).To(Equal("req-1"))
+	})
+})
+
+var _ = Describe("Handler", func() {
+	var (
+		app   *fiber.App
+		store *fakeStore
+		live  uuid.UUID
+	)
+
+	get := func(target string) (int, []map[string]any, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+		Expect(err).NotTo(HaveOccurred())
+		body, _ := io.ReadAll(resp.Body)
+		var out []map[string]any
+		if resp.StatusCode == fiber.StatusOK {
+			Expect(sonic.Unmarshal(body, &out)).To(Succeed())
+		}
+		return resp.StatusCode, out, resp.Header.Get(audit.HeaderNextCursor)
+	}
+
+	BeforeEach(func() {
+		live = uuid.Must(uuid.NewV7())
+		gone := uuid.Must(uuid.NewV7())
+		store = &fakeStore{portfolios: map[string]uuid.UUID{"auth0|alice/live": live}}
+		ctx := context.Background()
+		for _, e := range []audit.Entry{
+			{Action: audit.ActionPortfolioCreate, Actor: "auth0|alice", OwnerSub: "auth0|alice", PortfolioID: &live, PortfolioSlug: "live"},
+			{Action: audit.ActionAlertCreate, Actor: "auth0|alice", OwnerSub: "auth0|alice", PortfolioID: &live, PortfolioSlug: "live"},
+			{Action: audit.ActionPortfolioDelete, Actor: "auth0|alice", OwnerSub: "auth0|alice", PortfolioID: &gone, PortfolioSlug: "gone"},
+			{Action: audit.ActionPortfolioUpdate, Actor: "auth0|admin", Subject: "auth0|bob", OwnerSub: "auth0|bob"},
+		} {
+			Expect(store.Record(ctx, e)).To(Succeed())
+		}
+		h := audit.NewHandler(store)
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|alice")
+			return c.Next()
+		})
+		app.Get("/me/audit", h.Mine)
+		app.Get("/portfolios/:slug/audit", h.Portfolio)
+		app.Get("/admin/audit", h.Admin)
+	})
+
+	It("lists the caller's trail newest first, deleted portfolios included", func() {
+		status, out, _ := get("/me/audit")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out).To(HaveLen(3))
+		Expect(out[0]["action"]).To(Equal(audit.ActionPortfolioDelete))
+
+		_, out, _ = get("/me/audit?portfolio=gone")
+		Expect(out).To(HaveLen(1))
+		_, out, _ = get("/me/audit?action=alert")
+		Expect(out).To(HaveLen(1))
+		Expect(out[0]["action"]).To(Equal(audit.ActionAlertCreate))
+	})
+
+	It("pages with a cursor", func() {
+		_, out, next := get("/me/audit?limit=2")
+		Expect(out).To(HaveLen(2))
+		Expect(next).NotTo(BeEmpty())
+		_, out, next = get("/me/audit?limit=2&cursor=" + next)
+		Expect(out).To(HaveLen(1))
+		Expect(next).To(BeEmpty())
+	})
+
+	It("lists one live portfolio's trail", func() {
+		status, out, _ := get("/portfolios/live/audit")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out).To(HaveLen(2))
+		Expect(out[0]["portfolioId"]).To(Equal(live.String()))
+
+		status, _, _ = get("/portfolios/nope/audit")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("lets admins read everyone's trail", func() {
+		_, out, _ := get("/admin/audit?owner=auth0|bob")
+		Expect(out).To(HaveLen(1))
+		Expect(out[0]["actor"]).To(Equal("auth0|admin"))
+		Expect(out[0]["subject"]).To(Equal("auth0|bob"))
+		_, out, _ = get("/admin/audit")
+		Expect(out).To(HaveLen(4))
+	})
+
+	It("rejects bad filters", func() {
+		for _, q := range []string{"since=yesterday", "limit=0", "limit=500", "cursor=x"} {
+			status, _, _ := get("/me/audit?" + q)
+			Expect(status).To(Equal(fiber.StatusUnprocessableEntity), q)
+		}
+	})
+})
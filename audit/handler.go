@@ -0,0 +1,241 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+const (
+	// HeaderNextCursor carries the cursor for the next page of the trail.
+	HeaderNextCursor = "X-Next-Cursor"
+
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+var errNoSubject = errors.New("missing authenticated subject")
+
+// Handler serves the audit trail: per user, per portfolio and, for
+// admins, across everyone.
+type Handler struct {
+	store      Store
+	workspaces workspace.Roles
+}
+
+func NewHandler(store Store) *Handler { return &Handler{store: store} }
+
+// WithWorkspaces lets members read a workspace's trail with X-Workspace.
+func (h *Handler) WithWorkspaces(r workspace.Roles) *Handler {
+	h.workspaces = r
+	return h
+}
+
+// Mine implements GET /me/audit: changes to what the caller (or the
+// selected workspace) owns. ?portfolio= narrows it to one slug, including
+// portfolios since deleted.
+func (h *Handler) Mine(c fiber.Ctx) error {
+	ownerSub, ok, err := h.owner(c)
+	if !ok {
+		return err
+	}
+	q, err := parseQuery(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	q.OwnerSub = ownerSub
+	q.PortfolioSlug = string([]byte(c.Query("portfolio")))
+	return h.list(c, q)
+}
+
+// Portfolio implements GET /portfolios/{slug}/audit: the trail of one live
+// portfolio, including entries from before it was transferred.
+func (h *Handler) Portfolio(c fiber.Ctx) error {
+	ownerSub, ok, err := h.owner(c)
+	if !ok {
+		return err
+	}
+	slug := string([]byte(c.Params("slug")))
+	id, err := h.store.PortfolioID(c.Context(), ownerSub, slug)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	q, err := parseQuery(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	q.PortfolioID = &id
+	return h.list(c, q)
+}
+
+// Admin implements GET /admin/audit: the whole trail, filtered by ?owner=,
+// ?portfolioId= and ?portfolio= on top of the usual filters.
+func (h *Handler) Admin(c fiber.Ctx) error {
+	q, err := parseQuery(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	q.OwnerSub = string([]byte(c.Query("owner")))
+	q.PortfolioSlug = string([]byte(c.Query("portfolio")))
+	if v := c.Query("portfolioId"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "portfolioId must be a UUID")
+		}
+		q.PortfolioID = &id
+	}
+	return h.list(c, q)
+}
+
+func (h *Handler) owner(c fiber.Ctx) (string, bool, error) {
+	sub, ok := c.Locals(types.AuthSubjectKey{}).(string)
+	if !ok || sub == "" {
+		return "", false, writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", errNoSubject.Error())
+	}
+	ownerSub, err := workspace.Resolve(c, h.workspaces, sub, workspace.RoleViewer)
+	if err != nil {
+		status := workspace.Status(err)
+		return "", false, writeProblem(c, status, http.StatusText(status), err.Error())
+	}
+	return ownerSub, true, nil
+}
+
+// parseQuery reads the filters every listing shares: action, actor,
+// since, until, limit and cursor.
+func parseQuery(c fiber.Ctx) (Query, error) {
+	q := Query{
+		Action: string([]byte(c.Query("action"))),
+		Actor:  string([]byte(c.Query("actor"))),
+		Limit:  defaultListLimit,
+	}
+	for name, dst := range map[string]**time.Time{"since": &q.Since, "until": &q.Until} {
+		if v := c.Query(name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return Query{}, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+			}
+			*dst = &t
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxListLimit {
+			return Query{}, fmt.Errorf("limit must be between 1 and %d", maxListLimit)
+		}
+		q.Limit = n
+	}
+	if v := c.Query("cursor"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return Query{}, errors.New("invalid cursor")
+		}
+		q.Before = &id
+	}
+	return q, nil
+}
+
+func (h *Handler) list(c fiber.Ctx, q Query) error {
+	// Fetch one extra row to learn whether another page exists.
+	q.Limit++
+	rows, err := h.store.List(c.Context(), q)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if len(rows) == q.Limit {
+		rows = rows[:q.Limit-1]
+		c.Set(HeaderNextCursor, rows[len(rows)-1].ID.String())
+	}
+	out := make([]view, 0, len(rows))
+	for _, e := range rows {
+		out = append(out, toView(e))
+	}
+	return writeJSON(c, fiber.StatusOK, out)
+}
+
+type view struct {
+	ID            string            `json:"id"`
+	CreatedAt     string            `json:"createdAt"`
+	Action        string            `json:"action"`
+	Actor         string            `json:"actor"`
+	Subject       string            `json:"subject"`
+	Owner         string            `json:"owner,omitempty"`
+	PortfolioID   *string           `json:"portfolioId,omitempty"`
+	PortfolioSlug string            `json:"portfolioSlug,omitempty"`
+	Target        string            `json:"target,omitempty"`
+	RequestID     string            `json:"requestId,omitempty"`
+	Changes       map[string]Change `json:"changes"`
+}
+
+func toView(e Entry) view {
+	v := view{
+		ID:            e.ID.String(),
+		CreatedAt:     e.CreatedAt.UTC().Format(time.RFC3339),
+		Action:        e.Action,
+		Actor:         e.Actor,
+		Subject:       e.Subject,
+		Owner:         e.OwnerSub,
+		PortfolioSlug: e.PortfolioSlug,
+		Target:        e.Target,
+		RequestID:     e.RequestID,
+		Changes:       e.Changes,
+	}
+	if e.PortfolioID != nil {
+		id := e.PortfolioID.String()
+		v.PortfolioID = &id
+	}
+	if v.Changes == nil {
+		v.Changes = map[string]Change{}
+	}
+	return v
+}
+
+func writeJSON(c fiber.Ctx, status int, v any) error {
+	body, err := sonic.Marshal(v)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/json")
+	return c.Status(status).Send(body)
+}
+
+func writeProblem(c fiber.Ctx, status int, title, detail string) error {
+	type problem struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+	body, _ := sonic.Marshal(problem{
+		Type: "about:blank", Title: title, Status: status, Detail: detail, Instance: c.Path(),
+	})
+	c.Set(fiber.HeaderContentType, "application/problem+json")
+	return c.Status(status).Send(body)
+}
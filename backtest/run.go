@@ -55,6 +55,7 @@ type orchestrator struct {
 	rs           RunStore
 	resolve      ArtifactResolver
 	notifier     Notifier
+	inbox        Notifier
 	hub          *ProgressHub
 }
 
@@ -73,6 +74,13 @@ func (o *orchestrator) WithNotifier(n Notifier) *orchestrator {
 	return o
 }
 
+// WithInbox attaches an optional Notifier that is told about every run,
+// manual or scheduled, for the owner's in-app notifications.
+func (o *orchestrator) WithInbox(n Notifier) *orchestrator {
+	o.inbox = n
+	return o
+}
+
 // WithProgressHub attaches an optional ProgressHub for live progress streaming.
 func (o *orchestrator) WithProgressHub(h *ProgressHub) *orchestrator {
 	o.hub = h
@@ -157,6 +165,7 @@ func (o *orchestrator) Run(ctx context.Context, portfolioID, runID uuid.UUID, sc
 	if o.hub != nil {
 		o.hub.Complete(runID, "success", "")
 	}
+	o.notifyInbox(ctx, portfolioID, runID, true)
 	// Manual runs do not emit alert emails -- the user is already watching the
 	// run. Only scheduled runs feed the cadence-based alert path.
 	if o.notifier != nil && scheduled {
@@ -168,6 +177,17 @@ func (o *orchestrator) Run(ctx context.Context, portfolioID, runID uuid.UUID, sc
 	return nil
 }
 
+// notifyInbox records the run's outcome in the owner's notifications.
+// Failures are logged; the run itself has already been recorded.
+func (o *orchestrator) notifyInbox(ctx context.Context, portfolioID, runID uuid.UUID, success bool) {
+	if o.inbox == nil {
+		return
+	}
+	if err := o.inbox.NotifyRunComplete(ctx, portfolioID, runID, success); err != nil {
+		log.Warn().Err(err).Stringer("portfolio_id", portfolioID).Msg("run notification failed")
+	}
+}
+
 // ErrNonCanonicalSnapshotPath is returned by fsyncAndRename when the snapshot
 // path is not in canonical form, indicating a possible path-traversal attempt.
 var ErrNonCanonicalSnapshotPath = errors.New("fsync: refusing non-canonical tmp path")
@@ -260,6 +280,7 @@ func (o *orchestrator) fail(ctx context.Context, portfolioID, runID uuid.UUID, s
 	if o.hub != nil {
 		o.hub.Complete(runID, "failed", msg)
 	}
+	o.notifyInbox(ctx, portfolioID, runID, false)
 	// Manual runs do not emit alert emails, including failure alerts; the user
 	// initiating the run sees the error directly. Only scheduled runs notify.
 	if o.notifier != nil && scheduled {
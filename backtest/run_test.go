@@ -168,10 +168,14 @@ var _ = Describe("Run orchestration", func() {
 
 		manualPS := newPortfolio()
 		manualNotifier := &fakeNotifier{}
+		manualInbox := &fakeNotifier{}
 		manualRunner := backtest.NewRunner(cfg, &backtest.HostRunner{}, backtest.ArtifactBinary,
-			manualPS, &fakeRunStoreFull{}, resolve).WithNotifier(manualNotifier)
+			manualPS, &fakeRunStoreFull{}, resolve).WithNotifier(manualNotifier).WithInbox(manualInbox)
 		Expect(manualRunner.Run(context.Background(), manualPS.row.ID, uuid.New(), false)).To(Succeed())
 		Expect(manualNotifier.calls).To(Equal(0))
+		// The in-app inbox hears about manual runs too.
+		Expect(manualInbox.calls).To(Equal(1))
+		Expect(manualInbox.lastOK).To(BeTrue())
 	})
 
 	It("records a failure when the runner fails", func() {
@@ -184,17 +188,20 @@ var _ = Describe("Run orchestration", func() {
 			Parameters: map[string]any{}, Benchmark: "SPY", Status: "queued",
 		}}
 		rs := &fakeRunStoreFull{}
+		inbox := &fakeNotifier{lastOK: true}
 
 		r := backtest.NewRunner(backtest.Config{SnapshotsDir: snapsDir, RunnerMode: "host", Timeout: 5 * time.Second},
 			&backtest.HostRunner{}, backtest.ArtifactBinary, ps, rs,
 			func(_ context.Context, _, _ string) (string, func(), error) {
 				return fakeStratBin, func() {}, nil
-			})
+			}).WithInbox(inbox)
 
 		err := r.Run(context.Background(), ps.row.ID, uuid.New(), true)
 		Expect(err).To(HaveOccurred())
 		Expect(errors.Is(err, backtest.ErrRunnerFailed)).To(BeTrue())
 		Expect(ps.markFailed).NotTo(BeEmpty())
+		Expect(inbox.calls).To(Equal(1))
+		Expect(inbox.lastOK).To(BeFalse())
 	})
 
 	It("calls cleanup after a successful run", func() {
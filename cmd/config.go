@@ -26,6 +26,7 @@ type Config struct {
 	DataDir           string `mapstructure:"data_dir"`
 	AppBaseURL        string `mapstructure:"app_base_url"`
 	UnsubscribeSecret string `mapstructure:"unsubscribe_secret"`
+	CalendarSecret    string `mapstructure:"calendar_secret"`
 	Log               logConf
 	DB                dbConf
 	Server            serverConf
@@ -35,7 +36,11 @@ type Config struct {
 	Backtest          backtestConf
 	Runner            runnerConf
 	Scheduler         schedulerConf
+	Email             emailConf
 	Mailgun           mailgunConf
+	SMTP              smtpConf
+	Notify            notifyConf
+	Quota             quotaConf
 }
 
 // dbConf holds the PostgreSQL connection string.
@@ -47,13 +52,19 @@ type dbConf struct {
 type serverConf struct {
 	Port         int
 	AllowOrigins string `mapstructure:"allow_origins"`
+	// IdempotencyTTL is how long Idempotency-Key responses are replayed.
+	IdempotencyTTL time.Duration `mapstructure:"idempotency_ttl"`
 }
 
 // authConf configures the JWT-verification middleware.
 type authConf struct {
-	JWKSURL  string `mapstructure:"jwks_url"`
-	Audience string
-	Issuer   string
+	JWKSURL    string `mapstructure:"jwks_url"`
+	Audience   string
+	Issuer     string
+	AdminScope string `mapstructure:"admin_scope"`
+	RolesClaim string `mapstructure:"roles_claim"`
+	// RequireScopes makes routes require <resource>:read/write grants.
+	RequireScopes bool `mapstructure:"require_scopes"`
 }
 
 // githubConf holds optional GitHub credentials.
@@ -72,6 +83,7 @@ type strategyConf struct {
 	StatsRefreshTime        string        `mapstructure:"stats_refresh_time"`
 	StatsStartDate          string        `mapstructure:"stats_start_date"`
 	StatsTickInterval       time.Duration `mapstructure:"stats_tick_interval"`
+	WebhookSecret           string        `mapstructure:"webhook_secret"`
 }
 
 // backtestConf controls the backtest runtime.
@@ -99,6 +111,19 @@ type dockerConf struct {
 	SnapshotsHostPath string        `mapstructure:"snapshots_host_path"`
 }
 
+// emailConf picks the transport for outbound alert emails ("mailgun" or
+// "smtp") and how they look: template overrides, the default locale and the
+// operator's branding.
+type emailConf struct {
+	Transport        string `mapstructure:"transport"`
+	TemplatesDir     string `mapstructure:"templates_dir"`
+	DefaultLocale    string `mapstructure:"default_locale"`
+	BrandName        string `mapstructure:"brand_name"`
+	BrandLogo        string `mapstructure:"brand_logo"`
+	BrandAccentColor string `mapstructure:"brand_accent_color"`
+	BrandFooter      string `mapstructure:"brand_footer"`
+}
+
 // mailgunConf holds Mailgun credentials for outbound alert emails.
 type mailgunConf struct {
 	Domain      string `mapstructure:"domain"`
@@ -106,6 +131,36 @@ type mailgunConf struct {
 	FromAddress string `mapstructure:"from_address"`
 }
 
+// smtpConf configures the SMTP transport used when email.transport = "smtp".
+type smtpConf struct {
+	Host        string `mapstructure:"host"`
+	Port        int    `mapstructure:"port"`
+	Username    string `mapstructure:"username"`
+	Password    string `mapstructure:"password"`
+	TLS         string `mapstructure:"tls"`
+	FromAddress string `mapstructure:"from_address"`
+}
+
+// notifyConf configures the non-email alert channels, the delivery
+// outbox shared by every channel, and the portfolio digest schedule.
+type notifyConf struct {
+	TelegramBotToken string        `mapstructure:"telegram_bot_token"`
+	NtfyURL          string        `mapstructure:"ntfy_url"`
+	OutboxInterval   time.Duration `mapstructure:"outbox_interval"`
+	DigestInterval   time.Duration `mapstructure:"digest_interval"`
+	DigestHour       int           `mapstructure:"digest_hour"`
+}
+
+// quotaConf caps what each owner may use; zero means unlimited.
+type quotaConf struct {
+	Portfolios           int `mapstructure:"portfolios"`
+	UnofficialStrategies int `mapstructure:"unofficial_strategies"`
+	RunsPerDay           int `mapstructure:"runs_per_day"`
+	AlertRecipients      int `mapstructure:"alert_recipients"`
+	Workspaces           int `mapstructure:"workspaces"`
+	RequestsPerMinute    int `mapstructure:"requests_per_minute"`
+}
+
 // schedulerConf controls the in-process scheduler that picks up due
 // continuous portfolios and submits them to the backtest dispatcher.
 type schedulerConf struct {
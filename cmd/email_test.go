@@ -0,0 +1,67 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"testing"
+
+	alertEmail "github.com/penny-vault/pv-api/alert/email"
+)
+
+func TestEmailTransport(t *testing.T) {
+	t.Run("mailgun without a key disables email", func(t *testing.T) {
+		tr, err := emailTransport(Config{Email: emailConf{Transport: "mailgun"}})
+		if err != nil || tr != nil {
+			t.Fatalf("got %v, %v; want nil, nil", tr, err)
+		}
+	})
+	t.Run("mailgun with a key", func(t *testing.T) {
+		tr, err := emailTransport(Config{Mailgun: mailgunConf{APIKey: "key", Domain: "mg.example.com"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := tr.(alertEmail.Mailgun); !ok {
+			t.Fatalf("got %T, want Mailgun", tr)
+		}
+	})
+	t.Run("smtp", func(t *testing.T) {
+		tr, err := emailTransport(Config{
+			Email: emailConf{Transport: "smtp"},
+			SMTP:  smtpConf{Host: "localhost", Port: 1025, TLS: "none"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s, ok := tr.(alertEmail.SMTP)
+		if !ok || s.Host != "localhost" || s.Port != 1025 || s.TLS != alertEmail.SMTPNoTLS {
+			t.Fatalf("got %#v", tr)
+		}
+	})
+	t.Run("smtp with a bad tls mode", func(t *testing.T) {
+		_, err := emailTransport(Config{
+			Email: emailConf{Transport: "smtp"},
+			SMTP:  smtpConf{Host: "localhost", TLS: "ssl"},
+		})
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+	t.Run("unknown transport", func(t *testing.T) {
+		if _, err := emailTransport(Config{Email: emailConf{Transport: "pigeon"}}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -33,15 +33,19 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/penny-vault/pv-api/alert"
+	"github.com/penny-vault/pv-api/alert/channel"
 	alertEmail "github.com/penny-vault/pv-api/alert/email"
 	"github.com/penny-vault/pv-api/api"
 	"github.com/penny-vault/pv-api/backtest"
+	"github.com/penny-vault/pv-api/notification"
 	"github.com/penny-vault/pv-api/portfolio"
 	"github.com/penny-vault/pv-api/progress"
+	"github.com/penny-vault/pv-api/quota"
 	"github.com/penny-vault/pv-api/scheduler"
 	"github.com/penny-vault/pv-api/snapshot"
 	"github.com/penny-vault/pv-api/sql"
 	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/workspace"
 	"github.com/penny-vault/pvbt/data"
 	"github.com/penny-vault/pvbt/tradecron"
 )
@@ -170,9 +174,13 @@ func init() {
 	serverCmd.Flags().String("db-url", "", "PostgreSQL connection string")
 	serverCmd.Flags().Int("server-port", 3000, "port to bind the HTTP server to")
 	serverCmd.Flags().String("server-allow-origins", "http://localhost:5174,http://localhost:9000,https://pennyvault.com,https://www.pennyvault.com", "comma-separated CORS origins to allow; empty disables CORS")
+	serverCmd.Flags().Duration("server-idempotency-ttl", 24*time.Hour, "how long responses to requests with an Idempotency-Key are replayed to retries")
 	serverCmd.Flags().String("auth-jwks-url", "", "JWKS endpoint for JWT verification")
 	serverCmd.Flags().String("auth-audience", "", "expected JWT audience")
 	serverCmd.Flags().String("auth-issuer", "", "expected JWT issuer URL")
+	serverCmd.Flags().String("auth-admin-scope", "pvapi:admin", "JWT scope, permission, or role required for /admin routes; empty disables them")
+	serverCmd.Flags().Bool("auth-require-scopes", false, "require <resource>:read or <resource>:write grants (e.g. portfolios:write) on every route")
+	serverCmd.Flags().String("auth-roles-claim", "", "extra JWT claim (e.g. a namespaced roles claim) whose values count as granted scopes")
 	serverCmd.Flags().String("github-token", "", "GitHub API token; empty uses unauthenticated Search")
 	serverCmd.Flags().Duration("strategy-registry-sync-interval", time.Hour, "how often to poll GitHub for strategy updates")
 	serverCmd.Flags().Int("strategy-install-concurrency", 2, "maximum concurrent strategy installs")
@@ -180,6 +188,7 @@ func init() {
 	serverCmd.Flags().String("strategy-github-query", "owner:penny-vault topic:pvbt-strategy", "GitHub search query for official strategies (owner filter applied client-side)")
 	serverCmd.Flags().String("strategy-ephemeral-dir", "", "ephemeral build dir for unofficial strategies (default: <data-dir>/strategies/ephemeral)")
 	serverCmd.Flags().Duration("strategy-ephemeral-install-timeout", 5*time.Minute, "max time for one ephemeral clone+build")
+	serverCmd.Flags().String("strategy-webhook-secret", "", "HMAC secret for the strategy-sync webhooks (/api/webhooks/github, /api/webhooks/git); empty disables them")
 	serverCmd.Flags().String("backtest-snapshots-dir", "", "directory where backtest snapshot files are stored (default: <data-dir>/snapshots)")
 	serverCmd.Flags().Duration("backtest-orphan-gc-interval", 7*24*time.Hour, "how often to sweep snapshot files no DB row references; <0 disables (sweep still runs at startup)")
 	serverCmd.Flags().String("runner-docker-socket", "unix:///var/run/docker.sock", "Docker daemon socket URL")
@@ -189,11 +198,36 @@ func init() {
 	serverCmd.Flags().Duration("runner-docker-build-timeout", 10*time.Minute, "max time for one docker image build")
 	serverCmd.Flags().String("runner-docker-image-prefix", "pvapi-strategy", "prefix for strategy image tags")
 	serverCmd.Flags().String("runner-docker-snapshots-host-path", "", "host path that maps to backtest.snapshots_dir when pvapi itself runs in docker; empty = snapshots_dir")
+	serverCmd.Flags().String("email-transport", "mailgun", "transport for alert emails: mailgun or smtp")
+	serverCmd.Flags().String("email-templates-dir", "", "directory of alert email template and locale overrides; empty uses the built-in templates")
+	serverCmd.Flags().String("email-default-locale", "en-US", "locale for alert email recipients who have not chosen one")
+	serverCmd.Flags().String("email-brand-name", "Penny Vault", "product name shown in alert emails")
+	serverCmd.Flags().String("email-brand-logo", "", "logo for alert emails: an http(s) URL, a data URL or an image file path; empty uses the Penny Vault logo")
+	serverCmd.Flags().String("email-brand-accent-color", "#0ea5e9", "hex accent color for alert emails")
+	serverCmd.Flags().String("email-brand-footer", "", "extra footer line for alert emails, such as a postal address")
 	serverCmd.Flags().String("mailgun-domain", "", "Mailgun sending domain")
 	serverCmd.Flags().String("mailgun-api-key", "", "Mailgun API key; empty disables email alerts")
 	serverCmd.Flags().String("mailgun-from-address", "Penny Vault <no-reply@mg.pennyvault.com>", "From address for alert emails")
+	serverCmd.Flags().String("smtp-host", "", "SMTP server host; empty disables email alerts when email.transport = smtp")
+	serverCmd.Flags().Int("smtp-port", 587, "SMTP server port")
+	serverCmd.Flags().String("smtp-username", "", "SMTP AUTH username; empty skips authentication")
+	serverCmd.Flags().String("smtp-password", "", "SMTP AUTH password")
+	serverCmd.Flags().String("smtp-tls", "starttls", "SMTP TLS mode: starttls, tls (implicit, port 465) or none (local sinks only)")
+	serverCmd.Flags().String("smtp-from-address", "Penny Vault <no-reply@mg.pennyvault.com>", "From address for alert emails sent over SMTP")
+	serverCmd.Flags().String("notify-telegram-bot-token", "", "Telegram bot token for telegram alert channels; empty disables them")
+	serverCmd.Flags().String("notify-ntfy-url", "https://ntfy.sh", "ntfy server for ntfy alert channels that name no server")
+	serverCmd.Flags().Duration("notify-outbox-interval", 30*time.Second, "how often to retry queued alert deliveries")
+	serverCmd.Flags().Duration("notify-digest-interval", 5*time.Minute, "how often to look for portfolio digests that are due")
+	serverCmd.Flags().Int("notify-digest-hour", 18, "hour of day (America/New_York) after which digests are sent")
+	serverCmd.Flags().Int("quota-portfolios", 0, "most portfolios one user or workspace may have; 0 = unlimited")
+	serverCmd.Flags().Int("quota-unofficial-strategies", 0, "most distinct unofficial strategies one user or workspace may use; 0 = unlimited")
+	serverCmd.Flags().Int("quota-runs-per-day", 0, "most backtests one user or workspace may start in 24 hours, scheduled runs excepted; 0 = unlimited")
+	serverCmd.Flags().Int("quota-alert-recipients", 0, "most email recipients on one alert; 0 = unlimited")
+	serverCmd.Flags().Int("quota-workspaces", 0, "most workspaces one user may create; 0 = unlimited")
+	serverCmd.Flags().Int("quota-requests-per-minute", 0, "most API requests per user per minute on each server; 0 = unlimited")
 	serverCmd.Flags().String("app-base-url", "https://www.pennyvault.com", "Base URL for the Penny Vault web app (used in email links)")
 	serverCmd.Flags().String("unsubscribe-secret", "", "HMAC secret for signing unsubscribe tokens; if empty, unsubscribe links are omitted")
+	serverCmd.Flags().String("calendar-secret", "", "HMAC secret for hashing stored calendar feed tokens; if empty, per-user calendar feeds are disabled")
 	bindPFlagsToViper(serverCmd)
 
 	// The auto-transform in bindPFlagsToViper only handles one dash→dot
@@ -210,6 +244,42 @@ func init() {
 	mustBindPFlag("runner.docker.build_timeout", "runner-docker-build-timeout")
 	mustBindPFlag("runner.docker.image_prefix", "runner-docker-image-prefix")
 	mustBindPFlag("runner.docker.snapshots_host_path", "runner-docker-snapshots-host-path")
+	// It would also map calendar-secret to calendar.secret.
+	mustBindPFlag("calendar_secret", "calendar-secret")
+}
+
+// emailTransport builds the alert email transport selected by
+// email.transport. It returns nil (email disabled) when the selected
+// transport has no credentials or host configured.
+func emailTransport(c Config) (alertEmail.Transport, error) {
+	switch c.Email.Transport {
+	case "", "mailgun":
+		if c.Mailgun.APIKey == "" {
+			return nil, nil
+		}
+		return alertEmail.Mailgun{Config: alertEmail.Config{
+			Domain:      c.Mailgun.Domain,
+			APIKey:      c.Mailgun.APIKey,
+			FromAddress: c.Mailgun.FromAddress,
+		}}, nil
+	case "smtp":
+		if c.SMTP.Host == "" {
+			return nil, nil
+		}
+		if err := alertEmail.ValidateTLSMode(c.SMTP.TLS); err != nil {
+			return nil, err
+		}
+		return alertEmail.SMTP{
+			Host:        c.SMTP.Host,
+			Port:        c.SMTP.Port,
+			Username:    c.SMTP.Username,
+			Password:    c.SMTP.Password,
+			FromAddress: c.SMTP.FromAddress,
+			TLS:         c.SMTP.TLS,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown email.transport %q (want mailgun or smtp)", c.Email.Transport)
+	}
 }
 
 // parseStatsStartDate parses a "YYYY-MM-DD" string into a time.Time in UTC.
@@ -274,6 +344,7 @@ var serverCmd = &cobra.Command{
 			artifactKind    backtest.ArtifactKind
 			resolve         backtest.ArtifactResolver
 			dockerInstaller strategy.InstallerFunc
+			validateSandbox *strategy.ValidateSandbox
 		)
 
 		switch conf.Runner.Mode {
@@ -316,7 +387,7 @@ var serverCmd = &cobra.Command{
 			if snapHost == "" {
 				snapHost = conf.Backtest.SnapshotsDir
 			}
-			runner = &backtest.DockerRunner{
+			dockerRunner := &backtest.DockerRunner{
 				Client:           dc,
 				Network:          conf.Runner.Docker.Network,
 				NanoCPUs:         nanoCPUs,
@@ -324,6 +395,7 @@ var serverCmd = &cobra.Command{
 				SnapshotsHostDir: snapHost,
 				SnapshotsDir:     conf.Backtest.SnapshotsDir,
 			}
+			runner = dockerRunner
 			artifactKind = backtest.ArtifactImage
 			resolve = func(resolveCtx context.Context, cloneURL, ver string) (string, func(), error) {
 				if ver != "" {
@@ -351,6 +423,25 @@ var serverCmd = &cobra.Command{
 					BuildTimeout: conf.Runner.Docker.BuildTimeout,
 				})
 			}
+			// Strategy validation runs caller-supplied repos, so it is
+			// only offered here, inside the same container limits as
+			// backtests; the smoke snapshot lands in the bind-mounted
+			// snapshots dir, which the orphan sweep leaves alone.
+			validateSandbox = &strategy.ValidateSandbox{
+				Build: func(buildCtx context.Context, opts strategy.EphemeralOptions) (string, func(), error) {
+					return strategy.EphemeralImageBuild(buildCtx, strategy.DockerEphemeralOptions{
+						CloneURL:    opts.CloneURL,
+						Ver:         opts.Ver,
+						Dir:         opts.Dir,
+						Timeout:     opts.Timeout,
+						Client:      dc,
+						ImagePrefix: conf.Runner.Docker.ImagePrefix,
+					})
+				},
+				Describe: strategy.DescribeImage(dc, conf.Runner.Docker.Network),
+				Runner:   api.DockerStatRunner{Runner: dockerRunner},
+				WorkDir:  conf.Backtest.SnapshotsDir,
+			}
 
 		case "kubernetes":
 			log.Fatal().Msg("runner.mode = kubernetes lands in plan 9")
@@ -364,12 +455,38 @@ var serverCmd = &cobra.Command{
 		orch := backtest.NewRunner(btCfg, runner, artifactKind, portfolioAdapter, runAdapter, resolve)
 		appBaseURL := conf.AppBaseURL
 		unsubscribeSecret := conf.UnsubscribeSecret
-		checker := alert.NewChecker(pool, alertEmail.Config{
-			Domain:      conf.Mailgun.Domain,
-			APIKey:      conf.Mailgun.APIKey,
-			FromAddress: conf.Mailgun.FromAddress,
-		}, appBaseURL, unsubscribeSecret)
+		transport, err := emailTransport(conf)
+		if err != nil {
+			log.Fatal().Err(err).Msg("email transport")
+		}
+		renderer, err := alertEmail.NewRenderer(alertEmail.RendererConfig{
+			TemplatesDir:  conf.Email.TemplatesDir,
+			DefaultLocale: conf.Email.DefaultLocale,
+			Brand: alertEmail.Brand{
+				Name:        conf.Email.BrandName,
+				Logo:        conf.Email.BrandLogo,
+				AccentColor: conf.Email.BrandAccentColor,
+				Footer:      conf.Email.BrandFooter,
+			},
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("email templates")
+		}
+		checker := alert.NewChecker(pool, transport, appBaseURL, unsubscribeSecret).WithChannels(channel.New(channel.Config{
+			TelegramBotToken: conf.Notify.TelegramBotToken,
+			NtfyURL:          conf.Notify.NtfyURL,
+		})).WithRenderer(renderer)
+		if !checker.ConfirmationsEnabled() {
+			log.Warn().Msg("unsubscribe_secret or app_base_url unset: confirmation emails cannot be sent, so new alert and digest email recipients are refused")
+		}
+		notificationHub := notification.NewHub()
+		notifications := notification.NewService(notification.NewPoolStore(pool), notificationHub).
+			WithMembers(workspace.NewPoolStore(pool))
+		checker.WithInbox(notifications)
 		orch.WithNotifier(checker)
+		orch.WithInbox(notifications)
+		go checker.RunOutbox(ctx, conf.Notify.OutboxInterval)
+		go checker.RunDigests(ctx, conf.Notify.DigestInterval, conf.Notify.DigestHour)
 		hub := progress.NewHub()
 		orch.WithProgressHub(hub)
 		dispatcher := backtest.NewDispatcher(btCfg, runner, runAdapter, orch.Run)
@@ -411,12 +528,16 @@ var serverCmd = &cobra.Command{
 		}
 
 		app, err := api.NewApp(ctx, api.Config{
-			Port:         conf.Server.Port,
-			AllowOrigins: conf.Server.AllowOrigins,
+			Port:           conf.Server.Port,
+			AllowOrigins:   conf.Server.AllowOrigins,
+			IdempotencyTTL: conf.Server.IdempotencyTTL,
 			Auth: api.AuthConfig{
-				JWKSURL:  conf.Auth.JWKSURL,
-				Audience: conf.Auth.Audience,
-				Issuer:   conf.Auth.Issuer,
+				JWKSURL:       conf.Auth.JWKSURL,
+				Audience:      conf.Auth.Audience,
+				Issuer:        conf.Auth.Issuer,
+				AdminScope:    conf.Auth.AdminScope,
+				RolesClaim:    conf.Auth.RolesClaim,
+				RequireScopes: conf.Auth.RequireScopes,
 			},
 			Pool: pool,
 			Registry: api.RegistryConfig{
@@ -430,17 +551,29 @@ var serverCmd = &cobra.Command{
 				StatsRefreshTime:  conf.Strategy.StatsRefreshTime,
 				StatsStartDate:    parseStatsStartDate(conf.Strategy.StatsStartDate),
 				StatsTickInterval: conf.Strategy.StatsTickInterval,
+				WebhookSecret:     conf.Strategy.WebhookSecret,
 			},
 			Dispatcher:        dispatcherAdapter{bt: dispatcher},
 			SnapshotOpener:    snapshot.Opener{},
 			SnapshotsDir:      btCfg.SnapshotsDir,
 			ProgressHub:       hub,
+			NotificationHub:   notificationHub,
 			AlertChecker:      checker,
 			UnsubscribeSecret: unsubscribeSecret,
+			CalendarSecret:    conf.CalendarSecret,
+			Quotas: quota.Limits{
+				Portfolios:           conf.Quota.Portfolios,
+				UnofficialStrategies: conf.Quota.UnofficialStrategies,
+				RunsPerDay:           conf.Quota.RunsPerDay,
+				AlertRecipients:      conf.Quota.AlertRecipients,
+				Workspaces:           conf.Quota.Workspaces,
+				RequestsPerMinute:    conf.Quota.RequestsPerMinute,
+			},
 			Ephemeral: api.EphemeralConfig{
 				Dir:     conf.Strategy.EphemeralDir,
 				Timeout: conf.Strategy.EphemeralInstallTimeout,
 			},
+			ValidateSandbox: validateSandbox,
 		})
 		if err != nil {
 			return fmt.Errorf("build app: %w", err)
@@ -473,6 +606,8 @@ var serverCmd = &cobra.Command{
 				}
 			}
 			_ = dispatcher.Shutdown(30 * time.Second)
+			// Open notification streams never end on their own.
+			notificationHub.Close()
 			shutCtx, shutCancel := context.WithTimeout(context.Background(), 10*time.Second)
 			defer shutCancel()
 			if err := app.ShutdownWithContext(shutCtx); err != nil {
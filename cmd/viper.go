@@ -49,9 +49,27 @@ func setViperDefaults() {
 	viper.SetDefault("strategy.stats_refresh_time", "17:00")
 	viper.SetDefault("strategy.stats_start_date", "2010-01-01")
 	viper.SetDefault("strategy.stats_tick_interval", 5*time.Minute)
+	viper.SetDefault("email.transport", "mailgun")
+	viper.SetDefault("email.templates_dir", "")
+	viper.SetDefault("email.default_locale", "en-US")
+	viper.SetDefault("email.brand_name", "Penny Vault")
+	viper.SetDefault("email.brand_logo", "")
+	viper.SetDefault("email.brand_accent_color", "#0ea5e9")
+	viper.SetDefault("email.brand_footer", "")
 	viper.SetDefault("mailgun.domain", "")
 	viper.SetDefault("mailgun.api_key", "")
 	viper.SetDefault("mailgun.from_address", "Penny Vault <no-reply@mg.pennyvault.com>")
+	viper.SetDefault("smtp.host", "")
+	viper.SetDefault("smtp.port", 587)
+	viper.SetDefault("smtp.username", "")
+	viper.SetDefault("smtp.password", "")
+	viper.SetDefault("smtp.tls", "starttls")
+	viper.SetDefault("smtp.from_address", "Penny Vault <no-reply@mg.pennyvault.com>")
+	viper.SetDefault("notify.telegram_bot_token", "")
+	viper.SetDefault("notify.ntfy_url", "https://ntfy.sh")
+	viper.SetDefault("notify.outbox_interval", 30*time.Second)
+	viper.SetDefault("notify.digest_interval", 5*time.Minute)
+	viper.SetDefault("notify.digest_hour", 18)
 }
 
 func bindPFlagsToViper(cmd *cobra.Command) {
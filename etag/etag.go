@@ -0,0 +1,73 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package etag builds the entity tags pvapi sends with portfolios, alerts
+// and snapshot reads, and evaluates the If-Match and If-None-Match
+// preconditions clients send back.
+package etag
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// FromTime is a strong tag for a row versioned by its updated_at.
+func FromTime(t time.Time) string {
+	return `"` + strconv.FormatInt(t.UnixMicro(), 36) + `"`
+}
+
+// FromRun is a weak tag for data read from the snapshot of run id:
+// equivalent for as long as that run stays the active one, though not
+// necessarily byte-identical.
+func FromRun(id uuid.UUID) string {
+	return `W/"` + id.String() + `"`
+}
+
+// Match reports whether c's If-Match precondition holds for a resource
+// whose current tag is current. A request without If-Match always
+// matches. Comparison is strong, so weak tags never match.
+func Match(c fiber.Ctx, current string) bool {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return true
+	}
+	if strings.HasPrefix(current, "W/") {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == current {
+			return true
+		}
+	}
+	return false
+}
+
+// Conditional reports whether c's If-Match names specific versions. The
+// write it guards must then be conditional on the version Match checked,
+// or a concurrent writer can slip in between the check and the write.
+func Conditional(c fiber.Ctx) bool {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	return header != "" && header != "*"
+}
+
+// NotModified sets tag as c's ETag and reports whether c's If-None-Match
+// already names it, in which case the caller should answer 304.
+func NotModified(c fiber.Ctx, tag string) bool {
+	c.Set(fiber.HeaderETag, tag)
+	return c.Fresh()
+}
@@ -0,0 +1,82 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package etag_test
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/etag"
+)
+
+// check runs fn on a request carrying ifMatch, if any.
+func check(ifMatch string, fn func(fiber.Ctx) bool) bool {
+	app := fiber.New()
+	var ok bool
+	app.Get("/", func(c fiber.Ctx) error {
+		ok = fn(c)
+		return nil
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	resp, err := app.Test(req)
+	Expect(err).NotTo(HaveOccurred())
+	resp.Body.Close()
+	return ok
+}
+
+var _ = Describe("Match", func() {
+	current := etag.FromTime(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	match := func(ifMatch, tag string) bool {
+		return check(ifMatch, func(c fiber.Ctx) bool { return etag.Match(c, tag) })
+	}
+
+	It("passes requests without a precondition or with a wildcard", func() {
+		Expect(match("", current)).To(BeTrue())
+		Expect(match("*", current)).To(BeTrue())
+	})
+
+	It("matches any tag in the list", func() {
+		Expect(match(current, current)).To(BeTrue())
+		Expect(match(`"a", `+current, current)).To(BeTrue())
+		Expect(match(`"a", "b"`, current)).To(BeFalse())
+	})
+
+	It("tells versions apart", func() {
+		later := etag.FromTime(time.Date(2026, 3, 1, 12, 0, 0, 1000, time.UTC))
+		Expect(match(current, later)).To(BeFalse())
+	})
+
+	It("never matches a weak tag", func() {
+		weak := etag.FromRun(uuid.Must(uuid.NewV7()))
+		Expect(match(weak, weak)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Conditional", func() {
+	It("is set only when If-Match names versions", func() {
+		Expect(check("", etag.Conditional)).To(BeFalse())
+		Expect(check("*", etag.Conditional)).To(BeFalse())
+		Expect(check(`"a", "b"`, etag.Conditional)).To(BeTrue())
+	})
+})
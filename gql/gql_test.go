@@ -0,0 +1,250 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gql_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/gql"
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+type fakePortfolios struct {
+	rows []portfolio.Portfolio
+}
+
+func (f *fakePortfolios) List(_ context.Context, ownerSub string) ([]portfolio.Portfolio, error) {
+	out := []portfolio.Portfolio{}
+	for _, p := range f.rows {
+		if p.OwnerSub == ownerSub {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakePortfolios) Get(_ context.Context, ownerSub, slug string) (portfolio.Portfolio, error) {
+	for _, p := range f.rows {
+		if p.OwnerSub == ownerSub && p.Slug == slug {
+			return p, nil
+		}
+	}
+	return portfolio.Portfolio{}, portfolio.ErrNotFound
+}
+
+type fakeStrategies struct {
+	mu   sync.Mutex
+	rows []strategy.Strategy
+	gets int
+}
+
+func (f *fakeStrategies) List(context.Context) ([]strategy.Strategy, error) {
+	return f.rows, nil
+}
+
+func (f *fakeStrategies) Get(_ context.Context, shortCode string) (strategy.Strategy, error) {
+	f.mu.Lock()
+	f.gets++
+	f.mu.Unlock()
+	for _, s := range f.rows {
+		if s.ShortCode == shortCode {
+			return s, nil
+		}
+	}
+	return strategy.Strategy{}, strategy.ErrNotFound
+}
+
+// fakeOpener counts opens and closes per snapshot path.
+type fakeOpener struct {
+	mu     sync.Mutex
+	opens  map[string]int
+	closes map[string]int
+	from   *time.Time
+}
+
+func (o *fakeOpener) Open(path string) (portfolio.SnapshotReader, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.opens[path]++
+	return &fakeReader{path: path, o: o}, nil
+}
+
+type fakeReader struct {
+	portfolio.SnapshotReader
+	path string
+	o    *fakeOpener
+}
+
+func (r *fakeReader) Summary(context.Context) (*openapi.PortfolioSummary, error) {
+	sharpe := 1.25
+	return &openapi.PortfolioSummary{CurrentValue: 1000, Sharpe: &sharpe}, nil
+}
+
+func (r *fakeReader) CurrentHoldings(context.Context) (*openapi.HoldingsResponse, error) {
+	return &openapi.HoldingsResponse{
+		Date:             openapi_types.Date{Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		Items:            []openapi.Holding{{Ticker: "VTI", Quantity: 3, MarketValue: 900}},
+		TotalMarketValue: 900,
+	}, nil
+}
+
+func (r *fakeReader) Performance(_ context.Context, slug string, from, to *time.Time) (*openapi.PortfolioPerformance, error) {
+	r.o.mu.Lock()
+	r.o.from = from
+	r.o.mu.Unlock()
+	day := openapi_types.Date{Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)}
+	return &openapi.PortfolioPerformance{
+		PortfolioSlug: slug,
+		From:          day,
+		To:            day,
+		Points:        []openapi.PerformancePoint{{Date: day, PortfolioValue: 1000, BenchmarkValue: 990}},
+	}, nil
+}
+
+func (r *fakeReader) Close() error {
+	r.o.mu.Lock()
+	defer r.o.mu.Unlock()
+	r.o.closes[r.path]++
+	return nil
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		opener     *fakeOpener
+		strategies *fakeStrategies
+		app        *fiber.App
+	)
+
+	BeforeEach(func() {
+		path := "/snapshots/ready.sqlite"
+		opener = &fakeOpener{opens: map[string]int{}, closes: map[string]int{}}
+		strategies = &fakeStrategies{rows: []strategy.Strategy{
+			{ShortCode: "adm", RepoOwner: "penny-vault", RepoName: "adm", IsOfficial: true},
+			{ShortCode: "old", RepoOwner: "penny-vault", RepoName: "old", Hidden: true},
+		}}
+		portfolios := &fakePortfolios{rows: []portfolio.Portfolio{
+			{OwnerSub: "auth0|a", Slug: "ready", Name: "Ready", StrategyCode: "adm", Status: portfolio.StatusReady, SnapshotPath: &path},
+			{OwnerSub: "auth0|a", Slug: "pending", Name: "Pending", StrategyCode: "adm", Status: portfolio.StatusPending},
+			{OwnerSub: "auth0|b", Slug: "theirs", Name: "Theirs", StrategyCode: "adm", Status: portfolio.StatusPending},
+		}}
+		h := gql.NewHandler(portfolios, strategies, opener)
+		app = fiber.New()
+		app.Post("/graphql", func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|a")
+			return h.Query(c)
+		})
+	})
+
+	do := func(body string) (int, map[string]any) {
+		req := httptest.NewRequest("POST", "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		raw, _ := io.ReadAll(resp.Body)
+		var out map[string]any
+		Expect(sonic.Unmarshal(raw, &out)).To(Succeed())
+		return resp.StatusCode, out
+	}
+
+	query := func(q string) string {
+		body, _ := sonic.Marshal(map[string]any{"query": q})
+		return string(body)
+	}
+
+	It("serves a dashboard in one request, opening each snapshot once", func() {
+		status, out := do(query(`{
+			portfolios {
+				slug
+				strategy { shortCode }
+				summary { currentValue sharpe }
+				holdings { date items { ticker marketValue } }
+				performance(from: "2025-10-16") { points { date portfolioValue } }
+			}
+		}`))
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out).NotTo(HaveKey("errors"))
+
+		list := out["data"].(map[string]any)["portfolios"].([]any)
+		Expect(list).To(HaveLen(2))
+		ready := list[0].(map[string]any)
+		Expect(ready["summary"]).To(Equal(map[string]any{"currentValue": 1000.0, "sharpe": 1.25}))
+		Expect(ready["holdings"]).To(HaveKeyWithValue("date", "2026-10-16"))
+		Expect(ready["performance"]).To(HaveKeyWithValue("points", HaveLen(1)))
+		Expect(ready["strategy"]).To(Equal(map[string]any{"shortCode": "adm"}))
+
+		pending := list[1].(map[string]any)
+		Expect(pending["summary"]).To(BeNil())
+		Expect(pending["holdings"]).To(BeNil())
+
+		Expect(opener.opens).To(Equal(map[string]int{"/snapshots/ready.sqlite": 1}))
+		Expect(opener.closes).To(Equal(opener.opens))
+		Expect(opener.from.Format("2006-01-02")).To(Equal("2025-10-16"))
+		Expect(strategies.gets).To(Equal(1))
+	})
+
+	It("only sees the caller's portfolios", func() {
+		_, out := do(query(`{ portfolio(slug: "theirs") { slug } }`))
+		Expect(out["data"]).To(Equal(map[string]any{"portfolio": nil}))
+	})
+
+	It("omits hidden strategies from the catalog", func() {
+		_, out := do(query(`{ strategies { shortCode } }`))
+		Expect(out["data"]).To(Equal(map[string]any{"strategies": []any{map[string]any{"shortCode": "adm"}}}))
+	})
+
+	It("treats a hidden strategy as absent when looked up by short code", func() {
+		_, out := do(query(`{ hidden: strategy(shortCode: "old") { shortCode } shown: strategy(shortCode: "adm") { shortCode } }`))
+		Expect(out).NotTo(HaveKey("errors"))
+		Expect(out["data"]).To(Equal(map[string]any{
+			"hidden": nil,
+			"shown":  map[string]any{"shortCode": "adm"},
+		}))
+	})
+
+	It("refuses queries nested past the depth limit", func() {
+		status, out := do(query(`{ portfolios { holdings { items { ticker { a { b } } } } } }`))
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out["errors"]).To(ContainElement(HaveKeyWithValue("message", ContainSubstring("exceeds max depth"))))
+		Expect(opener.opens).To(BeEmpty())
+	})
+
+	It("reports bad arguments as GraphQL errors", func() {
+		status, out := do(query(`{ portfolio(slug: "ready") { performance(from: "last year") { from } } }`))
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out["errors"]).To(HaveLen(1))
+		Expect(opener.opens).To(BeEmpty())
+	})
+
+	It("rejects a body that is not a GraphQL request", func() {
+		status, _ := do(`{"variables": {}}`)
+		Expect(status).To(Equal(fiber.StatusBadRequest))
+	})
+})
@@ -0,0 +1,144 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+var errNoSubject = errors.New("missing authenticated subject")
+
+// Query limits. The deepest query the schema allows today is four levels
+// (portfolios → holdings → items → field); the depth cap keeps a nested
+// field added later from making queries unbounded, and the parallelism
+// cap bounds how many resolvers one request runs at once.
+const (
+	maxQueryDepth  = 5
+	maxParallelism = 10
+)
+
+// Portfolios is the subset of portfolio.Store the resolvers read.
+type Portfolios interface {
+	List(ctx context.Context, ownerSub string) ([]portfolio.Portfolio, error)
+	Get(ctx context.Context, ownerSub, slug string) (portfolio.Portfolio, error)
+}
+
+// Handler serves POST /graphql.
+type Handler struct {
+	schema     *graphql.Schema
+	portfolios Portfolios
+	strategies strategy.ReadStore
+	opener     portfolio.SnapshotOpener
+	workspaces workspace.Roles
+}
+
+func NewHandler(portfolios Portfolios, strategies strategy.ReadStore, opener portfolio.SnapshotOpener) *Handler {
+	schema := graphql.MustParseSchema(schemaSDL, &query{},
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(maxQueryDepth),
+		graphql.MaxParallelism(maxParallelism),
+	)
+	return &Handler{
+		schema:     schema,
+		portfolios: portfolios,
+		strategies: strategies,
+		opener:     opener,
+	}
+}
+
+// WithWorkspaces lets members query a workspace's portfolios with
+// X-Workspace.
+func (h *Handler) WithWorkspaces(r workspace.Roles) *Handler {
+	h.workspaces = r
+	return h
+}
+
+type queryBody struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Query implements POST /graphql. Query errors are reported in the
+// response's errors array with a 200, as GraphQL clients expect; only a
+// body that is not a GraphQL request is a 400.
+func (h *Handler) Query(c fiber.Ctx) error {
+	sub, ok := c.Locals(types.AuthSubjectKey{}).(string)
+	if !ok || sub == "" {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", errNoSubject.Error())
+	}
+	ownerSub, err := workspace.Resolve(c, h.workspaces, sub, workspace.RoleViewer)
+	if err != nil {
+		status := workspace.Status(err)
+		return writeProblem(c, status, http.StatusText(status), err.Error())
+	}
+	var body queryBody
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid JSON body: "+err.Error())
+	}
+	if body.Query == "" {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "query is required")
+	}
+
+	req := &request{
+		h:          h,
+		ownerSub:   string([]byte(ownerSub)),
+		strategies: map[string]*strategyResolver{},
+	}
+	defer req.close()
+	ctx := context.WithValue(c.Context(), requestKey{}, req)
+	resp := h.schema.Exec(ctx, body.Query, body.OperationName, body.Variables)
+	return writeJSON(c, fiber.StatusOK, resp)
+}
+
+func writeJSON(c fiber.Ctx, status int, v any) error {
+	body, err := sonic.Marshal(v)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/json")
+	return c.Status(status).Send(body)
+}
+
+func writeProblem(c fiber.Ctx, status int, title, detail string) error {
+	type problem struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+	body, _ := sonic.Marshal(problem{
+		Type: "about:blank", Title: title, Status: status, Detail: detail, Instance: c.Path(),
+	})
+	c.Set(fiber.HeaderContentType, "application/problem+json")
+	return c.Status(status).Send(body)
+}
@@ -0,0 +1,318 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gql serves a read-only GraphQL view over portfolios, their
+// snapshot data and the strategy catalog, so a dashboard can fetch what
+// would otherwise take many REST calls in one round trip.
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+)
+
+const (
+	dateFormat      = "2006-01-02"
+	timestampFormat = "2006-01-02T15:04:05Z"
+)
+
+type requestKey struct{}
+
+// request is the per-query state. Each portfolio opens its snapshot at
+// most once however many snapshot fields are selected; the readers are
+// closed when the query finishes. Strategies are looked up once per short
+// code.
+type request struct {
+	h        *Handler
+	ownerSub string
+
+	mu         sync.Mutex
+	readers    []portfolio.SnapshotReader
+	strategies map[string]*strategyResolver
+}
+
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// open returns p's snapshot reader, or nil when p has no ready snapshot.
+// A snapshot that fails to open is treated as missing, as the REST reads
+// do.
+func (req *request) open(p portfolio.Portfolio) portfolio.SnapshotReader {
+	if p.Status != portfolio.StatusReady || p.SnapshotPath == nil || *p.SnapshotPath == "" {
+		return nil
+	}
+	reader, err := req.h.opener.Open(*p.SnapshotPath)
+	if err != nil {
+		log.Warn().Err(err).Str("portfolio", p.Slug).Msg("graphql: open snapshot failed")
+		return nil
+	}
+	req.mu.Lock()
+	req.readers = append(req.readers, reader)
+	req.mu.Unlock()
+	return reader
+}
+
+func (req *request) strategy(ctx context.Context, shortCode string) (*strategyResolver, error) {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	if s, ok := req.strategies[shortCode]; ok {
+		return s, nil
+	}
+	row, err := req.h.strategies.Get(ctx, shortCode)
+	if errors.Is(err, strategy.ErrNotFound) {
+		req.strategies[shortCode] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := &strategyResolver{row}
+	req.strategies[shortCode] = s
+	return s, nil
+}
+
+func (req *request) close() {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	for _, r := range req.readers {
+		_ = r.Close()
+	}
+	req.readers = nil
+}
+
+// query is the root resolver.
+type query struct{}
+
+func (*query) Portfolios(ctx context.Context) ([]*portfolioResolver, error) {
+	req := requestFrom(ctx)
+	rows, err := req.h.portfolios.List(ctx, req.ownerSub)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*portfolioResolver, 0, len(rows))
+	for _, p := range rows {
+		out = append(out, &portfolioResolver{p: p, req: req})
+	}
+	return out, nil
+}
+
+func (*query) Portfolio(ctx context.Context, args struct{ Slug string }) (*portfolioResolver, error) {
+	req := requestFrom(ctx)
+	p, err := req.h.portfolios.Get(ctx, req.ownerSub, args.Slug)
+	if errors.Is(err, portfolio.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &portfolioResolver{p: p, req: req}, nil
+}
+
+// Strategies lists the catalog; hidden strategies are omitted, as in
+// GET /strategies.
+func (*query) Strategies(ctx context.Context) ([]*strategyResolver, error) {
+	rows, err := requestFrom(ctx).h.strategies.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*strategyResolver, 0, len(rows))
+	for _, s := range rows {
+		if !s.Hidden {
+			out = append(out, &strategyResolver{s})
+		}
+	}
+	return out, nil
+}
+
+// Strategy looks up one catalog entry. Like Strategies, it treats a hidden
+// strategy as absent; a portfolio's own strategy field still resolves it.
+func (*query) Strategy(ctx context.Context, args struct{ ShortCode string }) (*strategyResolver, error) {
+	s, err := requestFrom(ctx).strategy(ctx, args.ShortCode)
+	if err != nil || s == nil || s.Hidden {
+		return nil, err
+	}
+	return s, nil
+}
+
+type portfolioResolver struct {
+	p   portfolio.Portfolio
+	req *request
+
+	once   sync.Once
+	reader portfolio.SnapshotReader
+}
+
+func (r *portfolioResolver) snapshot() portfolio.SnapshotReader {
+	r.once.Do(func() { r.reader = r.req.open(r.p) })
+	return r.reader
+}
+
+func (r *portfolioResolver) Slug() string                 { return r.p.Slug }
+func (r *portfolioResolver) Name() string                 { return r.p.Name }
+func (r *portfolioResolver) Status() string               { return string(r.p.Status) }
+func (r *portfolioResolver) StrategyCode() string         { return r.p.StrategyCode }
+func (r *portfolioResolver) StrategyVer() *string         { return r.p.StrategyVer }
+func (r *portfolioResolver) Benchmark() string            { return r.p.Benchmark }
+func (r *portfolioResolver) StartDate() *string           { return formatDate(r.p.StartDate) }
+func (r *portfolioResolver) EndDate() *string             { return formatDate(r.p.EndDate) }
+func (r *portfolioResolver) InceptionDate() *string       { return formatDate(r.p.InceptionDate) }
+func (r *portfolioResolver) CreatedAt() string            { return r.p.CreatedAt.UTC().Format(timestampFormat) }
+func (r *portfolioResolver) UpdatedAt() string            { return r.p.UpdatedAt.UTC().Format(timestampFormat) }
+func (r *portfolioResolver) LastError() *string           { return r.p.LastError }
+func (r *portfolioResolver) CurrentValue() *float64       { return r.p.CurrentValue }
+func (r *portfolioResolver) YtdReturn() *float64          { return r.p.YtdReturn }
+func (r *portfolioResolver) MaxDrawDown() *float64        { return r.p.MaxDrawdown }
+func (r *portfolioResolver) Sharpe() *float64             { return r.p.Sharpe }
+func (r *portfolioResolver) CagrSinceInception() *float64 { return r.p.CagrSinceInception }
+
+func (r *portfolioResolver) Tags() []string {
+	if r.p.Tags == nil {
+		return []string{}
+	}
+	return r.p.Tags
+}
+
+func (r *portfolioResolver) LastRunAt() *string {
+	if r.p.LastRunAt == nil {
+		return nil
+	}
+	s := r.p.LastRunAt.UTC().Format(timestampFormat)
+	return &s
+}
+
+func (r *portfolioResolver) Strategy(ctx context.Context) (*strategyResolver, error) {
+	return r.req.strategy(ctx, r.p.StrategyCode)
+}
+
+func (r *portfolioResolver) Summary(ctx context.Context) (*openapi.PortfolioSummary, error) {
+	reader := r.snapshot()
+	if reader == nil {
+		return nil, nil
+	}
+	return reader.Summary(ctx)
+}
+
+func (r *portfolioResolver) Holdings(ctx context.Context) (*holdingsResolver, error) {
+	reader := r.snapshot()
+	if reader == nil {
+		return nil, nil
+	}
+	h, err := reader.CurrentHoldings(ctx)
+	if err != nil || h == nil {
+		return nil, err
+	}
+	return &holdingsResolver{h: h}, nil
+}
+
+func (r *portfolioResolver) Performance(ctx context.Context, args struct{ From, To *string }) (*performanceResolver, error) {
+	from, err := parseDate("from", args.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDate("to", args.To)
+	if err != nil {
+		return nil, err
+	}
+	reader := r.snapshot()
+	if reader == nil {
+		return nil, nil
+	}
+	perf, err := reader.Performance(ctx, r.p.Slug, from, to)
+	if err != nil || perf == nil {
+		return nil, err
+	}
+	return &performanceResolver{p: perf}, nil
+}
+
+// strategyResolver resolves Strategy fields from the row's own fields,
+// matched by name.
+type strategyResolver struct {
+	strategy.Strategy
+}
+
+func (r *strategyResolver) Categories() []string {
+	if r.Strategy.Categories == nil {
+		return []string{}
+	}
+	return r.Strategy.Categories
+}
+
+type holdingsResolver struct {
+	h *openapi.HoldingsResponse
+}
+
+func (r *holdingsResolver) Date() string              { return r.h.Date.Format(dateFormat) }
+func (r *holdingsResolver) TotalMarketValue() float64 { return r.h.TotalMarketValue }
+
+func (r *holdingsResolver) Items() []*openapi.Holding {
+	out := make([]*openapi.Holding, len(r.h.Items))
+	for i := range r.h.Items {
+		out[i] = &r.h.Items[i]
+	}
+	return out
+}
+
+type performanceResolver struct {
+	p *openapi.PortfolioPerformance
+}
+
+func (r *performanceResolver) From() string { return r.p.From.Format(dateFormat) }
+func (r *performanceResolver) To() string   { return r.p.To.Format(dateFormat) }
+
+func (r *performanceResolver) Points() []*pointResolver {
+	out := make([]*pointResolver, len(r.p.Points))
+	for i := range r.p.Points {
+		out[i] = &pointResolver{p: &r.p.Points[i]}
+	}
+	return out
+}
+
+type pointResolver struct {
+	p *openapi.PerformancePoint
+}
+
+func (r *pointResolver) Date() string            { return r.p.Date.Format(dateFormat) }
+func (r *pointResolver) PortfolioValue() float64 { return r.p.PortfolioValue }
+func (r *pointResolver) BenchmarkValue() float64 { return r.p.BenchmarkValue }
+func (r *pointResolver) RiskFreeRate() *float64  { return r.p.RiskFreeRate }
+
+func formatDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format(dateFormat)
+	return &s
+}
+
+func parseDate(name string, s *string) (*time.Time, error) {
+	if s == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(dateFormat, *s)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be YYYY-MM-DD", name)
+	}
+	return &t, nil
+}
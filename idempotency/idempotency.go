@@ -0,0 +1,178 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// Package idempotency remembers the responses to requests that carried an
+// Idempotency-Key header, so a client retrying after a dropped connection
+// gets the first response back instead of a second portfolio, run or
+// alert. Keys belong to the subject that sent them and are forgotten
+// after a retention window.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Header is the request header that carries the key.
+const Header = "Idempotency-Key"
+
+// ReplayedHeader marks a response replayed from an earlier request.
+const ReplayedHeader = "Idempotent-Replayed"
+
+// MaxKeyLength bounds the keys clients may send.
+const MaxKeyLength = 255
+
+// DefaultTTL is how long a response is replayed when no retention window
+// is configured.
+const DefaultTTL = 24 * time.Hour
+
+// abandonAfter is how long a key may stay claimed without a response
+// before it is treated as abandoned, for instance by a server that
+// crashed mid-request.
+const abandonAfter = 5 * time.Minute
+
+var (
+	// ErrInProgress is returned when the first request with a key has not
+	// finished yet.
+	ErrInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrMismatch is returned when a key is reused for a different request.
+	ErrMismatch = errors.New("idempotency key was already used for a different request")
+)
+
+// Response is a stored response.
+type Response struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Fingerprint identifies a request by its method, path, workspace and
+// body.
+func Fingerprint(method, path, workspace string, body []byte) []byte {
+	h := sha256.New()
+	for _, part := range [][]byte{[]byte(method), []byte(path), []byte(workspace)} {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Store keeps responses by subject and key.
+type Store interface {
+	// Reserve claims key for a request with fingerprint. It returns nil
+	// when the claim succeeded and the request should run, the stored
+	// response when one exists, ErrInProgress when the first request is
+	// still running and ErrMismatch when the key belongs to a different
+	// request. Keys older than ttl are free to claim again.
+	Reserve(ctx context.Context, subject, key string, fingerprint []byte, ttl time.Duration) (*Response, error)
+	// Complete stores the response to a claimed key.
+	Complete(ctx context.Context, subject, key string, r Response) error
+	// Release gives up a claim so the request can be retried.
+	Release(ctx context.Context, subject, key string) error
+}
+
+// PoolStore is the pgxpool-backed Store.
+type PoolStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolStore(pool *pgxpool.Pool) *PoolStore { return &PoolStore{pool: pool} }
+
+// Reserve also drops the subject's expired keys, which keeps the table
+// bounded without a separate sweep.
+func (s *PoolStore) Reserve(ctx context.Context, subject, key string, fingerprint []byte, ttl time.Duration) (*Response, error) {
+	if _, err := s.pool.Exec(ctx, `
+		DELETE FROM idempotency_keys
+		 WHERE subject = $1 AND created_at < now() - make_interval(secs => $2)`,
+		subject, ttl.Seconds()); err != nil {
+		return nil, fmt.Errorf("expire idempotency keys: %w", err)
+	}
+
+	var claimed bool
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO idempotency_keys (subject, key, fingerprint)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (subject, key) DO UPDATE
+		   SET fingerprint = EXCLUDED.fingerprint, status = NULL, content_type = NULL,
+		       body = NULL, created_at = now()
+		 WHERE idempotency_keys.status IS NULL
+		   AND idempotency_keys.created_at < now() - make_interval(secs => $4)
+		RETURNING true`,
+		subject, key, fingerprint, abandonAfter.Seconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("claim idempotency key: %w", err)
+	}
+
+	var (
+		stored      []byte
+		status      *int
+		contentType *string
+		body        []byte
+	)
+	err = s.pool.QueryRow(ctx, `
+		SELECT fingerprint, status, content_type, body
+		  FROM idempotency_keys
+		 WHERE subject = $1 AND key = $2`,
+		subject, key).Scan(&stored, &status, &contentType, &body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Released between the two statements; the client can retry.
+		return nil, ErrInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read idempotency key: %w", err)
+	}
+	if string(stored) != string(fingerprint) {
+		return nil, ErrMismatch
+	}
+	if status == nil {
+		return nil, ErrInProgress
+	}
+	r := &Response{Status: *status, Body: body}
+	if contentType != nil {
+		r.ContentType = *contentType
+	}
+	return r, nil
+}
+
+func (s *PoolStore) Complete(ctx context.Context, subject, key string, r Response) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE idempotency_keys
+		   SET status = $3, content_type = NULLIF($4, ''), body = $5
+		 WHERE subject = $1 AND key = $2`,
+		subject, key, r.Status, r.ContentType, r.Body)
+	if err != nil {
+		return fmt.Errorf("store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (s *PoolStore) Release(ctx context.Context, subject, key string) error {
+	_, err := s.pool.Exec(ctx,
+		`DELETE FROM idempotency_keys WHERE subject = $1 AND key = $2 AND status IS NULL`,
+		subject, key)
+	if err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
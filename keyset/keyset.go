@@ -0,0 +1,119 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keyset implements the keyset pagination shared by the list
+// endpoints: the opaque cursor returned in X-Next-Cursor and the SQL that
+// orders a page and resumes after that cursor, so a page costs LIMIT rows
+// however deep into the list it is.
+package keyset
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// HeaderNextCursor carries the opaque cursor for the next page of a list.
+const HeaderNextCursor = "X-Next-Cursor"
+
+// ErrInvalidCursor is returned by Decode for a malformed cursor or one
+// issued under a different sort.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the decoded form of the opaque pagination cursor: the sort it
+// was issued for and the sort key and unique key of the last row on the
+// previous page.
+type Cursor struct {
+	Sort  string   `json:"s"`
+	Value *float64 `json:"v,omitempty"`
+	Key   string   `json:"k"`
+}
+
+// Encode returns the opaque form of c.
+func Encode(c Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode parses an opaque cursor and checks it was issued for sort.
+func Decode(token, sort string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Args accumulates positional query arguments.
+type Args []any
+
+// Add appends v and returns its placeholder.
+func (a *Args) Add(v any) string {
+	*a = append(*a, v)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// Order is one list ordering: rows sort by Column in the requested
+// direction with NULLs last either way, then by the unique Key ascending so
+// the order is total and cursors are stable. An empty Column sorts by Key
+// alone, in the requested direction.
+type Order struct {
+	Column     string // nullable sort column; "" orders by Key alone
+	Key        string // unique, non-null tie-breaker column
+	Descending bool
+	// Bind converts a cursor value to the query argument compared against
+	// Column; nil binds the float64 as is.
+	Bind func(float64) any
+}
+
+// OrderBy returns the ORDER BY list for o.
+func (o Order) OrderBy() string {
+	dir := " ASC"
+	if o.Descending {
+		dir = " DESC"
+	}
+	if o.Column == "" {
+		return o.Key + dir
+	}
+	return o.Column + dir + " NULLS LAST, " + o.Key + " ASC"
+}
+
+// After returns a predicate that selects the rows ordered after c.
+func (o Order) After(c Cursor, args *Args) string {
+	cmp := " > "
+	if o.Descending {
+		cmp = " < "
+	}
+	if o.Column == "" {
+		return o.Key + cmp + args.Add(c.Key)
+	}
+	key := args.Add(c.Key)
+	if c.Value == nil {
+		return "(" + o.Column + " IS NULL AND " + o.Key + " > " + key + ")"
+	}
+	var v any = *c.Value
+	if o.Bind != nil {
+		v = o.Bind(*c.Value)
+	}
+	val := args.Add(v)
+	return "(" + o.Column + cmp + val +
+		" OR (" + o.Column + " = " + val + " AND " + o.Key + " > " + key + ")" +
+		" OR " + o.Column + " IS NULL)"
+}
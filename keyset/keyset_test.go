@@ -0,0 +1,79 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keyset_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/keyset"
+)
+
+var _ = Describe("Cursor", func() {
+	It("round-trips through Encode and Decode", func() {
+		v := 0.25
+		in := keyset.Cursor{Sort: "-cagr", Value: &v, Key: "adm"}
+		out, err := keyset.Decode(keyset.Encode(in), "-cagr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(Equal(in))
+	})
+
+	It("rejects a cursor issued for a different sort", func() {
+		_, err := keyset.Decode(keyset.Encode(keyset.Cursor{Sort: "-cagr", Key: "adm"}), "cagr")
+		Expect(err).To(MatchError(keyset.ErrInvalidCursor))
+	})
+
+	It("rejects a malformed cursor", func() {
+		_, err := keyset.Decode("%%%", "cagr")
+		Expect(err).To(MatchError(keyset.ErrInvalidCursor))
+	})
+})
+
+var _ = Describe("Order", func() {
+	It("orders by the key alone when there is no sort column", func() {
+		o := keyset.Order{Key: "slug", Descending: true}
+		Expect(o.OrderBy()).To(Equal("slug DESC"))
+		var args keyset.Args
+		Expect(o.After(keyset.Cursor{Key: "b"}, &args)).To(Equal("slug < $1"))
+		Expect(args).To(Equal(keyset.Args{"b"}))
+	})
+
+	It("puts NULLs last and breaks ties by ascending key", func() {
+		o := keyset.Order{Column: "cagr", Key: "short_code", Descending: true}
+		Expect(o.OrderBy()).To(Equal("cagr DESC NULLS LAST, short_code ASC"))
+		args := keyset.Args{"momentum"}
+		v := 0.1
+		Expect(o.After(keyset.Cursor{Value: &v, Key: "adm"}, &args)).To(Equal(
+			"(cagr < $3 OR (cagr = $3 AND short_code > $2) OR cagr IS NULL)"))
+		Expect(args).To(Equal(keyset.Args{"momentum", "adm", 0.1}))
+	})
+
+	It("resumes inside the NULL tail with a key comparison", func() {
+		o := keyset.Order{Column: "cagr", Key: "short_code"}
+		var args keyset.Args
+		Expect(o.After(keyset.Cursor{Key: "bad"}, &args)).To(Equal("(cagr IS NULL AND short_code > $1)"))
+	})
+
+	It("binds the cursor value through Bind", func() {
+		o := keyset.Order{Column: "created_at", Key: "slug", Bind: func(v float64) any { return time.UnixMicro(int64(v)).UTC() }}
+		var args keyset.Args
+		v := float64(time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC).UnixMicro())
+		o.After(keyset.Cursor{Value: &v, Key: "a"}, &args)
+		Expect(args[1]).To(Equal(time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC)))
+	})
+})
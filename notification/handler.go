@@ -0,0 +1,304 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/types"
+)
+
+const (
+	// HeaderNextCursor carries the cursor for the next page of the inbox.
+	HeaderNextCursor = "X-Next-Cursor"
+	// HeaderUnreadCount carries the caller's unread notification count.
+	HeaderUnreadCount = "X-Unread-Count"
+
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// keepAliveInterval is how often an idle stream sends an SSE comment, so
+// proxies do not time it out and a departed client is noticed.
+const keepAliveInterval = 30 * time.Second
+
+// Handler serves the caller's inbox under /notifications.
+type Handler struct {
+	store     Store
+	hub       *Hub
+	keepAlive time.Duration
+}
+
+// NewHandler builds a Handler. Without a hub the stream endpoint returns 501.
+func NewHandler(store Store, hub *Hub) *Handler {
+	return &Handler{store: store, hub: hub, keepAlive: keepAliveInterval}
+}
+
+// List implements GET /notifications: the caller's notifications, newest
+// first. ?unread=true limits it to unread ones; limit and cursor page it,
+// with the next page's cursor in the X-Next-Cursor header.
+func (h *Handler) List(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	q := ListQuery{Limit: defaultListLimit}
+	if v := string([]byte(c.Query("unread"))); v != "" {
+		if q.UnreadOnly, err = strconv.ParseBool(v); err != nil {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "unread must be true or false")
+		}
+	}
+	if v := string([]byte(c.Query("limit"))); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxListLimit {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity",
+				fmt.Sprintf("limit must be between 1 and %d", maxListLimit))
+		}
+		q.Limit = n
+	}
+	if v := string([]byte(c.Query("cursor"))); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "invalid cursor")
+		}
+		q.Before = &id
+	}
+
+	// Fetch one extra row to learn whether another page exists.
+	q.Limit++
+	rows, err := h.store.List(c.Context(), ownerSub, q)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if len(rows) == q.Limit {
+		rows = rows[:q.Limit-1]
+		c.Set(HeaderNextCursor, rows[len(rows)-1].ID.String())
+	}
+	unread, err := h.store.UnreadCount(c.Context(), ownerSub)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(HeaderUnreadCount, strconv.Itoa(unread))
+
+	out := make([]view, 0, len(rows))
+	for _, n := range rows {
+		out = append(out, toView(n))
+	}
+	return writeJSON(c, fiber.StatusOK, out)
+}
+
+// MarkRead implements POST /notifications/{id}/read.
+func (h *Handler) MarkRead(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "notification not found")
+	}
+	err = h.store.MarkRead(c.Context(), ownerSub, id)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "notification not found")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// MarkAllRead implements POST /notifications/read.
+func (h *Handler) MarkAllRead(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	if err := h.store.MarkAllRead(c.Context(), ownerSub); err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Stream implements GET /notifications/stream. It sends each new
+// notification for the caller as a Server-Sent Event named "notification"
+// whose id is the notification's id. A client reconnecting with a
+// Last-Event-ID header first receives what it missed.
+func (h *Handler) Stream(c fiber.Ctx) error {
+	if h.hub == nil {
+		return writeProblem(c, fiber.StatusNotImplemented, "Not Implemented", "notification streaming not configured")
+	}
+	ownerSub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+
+	// Subscribe before reading the backlog so nothing published in between
+	// is lost; replayed ids are skipped if they also arrive live.
+	events, unsub := h.hub.Subscribe(ownerSub)
+	var backlog []Notification
+	if v := c.Get("Last-Event-ID"); v != "" {
+		if last, perr := uuid.Parse(v); perr == nil {
+			backlog, err = h.store.After(c.Context(), ownerSub, last)
+			if err != nil {
+				unsub()
+				return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+			}
+		}
+	}
+
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("X-Accel-Buffering", "no")
+	c.Set("Connection", "keep-alive")
+
+	reqCtx := c.Context()
+	keepAlive := h.keepAlive
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer unsub()
+		seen := make(map[uuid.UUID]bool, len(backlog))
+		for _, n := range backlog {
+			seen[n.ID] = true
+			writeSSENotification(w, n)
+		}
+		// An initial comment commits the response so the client knows the
+		// stream is open even when there is nothing to replay.
+		_, _ = w.WriteString(": connected\n\n")
+		if w.Flush() != nil {
+			return
+		}
+		streamNotifications(reqCtx, w, events, seen, keepAlive)
+	})
+}
+
+// streamNotifications writes events until the channel closes, the client
+// goes away or ctx is cancelled.
+func streamNotifications(ctx context.Context, w *bufio.Writer, events <-chan Notification,
+	seen map[uuid.UUID]bool, keepAlive time.Duration,
+) {
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case n, ok := <-events:
+			if !ok {
+				return
+			}
+			if seen[n.ID] {
+				continue
+			}
+			writeSSENotification(w, n)
+			if w.Flush() != nil {
+				return
+			}
+		case <-ticker.C:
+			_, _ = w.WriteString(": keep-alive\n\n")
+			if w.Flush() != nil {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func writeSSENotification(w *bufio.Writer, n Notification) {
+	data, _ := json.Marshal(toView(n))
+	_, _ = fmt.Fprintf(w, "id: %s\nevent: notification\ndata: %s\n\n", n.ID, data)
+}
+
+// view mirrors the OpenAPI Notification schema.
+type view struct {
+	ID            string         `json:"id"`
+	Kind          string         `json:"kind"`
+	PortfolioSlug *string        `json:"portfolioSlug"`
+	RunID         *string        `json:"runId"`
+	Title         string         `json:"title"`
+	Body          string         `json:"body"`
+	Data          map[string]any `json:"data"`
+	Read          bool           `json:"read"`
+	ReadAt        *string        `json:"readAt"`
+	CreatedAt     string         `json:"createdAt"`
+}
+
+func toView(n Notification) view {
+	v := view{
+		ID:            n.ID.String(),
+		Kind:          n.Kind,
+		PortfolioSlug: n.PortfolioSlug,
+		Title:         n.Title,
+		Body:          n.Body,
+		Data:          n.Data,
+		Read:          n.ReadAt != nil,
+		CreatedAt:     n.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if v.Data == nil {
+		v.Data = map[string]any{}
+	}
+	if n.RunID != nil {
+		s := n.RunID.String()
+		v.RunID = &s
+	}
+	if n.ReadAt != nil {
+		s := n.ReadAt.UTC().Format(time.RFC3339)
+		v.ReadAt = &s
+	}
+	return v
+}
+
+// errNoSubject is returned when the auth middleware did not set a subject.
+var errNoSubject = errors.New("missing subject")
+
+func subject(c fiber.Ctx) (string, error) {
+	sub, ok := c.Locals(types.AuthSubjectKey{}).(string)
+	if !ok || sub == "" {
+		return "", errNoSubject
+	}
+	return string([]byte(sub)), nil
+}
+
+func writeJSON(c fiber.Ctx, status int, v any) error {
+	body, err := sonic.Marshal(v)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/json")
+	return c.Status(status).Send(body)
+}
+
+func writeProblem(c fiber.Ctx, status int, title, detail string) error {
+	type problem struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+	body, _ := sonic.Marshal(problem{
+		Type: "about:blank", Title: title, Status: status, Detail: detail, Instance: c.Path(),
+	})
+	c.Set(fiber.HeaderContentType, "application/problem+json")
+	return c.Status(status).Send(body)
+}
@@ -0,0 +1,303 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/notification"
+	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// fakeStore is an in-memory notification.Store. Rows are kept oldest first.
+type fakeStore struct {
+	mu         sync.Mutex
+	rows       []notification.Notification
+	portfolios map[uuid.UUID]notification.PortfolioRef
+	runErrors  map[uuid.UUID]string
+}
+
+func (f *fakeStore) Insert(_ context.Context, n notification.Notification) (notification.Notification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n.ID = uuid.Must(uuid.NewV7())
+	n.CreatedAt = time.Now()
+	f.rows = append(f.rows, n)
+	return n, nil
+}
+
+func (f *fakeStore) List(_ context.Context, ownerSub string, q notification.ListQuery) ([]notification.Notification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := []notification.Notification{}
+	for i := len(f.rows) - 1; i >= 0 && len(out) < q.Limit; i-- {
+		n := f.rows[i]
+		if n.OwnerSub != ownerSub || (q.UnreadOnly && n.ReadAt != nil) {
+			continue
+		}
+		if q.Before != nil && n.ID.String() >= q.Before.String() {
+			continue
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func (f *fakeStore) After(_ context.Context, ownerSub string, id uuid.UUID) ([]notification.Notification, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := []notification.Notification{}
+	for _, n := range f.rows {
+		if n.OwnerSub == ownerSub && n.ID.String() > id.String() {
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) UnreadCount(_ context.Context, ownerSub string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, n := range f.rows {
+		if n.OwnerSub == ownerSub && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeStore) MarkRead(_ context.Context, ownerSub string, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, n := range f.rows {
+		if n.OwnerSub == ownerSub && n.ID == id {
+			now := time.Now()
+			f.rows[i].ReadAt = &now
+			return nil
+		}
+	}
+	return notification.ErrNotFound
+}
+
+func (f *fakeStore) MarkAllRead(_ context.Context, ownerSub string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for i, n := range f.rows {
+		if n.OwnerSub == ownerSub && n.ReadAt == nil {
+			f.rows[i].ReadAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) Portfolio(_ context.Context, id uuid.UUID) (notification.PortfolioRef, error) {
+	p, ok := f.portfolios[id]
+	if !ok {
+		return p, errors.New("no such portfolio")
+	}
+	return p, nil
+}
+
+func (f *fakeStore) RunError(_ context.Context, runID uuid.UUID) (string, error) {
+	return f.runErrors[runID], nil
+}
+
+// staticMembers is a fixed notification.Members.
+type staticMembers map[uuid.UUID][]workspace.Member
+
+func (m staticMembers) Members(_ context.Context, id uuid.UUID) ([]workspace.Member, error) {
+	return m[id], nil
+}
+
+var _ = Describe("Notifications", func() {
+	var (
+		app         *fiber.App
+		store       *fakeStore
+		hub         *notification.Hub
+		svc         *notification.Service
+		sub         = "auth0|owner"
+		portfolioID uuid.UUID
+	)
+
+	do := func(method, target string, header map[string]string) (*httptestResponse, error) {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req, fiber.TestConfig{Timeout: 5 * time.Second})
+		if err != nil {
+			return nil, err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return &httptestResponse{status: resp.StatusCode, header: resp.Header.Get, body: string(body)}, nil
+	}
+
+	BeforeEach(func() {
+		portfolioID = uuid.Must(uuid.NewV7())
+		store = &fakeStore{
+			portfolios: map[uuid.UUID]notification.PortfolioRef{
+				portfolioID: {OwnerSub: sub, Slug: "core", Name: "Core"},
+			},
+			runErrors: map[uuid.UUID]string{},
+		}
+		hub = notification.NewHub()
+		svc = notification.NewService(store, hub)
+		h := notification.NewHandler(store, hub)
+
+		app = fiber.New(fiber.Config{JSONEncoder: sonic.Marshal, JSONDecoder: sonic.Unmarshal})
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, sub)
+			return c.Next()
+		})
+		app.Get("/notifications", h.List)
+		app.Get("/notifications/stream", h.Stream)
+		app.Post("/notifications/read", h.MarkAllRead)
+		app.Post("/notifications/:id/read", h.MarkRead)
+	})
+
+	It("records run outcomes for the portfolio owner", func() {
+		ctx := context.Background()
+		okRun, badRun := uuid.New(), uuid.New()
+		store.runErrors[badRun] = "strategy exited 1"
+		Expect(svc.NotifyRunComplete(ctx, portfolioID, okRun, true)).To(Succeed())
+		Expect(svc.NotifyRunComplete(ctx, portfolioID, badRun, false)).To(Succeed())
+
+		Expect(store.rows).To(HaveLen(2))
+		Expect(store.rows[0].Kind).To(Equal(notification.KindRunSucceeded))
+		Expect(store.rows[0].OwnerSub).To(Equal(sub))
+		Expect(*store.rows[0].PortfolioSlug).To(Equal("core"))
+		Expect(store.rows[1].Kind).To(Equal(notification.KindRunFailed))
+		Expect(store.rows[1].Body).To(Equal("strategy exited 1"))
+	})
+
+	It("delivers workspace portfolio notifications to every member", func() {
+		ctx := context.Background()
+		wsID := uuid.Must(uuid.NewV7())
+		store.portfolios[portfolioID] = notification.PortfolioRef{
+			OwnerSub: workspace.OwnerSub(wsID), Slug: "core", Name: "Core",
+		}
+		svc.WithMembers(staticMembers{wsID: {{Sub: sub, Role: workspace.RoleOwner}, {Sub: "auth0|teammate", Role: workspace.RoleViewer}}})
+		Expect(svc.NotifyRunComplete(ctx, portfolioID, uuid.New(), true)).To(Succeed())
+
+		Expect(store.rows).To(HaveLen(2))
+		Expect(store.rows[0].OwnerSub).To(Equal(sub))
+		Expect(store.rows[1].OwnerSub).To(Equal("auth0|teammate"))
+	})
+
+	It("lists newest first with paging, unread filter and read marking", func() {
+		ctx := context.Background()
+		for range 3 {
+			Expect(svc.Notify(ctx, notification.Notification{PortfolioID: &portfolioID, Kind: notification.KindRunSucceeded, Title: "t"})).To(Succeed())
+		}
+		Expect(svc.Notify(ctx, notification.Notification{OwnerSub: "auth0|other", Kind: notification.KindRunSucceeded, Title: "t"})).To(Succeed())
+
+		resp, err := do("GET", "/notifications?limit=2", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.status).To(Equal(fiber.StatusOK))
+		Expect(resp.header(notification.HeaderUnreadCount)).To(Equal("3"))
+		var page []struct {
+			ID   string `json:"id"`
+			Read bool   `json:"read"`
+		}
+		Expect(sonic.Unmarshal([]byte(resp.body), &page)).To(Succeed())
+		Expect(page).To(HaveLen(2))
+		Expect(page[0].ID).To(Equal(store.rows[2].ID.String()))
+		cursor := resp.header(notification.HeaderNextCursor)
+		Expect(cursor).To(Equal(page[1].ID))
+
+		resp, err = do("GET", "/notifications?limit=2&cursor="+cursor, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sonic.Unmarshal([]byte(resp.body), &page)).To(Succeed())
+		Expect(page).To(HaveLen(1))
+		Expect(resp.header(notification.HeaderNextCursor)).To(BeEmpty())
+
+		resp, err = do("POST", "/notifications/"+page[0].ID+"/read", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.status).To(Equal(fiber.StatusNoContent))
+		resp, err = do("GET", "/notifications?unread=true", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sonic.Unmarshal([]byte(resp.body), &page)).To(Succeed())
+		Expect(page).To(HaveLen(2))
+		Expect(resp.header(notification.HeaderUnreadCount)).To(Equal("2"))
+
+		// Another user's notification cannot be marked.
+		resp, err = do("POST", "/notifications/"+store.rows[3].ID.String()+"/read", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.status).To(Equal(fiber.StatusNotFound))
+
+		resp, err = do("POST", "/notifications/read", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.status).To(Equal(fiber.StatusNoContent))
+		resp, err = do("GET", "/notifications", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.header(notification.HeaderUnreadCount)).To(Equal("0"))
+	})
+
+	It("rejects a bad limit", func() {
+		resp, err := do("GET", "/notifications?limit=0", nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.status).To(Equal(fiber.StatusUnprocessableEntity))
+	})
+
+	It("streams missed and new notifications", func() {
+		ctx := context.Background()
+		Expect(svc.Notify(ctx, notification.Notification{PortfolioID: &portfolioID, Kind: notification.KindRunSucceeded, Title: "seen"})).To(Succeed())
+		Expect(svc.Notify(ctx, notification.Notification{PortfolioID: &portfolioID, Kind: notification.KindRunFailed, Title: "missed"})).To(Succeed())
+		lastSeen := store.rows[0].ID.String()
+
+		go func() {
+			defer GinkgoRecover()
+			Eventually(func() int { return hub.Subscribers(sub) }).Should(Equal(1))
+			Expect(svc.Notify(ctx, notification.Notification{PortfolioID: &portfolioID, Kind: notification.KindAlertTriggered, Title: "live"})).To(Succeed())
+			// Someone else's notification is not streamed.
+			Expect(svc.Notify(ctx, notification.Notification{OwnerSub: "auth0|other", Kind: notification.KindAlertTriggered, Title: "other"})).To(Succeed())
+			hub.Close()
+		}()
+
+		resp, err := do("GET", "/notifications/stream", map[string]string{"Last-Event-ID": lastSeen})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.status).To(Equal(fiber.StatusOK))
+		Expect(resp.header("Content-Type")).To(HavePrefix("text/event-stream"))
+		Expect(resp.body).NotTo(ContainSubstring(`"title":"seen"`))
+		Expect(resp.body).NotTo(ContainSubstring(`"title":"other"`))
+		missed := strings.Index(resp.body, `"title":"missed"`)
+		live := strings.Index(resp.body, `"title":"live"`)
+		Expect(missed).To(BeNumerically(">=", 0))
+		Expect(live).To(BeNumerically(">", missed))
+		Expect(resp.body).To(ContainSubstring("id: " + store.rows[2].ID.String() + "\nevent: notification\n"))
+	})
+})
+
+type httptestResponse struct {
+	status int
+	header func(string) string
+	body   string
+}
@@ -0,0 +1,106 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import "sync"
+
+const subChanCap = 32
+
+// Hub fans new notifications out to their owner's open streams. It is
+// in-process: streams only see notifications published by the same server.
+type Hub struct {
+	mu     sync.Mutex
+	subs   map[string][]chan Notification
+	closed bool
+}
+
+// NewHub returns a ready-to-use Hub.
+func NewHub() *Hub {
+	return &Hub{subs: make(map[string][]chan Notification)}
+}
+
+// Publish sends n to every stream n.OwnerSub has open. A subscriber whose
+// channel is full is dropped and its channel closed; the client reconnects
+// and replays what it missed with Last-Event-ID.
+func (h *Hub) Publish(n Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.subs[n.OwnerSub]
+	alive := subs[:0]
+	for _, ch := range subs {
+		select {
+		case ch <- n:
+			alive = append(alive, ch)
+		default:
+			close(ch)
+		}
+	}
+	if len(alive) == 0 {
+		delete(h.subs, n.OwnerSub)
+		return
+	}
+	h.subs[n.OwnerSub] = alive
+}
+
+// Subscribe returns a channel of ownerSub's new notifications. The returned
+// func unsubscribes. The channel is closed when the subscriber falls behind
+// or the Hub is closed.
+func (h *Hub) Subscribe(ownerSub string) (<-chan Notification, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan Notification, subChanCap)
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	h.subs[ownerSub] = append(h.subs[ownerSub], ch)
+	unsub := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		subs := h.subs[ownerSub]
+		for i, s := range subs {
+			if s == ch {
+				h.subs[ownerSub] = append(subs[:i], subs[i+1:]...)
+				if len(h.subs[ownerSub]) == 0 {
+					delete(h.subs, ownerSub)
+				}
+				close(ch)
+				return
+			}
+		}
+	}
+	return ch, unsub
+}
+
+// Subscribers reports how many streams ownerSub has open.
+func (h *Hub) Subscribers(ownerSub string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[ownerSub])
+}
+
+// Close ends every open stream, so server shutdown does not wait on them.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for owner, subs := range h.subs {
+		for _, ch := range subs {
+			close(ch)
+		}
+		delete(h.subs, owner)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notification is the per-user in-app inbox: run completions and
+// failures, automatic portfolio upgrades and alert triggers are recorded in
+// the notifications table and pushed to the owner's open SSE streams. It
+// lives in its own package so the backtest runner, the portfolio and alert
+// packages and the HTTP layer can all publish without an import cycle.
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Notification kinds.
+const (
+	KindRunSucceeded      = "run.succeeded"
+	KindRunFailed         = "run.failed"
+	KindPortfolioUpgraded = "portfolio.upgraded"
+	KindAlertTriggered    = "alert.triggered"
+)
+
+// ErrNotFound is returned when a notification does not exist or belongs to
+// someone else.
+var ErrNotFound = errors.New("notification not found")
+
+// Notification is one inbox entry.
+type Notification struct {
+	ID            uuid.UUID
+	OwnerSub      string
+	Kind          string
+	PortfolioID   *uuid.UUID
+	PortfolioSlug *string
+	RunID         *uuid.UUID
+	Title         string
+	Body          string
+	// Data carries kind-specific fields (versions, error text, ...) for
+	// clients that render their own message.
+	Data      map[string]any
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PortfolioRef is what a notification needs to know about its portfolio.
+type PortfolioRef struct {
+	OwnerSub string
+	Slug     string
+	Name     string
+}
+
+// ListQuery selects a page of a user's inbox, newest first.
+type ListQuery struct {
+	UnreadOnly bool
+	Limit      int
+	// Before is the id of the last entry on the previous page.
+	Before *uuid.UUID
+}
+
+// Store persists notifications.
+type Store interface {
+	Insert(ctx context.Context, n Notification) (Notification, error)
+	List(ctx context.Context, ownerSub string, q ListQuery) ([]Notification, error)
+	// After returns the owner's notifications newer than id, oldest first.
+	After(ctx context.Context, ownerSub string, id uuid.UUID) ([]Notification, error)
+	UnreadCount(ctx context.Context, ownerSub string) (int, error)
+	MarkRead(ctx context.Context, ownerSub string, id uuid.UUID) error
+	MarkAllRead(ctx context.Context, ownerSub string) error
+	Portfolio(ctx context.Context, id uuid.UUID) (PortfolioRef, error)
+	// RunError returns the error recorded on a failed run, or "".
+	RunError(ctx context.Context, runID uuid.UUID) (string, error)
+}
+
+// PoolStore is the pgxpool-backed Store.
+type PoolStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPoolStore returns a Store backed by pool.
+func NewPoolStore(pool *pgxpool.Pool) *PoolStore { return &PoolStore{pool: pool} }
+
+const columns = `id, owner_sub, kind, portfolio_id, portfolio_slug, run_id, title, body, data, read_at, created_at`
+
+func scan(row pgx.Row) (Notification, error) {
+	var (
+		n    Notification
+		data []byte
+	)
+	if err := row.Scan(&n.ID, &n.OwnerSub, &n.Kind, &n.PortfolioID, &n.PortfolioSlug, &n.RunID,
+		&n.Title, &n.Body, &data, &n.ReadAt, &n.CreatedAt); err != nil {
+		return n, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &n.Data); err != nil {
+			return n, fmt.Errorf("decode data: %w", err)
+		}
+	}
+	return n, nil
+}
+
+func collect(rows pgx.Rows) ([]Notification, error) {
+	defer rows.Close()
+	out := []Notification{}
+	for rows.Next() {
+		n, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, n)
+	}
+	return out, rows.Err()
+}
+
+func (s *PoolStore) Insert(ctx context.Context, n Notification) (Notification, error) {
+	data := n.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Notification{}, fmt.Errorf("insert notification: %w", err)
+	}
+	out, err := scan(s.pool.QueryRow(ctx, `
+		INSERT INTO notifications (owner_sub, kind, portfolio_id, portfolio_slug, run_id, title, body, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+columns,
+		n.OwnerSub, n.Kind, n.PortfolioID, n.PortfolioSlug, n.RunID, n.Title, n.Body, raw))
+	if err != nil {
+		return Notification{}, fmt.Errorf("insert notification: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PoolStore) List(ctx context.Context, ownerSub string, q ListQuery) ([]Notification, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+columns+` FROM notifications
+		 WHERE owner_sub = $1
+		   AND ($2::bool = false OR read_at IS NULL)
+		   AND ($3::uuid IS NULL OR id < $3)
+		 ORDER BY id DESC
+		 LIMIT $4`,
+		ownerSub, q.UnreadOnly, q.Before, q.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	out, err := collect(rows)
+	if err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PoolStore) After(ctx context.Context, ownerSub string, id uuid.UUID) ([]Notification, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+columns+` FROM notifications
+		 WHERE owner_sub = $1 AND id > $2
+		 ORDER BY id`,
+		ownerSub, id)
+	if err != nil {
+		return nil, fmt.Errorf("replay notifications: %w", err)
+	}
+	out, err := collect(rows)
+	if err != nil {
+		return nil, fmt.Errorf("replay notifications: %w", err)
+	}
+	return out, nil
+}
+
+func (s *PoolStore) UnreadCount(ctx context.Context, ownerSub string) (int, error) {
+	var n int
+	err := s.pool.QueryRow(ctx,
+		`SELECT count(*) FROM notifications WHERE owner_sub = $1 AND read_at IS NULL`, ownerSub).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count unread notifications: %w", err)
+	}
+	return n, nil
+}
+
+func (s *PoolStore) MarkRead(ctx context.Context, ownerSub string, id uuid.UUID) error {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE notifications SET read_at = COALESCE(read_at, now())
+		 WHERE owner_sub = $1 AND id = $2`, ownerSub, id)
+	if err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *PoolStore) MarkAllRead(ctx context.Context, ownerSub string) error {
+	if _, err := s.pool.Exec(ctx,
+		`UPDATE notifications SET read_at = now() WHERE owner_sub = $1 AND read_at IS NULL`, ownerSub); err != nil {
+		return fmt.Errorf("mark notifications read: %w", err)
+	}
+	return nil
+}
+
+func (s *PoolStore) Portfolio(ctx context.Context, id uuid.UUID) (PortfolioRef, error) {
+	var p PortfolioRef
+	err := s.pool.QueryRow(ctx,
+		`SELECT owner_sub, slug, name FROM portfolios WHERE id = $1`, id).Scan(&p.OwnerSub, &p.Slug, &p.Name)
+	if err != nil {
+		return p, fmt.Errorf("load portfolio: %w", err)
+	}
+	return p, nil
+}
+
+func (s *PoolStore) RunError(ctx context.Context, runID uuid.UUID) (string, error) {
+	var msg *string
+	err := s.pool.QueryRow(ctx, `SELECT error FROM backtest_runs WHERE id = $1`, runID).Scan(&msg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("load run error: %w", err)
+	}
+	if msg == nil {
+		return "", nil
+	}
+	return *msg, nil
+}
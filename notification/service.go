@@ -0,0 +1,126 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// Members lists a workspace's members, so notifications about the
+// workspace's portfolios reach each of them.
+type Members interface {
+	Members(ctx context.Context, id uuid.UUID) ([]workspace.Member, error)
+}
+
+// Service records notifications and pushes them to the owner's streams.
+type Service struct {
+	store   Store
+	hub     *Hub
+	members Members
+}
+
+// NewService builds a Service. hub may be nil, in which case notifications
+// are only stored.
+func NewService(store Store, hub *Hub) *Service {
+	return &Service{store: store, hub: hub}
+}
+
+// WithMembers delivers notifications about workspace portfolios to every
+// member of the workspace. Without it they are dropped.
+func (s *Service) WithMembers(m Members) *Service {
+	s.members = m
+	return s
+}
+
+// Notify records n. When n names a portfolio but no owner, the portfolio's
+// owner and slug are filled in. A workspace owner is expanded to one
+// notification per member.
+func (s *Service) Notify(ctx context.Context, n Notification) error {
+	if n.PortfolioID != nil && (n.OwnerSub == "" || n.PortfolioSlug == nil) {
+		p, err := s.store.Portfolio(ctx, *n.PortfolioID)
+		if err != nil {
+			return fmt.Errorf("notify: %w", err)
+		}
+		if n.OwnerSub == "" {
+			n.OwnerSub = p.OwnerSub
+		}
+		if n.PortfolioSlug == nil {
+			n.PortfolioSlug = &p.Slug
+		}
+	}
+	recipients := []string{n.OwnerSub}
+	if id, ok := workspace.ParseOwnerSub(n.OwnerSub); ok {
+		recipients = nil
+		if s.members != nil {
+			members, err := s.members.Members(ctx, id)
+			if err != nil {
+				return fmt.Errorf("notify: workspace members: %w", err)
+			}
+			for _, m := range members {
+				recipients = append(recipients, m.Sub)
+			}
+		}
+	}
+	for _, sub := range recipients {
+		n.OwnerSub = sub
+		saved, err := s.store.Insert(ctx, n)
+		if err != nil {
+			return fmt.Errorf("notify: %w", err)
+		}
+		if s.hub != nil {
+			s.hub.Publish(saved)
+		}
+	}
+	return nil
+}
+
+// NotifyRunComplete tells the portfolio's owner that a backtest run
+// finished. It implements backtest.Notifier.
+func (s *Service) NotifyRunComplete(ctx context.Context, portfolioID, runID uuid.UUID, success bool) error {
+	p, err := s.store.Portfolio(ctx, portfolioID)
+	if err != nil {
+		return fmt.Errorf("notify run: %w", err)
+	}
+	n := Notification{
+		OwnerSub:      p.OwnerSub,
+		Kind:          KindRunSucceeded,
+		PortfolioID:   &portfolioID,
+		PortfolioSlug: &p.Slug,
+		RunID:         &runID,
+		Title:         p.Name + " is up to date",
+		Body:          "The backtest finished successfully.",
+		Data:          map[string]any{"runId": runID.String()},
+	}
+	if !success {
+		msg, err := s.store.RunError(ctx, runID)
+		if err != nil {
+			return fmt.Errorf("notify run: %w", err)
+		}
+		n.Kind = KindRunFailed
+		n.Title = "Backtest failed for " + p.Name
+		n.Body = "The backtest did not finish."
+		if msg != "" {
+			n.Body = msg
+			n.Data["error"] = msg
+		}
+	}
+	return s.Notify(ctx, n)
+}
@@ -13,10 +13,191 @@ const (
 	BearerAuthScopes = "BearerAuth.Scopes"
 )
 
+// Defines values for AdminAcceptedStatus.
+const (
+	Accepted AdminAcceptedStatus = "accepted"
+)
+
+// Valid indicates whether the value is a known member of the AdminAcceptedStatus enum.
+func (e AdminAcceptedStatus) Valid() bool {
+	switch e {
+	case Accepted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AlertChannelType.
+const (
+	AlertChannelTypeDiscord  AlertChannelType = "discord"
+	AlertChannelTypeNtfy     AlertChannelType = "ntfy"
+	AlertChannelTypeSlack    AlertChannelType = "slack"
+	AlertChannelTypeTelegram AlertChannelType = "telegram"
+	AlertChannelTypeWebhook  AlertChannelType = "webhook"
+)
+
+// Valid indicates whether the value is a known member of the AlertChannelType enum.
+func (e AlertChannelType) Valid() bool {
+	switch e {
+	case AlertChannelTypeDiscord:
+		return true
+	case AlertChannelTypeNtfy:
+		return true
+	case AlertChannelTypeSlack:
+		return true
+	case AlertChannelTypeTelegram:
+		return true
+	case AlertChannelTypeWebhook:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AlertConditionType.
+const (
+	AlertConditionTypeBenchmarkUnderperformance AlertConditionType = "benchmark_underperformance"
+	AlertConditionTypeDayChange                 AlertConditionType = "day_change"
+	AlertConditionTypeDrawdown                  AlertConditionType = "drawdown"
+	AlertConditionTypeHoldingChange             AlertConditionType = "holding_change"
+	AlertConditionTypePredictedTrade            AlertConditionType = "predicted_trade"
+)
+
+// Valid indicates whether the value is a known member of the AlertConditionType enum.
+func (e AlertConditionType) Valid() bool {
+	switch e {
+	case AlertConditionTypeBenchmarkUnderperformance:
+		return true
+	case AlertConditionTypeDayChange:
+		return true
+	case AlertConditionTypeDrawdown:
+		return true
+	case AlertConditionTypeHoldingChange:
+		return true
+	case AlertConditionTypePredictedTrade:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AlertConditionWindow.
+const (
+	AlertConditionWindowN1m AlertConditionWindow = "1m"
+	AlertConditionWindowN1w AlertConditionWindow = "1w"
+	AlertConditionWindowN1y AlertConditionWindow = "1y"
+	AlertConditionWindowN3m AlertConditionWindow = "3m"
+	AlertConditionWindowN6m AlertConditionWindow = "6m"
+	AlertConditionWindowYtd AlertConditionWindow = "ytd"
+)
+
+// Valid indicates whether the value is a known member of the AlertConditionWindow enum.
+func (e AlertConditionWindow) Valid() bool {
+	switch e {
+	case AlertConditionWindowN1m:
+		return true
+	case AlertConditionWindowN1w:
+		return true
+	case AlertConditionWindowN1y:
+		return true
+	case AlertConditionWindowN3m:
+		return true
+	case AlertConditionWindowN6m:
+		return true
+	case AlertConditionWindowYtd:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AlertDeliveryChannel.
+const (
+	AlertDeliveryChannelDiscord  AlertDeliveryChannel = "discord"
+	AlertDeliveryChannelEmail    AlertDeliveryChannel = "email"
+	AlertDeliveryChannelNtfy     AlertDeliveryChannel = "ntfy"
+	AlertDeliveryChannelSlack    AlertDeliveryChannel = "slack"
+	AlertDeliveryChannelTelegram AlertDeliveryChannel = "telegram"
+	AlertDeliveryChannelWebhook  AlertDeliveryChannel = "webhook"
+)
+
+// Valid indicates whether the value is a known member of the AlertDeliveryChannel enum.
+func (e AlertDeliveryChannel) Valid() bool {
+	switch e {
+	case AlertDeliveryChannelDiscord:
+		return true
+	case AlertDeliveryChannelEmail:
+		return true
+	case AlertDeliveryChannelNtfy:
+		return true
+	case AlertDeliveryChannelSlack:
+		return true
+	case AlertDeliveryChannelTelegram:
+		return true
+	case AlertDeliveryChannelWebhook:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AlertDeliveryEvent.
+const (
+	AlertDeliveryEventPortfolioCondition AlertDeliveryEvent = "portfolio.condition"
+	AlertDeliveryEventPortfolioDigest    AlertDeliveryEvent = "portfolio.digest"
+	AlertDeliveryEventPortfolioError     AlertDeliveryEvent = "portfolio.error"
+	AlertDeliveryEventPortfolioUpdate    AlertDeliveryEvent = "portfolio.update"
+	AlertDeliveryEventRecipientConfirm   AlertDeliveryEvent = "recipient.confirm"
+)
+
+// Valid indicates whether the value is a known member of the AlertDeliveryEvent enum.
+func (e AlertDeliveryEvent) Valid() bool {
+	switch e {
+	case AlertDeliveryEventPortfolioCondition:
+		return true
+	case AlertDeliveryEventPortfolioDigest:
+		return true
+	case AlertDeliveryEventPortfolioError:
+		return true
+	case AlertDeliveryEventPortfolioUpdate:
+		return true
+	case AlertDeliveryEventRecipientConfirm:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AlertDeliveryStatus.
+const (
+	AlertDeliveryStatusDead    AlertDeliveryStatus = "dead"
+	AlertDeliveryStatusPending AlertDeliveryStatus = "pending"
+	AlertDeliveryStatusSending AlertDeliveryStatus = "sending"
+	AlertDeliveryStatusSent    AlertDeliveryStatus = "sent"
+)
+
+// Valid indicates whether the value is a known member of the AlertDeliveryStatus enum.
+func (e AlertDeliveryStatus) Valid() bool {
+	switch e {
+	case AlertDeliveryStatusDead:
+		return true
+	case AlertDeliveryStatusPending:
+		return true
+	case AlertDeliveryStatusSending:
+		return true
+	case AlertDeliveryStatusSent:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for AlertFrequency.
 const (
 	AlertFrequencyDaily        AlertFrequency = "daily"
 	AlertFrequencyMonthly      AlertFrequency = "monthly"
+	AlertFrequencyOnCondition  AlertFrequency = "on_condition"
 	AlertFrequencyScheduledRun AlertFrequency = "scheduled_run"
 	AlertFrequencyWeekly       AlertFrequency = "weekly"
 )
@@ -28,6 +209,8 @@ func (e AlertFrequency) Valid() bool {
 		return true
 	case AlertFrequencyMonthly:
 		return true
+	case AlertFrequencyOnCondition:
+		return true
 	case AlertFrequencyScheduledRun:
 		return true
 	case AlertFrequencyWeekly:
@@ -37,6 +220,186 @@ func (e AlertFrequency) Valid() bool {
 	}
 }
 
+// Defines values for AlertRecipientStatusStatus.
+const (
+	AlertRecipientStatusStatusPending  AlertRecipientStatusStatus = "pending"
+	AlertRecipientStatusStatusVerified AlertRecipientStatusStatus = "verified"
+)
+
+// Valid indicates whether the value is a known member of the AlertRecipientStatusStatus enum.
+func (e AlertRecipientStatusStatus) Valid() bool {
+	switch e {
+	case AlertRecipientStatusStatusPending:
+		return true
+	case AlertRecipientStatusStatusVerified:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ApiKeyScope.
+const (
+	ApiKeyScopeRead      ApiKeyScope = "read"
+	ApiKeyScopeReadWrite ApiKeyScope = "read-write"
+)
+
+// Valid indicates whether the value is a known member of the ApiKeyScope enum.
+func (e ApiKeyScope) Valid() bool {
+	switch e {
+	case ApiKeyScopeRead:
+		return true
+	case ApiKeyScopeReadWrite:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ApiKeyRequestScope.
+const (
+	ApiKeyRequestScopeRead      ApiKeyRequestScope = "read"
+	ApiKeyRequestScopeReadWrite ApiKeyRequestScope = "read-write"
+)
+
+// Valid indicates whether the value is a known member of the ApiKeyRequestScope enum.
+func (e ApiKeyRequestScope) Valid() bool {
+	switch e {
+	case ApiKeyRequestScopeRead:
+		return true
+	case ApiKeyRequestScopeReadWrite:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for AuditEntryAction.
+const (
+	AuditEntryActionAdmin                AuditEntryAction = "admin"
+	AuditEntryActionAlertCreate          AuditEntryAction = "alert.create"
+	AuditEntryActionAlertDelete          AuditEntryAction = "alert.delete"
+	AuditEntryActionAlertUnsubscribe     AuditEntryAction = "alert.unsubscribe"
+	AuditEntryActionAlertUpdate          AuditEntryAction = "alert.update"
+	AuditEntryActionPortfolioAutoUpgrade AuditEntryAction = "portfolio.auto_upgrade"
+	AuditEntryActionPortfolioCreate      AuditEntryAction = "portfolio.create"
+	AuditEntryActionPortfolioDelete      AuditEntryAction = "portfolio.delete"
+	AuditEntryActionPortfolioTransfer    AuditEntryAction = "portfolio.transfer"
+	AuditEntryActionPortfolioUpdate      AuditEntryAction = "portfolio.update"
+	AuditEntryActionPortfolioUpgrade     AuditEntryAction = "portfolio.upgrade"
+	AuditEntryActionRunTrigger           AuditEntryAction = "run.trigger"
+)
+
+// Valid indicates whether the value is a known member of the AuditEntryAction enum.
+func (e AuditEntryAction) Valid() bool {
+	switch e {
+	case AuditEntryActionAdmin:
+		return true
+	case AuditEntryActionAlertCreate:
+		return true
+	case AuditEntryActionAlertDelete:
+		return true
+	case AuditEntryActionAlertUnsubscribe:
+		return true
+	case AuditEntryActionAlertUpdate:
+		return true
+	case AuditEntryActionPortfolioAutoUpgrade:
+		return true
+	case AuditEntryActionPortfolioCreate:
+		return true
+	case AuditEntryActionPortfolioDelete:
+		return true
+	case AuditEntryActionPortfolioTransfer:
+		return true
+	case AuditEntryActionPortfolioUpdate:
+		return true
+	case AuditEntryActionPortfolioUpgrade:
+		return true
+	case AuditEntryActionRunTrigger:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for DigestFrequency.
+const (
+	DigestFrequencyDaily   DigestFrequency = "daily"
+	DigestFrequencyMonthly DigestFrequency = "monthly"
+	DigestFrequencyWeekly  DigestFrequency = "weekly"
+)
+
+// Valid indicates whether the value is a known member of the DigestFrequency enum.
+func (e DigestFrequency) Valid() bool {
+	switch e {
+	case DigestFrequencyDaily:
+		return true
+	case DigestFrequencyMonthly:
+		return true
+	case DigestFrequencyWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for DigestRequestFrequency.
+const (
+	DigestRequestFrequencyDaily   DigestRequestFrequency = "daily"
+	DigestRequestFrequencyMonthly DigestRequestFrequency = "monthly"
+	DigestRequestFrequencyWeekly  DigestRequestFrequency = "weekly"
+)
+
+// Valid indicates whether the value is a known member of the DigestRequestFrequency enum.
+func (e DigestRequestFrequency) Valid() bool {
+	switch e {
+	case DigestRequestFrequencyDaily:
+		return true
+	case DigestRequestFrequencyMonthly:
+		return true
+	case DigestRequestFrequencyWeekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for FillType.
+const (
+	FillTypeBuy  FillType = "buy"
+	FillTypeSell FillType = "sell"
+)
+
+// Valid indicates whether the value is a known member of the FillType enum.
+func (e FillType) Valid() bool {
+	switch e {
+	case FillTypeBuy:
+		return true
+	case FillTypeSell:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for FillCreateRequestType.
+const (
+	FillCreateRequestTypeBuy  FillCreateRequestType = "buy"
+	FillCreateRequestTypeSell FillCreateRequestType = "sell"
+)
+
+// Valid indicates whether the value is a known member of the FillCreateRequestType enum.
+func (e FillCreateRequestType) Valid() bool {
+	switch e {
+	case FillCreateRequestTypeBuy:
+		return true
+	case FillCreateRequestTypeSell:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for HoldingsImpactPeriodPeriod.
 const (
 	HoldingsImpactPeriodPeriodInception HoldingsImpactPeriodPeriod = "inception"
@@ -82,6 +445,48 @@ func (e MetricFormat) Valid() bool {
 	}
 }
 
+// Defines values for MissedTradeType.
+const (
+	MissedTradeTypeBuy  MissedTradeType = "buy"
+	MissedTradeTypeSell MissedTradeType = "sell"
+)
+
+// Valid indicates whether the value is a known member of the MissedTradeType enum.
+func (e MissedTradeType) Valid() bool {
+	switch e {
+	case MissedTradeTypeBuy:
+		return true
+	case MissedTradeTypeSell:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for NotificationKind.
+const (
+	AlertTriggered    NotificationKind = "alert.triggered"
+	PortfolioUpgraded NotificationKind = "portfolio.upgraded"
+	RunFailed         NotificationKind = "run.failed"
+	RunSucceeded      NotificationKind = "run.succeeded"
+)
+
+// Valid indicates whether the value is a known member of the NotificationKind enum.
+func (e NotificationKind) Valid() bool {
+	switch e {
+	case AlertTriggered:
+		return true
+	case PortfolioUpgraded:
+		return true
+	case RunFailed:
+		return true
+	case RunSucceeded:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for PortfolioPerformanceResolution.
 const (
 	PortfolioPerformanceResolutionDaily   PortfolioPerformanceResolution = "daily"
@@ -214,6 +619,90 @@ func (e StrategyInstallState) Valid() bool {
 	}
 }
 
+// Defines values for StrategyValidationIssueStage.
+const (
+	StrategyValidationIssueStageBacktest StrategyValidationIssueStage = "backtest"
+	StrategyValidationIssueStageBuild    StrategyValidationIssueStage = "build"
+	StrategyValidationIssueStageDescribe StrategyValidationIssueStage = "describe"
+	StrategyValidationIssueStageSnapshot StrategyValidationIssueStage = "snapshot"
+)
+
+// Valid indicates whether the value is a known member of the StrategyValidationIssueStage enum.
+func (e StrategyValidationIssueStage) Valid() bool {
+	switch e {
+	case StrategyValidationIssueStageBacktest:
+		return true
+	case StrategyValidationIssueStageBuild:
+		return true
+	case StrategyValidationIssueStageDescribe:
+		return true
+	case StrategyValidationIssueStageSnapshot:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for StrategyValidationReportSkipped.
+const (
+	StrategyValidationReportSkippedBacktest StrategyValidationReportSkipped = "backtest"
+	StrategyValidationReportSkippedDescribe StrategyValidationReportSkipped = "describe"
+	StrategyValidationReportSkippedSnapshot StrategyValidationReportSkipped = "snapshot"
+)
+
+// Valid indicates whether the value is a known member of the StrategyValidationReportSkipped enum.
+func (e StrategyValidationReportSkipped) Valid() bool {
+	switch e {
+	case StrategyValidationReportSkippedBacktest:
+		return true
+	case StrategyValidationReportSkippedDescribe:
+		return true
+	case StrategyValidationReportSkippedSnapshot:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for TrackingRowBasis.
+const (
+	Backtest   TrackingRowBasis = "backtest"
+	Prediction TrackingRowBasis = "prediction"
+	Unmatched  TrackingRowBasis = "unmatched"
+)
+
+// Valid indicates whether the value is a known member of the TrackingRowBasis enum.
+func (e TrackingRowBasis) Valid() bool {
+	switch e {
+	case Backtest:
+		return true
+	case Prediction:
+		return true
+	case Unmatched:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for TradeChecklistItemType.
+const (
+	TradeChecklistItemTypeBuy  TradeChecklistItemType = "buy"
+	TradeChecklistItemTypeSell TradeChecklistItemType = "sell"
+)
+
+// Valid indicates whether the value is a known member of the TradeChecklistItemType enum.
+func (e TradeChecklistItemType) Valid() bool {
+	switch e {
+	case TradeChecklistItemTypeBuy:
+		return true
+	case TradeChecklistItemTypeSell:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for TransactionType.
 const (
 	Buy        TransactionType = "buy"
@@ -253,36 +742,105 @@ func (e TransactionType) Valid() bool {
 	}
 }
 
+// Defines values for WorkspaceRole.
+const (
+	Editor WorkspaceRole = "editor"
+	Owner  WorkspaceRole = "owner"
+	Viewer WorkspaceRole = "viewer"
+)
+
+// Valid indicates whether the value is a known member of the WorkspaceRole enum.
+func (e WorkspaceRole) Valid() bool {
+	switch e {
+	case Editor:
+		return true
+	case Owner:
+		return true
+	case Viewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ListPortfoliosParamsSort.
+const (
+	ListPortfoliosParamsSortCagrSinceInception      ListPortfoliosParamsSort = "cagrSinceInception"
+	ListPortfoliosParamsSortCreatedAt               ListPortfoliosParamsSort = "createdAt"
+	ListPortfoliosParamsSortCurrentValue            ListPortfoliosParamsSort = "currentValue"
+	ListPortfoliosParamsSortMaxDrawDown             ListPortfoliosParamsSort = "maxDrawDown"
+	ListPortfoliosParamsSortMinusCagrSinceInception ListPortfoliosParamsSort = "-cagrSinceInception"
+	ListPortfoliosParamsSortMinusCreatedAt          ListPortfoliosParamsSort = "-createdAt"
+	ListPortfoliosParamsSortMinusCurrentValue       ListPortfoliosParamsSort = "-currentValue"
+	ListPortfoliosParamsSortMinusMaxDrawDown        ListPortfoliosParamsSort = "-maxDrawDown"
+	ListPortfoliosParamsSortMinusSharpe             ListPortfoliosParamsSort = "-sharpe"
+	ListPortfoliosParamsSortMinusYtdReturn          ListPortfoliosParamsSort = "-ytdReturn"
+	ListPortfoliosParamsSortSharpe                  ListPortfoliosParamsSort = "sharpe"
+	ListPortfoliosParamsSortYtdReturn               ListPortfoliosParamsSort = "ytdReturn"
+)
+
+// Valid indicates whether the value is a known member of the ListPortfoliosParamsSort enum.
+func (e ListPortfoliosParamsSort) Valid() bool {
+	switch e {
+	case ListPortfoliosParamsSortCagrSinceInception:
+		return true
+	case ListPortfoliosParamsSortCreatedAt:
+		return true
+	case ListPortfoliosParamsSortCurrentValue:
+		return true
+	case ListPortfoliosParamsSortMaxDrawDown:
+		return true
+	case ListPortfoliosParamsSortMinusCagrSinceInception:
+		return true
+	case ListPortfoliosParamsSortMinusCreatedAt:
+		return true
+	case ListPortfoliosParamsSortMinusCurrentValue:
+		return true
+	case ListPortfoliosParamsSortMinusMaxDrawDown:
+		return true
+	case ListPortfoliosParamsSortMinusSharpe:
+		return true
+	case ListPortfoliosParamsSortMinusYtdReturn:
+		return true
+	case ListPortfoliosParamsSortSharpe:
+		return true
+	case ListPortfoliosParamsSortYtdReturn:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for GetPortfolioMetricsParamsWindow.
 const (
-	GetPortfolioMetricsParamsWindowMtd            GetPortfolioMetricsParamsWindow = "mtd"
-	GetPortfolioMetricsParamsWindowN10yr          GetPortfolioMetricsParamsWindow = "10yr"
-	GetPortfolioMetricsParamsWindowN1yr           GetPortfolioMetricsParamsWindow = "1yr"
-	GetPortfolioMetricsParamsWindowN3yr           GetPortfolioMetricsParamsWindow = "3yr"
-	GetPortfolioMetricsParamsWindowN5yr           GetPortfolioMetricsParamsWindow = "5yr"
-	GetPortfolioMetricsParamsWindowSinceInception GetPortfolioMetricsParamsWindow = "since_inception"
-	GetPortfolioMetricsParamsWindowWtd            GetPortfolioMetricsParamsWindow = "wtd"
-	GetPortfolioMetricsParamsWindowYtd            GetPortfolioMetricsParamsWindow = "ytd"
+	Mtd            GetPortfolioMetricsParamsWindow = "mtd"
+	N10yr          GetPortfolioMetricsParamsWindow = "10yr"
+	N1yr           GetPortfolioMetricsParamsWindow = "1yr"
+	N3yr           GetPortfolioMetricsParamsWindow = "3yr"
+	N5yr           GetPortfolioMetricsParamsWindow = "5yr"
+	SinceInception GetPortfolioMetricsParamsWindow = "since_inception"
+	Wtd            GetPortfolioMetricsParamsWindow = "wtd"
+	Ytd            GetPortfolioMetricsParamsWindow = "ytd"
 )
 
 // Valid indicates whether the value is a known member of the GetPortfolioMetricsParamsWindow enum.
 func (e GetPortfolioMetricsParamsWindow) Valid() bool {
 	switch e {
-	case GetPortfolioMetricsParamsWindowMtd:
+	case Mtd:
 		return true
-	case GetPortfolioMetricsParamsWindowN10yr:
+	case N10yr:
 		return true
-	case GetPortfolioMetricsParamsWindowN1yr:
+	case N1yr:
 		return true
-	case GetPortfolioMetricsParamsWindowN3yr:
+	case N3yr:
 		return true
-	case GetPortfolioMetricsParamsWindowN5yr:
+	case N5yr:
 		return true
-	case GetPortfolioMetricsParamsWindowSinceInception:
+	case SinceInception:
 		return true
-	case GetPortfolioMetricsParamsWindowWtd:
+	case Wtd:
 		return true
-	case GetPortfolioMetricsParamsWindowYtd:
+	case Ytd:
 		return true
 	default:
 		return false
@@ -291,341 +849,655 @@ func (e GetPortfolioMetricsParamsWindow) Valid() bool {
 
 // Defines values for GetPortfolioMetricsParamsMetric.
 const (
-	ActiveReturn               GetPortfolioMetricsParamsMetric = "ActiveReturn"
-	AfterTaxCAGR               GetPortfolioMetricsParamsMetric = "AfterTaxCAGR"
-	AfterTaxTWRR               GetPortfolioMetricsParamsMetric = "AfterTaxTWRR"
-	Alpha                      GetPortfolioMetricsParamsMetric = "Alpha"
-	AverageHoldingPeriod       GetPortfolioMetricsParamsMetric = "AverageHoldingPeriod"
-	AverageLoss                GetPortfolioMetricsParamsMetric = "AverageLoss"
-	AverageMAE                 GetPortfolioMetricsParamsMetric = "AverageMAE"
-	AverageMFE                 GetPortfolioMetricsParamsMetric = "AverageMFE"
-	AverageWin                 GetPortfolioMetricsParamsMetric = "AverageWin"
-	AvgDrawdown                GetPortfolioMetricsParamsMetric = "AvgDrawdown"
-	AvgDrawdownDays            GetPortfolioMetricsParamsMetric = "AvgDrawdownDays"
-	AvgUlcerIndex              GetPortfolioMetricsParamsMetric = "AvgUlcerIndex"
-	BenchmarkAfterTaxCAGR      GetPortfolioMetricsParamsMetric = "BenchmarkAfterTaxCAGR"
-	BenchmarkAfterTaxTWRR      GetPortfolioMetricsParamsMetric = "BenchmarkAfterTaxTWRR"
-	BenchmarkAvgUlcerIndex     GetPortfolioMetricsParamsMetric = "BenchmarkAvgUlcerIndex"
-	BenchmarkCAGR              GetPortfolioMetricsParamsMetric = "BenchmarkCAGR"
-	BenchmarkCalmar            GetPortfolioMetricsParamsMetric = "BenchmarkCalmar"
-	BenchmarkDownsideDeviation GetPortfolioMetricsParamsMetric = "BenchmarkDownsideDeviation"
-	BenchmarkExcessKurtosis    GetPortfolioMetricsParamsMetric = "BenchmarkExcessKurtosis"
-	BenchmarkMWRR              GetPortfolioMetricsParamsMetric = "BenchmarkMWRR"
-	BenchmarkMaxDrawdown       GetPortfolioMetricsParamsMetric = "BenchmarkMaxDrawdown"
-	BenchmarkMedianUlcerIndex  GetPortfolioMetricsParamsMetric = "BenchmarkMedianUlcerIndex"
-	BenchmarkP90UlcerIndex     GetPortfolioMetricsParamsMetric = "BenchmarkP90UlcerIndex"
-	BenchmarkSharpe            GetPortfolioMetricsParamsMetric = "BenchmarkSharpe"
-	BenchmarkSkewness          GetPortfolioMetricsParamsMetric = "BenchmarkSkewness"
-	BenchmarkSortino           GetPortfolioMetricsParamsMetric = "BenchmarkSortino"
-	BenchmarkStdDev            GetPortfolioMetricsParamsMetric = "BenchmarkStdDev"
-	BenchmarkTWRR              GetPortfolioMetricsParamsMetric = "BenchmarkTWRR"
-	BenchmarkUlcerIndex        GetPortfolioMetricsParamsMetric = "BenchmarkUlcerIndex"
-	BenchmarkValueAtRisk       GetPortfolioMetricsParamsMetric = "BenchmarkValueAtRisk"
-	Beta                       GetPortfolioMetricsParamsMetric = "Beta"
-	CAGR                       GetPortfolioMetricsParamsMetric = "CAGR"
-	CVaR                       GetPortfolioMetricsParamsMetric = "CVaR"
-	Calmar                     GetPortfolioMetricsParamsMetric = "Calmar"
-	ConsecutiveLosses          GetPortfolioMetricsParamsMetric = "ConsecutiveLosses"
-	ConsecutiveWins            GetPortfolioMetricsParamsMetric = "ConsecutiveWins"
-	DownsideCaptureRatio       GetPortfolioMetricsParamsMetric = "DownsideCaptureRatio"
-	DownsideDeviation          GetPortfolioMetricsParamsMetric = "DownsideDeviation"
-	DynamicWithdrawalRate      GetPortfolioMetricsParamsMetric = "DynamicWithdrawalRate"
-	EdgeRatio                  GetPortfolioMetricsParamsMetric = "EdgeRatio"
-	ExcessKurtosis             GetPortfolioMetricsParamsMetric = "ExcessKurtosis"
-	Exposure                   GetPortfolioMetricsParamsMetric = "Exposure"
-	GainLossRatio              GetPortfolioMetricsParamsMetric = "GainLossRatio"
-	GainToPainRatio            GetPortfolioMetricsParamsMetric = "GainToPainRatio"
-	InformationRatio           GetPortfolioMetricsParamsMetric = "InformationRatio"
-	KRatio                     GetPortfolioMetricsParamsMetric = "KRatio"
-	KellerRatio                GetPortfolioMetricsParamsMetric = "KellerRatio"
-	KellyCriterion             GetPortfolioMetricsParamsMetric = "KellyCriterion"
-	LTCG                       GetPortfolioMetricsParamsMetric = "LTCG"
-	LongProfitFactor           GetPortfolioMetricsParamsMetric = "LongProfitFactor"
-	LongWinRate                GetPortfolioMetricsParamsMetric = "LongWinRate"
-	MWRR                       GetPortfolioMetricsParamsMetric = "MWRR"
-	MaxDrawdown                GetPortfolioMetricsParamsMetric = "MaxDrawdown"
-	MedianMAE                  GetPortfolioMetricsParamsMetric = "MedianMAE"
-	MedianMFE                  GetPortfolioMetricsParamsMetric = "MedianMFE"
-	MedianUlcerIndex           GetPortfolioMetricsParamsMetric = "MedianUlcerIndex"
-	NPositivePeriods           GetPortfolioMetricsParamsMetric = "NPositivePeriods"
-	NonQualifiedIncome         GetPortfolioMetricsParamsMetric = "NonQualifiedIncome"
-	OmegaRatio                 GetPortfolioMetricsParamsMetric = "OmegaRatio"
-	P90UlcerIndex              GetPortfolioMetricsParamsMetric = "P90UlcerIndex"
-	PerpetualWithdrawalRate    GetPortfolioMetricsParamsMetric = "PerpetualWithdrawalRate"
-	ProbabilisticSharpe        GetPortfolioMetricsParamsMetric = "ProbabilisticSharpe"
-	ProfitFactor               GetPortfolioMetricsParamsMetric = "ProfitFactor"
-	QualifiedDividends         GetPortfolioMetricsParamsMetric = "QualifiedDividends"
-	RSquared                   GetPortfolioMetricsParamsMetric = "RSquared"
-	RecoveryFactor             GetPortfolioMetricsParamsMetric = "RecoveryFactor"
-	STCG                       GetPortfolioMetricsParamsMetric = "STCG"
-	SafeWithdrawalRate         GetPortfolioMetricsParamsMetric = "SafeWithdrawalRate"
-	Sharpe                     GetPortfolioMetricsParamsMetric = "Sharpe"
-	ShortProfitFactor          GetPortfolioMetricsParamsMetric = "ShortProfitFactor"
-	ShortWinRate               GetPortfolioMetricsParamsMetric = "ShortWinRate"
-	Skewness                   GetPortfolioMetricsParamsMetric = "Skewness"
-	SmartSharpe                GetPortfolioMetricsParamsMetric = "SmartSharpe"
-	SmartSortino               GetPortfolioMetricsParamsMetric = "SmartSortino"
-	Sortino                    GetPortfolioMetricsParamsMetric = "Sortino"
-	StdDev                     GetPortfolioMetricsParamsMetric = "StdDev"
-	TWRR                       GetPortfolioMetricsParamsMetric = "TWRR"
-	TailRatio                  GetPortfolioMetricsParamsMetric = "TailRatio"
-	TaxCostRatio               GetPortfolioMetricsParamsMetric = "TaxCostRatio"
-	TaxDrag                    GetPortfolioMetricsParamsMetric = "TaxDrag"
-	TrackingError              GetPortfolioMetricsParamsMetric = "TrackingError"
-	TradeCaptureRatio          GetPortfolioMetricsParamsMetric = "TradeCaptureRatio"
-	TradeGainLossRatio         GetPortfolioMetricsParamsMetric = "TradeGainLossRatio"
-	Treynor                    GetPortfolioMetricsParamsMetric = "Treynor"
-	Turnover                   GetPortfolioMetricsParamsMetric = "Turnover"
-	UlcerIndex                 GetPortfolioMetricsParamsMetric = "UlcerIndex"
-	UnrealizedLTCG             GetPortfolioMetricsParamsMetric = "UnrealizedLTCG"
-	UnrealizedSTCG             GetPortfolioMetricsParamsMetric = "UnrealizedSTCG"
-	UpsideCaptureRatio         GetPortfolioMetricsParamsMetric = "UpsideCaptureRatio"
-	ValueAtRisk                GetPortfolioMetricsParamsMetric = "ValueAtRisk"
-	WinRate                    GetPortfolioMetricsParamsMetric = "WinRate"
+	GetPortfolioMetricsParamsMetricActiveReturn               GetPortfolioMetricsParamsMetric = "ActiveReturn"
+	GetPortfolioMetricsParamsMetricAfterTaxCAGR               GetPortfolioMetricsParamsMetric = "AfterTaxCAGR"
+	GetPortfolioMetricsParamsMetricAfterTaxTWRR               GetPortfolioMetricsParamsMetric = "AfterTaxTWRR"
+	GetPortfolioMetricsParamsMetricAlpha                      GetPortfolioMetricsParamsMetric = "Alpha"
+	GetPortfolioMetricsParamsMetricAverageHoldingPeriod       GetPortfolioMetricsParamsMetric = "AverageHoldingPeriod"
+	GetPortfolioMetricsParamsMetricAverageLoss                GetPortfolioMetricsParamsMetric = "AverageLoss"
+	GetPortfolioMetricsParamsMetricAverageMAE                 GetPortfolioMetricsParamsMetric = "AverageMAE"
+	GetPortfolioMetricsParamsMetricAverageMFE                 GetPortfolioMetricsParamsMetric = "AverageMFE"
+	GetPortfolioMetricsParamsMetricAverageWin                 GetPortfolioMetricsParamsMetric = "AverageWin"
+	GetPortfolioMetricsParamsMetricAvgDrawdown                GetPortfolioMetricsParamsMetric = "AvgDrawdown"
+	GetPortfolioMetricsParamsMetricAvgDrawdownDays            GetPortfolioMetricsParamsMetric = "AvgDrawdownDays"
+	GetPortfolioMetricsParamsMetricAvgUlcerIndex              GetPortfolioMetricsParamsMetric = "AvgUlcerIndex"
+	GetPortfolioMetricsParamsMetricBenchmarkAfterTaxCAGR      GetPortfolioMetricsParamsMetric = "BenchmarkAfterTaxCAGR"
+	GetPortfolioMetricsParamsMetricBenchmarkAfterTaxTWRR      GetPortfolioMetricsParamsMetric = "BenchmarkAfterTaxTWRR"
+	GetPortfolioMetricsParamsMetricBenchmarkAvgUlcerIndex     GetPortfolioMetricsParamsMetric = "BenchmarkAvgUlcerIndex"
+	GetPortfolioMetricsParamsMetricBenchmarkCAGR              GetPortfolioMetricsParamsMetric = "BenchmarkCAGR"
+	GetPortfolioMetricsParamsMetricBenchmarkCalmar            GetPortfolioMetricsParamsMetric = "BenchmarkCalmar"
+	GetPortfolioMetricsParamsMetricBenchmarkDownsideDeviation GetPortfolioMetricsParamsMetric = "BenchmarkDownsideDeviation"
+	GetPortfolioMetricsParamsMetricBenchmarkExcessKurtosis    GetPortfolioMetricsParamsMetric = "BenchmarkExcessKurtosis"
+	GetPortfolioMetricsParamsMetricBenchmarkMWRR              GetPortfolioMetricsParamsMetric = "BenchmarkMWRR"
+	GetPortfolioMetricsParamsMetricBenchmarkMaxDrawdown       GetPortfolioMetricsParamsMetric = "BenchmarkMaxDrawdown"
+	GetPortfolioMetricsParamsMetricBenchmarkMedianUlcerIndex  GetPortfolioMetricsParamsMetric = "BenchmarkMedianUlcerIndex"
+	GetPortfolioMetricsParamsMetricBenchmarkP90UlcerIndex     GetPortfolioMetricsParamsMetric = "BenchmarkP90UlcerIndex"
+	GetPortfolioMetricsParamsMetricBenchmarkSharpe            GetPortfolioMetricsParamsMetric = "BenchmarkSharpe"
+	GetPortfolioMetricsParamsMetricBenchmarkSkewness          GetPortfolioMetricsParamsMetric = "BenchmarkSkewness"
+	GetPortfolioMetricsParamsMetricBenchmarkSortino           GetPortfolioMetricsParamsMetric = "BenchmarkSortino"
+	GetPortfolioMetricsParamsMetricBenchmarkStdDev            GetPortfolioMetricsParamsMetric = "BenchmarkStdDev"
+	GetPortfolioMetricsParamsMetricBenchmarkTWRR              GetPortfolioMetricsParamsMetric = "BenchmarkTWRR"
+	GetPortfolioMetricsParamsMetricBenchmarkUlcerIndex        GetPortfolioMetricsParamsMetric = "BenchmarkUlcerIndex"
+	GetPortfolioMetricsParamsMetricBenchmarkValueAtRisk       GetPortfolioMetricsParamsMetric = "BenchmarkValueAtRisk"
+	GetPortfolioMetricsParamsMetricBeta                       GetPortfolioMetricsParamsMetric = "Beta"
+	GetPortfolioMetricsParamsMetricCAGR                       GetPortfolioMetricsParamsMetric = "CAGR"
+	GetPortfolioMetricsParamsMetricCVaR                       GetPortfolioMetricsParamsMetric = "CVaR"
+	GetPortfolioMetricsParamsMetricCalmar                     GetPortfolioMetricsParamsMetric = "Calmar"
+	GetPortfolioMetricsParamsMetricConsecutiveLosses          GetPortfolioMetricsParamsMetric = "ConsecutiveLosses"
+	GetPortfolioMetricsParamsMetricConsecutiveWins            GetPortfolioMetricsParamsMetric = "ConsecutiveWins"
+	GetPortfolioMetricsParamsMetricDownsideCaptureRatio       GetPortfolioMetricsParamsMetric = "DownsideCaptureRatio"
+	GetPortfolioMetricsParamsMetricDownsideDeviation          GetPortfolioMetricsParamsMetric = "DownsideDeviation"
+	GetPortfolioMetricsParamsMetricDynamicWithdrawalRate      GetPortfolioMetricsParamsMetric = "DynamicWithdrawalRate"
+	GetPortfolioMetricsParamsMetricEdgeRatio                  GetPortfolioMetricsParamsMetric = "EdgeRatio"
+	GetPortfolioMetricsParamsMetricExcessKurtosis             GetPortfolioMetricsParamsMetric = "ExcessKurtosis"
+	GetPortfolioMetricsParamsMetricExposure                   GetPortfolioMetricsParamsMetric = "Exposure"
+	GetPortfolioMetricsParamsMetricGainLossRatio              GetPortfolioMetricsParamsMetric = "GainLossRatio"
+	GetPortfolioMetricsParamsMetricGainToPainRatio            GetPortfolioMetricsParamsMetric = "GainToPainRatio"
+	GetPortfolioMetricsParamsMetricInformationRatio           GetPortfolioMetricsParamsMetric = "InformationRatio"
+	GetPortfolioMetricsParamsMetricKRatio                     GetPortfolioMetricsParamsMetric = "KRatio"
+	GetPortfolioMetricsParamsMetricKellerRatio                GetPortfolioMetricsParamsMetric = "KellerRatio"
+	GetPortfolioMetricsParamsMetricKellyCriterion             GetPortfolioMetricsParamsMetric = "KellyCriterion"
+	GetPortfolioMetricsParamsMetricLTCG                       GetPortfolioMetricsParamsMetric = "LTCG"
+	GetPortfolioMetricsParamsMetricLongProfitFactor           GetPortfolioMetricsParamsMetric = "LongProfitFactor"
+	GetPortfolioMetricsParamsMetricLongWinRate                GetPortfolioMetricsParamsMetric = "LongWinRate"
+	GetPortfolioMetricsParamsMetricMWRR                       GetPortfolioMetricsParamsMetric = "MWRR"
+	GetPortfolioMetricsParamsMetricMaxDrawdown                GetPortfolioMetricsParamsMetric = "MaxDrawdown"
+	GetPortfolioMetricsParamsMetricMedianMAE                  GetPortfolioMetricsParamsMetric = "MedianMAE"
+	GetPortfolioMetricsParamsMetricMedianMFE                  GetPortfolioMetricsParamsMetric = "MedianMFE"
+	GetPortfolioMetricsParamsMetricMedianUlcerIndex           GetPortfolioMetricsParamsMetric = "MedianUlcerIndex"
+	GetPortfolioMetricsParamsMetricNPositivePeriods           GetPortfolioMetricsParamsMetric = "NPositivePeriods"
+	GetPortfolioMetricsParamsMetricNonQualifiedIncome         GetPortfolioMetricsParamsMetric = "NonQualifiedIncome"
+	GetPortfolioMetricsParamsMetricOmegaRatio                 GetPortfolioMetricsParamsMetric = "OmegaRatio"
+	GetPortfolioMetricsParamsMetricP90UlcerIndex              GetPortfolioMetricsParamsMetric = "P90UlcerIndex"
+	GetPortfolioMetricsParamsMetricPerpetualWithdrawalRate    GetPortfolioMetricsParamsMetric = "PerpetualWithdrawalRate"
+	GetPortfolioMetricsParamsMetricProbabilisticSharpe        GetPortfolioMetricsParamsMetric = "ProbabilisticSharpe"
+	GetPortfolioMetricsParamsMetricProfitFactor               GetPortfolioMetricsParamsMetric = "ProfitFactor"
+	GetPortfolioMetricsParamsMetricQualifiedDividends         GetPortfolioMetricsParamsMetric = "QualifiedDividends"
+	GetPortfolioMetricsParamsMetricRSquared                   GetPortfolioMetricsParamsMetric = "RSquared"
+	GetPortfolioMetricsParamsMetricRecoveryFactor             GetPortfolioMetricsParamsMetric = "RecoveryFactor"
+	GetPortfolioMetricsParamsMetricSTCG                       GetPortfolioMetricsParamsMetric = "STCG"
+	GetPortfolioMetricsParamsMetricSafeWithdrawalRate         GetPortfolioMetricsParamsMetric = "SafeWithdrawalRate"
+	GetPortfolioMetricsParamsMetricSharpe                     GetPortfolioMetricsParamsMetric = "Sharpe"
+	GetPortfolioMetricsParamsMetricShortProfitFactor          GetPortfolioMetricsParamsMetric = "ShortProfitFactor"
+	GetPortfolioMetricsParamsMetricShortWinRate               GetPortfolioMetricsParamsMetric = "ShortWinRate"
+	GetPortfolioMetricsParamsMetricSkewness                   GetPortfolioMetricsParamsMetric = "Skewness"
+	GetPortfolioMetricsParamsMetricSmartSharpe                GetPortfolioMetricsParamsMetric = "SmartSharpe"
+	GetPortfolioMetricsParamsMetricSmartSortino               GetPortfolioMetricsParamsMetric = "SmartSortino"
+	GetPortfolioMetricsParamsMetricSortino                    GetPortfolioMetricsParamsMetric = "Sortino"
+	GetPortfolioMetricsParamsMetricStdDev                     GetPortfolioMetricsParamsMetric = "StdDev"
+	GetPortfolioMetricsParamsMetricTWRR                       GetPortfolioMetricsParamsMetric = "TWRR"
+	GetPortfolioMetricsParamsMetricTailRatio                  GetPortfolioMetricsParamsMetric = "TailRatio"
+	GetPortfolioMetricsParamsMetricTaxCostRatio               GetPortfolioMetricsParamsMetric = "TaxCostRatio"
+	GetPortfolioMetricsParamsMetricTaxDrag                    GetPortfolioMetricsParamsMetric = "TaxDrag"
+	GetPortfolioMetricsParamsMetricTrackingError              GetPortfolioMetricsParamsMetric = "TrackingError"
+	GetPortfolioMetricsParamsMetricTradeCaptureRatio          GetPortfolioMetricsParamsMetric = "TradeCaptureRatio"
+	GetPortfolioMetricsParamsMetricTradeGainLossRatio         GetPortfolioMetricsParamsMetric = "TradeGainLossRatio"
+	GetPortfolioMetricsParamsMetricTreynor                    GetPortfolioMetricsParamsMetric = "Treynor"
+	GetPortfolioMetricsParamsMetricTurnover                   GetPortfolioMetricsParamsMetric = "Turnover"
+	GetPortfolioMetricsParamsMetricUlcerIndex                 GetPortfolioMetricsParamsMetric = "UlcerIndex"
+	GetPortfolioMetricsParamsMetricUnrealizedLTCG             GetPortfolioMetricsParamsMetric = "UnrealizedLTCG"
+	GetPortfolioMetricsParamsMetricUnrealizedSTCG             GetPortfolioMetricsParamsMetric = "UnrealizedSTCG"
+	GetPortfolioMetricsParamsMetricUpsideCaptureRatio         GetPortfolioMetricsParamsMetric = "UpsideCaptureRatio"
+	GetPortfolioMetricsParamsMetricValueAtRisk                GetPortfolioMetricsParamsMetric = "ValueAtRisk"
+	GetPortfolioMetricsParamsMetricWinRate                    GetPortfolioMetricsParamsMetric = "WinRate"
 )
 
 // Valid indicates whether the value is a known member of the GetPortfolioMetricsParamsMetric enum.
 func (e GetPortfolioMetricsParamsMetric) Valid() bool {
 	switch e {
-	case ActiveReturn:
+	case GetPortfolioMetricsParamsMetricActiveReturn:
 		return true
-	case AfterTaxCAGR:
+	case GetPortfolioMetricsParamsMetricAfterTaxCAGR:
 		return true
-	case AfterTaxTWRR:
+	case GetPortfolioMetricsParamsMetricAfterTaxTWRR:
 		return true
-	case Alpha:
+	case GetPortfolioMetricsParamsMetricAlpha:
 		return true
-	case AverageHoldingPeriod:
+	case GetPortfolioMetricsParamsMetricAverageHoldingPeriod:
 		return true
-	case AverageLoss:
+	case GetPortfolioMetricsParamsMetricAverageLoss:
 		return true
-	case AverageMAE:
+	case GetPortfolioMetricsParamsMetricAverageMAE:
 		return true
-	case AverageMFE:
+	case GetPortfolioMetricsParamsMetricAverageMFE:
 		return true
-	case AverageWin:
+	case GetPortfolioMetricsParamsMetricAverageWin:
 		return true
-	case AvgDrawdown:
+	case GetPortfolioMetricsParamsMetricAvgDrawdown:
 		return true
-	case AvgDrawdownDays:
+	case GetPortfolioMetricsParamsMetricAvgDrawdownDays:
 		return true
-	case AvgUlcerIndex:
+	case GetPortfolioMetricsParamsMetricAvgUlcerIndex:
 		return true
-	case BenchmarkAfterTaxCAGR:
+	case GetPortfolioMetricsParamsMetricBenchmarkAfterTaxCAGR:
 		return true
-	case BenchmarkAfterTaxTWRR:
+	case GetPortfolioMetricsParamsMetricBenchmarkAfterTaxTWRR:
 		return true
-	case BenchmarkAvgUlcerIndex:
+	case GetPortfolioMetricsParamsMetricBenchmarkAvgUlcerIndex:
 		return true
-	case BenchmarkCAGR:
+	case GetPortfolioMetricsParamsMetricBenchmarkCAGR:
 		return true
-	case BenchmarkCalmar:
+	case GetPortfolioMetricsParamsMetricBenchmarkCalmar:
 		return true
-	case BenchmarkDownsideDeviation:
+	case GetPortfolioMetricsParamsMetricBenchmarkDownsideDeviation:
 		return true
-	case BenchmarkExcessKurtosis:
+	case GetPortfolioMetricsParamsMetricBenchmarkExcessKurtosis:
 		return true
-	case BenchmarkMWRR:
+	case GetPortfolioMetricsParamsMetricBenchmarkMWRR:
 		return true
-	case BenchmarkMaxDrawdown:
+	case GetPortfolioMetricsParamsMetricBenchmarkMaxDrawdown:
 		return true
-	case BenchmarkMedianUlcerIndex:
+	case GetPortfolioMetricsParamsMetricBenchmarkMedianUlcerIndex:
 		return true
-	case BenchmarkP90UlcerIndex:
+	case GetPortfolioMetricsParamsMetricBenchmarkP90UlcerIndex:
 		return true
-	case BenchmarkSharpe:
+	case GetPortfolioMetricsParamsMetricBenchmarkSharpe:
 		return true
-	case BenchmarkSkewness:
+	case GetPortfolioMetricsParamsMetricBenchmarkSkewness:
 		return true
-	case BenchmarkSortino:
+	case GetPortfolioMetricsParamsMetricBenchmarkSortino:
 		return true
-	case BenchmarkStdDev:
+	case GetPortfolioMetricsParamsMetricBenchmarkStdDev:
 		return true
-	case BenchmarkTWRR:
+	case GetPortfolioMetricsParamsMetricBenchmarkTWRR:
 		return true
-	case BenchmarkUlcerIndex:
+	case GetPortfolioMetricsParamsMetricBenchmarkUlcerIndex:
 		return true
-	case BenchmarkValueAtRisk:
+	case GetPortfolioMetricsParamsMetricBenchmarkValueAtRisk:
 		return true
-	case Beta:
+	case GetPortfolioMetricsParamsMetricBeta:
 		return true
-	case CAGR:
+	case GetPortfolioMetricsParamsMetricCAGR:
 		return true
-	case CVaR:
+	case GetPortfolioMetricsParamsMetricCVaR:
 		return true
-	case Calmar:
+	case GetPortfolioMetricsParamsMetricCalmar:
 		return true
-	case ConsecutiveLosses:
+	case GetPortfolioMetricsParamsMetricConsecutiveLosses:
 		return true
-	case ConsecutiveWins:
+	case GetPortfolioMetricsParamsMetricConsecutiveWins:
 		return true
-	case DownsideCaptureRatio:
+	case GetPortfolioMetricsParamsMetricDownsideCaptureRatio:
 		return true
-	case DownsideDeviation:
+	case GetPortfolioMetricsParamsMetricDownsideDeviation:
 		return true
-	case DynamicWithdrawalRate:
+	case GetPortfolioMetricsParamsMetricDynamicWithdrawalRate:
 		return true
-	case EdgeRatio:
+	case GetPortfolioMetricsParamsMetricEdgeRatio:
 		return true
-	case ExcessKurtosis:
+	case GetPortfolioMetricsParamsMetricExcessKurtosis:
 		return true
-	case Exposure:
+	case GetPortfolioMetricsParamsMetricExposure:
 		return true
-	case GainLossRatio:
+	case GetPortfolioMetricsParamsMetricGainLossRatio:
 		return true
-	case GainToPainRatio:
+	case GetPortfolioMetricsParamsMetricGainToPainRatio:
 		return true
-	case InformationRatio:
+	case GetPortfolioMetricsParamsMetricInformationRatio:
 		return true
-	case KRatio:
+	case GetPortfolioMetricsParamsMetricKRatio:
 		return true
-	case KellerRatio:
+	case GetPortfolioMetricsParamsMetricKellerRatio:
 		return true
-	case KellyCriterion:
+	case GetPortfolioMetricsParamsMetricKellyCriterion:
 		return true
-	case LTCG:
+	case GetPortfolioMetricsParamsMetricLTCG:
 		return true
-	case LongProfitFactor:
+	case GetPortfolioMetricsParamsMetricLongProfitFactor:
 		return true
-	case LongWinRate:
+	case GetPortfolioMetricsParamsMetricLongWinRate:
 		return true
-	case MWRR:
+	case GetPortfolioMetricsParamsMetricMWRR:
 		return true
-	case MaxDrawdown:
+	case GetPortfolioMetricsParamsMetricMaxDrawdown:
 		return true
-	case MedianMAE:
+	case GetPortfolioMetricsParamsMetricMedianMAE:
 		return true
-	case MedianMFE:
+	case GetPortfolioMetricsParamsMetricMedianMFE:
 		return true
-	case MedianUlcerIndex:
+	case GetPortfolioMetricsParamsMetricMedianUlcerIndex:
 		return true
-	case NPositivePeriods:
+	case GetPortfolioMetricsParamsMetricNPositivePeriods:
 		return true
-	case NonQualifiedIncome:
+	case GetPortfolioMetricsParamsMetricNonQualifiedIncome:
 		return true
-	case OmegaRatio:
+	case GetPortfolioMetricsParamsMetricOmegaRatio:
 		return true
-	case P90UlcerIndex:
+	case GetPortfolioMetricsParamsMetricP90UlcerIndex:
 		return true
-	case PerpetualWithdrawalRate:
+	case GetPortfolioMetricsParamsMetricPerpetualWithdrawalRate:
 		return true
-	case ProbabilisticSharpe:
+	case GetPortfolioMetricsParamsMetricProbabilisticSharpe:
 		return true
-	case ProfitFactor:
+	case GetPortfolioMetricsParamsMetricProfitFactor:
 		return true
-	case QualifiedDividends:
+	case GetPortfolioMetricsParamsMetricQualifiedDividends:
 		return true
-	case RSquared:
+	case GetPortfolioMetricsParamsMetricRSquared:
 		return true
-	case RecoveryFactor:
+	case GetPortfolioMetricsParamsMetricRecoveryFactor:
 		return true
-	case STCG:
+	case GetPortfolioMetricsParamsMetricSTCG:
 		return true
-	case SafeWithdrawalRate:
+	case GetPortfolioMetricsParamsMetricSafeWithdrawalRate:
 		return true
-	case Sharpe:
+	case GetPortfolioMetricsParamsMetricSharpe:
 		return true
-	case ShortProfitFactor:
+	case GetPortfolioMetricsParamsMetricShortProfitFactor:
 		return true
-	case ShortWinRate:
+	case GetPortfolioMetricsParamsMetricShortWinRate:
 		return true
-	case Skewness:
+	case GetPortfolioMetricsParamsMetricSkewness:
 		return true
-	case SmartSharpe:
+	case GetPortfolioMetricsParamsMetricSmartSharpe:
 		return true
-	case SmartSortino:
+	case GetPortfolioMetricsParamsMetricSmartSortino:
 		return true
-	case Sortino:
+	case GetPortfolioMetricsParamsMetricSortino:
 		return true
-	case StdDev:
+	case GetPortfolioMetricsParamsMetricStdDev:
 		return true
-	case TWRR:
+	case GetPortfolioMetricsParamsMetricTWRR:
 		return true
-	case TailRatio:
+	case GetPortfolioMetricsParamsMetricTailRatio:
 		return true
-	case TaxCostRatio:
+	case GetPortfolioMetricsParamsMetricTaxCostRatio:
 		return true
-	case TaxDrag:
+	case GetPortfolioMetricsParamsMetricTaxDrag:
 		return true
-	case TrackingError:
+	case GetPortfolioMetricsParamsMetricTrackingError:
 		return true
-	case TradeCaptureRatio:
+	case GetPortfolioMetricsParamsMetricTradeCaptureRatio:
 		return true
-	case TradeGainLossRatio:
+	case GetPortfolioMetricsParamsMetricTradeGainLossRatio:
 		return true
-	case Treynor:
+	case GetPortfolioMetricsParamsMetricTreynor:
 		return true
-	case Turnover:
+	case GetPortfolioMetricsParamsMetricTurnover:
 		return true
-	case UlcerIndex:
+	case GetPortfolioMetricsParamsMetricUlcerIndex:
+		return true
+	case GetPortfolioMetricsParamsMetricUnrealizedLTCG:
+		return true
+	case GetPortfolioMetricsParamsMetricUnrealizedSTCG:
+		return true
+	case GetPortfolioMetricsParamsMetricUpsideCaptureRatio:
+		return true
+	case GetPortfolioMetricsParamsMetricValueAtRisk:
+		return true
+	case GetPortfolioMetricsParamsMetricWinRate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for GetPortfolioPerformanceParamsResolution.
+const (
+	Daily   GetPortfolioPerformanceParamsResolution = "daily"
+	Monthly GetPortfolioPerformanceParamsResolution = "monthly"
+	Weekly  GetPortfolioPerformanceParamsResolution = "weekly"
+)
+
+// Valid indicates whether the value is a known member of the GetPortfolioPerformanceParamsResolution enum.
+func (e GetPortfolioPerformanceParamsResolution) Valid() bool {
+	switch e {
+	case Daily:
+		return true
+	case Monthly:
+		return true
+	case Weekly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Defines values for ListStrategiesParamsSort.
+const (
+	Alpha                   ListStrategiesParamsSort = "alpha"
+	BenchmarkYtdReturn      ListStrategiesParamsSort = "benchmarkYtdReturn"
+	Beta                    ListStrategiesParamsSort = "beta"
+	Cagr                    ListStrategiesParamsSort = "cagr"
+	MaxDrawDown             ListStrategiesParamsSort = "maxDrawDown"
+	MinusAlpha              ListStrategiesParamsSort = "-alpha"
+	MinusBenchmarkYtdReturn ListStrategiesParamsSort = "-benchmarkYtdReturn"
+	MinusBeta               ListStrategiesParamsSort = "-beta"
+	MinusCagr               ListStrategiesParamsSort = "-cagr"
+	MinusMaxDrawDown        ListStrategiesParamsSort = "-maxDrawDown"
+	MinusOneYearReturn      ListStrategiesParamsSort = "-oneYearReturn"
+	MinusRiskAdjustedScore  ListStrategiesParamsSort = "-riskAdjustedScore"
+	MinusSharpe             ListStrategiesParamsSort = "-sharpe"
+	MinusShortCode          ListStrategiesParamsSort = "-shortCode"
+	MinusSortino            ListStrategiesParamsSort = "-sortino"
+	MinusStars              ListStrategiesParamsSort = "-stars"
+	MinusStdDev             ListStrategiesParamsSort = "-stdDev"
+	MinusTaxCostRatio       ListStrategiesParamsSort = "-taxCostRatio"
+	MinusUlcerIndex         ListStrategiesParamsSort = "-ulcerIndex"
+	MinusYtdReturn          ListStrategiesParamsSort = "-ytdReturn"
+	OneYearReturn           ListStrategiesParamsSort = "oneYearReturn"
+	RiskAdjustedScore       ListStrategiesParamsSort = "riskAdjustedScore"
+	Sharpe                  ListStrategiesParamsSort = "sharpe"
+	ShortCode               ListStrategiesParamsSort = "shortCode"
+	Sortino                 ListStrategiesParamsSort = "sortino"
+	Stars                   ListStrategiesParamsSort = "stars"
+	StdDev                  ListStrategiesParamsSort = "stdDev"
+	TaxCostRatio            ListStrategiesParamsSort = "taxCostRatio"
+	UlcerIndex              ListStrategiesParamsSort = "ulcerIndex"
+	YtdReturn               ListStrategiesParamsSort = "ytdReturn"
+)
+
+// Valid indicates whether the value is a known member of the ListStrategiesParamsSort enum.
+func (e ListStrategiesParamsSort) Valid() bool {
+	switch e {
+	case Alpha:
+		return true
+	case BenchmarkYtdReturn:
+		return true
+	case Beta:
+		return true
+	case Cagr:
+		return true
+	case MaxDrawDown:
+		return true
+	case MinusAlpha:
+		return true
+	case MinusBenchmarkYtdReturn:
+		return true
+	case MinusBeta:
+		return true
+	case MinusCagr:
+		return true
+	case MinusMaxDrawDown:
+		return true
+	case MinusOneYearReturn:
 		return true
-	case UnrealizedLTCG:
+	case MinusRiskAdjustedScore:
 		return true
-	case UnrealizedSTCG:
+	case MinusSharpe:
 		return true
-	case UpsideCaptureRatio:
+	case MinusShortCode:
 		return true
-	case ValueAtRisk:
+	case MinusSortino:
 		return true
-	case WinRate:
+	case MinusStars:
+		return true
+	case MinusStdDev:
+		return true
+	case MinusTaxCostRatio:
+		return true
+	case MinusUlcerIndex:
+		return true
+	case MinusYtdReturn:
+		return true
+	case OneYearReturn:
+		return true
+	case RiskAdjustedScore:
+		return true
+	case Sharpe:
+		return true
+	case ShortCode:
+		return true
+	case Sortino:
+		return true
+	case Stars:
+		return true
+	case StdDev:
+		return true
+	case TaxCostRatio:
+		return true
+	case UlcerIndex:
+		return true
+	case YtdReturn:
 		return true
 	default:
 		return false
 	}
 }
 
-// Defines values for GetPortfolioPerformanceParamsResolution.
-const (
-	Daily   GetPortfolioPerformanceParamsResolution = "daily"
-	Monthly GetPortfolioPerformanceParamsResolution = "monthly"
-	Weekly  GetPortfolioPerformanceParamsResolution = "weekly"
-)
+// AdminAccepted defines model for AdminAccepted.
+type AdminAccepted struct {
+	ShortCode *string             `json:"shortCode,omitempty"`
+	Status    AdminAcceptedStatus `json:"status"`
+	Version   *string             `json:"version,omitempty"`
+}
+
+// AdminAcceptedStatus defines model for AdminAccepted.Status.
+type AdminAcceptedStatus string
+
+// Alert defines model for Alert.
+type Alert struct {
+	// Channels Non-email delivery targets.
+	Channels *[]AlertChannel `json:"channels,omitempty"`
+
+	// Conditions Rules for an `on_condition` alert; empty for cadence alerts.
+	Conditions *[]AlertCondition `json:"conditions,omitempty"`
+
+	// Frequency How often the alert fires. `scheduled_run` fires on every completed backtest run; `daily`, `weekly` and `monthly` fire on calendar cadence; `on_condition` is checked after every successful run and sends only when one of its `conditions` newly fires.
+	Frequency   AlertFrequency     `json:"frequency"`
+	Id          openapi_types.UUID `json:"id"`
+	LastSentAt  *time.Time         `json:"lastSentAt,omitempty"`
+	PortfolioId openapi_types.UUID `json:"portfolioId"`
+
+	// RecipientStatus Confirmation state of each recipient. New recipients get an email
+	// with a confirmation link and stay `pending` until they follow it.
+	RecipientStatus *[]AlertRecipientStatus `json:"recipientStatus,omitempty"`
+
+	// Recipients Email addresses on the alert. Only those whose status is
+	// `verified` in `recipientStatus` are emailed.
+	Recipients []string `json:"recipients"`
+}
+
+// AlertChannel A non-email delivery target. `webhook` POSTs the alert data as JSON,
+// signed with `X-PV-Signature` when `secret` is set; `slack` and
+// `discord` post to incoming webhooks; `telegram` messages `chatId`
+// from the server's bot; `ntfy` publishes to `topic` on `url` or the
+// server's default ntfy instance.
+type AlertChannel struct {
+	// ChatId Telegram chat to message.
+	ChatId *string `json:"chatId,omitempty"`
+
+	// Secret Webhook signing key. Never returned; see `signed`.
+	Secret *string `json:"secret,omitempty"`
+
+	// Signed True when a webhook target has a signing secret.
+	Signed *bool `json:"signed,omitempty"`
+
+	// Topic ntfy topic.
+	Topic *string          `json:"topic,omitempty"`
+	Type  AlertChannelType `json:"type"`
+
+	// Url Destination for webhook, slack and discord (https only); optional ntfy server.
+	Url *string `json:"url,omitempty"`
+}
+
+// AlertChannelType defines model for AlertChannel.Type.
+type AlertChannelType string
+
+// AlertCondition One rule on an `on_condition` alert. Each condition fires once when it
+// becomes true and re-arms after it clears, so a drawdown that lasts for
+// weeks produces a single email.
+//
+//   - `drawdown`: the portfolio is more than `threshold` below its peak.
+//   - `day_change`: the latest day's move exceeds ±`threshold`.
+//   - `benchmark_underperformance`: the benchmark beat the portfolio by at
+//     least `threshold` over `window`.
+//   - `predicted_trade`: the next-trade prediction contains trades.
+//   - `holding_change`: a ticker entered or left the portfolio.
+type AlertCondition struct {
+	// Threshold Positive fraction (0.10 = 10%). Required by `drawdown`, `day_change` and `benchmark_underperformance`; rejected by the others.
+	Threshold *float64           `json:"threshold,omitempty"`
+	Type      AlertConditionType `json:"type"`
+
+	// Window Lookback for `benchmark_underperformance`; rejected by the others.
+	Window *AlertConditionWindow `json:"window,omitempty"`
+}
+
+// AlertConditionType defines model for AlertCondition.Type.
+type AlertConditionType string
+
+// AlertConditionWindow Lookback for `benchmark_underperformance`; rejected by the others.
+type AlertConditionWindow string
+
+// AlertCreateRequest defines model for AlertCreateRequest.
+type AlertCreateRequest struct {
+	// Channels Non-email delivery targets.
+	Channels *[]AlertChannel `json:"channels,omitempty"`
+
+	// Conditions Required (at least one) when frequency is `on_condition`; not allowed otherwise.
+	Conditions *[]AlertCondition `json:"conditions,omitempty"`
+
+	// Frequency How often the alert fires. `scheduled_run` fires on every completed backtest run; `daily`, `weekly` and `monthly` fire on calendar cadence; `on_condition` is checked after every successful run and sends only when one of its `conditions` newly fires.
+	Frequency AlertFrequency `json:"frequency"`
+
+	// RecipientLocales Locale (BCP 47 tag, e.g. `de-DE`) per recipient for email
+	// language, number and date formats. Keys must be listed in
+	// `recipients`; recipients without an entry get the server default.
+	RecipientLocales *map[string]string `json:"recipientLocales,omitempty"`
+
+	// Recipients Email recipients. At least one recipient or channel is required.
+	Recipients *[]string `json:"recipients,omitempty"`
+}
+
+// AlertDelivery defines model for AlertDelivery.
+type AlertDelivery struct {
+	Attempts int `json:"attempts"`
+
+	// Channel `email` or the channel target type.
+	Channel   AlertDeliveryChannel `json:"channel"`
+	CreatedAt time.Time            `json:"createdAt"`
+
+	// Destination Email address, webhook host, Telegram chat ID or ntfy topic.
+	Destination string             `json:"destination"`
+	Event       AlertDeliveryEvent `json:"event"`
+	Id          openapi_types.UUID `json:"id"`
+	LastError   *string            `json:"lastError,omitempty"`
+
+	// NextAttemptAt When the next attempt is due; null once sent or dead.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+
+	// ProviderMessageId Message ID assigned by Mailgun or the SMTP sender.
+	ProviderMessageId *string             `json:"providerMessageId,omitempty"`
+	SentAt            *time.Time          `json:"sentAt,omitempty"`
+	Status            AlertDeliveryStatus `json:"status"`
+	Subject           string              `json:"subject"`
+}
+
+// AlertDeliveryChannel `email` or the channel target type.
+type AlertDeliveryChannel string
+
+// AlertDeliveryEvent defines model for AlertDelivery.Event.
+type AlertDeliveryEvent string
+
+// AlertDeliveryStatus defines model for AlertDelivery.Status.
+type AlertDeliveryStatus string
+
+// AlertFrequency How often the alert fires. `scheduled_run` fires on every completed backtest run; `daily`, `weekly` and `monthly` fire on calendar cadence; `on_condition` is checked after every successful run and sends only when one of its `conditions` newly fires.
+type AlertFrequency string
+
+// AlertRecipientStatus defines model for AlertRecipientStatus.
+type AlertRecipientStatus struct {
+	Email string `json:"email"`
+
+	// Locale BCP 47 locale the recipient's email is rendered in; absent for the server default.
+	Locale *string                    `json:"locale,omitempty"`
+	Status AlertRecipientStatusStatus `json:"status"`
+}
+
+// AlertRecipientStatusStatus defines model for AlertRecipientStatus.Status.
+type AlertRecipientStatusStatus string
+
+// AlertUpdateRequest All fields optional; omit any field you do not want to change.
+type AlertUpdateRequest struct {
+	// Channels Replaces the channel targets. A webhook resubmitted without `secret` keeps its stored secret.
+	Channels *[]AlertChannel `json:"channels,omitempty"`
+
+	// Conditions Replaces the alert's conditions and resets what they remember. Omitted conditions are kept while the alert stays `on_condition`.
+	Conditions *[]AlertCondition `json:"conditions,omitempty"`
+
+	// Frequency How often the alert fires. `scheduled_run` fires on every completed backtest run; `daily`, `weekly` and `monthly` fire on calendar cadence; `on_condition` is checked after every successful run and sends only when one of its `conditions` newly fires.
+	Frequency *AlertFrequency `json:"frequency,omitempty"`
+
+	// RecipientLocales Sets the locale (BCP 47 tag) of the listed recipients; an empty
+	// string clears one. Recipients left out keep their locale.
+	RecipientLocales *map[string]string `json:"recipientLocales,omitempty"`
+
+	// Recipients Replaces the email recipients; may be empty when the alert has channels.
+	Recipients *[]string `json:"recipients,omitempty"`
+}
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	ExpiresAt *time.Time         `json:"expiresAt,omitempty"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Key The key itself; only in the create response.
+	Key *string `json:"key,omitempty"`
 
-// Valid indicates whether the value is a known member of the GetPortfolioPerformanceParamsResolution enum.
-func (e GetPortfolioPerformanceParamsResolution) Valid() bool {
-	switch e {
-	case Daily:
-		return true
-	case Monthly:
-		return true
-	case Weekly:
-		return true
-	default:
-		return false
-	}
+	// LastUsedAt Updated at most once a minute.
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+
+	// Prefix The start of the key, to tell keys apart.
+	Prefix string      `json:"prefix"`
+	Scope  ApiKeyScope `json:"scope"`
 }
 
-// Alert defines model for Alert.
-type Alert struct {
-	// Frequency How often the alert fires. `scheduled_run` fires on every completed backtest run; the others fire on calendar cadence.
-	Frequency   AlertFrequency     `json:"frequency"`
-	Id          openapi_types.UUID `json:"id"`
-	LastSentAt  *time.Time         `json:"lastSentAt,omitempty"`
-	PortfolioId openapi_types.UUID `json:"portfolioId"`
+// ApiKeyScope defines model for ApiKey.Scope.
+type ApiKeyScope string
 
-	// Recipients Email addresses that receive the alert.
-	Recipients []string `json:"recipients"`
+// ApiKeyRequest defines model for ApiKeyRequest.
+type ApiKeyRequest struct {
+	// ExpiresAt Optional; must be in the future.
+	ExpiresAt *time.Time          `json:"expiresAt,omitempty"`
+	Name      string              `json:"name"`
+	Scope     *ApiKeyRequestScope `json:"scope,omitempty"`
 }
 
-// AlertCreateRequest defines model for AlertCreateRequest.
-type AlertCreateRequest struct {
-	// Frequency How often the alert fires. `scheduled_run` fires on every completed backtest run; the others fire on calendar cadence.
-	Frequency AlertFrequency `json:"frequency"`
+// ApiKeyRequestScope defines model for ApiKeyRequest.Scope.
+type ApiKeyRequestScope string
 
-	// Recipients At least one recipient required.
-	Recipients []string `json:"recipients"`
+// AuditChange defines model for AuditChange.
+type AuditChange struct {
+	// After The value after; null for deletes.
+	After interface{} `json:"after,omitempty"`
+
+	// Before The value before; null for creates.
+	Before interface{} `json:"before,omitempty"`
 }
 
-// AlertFrequency How often the alert fires. `scheduled_run` fires on every completed backtest run; the others fire on calendar cadence.
-type AlertFrequency string
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action AuditEntryAction `json:"action"`
 
-// AlertUpdateRequest All fields optional; omit any field you do not want to change.
-type AlertUpdateRequest struct {
-	// Frequency How often the alert fires. `scheduled_run` fires on every completed backtest run; the others fire on calendar cadence.
-	Frequency  *AlertFrequency `json:"frequency,omitempty"`
-	Recipients *[]string       `json:"recipients,omitempty"`
+	// Actor Who acted: a user's subject, `system` for automatic changes, or
+	// `email:<address>` for an unsubscribe link.
+	Actor string `json:"actor"`
+
+	// Changes Each changed field's value before and after.
+	Changes   map[string]AuditChange `json:"changes"`
+	CreatedAt time.Time              `json:"createdAt"`
+	Id        openapi_types.UUID     `json:"id"`
+
+	// Owner Owner of what changed.
+	Owner       *string             `json:"owner,omitempty"`
+	PortfolioId *openapi_types.UUID `json:"portfolioId,omitempty"`
+
+	// PortfolioSlug The portfolio's slug when the entry was written.
+	PortfolioSlug *string `json:"portfolioSlug,omitempty"`
+	RequestId     *string `json:"requestId,omitempty"`
+
+	// Subject Whose authority the actor used; differs from `actor` when an admin acts as a user.
+	Subject string `json:"subject"`
+
+	// Target The alert or run acted on, or the method and path of an admin action.
+	Target *string `json:"target,omitempty"`
 }
 
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
 // BacktestRun defines model for BacktestRun.
 type BacktestRun struct {
 	DurationMs    *int               `json:"durationMs,omitempty"`
@@ -644,6 +1516,39 @@ type BacktestRun struct {
 	Status    RunStatus    `json:"status"`
 }
 
+// CalendarFeed defines model for CalendarFeed.
+type CalendarFeed struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Url Only returned when the URL is issued.
+	Url *string `json:"url,omitempty"`
+}
+
+// Digest defines model for Digest.
+type Digest struct {
+	Frequency       DigestFrequency        `json:"frequency"`
+	Id              openapi_types.UUID     `json:"id"`
+	LastSentAt      *time.Time             `json:"lastSentAt,omitempty"`
+	RecipientStatus []AlertRecipientStatus `json:"recipientStatus"`
+	Recipients      []string               `json:"recipients"`
+}
+
+// DigestFrequency defines model for Digest.Frequency.
+type DigestFrequency string
+
+// DigestRequest defines model for DigestRequest.
+type DigestRequest struct {
+	Frequency DigestRequestFrequency `json:"frequency"`
+
+	// RecipientLocales Sets the locale (BCP 47 tag) of the listed recipients; an empty
+	// string clears one. Recipients left out keep their locale.
+	RecipientLocales *map[string]string `json:"recipientLocales,omitempty"`
+	Recipients       []string           `json:"recipients"`
+}
+
+// DigestRequestFrequency defines model for DigestRequest.Frequency.
+type DigestRequestFrequency string
+
 // Drawdown defines model for Drawdown.
 type Drawdown struct {
 	// Days Trading days from start to recovery (or end of series if unrecovered).
@@ -667,6 +1572,71 @@ type EmailSummaryRequest struct {
 	Recipient openapi_types.Email `json:"recipient"`
 }
 
+// Fill defines model for Fill.
+type Fill struct {
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Date The strategy's trade date.
+	Date           openapi_types.Date `json:"date"`
+	ExecutedAt     time.Time          `json:"executedAt"`
+	Figi           *string            `json:"figi"`
+	Id             openapi_types.UUID `json:"id"`
+	Note           string             `json:"note"`
+	PredictedPrice *float64           `json:"predictedPrice"`
+
+	// PredictedQuantity The prediction's quantity when the fill was recorded; null if the trade was not predicted.
+	PredictedQuantity *float64 `json:"predictedQuantity"`
+	Price             float64  `json:"price"`
+	Quantity          float64  `json:"quantity"`
+	Ticker            string   `json:"ticker"`
+	Type              FillType `json:"type"`
+}
+
+// FillType defines model for Fill.Type.
+type FillType string
+
+// FillCreateRequest defines model for FillCreateRequest.
+type FillCreateRequest struct {
+	// Date Trade date. Defaults to the prediction's date.
+	Date *openapi_types.Date `json:"date,omitempty"`
+
+	// ExecutedAt When the order filled. Defaults to now.
+	ExecutedAt *time.Time            `json:"executedAt,omitempty"`
+	Note       *string               `json:"note,omitempty"`
+	Price      float64               `json:"price"`
+	Quantity   float64               `json:"quantity"`
+	Ticker     string                `json:"ticker"`
+	Type       FillCreateRequestType `json:"type"`
+}
+
+// FillCreateRequestType defines model for FillCreateRequest.Type.
+type FillCreateRequestType string
+
+// FillList defines model for FillList.
+type FillList struct {
+	Items []Fill `json:"items"`
+}
+
+// GraphQLRequest defines model for GraphQLRequest.
+type GraphQLRequest struct {
+	OperationName *string                 `json:"operationName,omitempty"`
+	Query         string                  `json:"query"`
+	Variables     *map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse defines model for GraphQLResponse.
+type GraphQLResponse struct {
+	Data   *map[string]interface{} `json:"data,omitempty"`
+	Errors *[]struct {
+		Locations *[]struct {
+			Column *int `json:"column,omitempty"`
+			Line   *int `json:"line,omitempty"`
+		} `json:"locations,omitempty"`
+		Message string         `json:"message"`
+		Path    *[]interface{} `json:"path,omitempty"`
+	} `json:"errors,omitempty"`
+}
+
 // HistoricalHolding Position reconstructed by replaying transactions. lastTradeValue is
 // quantity × last trade price seen in the transaction log, not a
 // mark-to-market value from a pricing feed.
@@ -786,6 +1756,39 @@ type MetricFormat string
 // Other metrics carry pvbt's documented units.
 type MetricGroup map[string][]*float64
 
+// MissedTrade defines model for MissedTrade.
+type MissedTrade struct {
+	Date     openapi_types.Date `json:"date"`
+	Price    *float64           `json:"price"`
+	Quantity *float64           `json:"quantity"`
+	Ticker   string             `json:"ticker"`
+	Type     MissedTradeType    `json:"type"`
+}
+
+// MissedTradeType defines model for MissedTrade.Type.
+type MissedTradeType string
+
+// Notification defines model for Notification.
+type Notification struct {
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Data Kind-specific fields for clients that render their own text:
+	// `runId` and `error` for runs, `fromVersion` and `toVersion` for
+	// upgrades, `alertId` and `triggers` for alerts.
+	Data          map[string]interface{} `json:"data"`
+	Id            openapi_types.UUID     `json:"id"`
+	Kind          NotificationKind       `json:"kind"`
+	PortfolioSlug *string                `json:"portfolioSlug"`
+	Read          bool                   `json:"read"`
+	ReadAt        *time.Time             `json:"readAt"`
+	RunId         *openapi_types.UUID    `json:"runId"`
+	Title         string                 `json:"title"`
+}
+
+// NotificationKind defines model for Notification.Kind.
+type NotificationKind string
+
 // PerformancePoint defines model for PerformancePoint.
 type PerformancePoint struct {
 	// BenchmarkValue Normalized to start at the portfolio's opening value.
@@ -842,8 +1845,15 @@ type Portfolio struct {
 	StrategyCode     string  `json:"strategyCode"`
 
 	// StrategyVer Pinned strategy version (null for unofficial portfolios).
-	StrategyVer *string   `json:"strategyVer,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	StrategyVer *string `json:"strategyVer,omitempty"`
+
+	// Tags Labels for filtering the portfolio list. Stored lower-cased
+	// without duplicates; commas are not allowed.
+	Tags      []string  `json:"tags"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// WorkspaceId The workspace that owns the portfolio; absent for personal portfolios.
+	WorkspaceId *openapi_types.UUID `json:"workspaceId,omitempty"`
 
 	// YtdReturn Year-to-date return as a decimal fraction. Null until a run has succeeded.
 	YtdReturn *float64 `json:"ytdReturn,omitempty"`
@@ -878,6 +1888,10 @@ type PortfolioCreateRequest struct {
 
 	// StrategyVer Specific version to pin; omit for latest installed.
 	StrategyVer *string `json:"strategyVer,omitempty"`
+
+	// Tags Labels for filtering the portfolio list. Stored lower-cased
+	// without duplicates; commas are not allowed.
+	Tags *[]string `json:"tags,omitempty"`
 }
 
 // PortfolioCreated defines model for PortfolioCreated.
@@ -928,8 +1942,15 @@ type PortfolioCreated struct {
 	StrategyCode     string  `json:"strategyCode"`
 
 	// StrategyVer Pinned strategy version (null for unofficial portfolios).
-	StrategyVer *string   `json:"strategyVer,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	StrategyVer *string `json:"strategyVer,omitempty"`
+
+	// Tags Labels for filtering the portfolio list. Stored lower-cased
+	// without duplicates; commas are not allowed.
+	Tags      []string  `json:"tags"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// WorkspaceId The workspace that owns the portfolio; absent for personal portfolios.
+	WorkspaceId *openapi_types.UUID `json:"workspaceId,omitempty"`
 
 	// YtdReturn Year-to-date return as a decimal fraction. Null until a run has succeeded.
 	YtdReturn *float64 `json:"ytdReturn,omitempty"`
@@ -1049,6 +2070,39 @@ type PortfolioPerformance struct {
 // PortfolioPerformanceResolution defines model for PortfolioPerformance.Resolution.
 type PortfolioPerformanceResolution string
 
+// PortfolioShare defines model for PortfolioShare.
+type PortfolioShare struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Handle    *string   `json:"handle"`
+
+	// HandleUrl Public URL of the share under its handle, when it has one.
+	HandleUrl *string `json:"handleUrl,omitempty"`
+
+	// HideValues Omit dollar values and rebase performance to a growth of 10,000.
+	HideValues bool `json:"hideValues"`
+
+	// ShowHoldings Expose current holdings.
+	ShowHoldings bool `json:"showHoldings"`
+
+	// Token Unguessable share token.
+	Token     string    `json:"token"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Url Public URL of the share under its token.
+	Url string `json:"url"`
+}
+
+// PortfolioShareRequest defines model for PortfolioShareRequest.
+type PortfolioShareRequest struct {
+	// Handle Public handle; lowercased before validation.
+	Handle     *string `json:"handle,omitempty"`
+	HideValues *bool   `json:"hideValues,omitempty"`
+
+	// RotateToken Issue a new token, revoking the old link.
+	RotateToken  *bool `json:"rotateToken,omitempty"`
+	ShowHoldings *bool `json:"showHoldings,omitempty"`
+}
+
 // PortfolioStatistic defines model for PortfolioStatistic.
 type PortfolioStatistic struct {
 	// Format How the value should be rendered. Percent values are decimal (0.1147 = 11.47%).
@@ -1088,7 +2142,8 @@ type PortfolioSummary struct {
 	YtdReturn *float64 `json:"ytdReturn,omitempty"`
 }
 
-// PortfolioUpdateRequest PATCH body. Only `name`, `startDate`, `endDate`, and `runRetention` are mutable.
+// PortfolioUpdateRequest PATCH body. Only `name`, `startDate`, `endDate`, `runRetention`, and
+// `tags` are mutable; `tags` replaces the whole list.
 // Any other field is rejected with 422. All fields are optional; omit
 // any field you do not want to change.
 type PortfolioUpdateRequest struct {
@@ -1101,6 +2156,10 @@ type PortfolioUpdateRequest struct {
 
 	// StartDate Backtest start date (YYYY-MM-DD).
 	StartDate *openapi_types.Date `json:"startDate,omitempty"`
+
+	// Tags Labels for filtering the portfolio list. Stored lower-cased
+	// without duplicates; commas are not allowed.
+	Tags *[]string `json:"tags,omitempty"`
 }
 
 // PredictedHolding defines model for PredictedHolding.
@@ -1241,17 +2300,22 @@ type Strategy struct {
 	OneYearReturn      *float64             `json:"oneYearReturn,omitempty"`
 
 	// OwnerSub Auth0 sub of the registering user; NULL for official strategies.
-	OwnerSub     *string  `json:"ownerSub,omitempty"`
-	RepoName     string   `json:"repoName"`
-	RepoOwner    string   `json:"repoOwner"`
-	Sharpe       *float64 `json:"sharpe,omitempty"`
-	ShortCode    string   `json:"shortCode"`
-	Sortino      *float64 `json:"sortino,omitempty"`
-	Stars        *int     `json:"stars,omitempty"`
-	StdDev       *float64 `json:"stdDev,omitempty"`
-	TaxCostRatio *float64 `json:"taxCostRatio,omitempty"`
-	UlcerIndex   *float64 `json:"ulcerIndex,omitempty"`
-	YtdReturn    *float64 `json:"ytdReturn,omitempty"`
+	OwnerSub  *string `json:"ownerSub,omitempty"`
+	RepoName  string  `json:"repoName"`
+	RepoOwner string  `json:"repoOwner"`
+
+	// RiskAdjustedScore Composite of Sortino (40%), Sharpe (30%), and CAGR over max
+	// drawdown (30%), each clamped to [-3, 5]. Missing inputs are
+	// dropped and the remaining weights rescaled.
+	RiskAdjustedScore *float64 `json:"riskAdjustedScore,omitempty"`
+	Sharpe            *float64 `json:"sharpe,omitempty"`
+	ShortCode         string   `json:"shortCode"`
+	Sortino           *float64 `json:"sortino,omitempty"`
+	Stars             *int     `json:"stars,omitempty"`
+	StdDev            *float64 `json:"stdDev,omitempty"`
+	TaxCostRatio      *float64 `json:"taxCostRatio,omitempty"`
+	UlcerIndex        *float64 `json:"ulcerIndex,omitempty"`
+	YtdReturn         *float64 `json:"ytdReturn,omitempty"`
 }
 
 // StrategyDescribe defines model for StrategyDescribe.
@@ -1273,8 +2337,33 @@ type StrategyParameter struct {
 	// Default Default value — may be any JSON-serializable type.
 	Default     interface{} `json:"default,omitempty"`
 	Description *string     `json:"description,omitempty"`
-	Name        string      `json:"name"`
-	Type        string      `json:"type"`
+
+	// Enum Allowed values. Absent means any value of `type`.
+	Enum *[]interface{} `json:"enum,omitempty"`
+
+	// Group UI hint for grouping related parameters.
+	Group *string `json:"group,omitempty"`
+
+	// Max Inclusive upper bound for numeric parameters.
+	Max *float32 `json:"max,omitempty"`
+
+	// Min Inclusive lower bound for numeric parameters.
+	Min  *float32 `json:"min,omitempty"`
+	Name string   `json:"name"`
+
+	// Required When false the parameter may be omitted. Absent means required.
+	Required *bool `json:"required,omitempty"`
+
+	// Step Numeric values must equal `min + k*step` (or `k*step` when `min` is absent).
+	Step *float32 `json:"step,omitempty"`
+	Type string   `json:"type"`
+
+	// Universe Constraints on a `universe` parameter's ticker list.
+	Universe *struct {
+		Allowed *[]string `json:"allowed,omitempty"`
+		MaxSize *int      `json:"maxSize,omitempty"`
+		MinSize *int      `json:"minSize,omitempty"`
+	} `json:"universe,omitempty"`
 }
 
 // StrategyPreset defines model for StrategyPreset.
@@ -1283,6 +2372,105 @@ type StrategyPreset struct {
 	Parameters map[string]interface{} `json:"parameters"`
 }
 
+// StrategyValidateRequest defines model for StrategyValidateRequest.
+type StrategyValidateRequest struct {
+	// CloneUrl HTTPS GitHub clone URL.
+	CloneUrl string `json:"cloneUrl"`
+
+	// Ref Branch or tag to build. Defaults to the repository's default branch.
+	Ref *string `json:"ref,omitempty"`
+}
+
+// StrategyValidationIssue defines model for StrategyValidationIssue.
+type StrategyValidationIssue struct {
+	// Column Missing column (snapshot stage only).
+	Column *string `json:"column,omitempty"`
+
+	// Key Metadata key the issue refers to (snapshot stage only).
+	Key     *string                      `json:"key,omitempty"`
+	Message string                       `json:"message"`
+	Stage   StrategyValidationIssueStage `json:"stage"`
+
+	// Table Snapshot table the issue refers to (snapshot stage only).
+	Table *string `json:"table,omitempty"`
+}
+
+// StrategyValidationIssueStage defines model for StrategyValidationIssue.Stage.
+type StrategyValidationIssueStage string
+
+// StrategyValidationReport defines model for StrategyValidationReport.
+type StrategyValidationReport struct {
+	CloneUrl string                            `json:"cloneUrl"`
+	Describe *StrategyDescribe                 `json:"describe,omitempty"`
+	Issues   []StrategyValidationIssue         `json:"issues"`
+	Ref      *string                           `json:"ref,omitempty"`
+	Skipped  []StrategyValidationReportSkipped `json:"skipped"`
+
+	// Valid True when every stage ran and no issues were found.
+	Valid bool `json:"valid"`
+}
+
+// StrategyValidationReportSkipped defines model for StrategyValidationReport.Skipped.
+type StrategyValidationReportSkipped string
+
+// TrackingReport defines model for TrackingReport.
+type TrackingReport struct {
+	// CostImpactBps Total cost impact in basis points of the model notional.
+	CostImpactBps *float64      `json:"costImpactBps"`
+	Fills         int           `json:"fills"`
+	Items         []TrackingRow `json:"items"`
+
+	// Matched Fills with a backtest or predicted trade to compare against.
+	Matched      int           `json:"matched"`
+	MissedTrades []MissedTrade `json:"missedTrades"`
+
+	// TotalCostImpact Sum of the matched fills' cost impact, in dollars.
+	TotalCostImpact float64 `json:"totalCostImpact"`
+}
+
+// TrackingRow defines model for TrackingRow.
+type TrackingRow struct {
+	Basis         TrackingRowBasis `json:"basis"`
+	CostImpact    *float64         `json:"costImpact"`
+	CostImpactBps *float64         `json:"costImpactBps"`
+	Fill          Fill             `json:"fill"`
+
+	// ModelPrice Quantity-weighted price of the model's trades.
+	ModelPrice    *float64 `json:"modelPrice"`
+	ModelQuantity *float64 `json:"modelQuantity"`
+
+	// PriceDiff Fill price minus model price.
+	PriceDiff *float64 `json:"priceDiff"`
+
+	// QuantityDiff Filled quantity minus model quantity.
+	QuantityDiff *float64 `json:"quantityDiff"`
+}
+
+// TrackingRowBasis defines model for TrackingRow.Basis.
+type TrackingRowBasis string
+
+// TradeChecklist defines model for TradeChecklist.
+type TradeChecklist struct {
+	Date  openapi_types.Date   `json:"date"`
+	Items []TradeChecklistItem `json:"items"`
+}
+
+// TradeChecklistItem defines model for TradeChecklistItem.
+type TradeChecklistItem struct {
+	Amount        *float64               `json:"amount"`
+	Executed      bool                   `json:"executed"`
+	Figi          *string                `json:"figi"`
+	Fill          *Fill                  `json:"fill"`
+	Justification *string                `json:"justification"`
+	Price         *float64               `json:"price"`
+	Quantity      *float64               `json:"quantity"`
+	Ticker        string                 `json:"ticker"`
+	Type          TradeChecklistItemType `json:"type"`
+}
+
+// TradeChecklistItemType defines model for TradeChecklistItem.Type.
+type TradeChecklistItemType string
+
 // TrailingReturnRow Trailing returns for a portfolio or its benchmark. Sub-annual cells
 // (ytd, oneYear) are cumulative period returns. Multi-year cells
 // (threeYear, fiveYear, tenYear, sinceInception) are annualized (CAGR).
@@ -1324,18 +2512,104 @@ type TransactionsResponse struct {
 	Items []Transaction `json:"items"`
 }
 
+// Usage defines model for Usage.
+type Usage struct {
+	// AlertRecipients Email recipients allowed on one alert.
+	AlertRecipients   UsageMeter `json:"alertRecipients"`
+	Portfolios        UsageMeter `json:"portfolios"`
+	RequestsPerMinute UsageMeter `json:"requestsPerMinute"`
+
+	// RunsPerDay Backtests started by hand in the last 24 hours.
+	RunsPerDay           UsageMeter `json:"runsPerDay"`
+	UnofficialStrategies UsageMeter `json:"unofficialStrategies"`
+}
+
+// UsageMeter defines model for UsageMeter.
+type UsageMeter struct {
+	// Limit Absent when unlimited.
+	Limit *int `json:"limit,omitempty"`
+	Used  *int `json:"used,omitempty"`
+}
+
+// Workspace defines model for Workspace.
+type Workspace struct {
+	CreatedAt time.Time          `json:"createdAt"`
+	CreatedBy string             `json:"createdBy"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Members Present on create and get.
+	Members   *[]WorkspaceMember `json:"members,omitempty"`
+	Name      string             `json:"name"`
+	Role      *WorkspaceRole     `json:"role,omitempty"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+// WorkspaceMember defines model for WorkspaceMember.
+type WorkspaceMember struct {
+	CreatedAt time.Time     `json:"createdAt"`
+	Role      WorkspaceRole `json:"role"`
+	Sub       string        `json:"sub"`
+}
+
+// WorkspaceRequest defines model for WorkspaceRequest.
+type WorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+// WorkspaceRole defines model for WorkspaceRole.
+type WorkspaceRole string
+
+// AuditAction defines model for AuditAction.
+type AuditAction = string
+
+// AuditActor defines model for AuditActor.
+type AuditActor = string
+
+// AuditCursor defines model for AuditCursor.
+type AuditCursor = string
+
+// AuditLimit defines model for AuditLimit.
+type AuditLimit = int
+
+// AuditSince defines model for AuditSince.
+type AuditSince = time.Time
+
+// AuditUntil defines model for AuditUntil.
+type AuditUntil = time.Time
+
+// IdempotencyKey defines model for IdempotencyKey.
+type IdempotencyKey = string
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
 // PortfolioSlug defines model for PortfolioSlug.
 type PortfolioSlug = string
 
+// WorkspaceId defines model for WorkspaceId.
+type WorkspaceId = openapi_types.UUID
+
 // BadRequest RFC 7807 Problem Details.
 type BadRequest = Problem
 
 // Conflict RFC 7807 Problem Details.
 type Conflict = Problem
 
+// Forbidden RFC 7807 Problem Details.
+type Forbidden = Problem
+
 // NotFound RFC 7807 Problem Details.
 type NotFound = Problem
 
+// PreconditionFailed RFC 7807 Problem Details.
+type PreconditionFailed = Problem
+
+// QuotaExceeded RFC 7807 Problem Details.
+type QuotaExceeded = Problem
+
 // Recalculating Returned with a `202 Accepted` status from the snapshot-reading
 // endpoints when the portfolio's run database is missing and a
 // recompute has been queued. Clients should poll `pollUrl` until the
@@ -1346,16 +2620,251 @@ type Recalculating = RecalculatingResponse
 // ServerError RFC 7807 Problem Details.
 type ServerError = Problem
 
+// TooManyRequests RFC 7807 Problem Details.
+type TooManyRequests = Problem
+
 // Unauthorized RFC 7807 Problem Details.
 type Unauthorized = Problem
 
 // UnprocessableEntity RFC 7807 Problem Details.
 type UnprocessableEntity = Problem
 
+// AdminListAuditParams defines parameters for AdminListAudit.
+type AdminListAuditParams struct {
+	// Owner Only entries about what this subject owns.
+	Owner *string `form:"owner,omitempty" json:"owner,omitempty"`
+
+	// Portfolio Only entries about the portfolio with this slug.
+	Portfolio *string `form:"portfolio,omitempty" json:"portfolio,omitempty"`
+
+	// PortfolioId Only entries about this portfolio.
+	PortfolioId *openapi_types.UUID `form:"portfolioId,omitempty" json:"portfolioId,omitempty"`
+
+	// Action Only this action, such as `portfolio.update`, or a family of
+	// actions, such as `alert`.
+	Action *AuditAction `form:"action,omitempty" json:"action,omitempty"`
+
+	// Actor Only entries by this actor.
+	Actor *AuditActor `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Since Only entries at or after this time.
+	Since *AuditSince `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only entries before this time.
+	Until *AuditUntil `form:"until,omitempty" json:"until,omitempty"`
+
+	// Limit Page size; defaults to 50.
+	Limit *AuditLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor from a previous response's `X-Next-Cursor` header.
+	Cursor *AuditCursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// AdminReinstallStrategyJSONBody defines parameters for AdminReinstallStrategy.
+type AdminReinstallStrategyJSONBody struct {
+	Version *string `json:"version,omitempty"`
+}
+
+// ListMyAuditParams defines parameters for ListMyAudit.
+type ListMyAuditParams struct {
+	// Portfolio Only entries about the portfolio with this slug.
+	Portfolio *string `form:"portfolio,omitempty" json:"portfolio,omitempty"`
+
+	// Action Only this action, such as `portfolio.update`, or a family of
+	// actions, such as `alert`.
+	Action *AuditAction `form:"action,omitempty" json:"action,omitempty"`
+
+	// Actor Only entries by this actor.
+	Actor *AuditActor `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Since Only entries at or after this time.
+	Since *AuditSince `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only entries before this time.
+	Until *AuditUntil `form:"until,omitempty" json:"until,omitempty"`
+
+	// Limit Page size; defaults to 50.
+	Limit *AuditLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor from a previous response's `X-Next-Cursor` header.
+	Cursor *AuditCursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListNotificationsParams defines parameters for ListNotifications.
+type ListNotificationsParams struct {
+	// Unread Only unread notifications.
+	Unread *bool `form:"unread,omitempty" json:"unread,omitempty"`
+
+	// Limit Page size (1-200, default 50).
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor from a previous response's `X-Next-Cursor` header.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// StreamNotificationsParams defines parameters for StreamNotifications.
+type StreamNotificationsParams struct {
+	// LastEventID Id of the last notification the client received.
+	LastEventID *openapi_types.UUID `json:"Last-Event-ID,omitempty"`
+}
+
+// ListPortfoliosParams defines parameters for ListPortfolios.
+type ListPortfoliosParams struct {
+	// Strategy Comma-separated strategy short codes to include.
+	Strategy *string `form:"strategy,omitempty" json:"strategy,omitempty"`
+
+	// Status Comma-separated statuses to include.
+	Status *string `form:"status,omitempty" json:"status,omitempty"`
+
+	// Benchmark Comma-separated benchmark tickers to include, ignoring case.
+	Benchmark *string `form:"benchmark,omitempty" json:"benchmark,omitempty"`
+
+	// Tag Comma-separated tags; a portfolio matches if it has any of them.
+	Tag *string `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// CreatedFrom Only portfolios created on or after this date.
+	CreatedFrom *openapi_types.Date `form:"createdFrom,omitempty" json:"createdFrom,omitempty"`
+
+	// CreatedTo Only portfolios created on or before this date.
+	CreatedTo *openapi_types.Date `form:"createdTo,omitempty" json:"createdTo,omitempty"`
+
+	// LastRunFrom Only portfolios last run on or after this date.
+	LastRunFrom *openapi_types.Date `form:"lastRunFrom,omitempty" json:"lastRunFrom,omitempty"`
+
+	// LastRunTo Only portfolios last run on or before this date.
+	LastRunTo *openapi_types.Date `form:"lastRunTo,omitempty" json:"lastRunTo,omitempty"`
+
+	// Q Name search. Every word must start a word of the portfolio's
+	// name, ignoring case, so `adm gro` matches "ADM Growth".
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
+
+	// Sort Field to sort on, prefixed with `-` for descending. Portfolios
+	// missing the field sort last in either direction; ties break by
+	// slug.
+	Sort  *ListPortfoliosParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+	Limit *int                      `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor from a previous response's `X-Next-Cursor` header.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListPortfoliosParamsSort defines parameters for ListPortfolios.
+type ListPortfoliosParamsSort string
+
+// CreatePortfolioParams defines parameters for CreatePortfolio.
+type CreatePortfolioParams struct {
+	// IdempotencyKey A unique value per logical request, such as a UUID. A retry with
+	// the same key within the retention window (24 hours by default)
+	// gets the first response back, marked `Idempotent-Replayed: true`,
+	// instead of repeating the change. Reusing a key for a different
+	// request answers 422, and retrying while the first request is still
+	// running answers 409. Server errors and 429s are not kept, so those
+	// retries run again.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// DeletePortfolioParams defines parameters for DeletePortfolio.
+type DeletePortfolioParams struct {
+	// IfMatch ETags from an earlier read. The change is refused with 412 unless
+	// one of them is still current, so concurrent edits do not silently
+	// overwrite each other.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetPortfolioParams defines parameters for GetPortfolio.
+type GetPortfolioParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// UpdatePortfolioParams defines parameters for UpdatePortfolio.
+type UpdatePortfolioParams struct {
+	// IfMatch ETags from an earlier read. The change is refused with 412 unless
+	// one of them is still current, so concurrent edits do not silently
+	// overwrite each other.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// CreatePortfolioAlertParams defines parameters for CreatePortfolioAlert.
+type CreatePortfolioAlertParams struct {
+	// IdempotencyKey A unique value per logical request, such as a UUID. A retry with
+	// the same key within the retention window (24 hours by default)
+	// gets the first response back, marked `Idempotent-Replayed: true`,
+	// instead of repeating the change. Reusing a key for a different
+	// request answers 422, and retrying while the first request is still
+	// running answers 409. Server errors and 429s are not kept, so those
+	// retries run again.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// DeletePortfolioAlertParams defines parameters for DeletePortfolioAlert.
+type DeletePortfolioAlertParams struct {
+	// IfMatch ETags from an earlier read. The change is refused with 412 unless
+	// one of them is still current, so concurrent edits do not silently
+	// overwrite each other.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetPortfolioAlertParams defines parameters for GetPortfolioAlert.
+type GetPortfolioAlertParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// UpdatePortfolioAlertParams defines parameters for UpdatePortfolioAlert.
+type UpdatePortfolioAlertParams struct {
+	// IfMatch ETags from an earlier read. The change is refused with 412 unless
+	// one of them is still current, so concurrent edits do not silently
+	// overwrite each other.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// ListPortfolioAlertDeliveriesParams defines parameters for ListPortfolioAlertDeliveries.
+type ListPortfolioAlertDeliveriesParams struct {
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// ListPortfolioAuditParams defines parameters for ListPortfolioAudit.
+type ListPortfolioAuditParams struct {
+	// Action Only this action, such as `portfolio.update`, or a family of
+	// actions, such as `alert`.
+	Action *AuditAction `form:"action,omitempty" json:"action,omitempty"`
+
+	// Actor Only entries by this actor.
+	Actor *AuditActor `form:"actor,omitempty" json:"actor,omitempty"`
+
+	// Since Only entries at or after this time.
+	Since *AuditSince `form:"since,omitempty" json:"since,omitempty"`
+
+	// Until Only entries before this time.
+	Until *AuditUntil `form:"until,omitempty" json:"until,omitempty"`
+
+	// Limit Page size; defaults to 50.
+	Limit *AuditLimit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Cursor from a previous response's `X-Next-Cursor` header.
+	Cursor *AuditCursor `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// GetPortfolioDrawdownsParams defines parameters for GetPortfolioDrawdowns.
+type GetPortfolioDrawdownsParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetPortfolioHoldingsParams defines parameters for GetPortfolioHoldings.
+type GetPortfolioHoldingsParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
 // GetPortfolioHoldingsImpactParams defines parameters for GetPortfolioHoldingsImpact.
 type GetPortfolioHoldingsImpactParams struct {
 	// Top Maximum number of named holdings per period (remaining folded into `rest`). Values outside [1, 50] are clamped silently.
 	Top *int `form:"top,omitempty" json:"top,omitempty"`
+
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
 // GetPortfolioHoldingsHistoryParams defines parameters for GetPortfolioHoldingsHistory.
@@ -1365,6 +2874,15 @@ type GetPortfolioHoldingsHistoryParams struct {
 
 	// To Inclusive upper bound on batch timestamp (YYYY-MM-DD).
 	To *openapi_types.Date `form:"to,omitempty" json:"to,omitempty"`
+
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetPortfolioHoldingsAsOfParams defines parameters for GetPortfolioHoldingsAsOf.
+type GetPortfolioHoldingsAsOfParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
 // GetPortfolioMetricsParams defines parameters for GetPortfolioMetrics.
@@ -1374,6 +2892,9 @@ type GetPortfolioMetricsParams struct {
 
 	// Metric One or more pvbt PascalCase metric names to include. Repeatable. Default is all metrics.
 	Metric *[]GetPortfolioMetricsParamsMetric `form:"metric,omitempty" json:"metric,omitempty"`
+
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
 // GetPortfolioMetricsParamsWindow defines parameters for GetPortfolioMetrics.
@@ -1392,11 +2913,50 @@ type GetPortfolioPerformanceParams struct {
 
 	// Resolution Downsample target. `daily` (default) or `weekly`/`monthly` for coarser series.
 	Resolution *GetPortfolioPerformanceParamsResolution `form:"resolution,omitempty" json:"resolution,omitempty"`
+
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
 // GetPortfolioPerformanceParamsResolution defines parameters for GetPortfolioPerformance.
 type GetPortfolioPerformanceParamsResolution string
 
+// GetPortfolioPredictionParams defines parameters for GetPortfolioPrediction.
+type GetPortfolioPredictionParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// TriggerPortfolioRunParams defines parameters for TriggerPortfolioRun.
+type TriggerPortfolioRunParams struct {
+	// IdempotencyKey A unique value per logical request, such as a UUID. A retry with
+	// the same key within the retention window (24 hours by default)
+	// gets the first response back, marked `Idempotent-Replayed: true`,
+	// instead of repeating the change. Reusing a key for a different
+	// request answers 422, and retrying while the first request is still
+	// running answers 409. Server errors and 429s are not kept, so those
+	// retries run again.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// GetPortfolioStatisticsParams defines parameters for GetPortfolioStatistics.
+type GetPortfolioStatisticsParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetPortfolioSummaryParams defines parameters for GetPortfolioSummary.
+type GetPortfolioSummaryParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// GetPortfolioTrailingReturnsParams defines parameters for GetPortfolioTrailingReturns.
+type GetPortfolioTrailingReturnsParams struct {
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
 // GetPortfolioTransactionsParams defines parameters for GetPortfolioTransactions.
 type GetPortfolioTransactionsParams struct {
 	// From Inclusive start date (YYYY-MM-DD).
@@ -1407,6 +2967,15 @@ type GetPortfolioTransactionsParams struct {
 
 	// Type Comma-separated list of transaction types to include.
 	Type *string `form:"type,omitempty" json:"type,omitempty"`
+
+	// IfNoneMatch An ETag from an earlier read; answers 304 while it is still current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// TransferPortfolioJSONBody defines parameters for TransferPortfolio.
+type TransferPortfolioJSONBody struct {
+	// WorkspaceId Destination workspace; null moves the portfolio to the caller.
+	WorkspaceId *openapi_types.UUID `json:"workspaceId"`
 }
 
 // UpgradePortfolioStrategyJSONBody defines parameters for UpgradePortfolioStrategy.
@@ -1416,12 +2985,65 @@ type UpgradePortfolioStrategyJSONBody struct {
 	Parameters *map[string]interface{} `json:"parameters,omitempty"`
 }
 
+// UpgradePortfolioStrategyParams defines parameters for UpgradePortfolioStrategy.
+type UpgradePortfolioStrategyParams struct {
+	// IdempotencyKey A unique value per logical request, such as a UUID. A retry with
+	// the same key within the retention window (24 hours by default)
+	// gets the first response back, marked `Idempotent-Replayed: true`,
+	// instead of repeating the change. Reusing a key for a different
+	// request answers 422, and retrying while the first request is still
+	// running answers 409. Server errors and 429s are not kept, so those
+	// retries run again.
+	IdempotencyKey *IdempotencyKey `json:"Idempotency-Key,omitempty"`
+}
+
+// ListStrategiesParams defines parameters for ListStrategies.
+type ListStrategiesParams struct {
+	// Category Comma-separated categories; a strategy matches if it has any of them.
+	Category *string `form:"category,omitempty" json:"category,omitempty"`
+
+	// InstallState Comma-separated install states to include.
+	InstallState *string `form:"installState,omitempty" json:"installState,omitempty"`
+
+	// Official Only official (`true`) or only unofficial (`false`) strategies.
+	Official *bool `form:"official,omitempty" json:"official,omitempty"`
+
+	// Sort Field to sort on, prefixed with `-` for descending. Strategies
+	// missing the field sort last in either direction; ties break by
+	// short code.
+	Sort  *ListStrategiesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+	Limit *int                      `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Cursor Opaque cursor from a previous response's `X-Next-Cursor` header.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+}
+
+// ListStrategiesParamsSort defines parameters for ListStrategies.
+type ListStrategiesParamsSort string
+
 // DescribeStrategyParams defines parameters for DescribeStrategy.
 type DescribeStrategyParams struct {
 	// CloneUrl HTTPS GitHub clone URL.
 	CloneUrl string `form:"cloneUrl" json:"cloneUrl"`
 }
 
+// PutWorkspaceMemberJSONBody defines parameters for PutWorkspaceMember.
+type PutWorkspaceMemberJSONBody struct {
+	Role WorkspaceRole `json:"role"`
+}
+
+// AdminReinstallStrategyJSONRequestBody defines body for AdminReinstallStrategy for application/json ContentType.
+type AdminReinstallStrategyJSONRequestBody AdminReinstallStrategyJSONBody
+
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody = ApiKeyRequest
+
+// GraphqlQueryJSONRequestBody defines body for GraphqlQuery for application/json ContentType.
+type GraphqlQueryJSONRequestBody = GraphQLRequest
+
+// PutDigestJSONRequestBody defines body for PutDigest for application/json ContentType.
+type PutDigestJSONRequestBody = DigestRequest
+
 // CreatePortfolioJSONRequestBody defines body for CreatePortfolio for application/json ContentType.
 type CreatePortfolioJSONRequestBody = PortfolioCreateRequest
 
@@ -1437,5 +3059,26 @@ type UpdatePortfolioAlertJSONRequestBody = AlertUpdateRequest
 // SendPortfolioEmailSummaryJSONRequestBody defines body for SendPortfolioEmailSummary for application/json ContentType.
 type SendPortfolioEmailSummaryJSONRequestBody = EmailSummaryRequest
 
+// CreatePortfolioFillJSONRequestBody defines body for CreatePortfolioFill for application/json ContentType.
+type CreatePortfolioFillJSONRequestBody = FillCreateRequest
+
+// PutPortfolioShareJSONRequestBody defines body for PutPortfolioShare for application/json ContentType.
+type PutPortfolioShareJSONRequestBody = PortfolioShareRequest
+
+// TransferPortfolioJSONRequestBody defines body for TransferPortfolio for application/json ContentType.
+type TransferPortfolioJSONRequestBody TransferPortfolioJSONBody
+
 // UpgradePortfolioStrategyJSONRequestBody defines body for UpgradePortfolioStrategy for application/json ContentType.
 type UpgradePortfolioStrategyJSONRequestBody UpgradePortfolioStrategyJSONBody
+
+// ValidateStrategyJSONRequestBody defines body for ValidateStrategy for application/json ContentType.
+type ValidateStrategyJSONRequestBody = StrategyValidateRequest
+
+// CreateWorkspaceJSONRequestBody defines body for CreateWorkspace for application/json ContentType.
+type CreateWorkspaceJSONRequestBody = WorkspaceRequest
+
+// RenameWorkspaceJSONRequestBody defines body for RenameWorkspace for application/json ContentType.
+type RenameWorkspaceJSONRequestBody = WorkspaceRequest
+
+// PutWorkspaceMemberJSONRequestBody defines body for PutWorkspaceMember for application/json ContentType.
+type PutWorkspaceMemberJSONRequestBody PutWorkspaceMemberJSONBody
@@ -0,0 +1,78 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"context"
+	"time"
+
+	"github.com/penny-vault/pv-api/audit"
+)
+
+// WithAudit records portfolio changes, upgrades and run triggers in the
+// audit trail.
+func (h *Handler) WithAudit(r audit.Recorder) *Handler {
+	h.audit = r
+	return h
+}
+
+// auditState is the part of a portfolio the audit trail follows: what a
+// user configures, not what a run computes.
+func auditState(p Portfolio) map[string]any {
+	return map[string]any{
+		"name":             p.Name,
+		"strategyCode":     p.StrategyCode,
+		"strategyVer":      p.StrategyVer,
+		"strategyCloneUrl": p.StrategyCloneURL,
+		"parameters":       p.Parameters,
+		"benchmark":        p.Benchmark,
+		"startDate":        formatDatePtr(p.StartDate),
+		"endDate":          formatDatePtr(p.EndDate),
+		"runRetention":     p.RunRetention,
+		"tags":             tagsOrEmpty(p.Tags),
+	}
+}
+
+// record adds e, an action on p, to the audit trail.
+func (h *Handler) record(ctx context.Context, e audit.Entry, p Portfolio) {
+	id := p.ID
+	e.OwnerSub = p.OwnerSub
+	e.PortfolioID = &id
+	e.PortfolioSlug = p.Slug
+	audit.Record(ctx, h.audit, e)
+}
+
+// recordUpgrade records an applied upgrade of before, re-reading the
+// portfolio for its new version and parameters.
+func (h *Handler) recordUpgrade(ctx context.Context, e audit.Entry, before Portfolio) {
+	if h.audit == nil {
+		return
+	}
+	after, err := h.store.Get(ctx, before.OwnerSub, before.Slug)
+	if err != nil {
+		return
+	}
+	e.Changes = audit.Diff(auditState(before), auditState(after))
+	h.record(ctx, e, after)
+}
+
+func formatDatePtr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.Format("2006-01-02")
+	return &s
+}
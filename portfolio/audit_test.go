@@ -0,0 +1,99 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package portfolio_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// auditLog is an audit.Recorder that keeps entries in memory.
+type auditLog []audit.Entry
+
+func (l *auditLog) Record(_ context.Context, e audit.Entry) error {
+	*l = append(*l, e)
+	return nil
+}
+
+var _ = Describe("Audit", func() {
+	var (
+		app *fiber.App
+		log *auditLog
+		id  uuid.UUID
+	)
+
+	BeforeEach(func() {
+		id = uuid.Must(uuid.NewV7())
+		store := &fakeStore{rows: []portfolio.Portfolio{
+			{ID: id, OwnerSub: "auth0|user-1", Slug: "mine", Name: "Old", Status: portfolio.StatusReady, RunRetention: 2},
+		}}
+		log = &auditLog{}
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, nil, nil, nil, nil, strategy.EphemeralOptions{}).
+			WithAudit(log)
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|user-1")
+			c.Locals(types.AuthActorKey{}, "auth0|admin")
+			return c.Next()
+		})
+		app.Patch("/portfolios/:slug", h.Patch)
+		app.Delete("/portfolios/:slug", h.Delete)
+	})
+
+	do := func(method, target, body string) int {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	It("records what an edit changed and who made it", func() {
+		Expect(do("PATCH", "/portfolios/mine", `{"name":"New","runRetention":2}`)).To(Equal(fiber.StatusOK))
+		Expect(*log).To(HaveLen(1))
+		e := (*log)[0]
+		Expect(e.Action).To(Equal(audit.ActionPortfolioUpdate))
+		Expect(e.Actor).To(Equal("auth0|admin"))
+		Expect(e.Subject).To(Equal("auth0|user-1"))
+		Expect(e.OwnerSub).To(Equal("auth0|user-1"))
+		Expect(*e.PortfolioID).To(Equal(id))
+		Expect(e.Changes).To(Equal(map[string]audit.Change{"name": {Before: "Old", After: "New"}}))
+	})
+
+	It("records nothing for an edit that changes nothing", func() {
+		Expect(do("PATCH", "/portfolios/mine", `{"name":"Old"}`)).To(Equal(fiber.StatusOK))
+		Expect(*log).To(BeEmpty())
+	})
+
+	It("records a delete with the last state", func() {
+		Expect(do("DELETE", "/portfolios/mine", "")).To(Equal(fiber.StatusNoContent))
+		Expect(*log).To(HaveLen(1))
+		Expect((*log)[0].Action).To(Equal(audit.ActionPortfolioDelete))
+		Expect((*log)[0].PortfolioSlug).To(Equal("mine"))
+		Expect((*log)[0].Changes["name"]).To(Equal(audit.Change{Before: "Old"}))
+	})
+})
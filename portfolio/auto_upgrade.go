@@ -22,9 +22,17 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/rs/zerolog/log"
 
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/notification"
 	"github.com/penny-vault/pv-api/strategy"
 )
 
+// Inbox records in-app notifications for portfolio owners.
+// notification.Service implements it.
+type Inbox interface {
+	Notify(ctx context.Context, n notification.Notification) error
+}
+
 // AutoUpgrader is the portfolio-side hook the strategy Syncer calls after a
 // new version is installed. It scans portfolios on that strategy and
 // upgrades the ones whose pinned version differs only in the semver patch
@@ -34,6 +42,7 @@ import (
 type AutoUpgrader struct {
 	handler    *Handler
 	strategies strategy.ReadStore
+	inbox      Inbox
 }
 
 // NewAutoUpgrader builds an AutoUpgrader that reuses the Handler's store
@@ -42,6 +51,13 @@ func NewAutoUpgrader(h *Handler, strategies strategy.ReadStore) *AutoUpgrader {
 	return &AutoUpgrader{handler: h, strategies: strategies}
 }
 
+// WithInbox tells each portfolio's owner, through their in-app
+// notifications, when their portfolio was upgraded.
+func (a *AutoUpgrader) WithInbox(inbox Inbox) *AutoUpgrader {
+	a.inbox = inbox
+	return a
+}
+
 // AutoUpgradeAfterInstall is invoked by the strategy Syncer after a
 // successful install. It enumerates portfolios on shortCode and applies the
 // patch-only auto-upgrade rule to each.
@@ -133,6 +149,8 @@ func (a *AutoUpgrader) tryUpgradeOne(ctx context.Context, p Portfolio, s strateg
 			ev = ev.AnErr("dispatch_err", res.RunErr)
 		}
 		ev.Msg("auto-upgrade applied")
+		a.handler.recordUpgrade(ctx, audit.System(audit.ActionPortfolioAutoUpgrade), p)
+		a.notifyUpgraded(ctx, p, res)
 	case UpgradeOutcomeIncompatibleParams:
 		logger.Info().
 			Str("from_version", res.FromVersion).
@@ -149,6 +167,30 @@ func (a *AutoUpgrader) tryUpgradeOne(ctx context.Context, p Portfolio, s strateg
 	}
 }
 
+// notifyUpgraded tells p's owner that p now runs res.ToVersion.
+func (a *AutoUpgrader) notifyUpgraded(ctx context.Context, p Portfolio, res UpgradeResult) {
+	if a.inbox == nil {
+		return
+	}
+	n := notification.Notification{
+		OwnerSub:      p.OwnerSub,
+		Kind:          notification.KindPortfolioUpgraded,
+		PortfolioID:   &p.ID,
+		PortfolioSlug: &p.Slug,
+		RunID:         res.RunID,
+		Title:         p.Name + " was upgraded to " + p.StrategyCode + " " + res.ToVersion,
+		Body: "A patch release of " + p.StrategyCode + " was installed, so " + p.Name +
+			" moved from " + res.FromVersion + " to " + res.ToVersion + ".",
+		Data: map[string]any{"fromVersion": res.FromVersion, "toVersion": res.ToVersion},
+	}
+	if res.RunID != nil {
+		n.Body += " It is being recalculated."
+	}
+	if err := a.inbox.Notify(ctx, n); err != nil {
+		log.Warn().Err(err).Str("portfolio_id", p.ID.String()).Msg("auto-upgrade: notify owner")
+	}
+}
+
 // isPatchBump returns true when newVer differs from curVer only in the patch
 // component and is strictly greater. Pre-release identifiers are ignored:
 // 0.2.1-rc.1 → 0.2.2 still counts as a patch bump.
@@ -22,10 +22,21 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/penny-vault/pv-api/notification"
 	"github.com/penny-vault/pv-api/portfolio"
 	"github.com/penny-vault/pv-api/strategy"
 )
 
+// recordingInbox is a portfolio.Inbox that keeps what it was sent.
+type recordingInbox struct {
+	sent []notification.Notification
+}
+
+func (r *recordingInbox) Notify(_ context.Context, n notification.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
 var _ = Describe("AutoUpgrader", func() {
 	const shortCode = "adm"
 	describeJSON := []byte(`{"shortCode":"adm","name":"ADM","description":"","parameters":[{"name":"riskOn","type":"universe"}],"presets":[],"schedule":"@monthend","benchmark":"SPY"}`)
@@ -73,11 +84,27 @@ var _ = Describe("AutoUpgrader", func() {
 		Expect(disp.SubmitCalls).To(HaveLen(1))
 	})
 
+	It("notifies the owner of an applied upgrade", func() {
+		p := seedPortfolio("v0.2.1")
+		_, _, au := newSetup("v0.2.2", []portfolio.Portfolio{p})
+		inbox := &recordingInbox{}
+		au.WithInbox(inbox).AutoUpgradeAfterInstall(ctx, shortCode, "v0.2.2")
+		Expect(inbox.sent).To(HaveLen(1))
+		n := inbox.sent[0]
+		Expect(n.OwnerSub).To(Equal(p.OwnerSub))
+		Expect(n.Kind).To(Equal(notification.KindPortfolioUpgraded))
+		Expect(*n.PortfolioSlug).To(Equal(p.Slug))
+		Expect(n.RunID).NotTo(BeNil())
+		Expect(n.Data).To(HaveKeyWithValue("toVersion", "v0.2.2"))
+	})
+
 	It("skips a minor bump", func() {
 		st, disp, au := newSetup("v0.3.0", []portfolio.Portfolio{seedPortfolio("v0.2.1")})
-		au.AutoUpgradeAfterInstall(ctx, shortCode, "v0.3.0")
+		inbox := &recordingInbox{}
+		au.WithInbox(inbox).AutoUpgradeAfterInstall(ctx, shortCode, "v0.3.0")
 		Expect(st.ApplyUpgradeCalls).To(BeEmpty())
 		Expect(disp.SubmitCalls).To(BeEmpty())
+		Expect(inbox.sent).To(BeEmpty())
 	})
 
 	It("skips a major bump", func() {
@@ -0,0 +1,464 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/penny-vault/pvbt/tradecron"
+	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// calendarHorizon is how far ahead the calendar feeds list scheduled
+// rebalances.
+const calendarHorizon = 90 * 24 * time.Hour
+
+// ErrInvalidCalendarToken is returned for a calendar feed token that was
+// never issued or has been revoked.
+var ErrInvalidCalendarToken = errors.New("invalid calendar token")
+
+// ErrCalendarTokenNotFound is returned when the caller has no calendar feed.
+var ErrCalendarTokenNotFound = errors.New("calendar feed not found")
+
+var nyseLoc = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// CalendarToken records that a user has a per-user calendar feed. The
+// token itself is never stored.
+type CalendarToken struct {
+	OwnerSub  string
+	CreatedAt time.Time
+}
+
+// CalendarTokenStore persists per-user calendar feed tokens, one per user,
+// by the hash CalendarTokenHash returns.
+type CalendarTokenStore interface {
+	// GetCalendarToken returns ErrCalendarTokenNotFound if ownerSub has no
+	// feed.
+	GetCalendarToken(ctx context.Context, ownerSub string) (CalendarToken, error)
+	// SaveCalendarToken creates or replaces ownerSub's token.
+	SaveCalendarToken(ctx context.Context, ownerSub string, hash []byte) (CalendarToken, error)
+	// DeleteCalendarToken returns ErrCalendarTokenNotFound if ownerSub has
+	// no feed.
+	DeleteCalendarToken(ctx context.Context, ownerSub string) error
+	// ResolveCalendarToken returns the token stored under hash, or
+	// ErrInvalidCalendarToken.
+	ResolveCalendarToken(ctx context.Context, hash []byte) (CalendarToken, error)
+}
+
+// PoolCalendarTokenStore is the pgxpool-backed CalendarTokenStore.
+type PoolCalendarTokenStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolCalendarTokenStore(pool *pgxpool.Pool) *PoolCalendarTokenStore {
+	return &PoolCalendarTokenStore{pool: pool}
+}
+
+func (s *PoolCalendarTokenStore) GetCalendarToken(ctx context.Context, ownerSub string) (CalendarToken, error) {
+	t := CalendarToken{OwnerSub: ownerSub}
+	err := s.pool.QueryRow(ctx,
+		`SELECT created_at FROM calendar_tokens WHERE owner_sub = $1`, ownerSub).Scan(&t.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CalendarToken{}, ErrCalendarTokenNotFound
+	}
+	if err != nil {
+		return CalendarToken{}, fmt.Errorf("get calendar token: %w", err)
+	}
+	return t, nil
+}
+
+func (s *PoolCalendarTokenStore) SaveCalendarToken(ctx context.Context, ownerSub string, hash []byte) (CalendarToken, error) {
+	t := CalendarToken{OwnerSub: ownerSub}
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO calendar_tokens (owner_sub, token_hash) VALUES ($1, $2)
+		ON CONFLICT (owner_sub) DO UPDATE
+		   SET token_hash = EXCLUDED.token_hash, created_at = now()
+		RETURNING created_at`, ownerSub, hash).Scan(&t.CreatedAt)
+	if err != nil {
+		return CalendarToken{}, fmt.Errorf("save calendar token: %w", err)
+	}
+	return t, nil
+}
+
+func (s *PoolCalendarTokenStore) DeleteCalendarToken(ctx context.Context, ownerSub string) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM calendar_tokens WHERE owner_sub = $1`, ownerSub)
+	if err != nil {
+		return fmt.Errorf("delete calendar token: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCalendarTokenNotFound
+	}
+	return nil
+}
+
+func (s *PoolCalendarTokenStore) ResolveCalendarToken(ctx context.Context, hash []byte) (CalendarToken, error) {
+	var t CalendarToken
+	err := s.pool.QueryRow(ctx,
+		`SELECT owner_sub, created_at FROM calendar_tokens WHERE token_hash = $1`, hash).
+		Scan(&t.OwnerSub, &t.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return CalendarToken{}, ErrInvalidCalendarToken
+	}
+	if err != nil {
+		return CalendarToken{}, fmt.Errorf("resolve calendar token: %w", err)
+	}
+	return t, nil
+}
+
+// WithCalendarTokens sets the store for per-user calendar feed tokens and
+// the secret their stored hashes are keyed with. Without both the per-user
+// feed is disabled.
+func (h *Handler) WithCalendarTokens(s CalendarTokenStore, secret string) *Handler {
+	h.calendarTokens = s
+	h.calendarSecret = secret
+	return h
+}
+
+// NewCalendarToken returns a random, URL-safe calendar feed token.
+func NewCalendarToken() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("calendar token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// CalendarTokenHash is what is stored for token: an HMAC-SHA256 keyed with
+// the server's calendar secret, so a leaked table cannot be replayed
+// against the feed without the secret as well.
+func CalendarTokenHash(secret, token string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token))
+	return mac.Sum(nil)
+}
+
+// Calendar implements GET /portfolios/{slug}/calendar.ics: the portfolio's
+// upcoming rebalances as an iCalendar feed.
+func (h *Handler) Calendar(c fiber.Ctx) error {
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
+	}
+	slug := string([]byte(c.Params("slug")))
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	now := time.Now()
+	return writeCalendar(c, p.Name, h.calendarEvents(c.Context(), p, now), now)
+}
+
+func (h *Handler) calendarFeedsUnavailable(c fiber.Ctx) error {
+	return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable",
+		"calendar feeds are not configured on this server")
+}
+
+// calendarFeedJSON is the body of the /me/calendar endpoints. The URL is
+// only known when the token is issued.
+func calendarFeedJSON(t CalendarToken, feedURL string) map[string]any {
+	out := map[string]any{"createdAt": t.CreatedAt}
+	if feedURL != "" {
+		out["url"] = feedURL
+	}
+	return out
+}
+
+// CalendarFeed implements GET /me/calendar: whether the caller has a
+// per-user feed and when its URL was issued.
+func (h *Handler) CalendarFeed(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	if h.calendarTokens == nil || h.calendarSecret == "" {
+		return h.calendarFeedsUnavailable(c)
+	}
+	t, err := h.calendarTokens.GetCalendarToken(c.Context(), ownerSub)
+	if errors.Is(err, ErrCalendarTokenNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", err.Error())
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return writeJSON(c, fiber.StatusOK, calendarFeedJSON(t, ""))
+}
+
+// IssueCalendarFeed implements POST /me/calendar: it issues a new URL for
+// the caller's per-user feed, which covers every portfolio they own and
+// needs no Authorization header, so calendar apps can subscribe to it.
+// Any earlier URL stops working.
+func (h *Handler) IssueCalendarFeed(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	if h.calendarTokens == nil || h.calendarSecret == "" {
+		return h.calendarFeedsUnavailable(c)
+	}
+	tok, err := NewCalendarToken()
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	t, err := h.calendarTokens.SaveCalendarToken(c.Context(), ownerSub, CalendarTokenHash(h.calendarSecret, tok))
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return writeJSON(c, fiber.StatusCreated,
+		calendarFeedJSON(t, c.BaseURL()+"/api/calendar.ics?token="+url.QueryEscape(tok)))
+}
+
+// RevokeCalendarFeed implements DELETE /me/calendar: the caller's feed URL
+// stops working.
+func (h *Handler) RevokeCalendarFeed(c fiber.Ctx) error {
+	ownerSub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	if h.calendarTokens == nil || h.calendarSecret == "" {
+		return h.calendarFeedsUnavailable(c)
+	}
+	err = h.calendarTokens.DeleteCalendarToken(c.Context(), ownerSub)
+	if errors.Is(err, ErrCalendarTokenNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", err.Error())
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// PublicCalendar implements GET /api/calendar.ics?token=...: the per-user
+// feed. The token stands in for authentication.
+func (h *Handler) PublicCalendar(c fiber.Ctx) error {
+	if h.calendarTokens == nil || h.calendarSecret == "" {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "calendar feeds are not configured")
+	}
+	tok := c.Query("token")
+	if tok == "" {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", ErrInvalidCalendarToken.Error())
+	}
+	t, err := h.calendarTokens.ResolveCalendarToken(c.Context(), CalendarTokenHash(h.calendarSecret, tok))
+	if errors.Is(err, ErrInvalidCalendarToken) {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	rows, err := h.store.List(c.Context(), t.OwnerSub)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	now := time.Now()
+	var events []calendarEvent
+	for _, p := range rows {
+		events = append(events, h.calendarEvents(c.Context(), p, now)...)
+	}
+	return writeCalendar(c, "Penny Vault rebalances", events, now)
+}
+
+// calendarEvent is one all-day rebalance reminder.
+type calendarEvent struct {
+	uid         string
+	day         time.Time
+	summary     string
+	description string
+}
+
+// calendarEvents lists p's rebalances from today through calendarHorizon.
+// Dates come from the strategy's tradecron schedule; the snapshot's
+// prediction adds its trade date (if the schedule missed it) and the
+// expected trades.
+func (h *Handler) calendarEvents(ctx context.Context, p Portfolio, now time.Time) []calendarEvent {
+	today := calendarDay(now.In(nyseLoc))
+	byDay := map[time.Time]*calendarEvent{}
+	var order []time.Time
+	add := func(day time.Time) *calendarEvent {
+		if ev, ok := byDay[day]; ok {
+			return ev
+		}
+		ev := &calendarEvent{
+			uid:         fmt.Sprintf("%s-%s@pvapi", p.ID, day.Format("20060102")),
+			day:         day,
+			summary:     "Rebalance " + p.Name,
+			description: fmt.Sprintf("Scheduled rebalance for %s (%s).", p.Name, p.StrategyCode),
+		}
+		byDay[day] = ev
+		order = append(order, day)
+		return ev
+	}
+
+	for _, day := range scheduleDays(p, today, today.Add(calendarHorizon)) {
+		add(day)
+	}
+
+	if p.SnapshotPath != nil && *p.SnapshotPath != "" && h.opener != nil {
+		if lines, day, ok := h.predictedTrades(ctx, p); ok && !day.Before(today) {
+			ev := add(day)
+			ev.description += "\n\nExpected trades:\n" + strings.Join(lines, "\n")
+		}
+	}
+
+	out := make([]calendarEvent, 0, len(order))
+	for _, day := range order {
+		out = append(out, *byDay[day])
+	}
+	return out
+}
+
+// scheduleDays returns the days in [from, to) on which p's strategy
+// schedule fires. It returns none without a schedule or trading calendar.
+func scheduleDays(p Portfolio, from, to time.Time) []time.Time {
+	if len(p.StrategyDescribeJSON) == 0 || !tradecron.HolidaysInitialized() {
+		return nil
+	}
+	var d strategy.Describe
+	if err := json.Unmarshal(p.StrategyDescribeJSON, &d); err != nil || d.Schedule == "" {
+		return nil
+	}
+	tc, err := tradecron.New(d.Schedule, tradecron.RegularHours)
+	if err != nil {
+		log.Warn().Err(err).Str("schedule", d.Schedule).Str("portfolio", p.Slug).Msg("calendar: unparseable strategy schedule")
+		return nil
+	}
+	var days []time.Time
+	// Start just before midnight ET so a fire later on from is included.
+	probe := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, nyseLoc).Add(-time.Nanosecond)
+	for {
+		next := tc.Next(probe)
+		day := calendarDay(next)
+		if !day.Before(to) {
+			return days
+		}
+		if len(days) == 0 || !days[len(days)-1].Equal(day) {
+			days = append(days, day)
+		}
+		probe = next
+	}
+}
+
+// predictedTrades reads p's prediction and formats its trades, one per
+// line. ok is false when the snapshot has no prediction or no trades.
+func (h *Handler) predictedTrades(ctx context.Context, p Portfolio) ([]string, time.Time, bool) {
+	r, err := h.opener.Open(*p.SnapshotPath)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	defer func() { _ = r.Close() }()
+	pred, err := r.Prediction(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrSnapshotNotFound) {
+			log.Warn().Err(err).Str("portfolio", p.Slug).Msg("calendar: read prediction")
+		}
+		return nil, time.Time{}, false
+	}
+	if len(pred.Transactions) == 0 {
+		return nil, time.Time{}, false
+	}
+	lines := make([]string, 0, len(pred.Transactions))
+	for _, tx := range pred.Transactions {
+		ticker := "?"
+		if tx.Ticker != nil {
+			ticker = *tx.Ticker
+		}
+		line := tx.Type + " " + ticker
+		if tx.Quantity != nil {
+			line += fmt.Sprintf(": %.0f shares", *tx.Quantity)
+		}
+		if tx.Amount != nil {
+			line += fmt.Sprintf(" (~$%.2f)", *tx.Amount)
+		}
+		lines = append(lines, line)
+	}
+	return lines, calendarDay(pred.Date.Time), true
+}
+
+// calendarDay truncates t to its calendar date, as midnight UTC.
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// writeCalendar writes events as an RFC 5545 VCALENDAR.
+func writeCalendar(c fiber.Ctx, name string, events []calendarEvent, now time.Time) error {
+	var b strings.Builder
+	line := func(s string) {
+		// Fold at 75 octets, continuing with a leading space.
+		for len(s) > 75 {
+			cut := 75
+			for cut > 0 && !utf8Start(s[cut]) {
+				cut--
+			}
+			b.WriteString(s[:cut] + "\r\n")
+			s = " " + s[cut:]
+		}
+		b.WriteString(s + "\r\n")
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//Penny Vault//pv-api//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + icsEscape(name))
+	for _, ev := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + ev.uid)
+		line("DTSTAMP:" + stamp)
+		line("DTSTART;VALUE=DATE:" + ev.day.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + ev.day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + icsEscape(ev.summary))
+		line("DESCRIPTION:" + icsEscape(ev.description))
+		line("TRANSP:TRANSPARENT")
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.Status(fiber.StatusOK).SendString(b.String())
+}
+
+// icsEscape escapes a TEXT value per RFC 5545 section 3.3.11.
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// utf8Start reports whether b begins a UTF-8 sequence, so folding never
+// splits a multi-byte character.
+func utf8Start(b byte) bool { return b&0xC0 != 0x80 }
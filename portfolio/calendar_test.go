@@ -0,0 +1,209 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/penny-vault/pvbt/tradecron"
+
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// fakeCalendarTokens is an in-memory portfolio.CalendarTokenStore.
+type fakeCalendarTokens struct {
+	hashes map[string][]byte
+}
+
+func (f *fakeCalendarTokens) GetCalendarToken(_ context.Context, ownerSub string) (portfolio.CalendarToken, error) {
+	if _, ok := f.hashes[ownerSub]; !ok {
+		return portfolio.CalendarToken{}, portfolio.ErrCalendarTokenNotFound
+	}
+	return portfolio.CalendarToken{OwnerSub: ownerSub}, nil
+}
+
+func (f *fakeCalendarTokens) SaveCalendarToken(_ context.Context, ownerSub string, hash []byte) (portfolio.CalendarToken, error) {
+	if f.hashes == nil {
+		f.hashes = map[string][]byte{}
+	}
+	f.hashes[ownerSub] = hash
+	return portfolio.CalendarToken{OwnerSub: ownerSub, CreatedAt: time.Now()}, nil
+}
+
+func (f *fakeCalendarTokens) DeleteCalendarToken(_ context.Context, ownerSub string) error {
+	if _, ok := f.hashes[ownerSub]; !ok {
+		return portfolio.ErrCalendarTokenNotFound
+	}
+	delete(f.hashes, ownerSub)
+	return nil
+}
+
+func (f *fakeCalendarTokens) ResolveCalendarToken(_ context.Context, hash []byte) (portfolio.CalendarToken, error) {
+	for sub, h := range f.hashes {
+		if bytes.Equal(h, hash) {
+			return portfolio.CalendarToken{OwnerSub: sub}, nil
+		}
+	}
+	return portfolio.CalendarToken{}, portfolio.ErrInvalidCalendarToken
+}
+
+var _ = Describe("Calendar feeds", func() {
+	var (
+		app    *fiber.App
+		store  *fakeStore
+		reader *fakeSnapshotReader
+		sub    = "auth0|owner"
+		path   = "/fake/snap.sqlite"
+		secret = "calendar-secret"
+	)
+
+	do := func(method, target string) (int, string) {
+		resp, err := app.Test(httptest.NewRequest(method, target, nil))
+		Expect(err).NotTo(HaveOccurred())
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+	get := func(target string) (int, string) { return do("GET", target) }
+
+	// issue asks for a new feed URL and returns its path and query.
+	issue := func() string {
+		status, body := do("POST", "/me/calendar")
+		Expect(status).To(Equal(fiber.StatusCreated))
+		var feed struct {
+			URL string `json:"url"`
+		}
+		Expect(sonic.Unmarshal([]byte(body), &feed)).To(Succeed())
+		u, err := url.Parse(feed.URL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u.Path).To(Equal("/api/calendar.ics"))
+		return u.RequestURI()
+	}
+
+	BeforeEach(func() {
+		tradecron.SetMarketHolidays([]tradecron.MarketHoliday{
+			{Date: time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC)},
+		})
+		store = &fakeStore{}
+		reader = &fakeSnapshotReader{}
+		opener := &fakeSnapshotOpener{readers: map[string]portfolio.SnapshotReader{path: reader}}
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, opener, nil, nil, nil, strategy.EphemeralOptions{}).
+			WithCalendarTokens(&fakeCalendarTokens{}, secret)
+
+		app = fiber.New(fiber.Config{JSONEncoder: sonic.Marshal, JSONDecoder: sonic.Unmarshal})
+		app.Get("/api/calendar.ics", h.PublicCalendar)
+		authed := app.Group("", func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, sub)
+			return c.Next()
+		})
+		authed.Get("/portfolios/:slug/calendar.ics", h.Calendar)
+		authed.Get("/me/calendar", h.CalendarFeed)
+		authed.Post("/me/calendar", h.IssueCalendarFeed)
+		authed.Delete("/me/calendar", h.RevokeCalendarFeed)
+
+		store.rows = []portfolio.Portfolio{{
+			ID: uuid.Must(uuid.NewV7()), OwnerSub: sub, Slug: "s1", Name: "Core, Growth",
+			StrategyCode: "adm", StrategyDescribeJSON: []byte(`{"schedule":"@monthend"}`),
+			Status: portfolio.StatusReady, SnapshotPath: &path,
+		}}
+	})
+
+	It("lists scheduled rebalances and the predicted trades", func() {
+		day := time.Now().In(time.UTC).AddDate(0, 0, 2)
+		ticker := "QQQ"
+		qty := 30.0
+		reader.prediction = &openapi.PredictionResponse{
+			Date:         openapi_types.Date{Time: day},
+			Transactions: []openapi.PredictedTransaction{{Type: "buy", Ticker: &ticker, Quantity: &qty}},
+		}
+
+		status, body := get("/portfolios/s1/calendar.ics")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(HavePrefix("BEGIN:VCALENDAR\r\n"))
+		Expect(body).To(HaveSuffix("END:VCALENDAR\r\n"))
+		Expect(body).To(ContainSubstring(`SUMMARY:Rebalance Core\, Growth`))
+		Expect(body).To(ContainSubstring("DTSTART;VALUE=DATE:" + day.Format("20060102")))
+		Expect(strings.ReplaceAll(body, "\r\n ", "")).To(ContainSubstring(`Expected trades:\nbuy QQQ: 30 shares`))
+		// Three months of @monthend plus the predicted day.
+		Expect(strings.Count(body, "BEGIN:VEVENT")).To(BeNumerically(">=", 3))
+		for _, l := range strings.Split(body, "\r\n") {
+			Expect(len(l)).To(BeNumerically("<=", 75))
+		}
+	})
+
+	It("returns 404 for another owner's portfolio", func() {
+		store.rows[0].OwnerSub = "auth0|other"
+		status, _ := get("/portfolios/s1/calendar.ics")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("serves the per-user feed only with an issued token", func() {
+		status, _ := get("/me/calendar")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+
+		feed := issue()
+		status, body := get(feed)
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(ContainSubstring("Rebalance Core"))
+		status, _ = get("/me/calendar")
+		Expect(status).To(Equal(fiber.StatusOK))
+
+		status, _ = get("/api/calendar.ics")
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+		forged, err := portfolio.NewCalendarToken()
+		Expect(err).NotTo(HaveOccurred())
+		status, _ = get("/api/calendar.ics?token=" + forged)
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+	})
+
+	It("stops serving a feed URL once it is replaced or revoked", func() {
+		first := issue()
+		second := issue()
+		Expect(second).NotTo(Equal(first))
+		status, _ := get(first)
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+		status, _ = get(second)
+		Expect(status).To(Equal(fiber.StatusOK))
+
+		status, _ = do("DELETE", "/me/calendar")
+		Expect(status).To(Equal(fiber.StatusNoContent))
+		status, _ = get(second)
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+		status, _ = do("DELETE", "/me/calendar")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("keys stored token hashes with the calendar secret", func() {
+		tok, err := portfolio.NewCalendarToken()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(portfolio.CalendarTokenHash(secret, tok)).To(Equal(portfolio.CalendarTokenHash(secret, tok)))
+		Expect(portfolio.CalendarTokenHash("other", tok)).NotTo(Equal(portfolio.CalendarTokenHash(secret, tok)))
+	})
+})
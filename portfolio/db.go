@@ -27,12 +27,18 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog/log"
+
+	"github.com/penny-vault/pv-api/keyset"
 )
 
 // ErrNotFound is returned when a portfolio lookup does not match any row
 // owned by the calling user.
 var ErrNotFound = errors.New("portfolio not found")
 
+// ErrStale is returned by a conditional update or delete when the
+// portfolio has changed, or gone, since the version the caller read.
+var ErrStale = errors.New("the resource has changed since it was read; fetch it again and retry")
+
 // ErrDuplicateSlug is returned on (owner_sub, slug) unique-constraint hits.
 var ErrDuplicateSlug = errors.New("duplicate portfolio slug")
 
@@ -42,7 +48,7 @@ const portfolioColumns = `
 	preset_name, benchmark, start_date, end_date, status, last_run_at,
 	last_error, snapshot_path,
 	current_value, ytd_return, max_drawdown, sharpe, cagr_since_inception, inception_date,
-	created_at, updated_at, run_retention
+	created_at, updated_at, run_retention, tags
 `
 
 // List returns every portfolio owned by ownerSub, sorted newest-first.
@@ -67,6 +73,36 @@ func List(ctx context.Context, pool *pgxpool.Pool, ownerSub string) ([]Portfolio
 	return out, rows.Err()
 }
 
+// ListPage returns one page of ownerSub's portfolios for q: filtered,
+// ordered and resumed after q.Cursor in SQL, reading one row past the page
+// so the caller can tell whether another follows.
+func ListPage(ctx context.Context, pool *pgxpool.Pool, ownerSub string, q ListQuery) ([]Portfolio, error) {
+	var args keyset.Args
+	sql := `SELECT ` + portfolioColumns + ` FROM portfolios WHERE ` + q.where(ownerSub, &args) +
+		` ORDER BY ` + q.order().OrderBy()
+	if n := q.fetchLimit(); n > 0 {
+		sql += ` LIMIT ` + args.Add(n)
+	}
+	rows, err := pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying portfolio page: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Portfolio
+	for rows.Next() {
+		p, scanErr := scan(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating portfolio page: %w", err)
+	}
+	return out, nil
+}
+
 // ListByStrategyCode returns every portfolio (across all owners) whose
 // strategy_code equals shortCode. Used by the auto-upgrader after a new
 // strategy version installs to find candidates for upgrade.
@@ -117,12 +153,12 @@ func Insert(ctx context.Context, pool *pgxpool.Pool, p Portfolio) error {
 		INSERT INTO portfolios (
 			owner_sub, slug, name, strategy_code, strategy_ver,
 			strategy_clone_url, strategy_describe_json, parameters,
-			preset_name, benchmark, start_date, end_date, status, run_retention
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			preset_name, benchmark, start_date, end_date, status, run_retention, tags
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 	`, p.OwnerSub, p.Slug, p.Name, p.StrategyCode, p.StrategyVer,
 		p.StrategyCloneURL, p.StrategyDescribeJSON, paramsJSON,
 		p.PresetName, p.Benchmark, p.StartDate, p.EndDate,
-		string(p.Status), p.RunRetention)
+		string(p.Status), p.RunRetention, tagsOrEmpty(p.Tags))
 	if err != nil {
 		if uniqueViolation(err) {
 			return ErrDuplicateSlug
@@ -132,53 +168,84 @@ func Insert(ctx context.Context, pool *pgxpool.Pool, p Portfolio) error {
 	return nil
 }
 
-// UpdateName updates a portfolio's display name. Returns ErrNotFound if
-// the (ownerSub, slug) pair does not match any row.
-func UpdateName(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug, name string) error {
+// Changes are the editable fields a PATCH sets on a portfolio. Nil fields
+// are left as they are.
+type Changes struct {
+	Name         *string
+	StartDate    *time.Time
+	EndDate      *time.Time
+	RunRetention *int
+	Tags         *[]string
+}
+
+// Empty reports whether ch changes nothing.
+func (ch Changes) Empty() bool {
+	return ch.Name == nil && ch.StartDate == nil && ch.EndDate == nil &&
+		ch.RunRetention == nil && ch.Tags == nil
+}
+
+// Update applies ch to a portfolio in one statement. When ifUpdatedAt is
+// set the row is only written if its updated_at still equals it, and
+// ErrStale is returned otherwise; without it, ErrNotFound is returned if
+// the (ownerSub, slug) pair does not match any row. The caller must ensure
+// end is not before start; no validation is performed here.
+func Update(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug string, ch Changes, ifUpdatedAt *time.Time) error {
+	var tags []string
+	if ch.Tags != nil {
+		tags = tagsOrEmpty(*ch.Tags)
+	}
 	tag, err := pool.Exec(ctx, `
 		UPDATE portfolios
-		   SET name = $3, updated_at = NOW()
+		   SET name          = COALESCE($3, name),
+		       start_date    = COALESCE($4, start_date),
+		       end_date      = COALESCE($5, end_date),
+		       run_retention = COALESCE($6, run_retention),
+		       tags          = COALESCE($7::text[], tags),
+		       updated_at    = NOW()
 		 WHERE owner_sub = $1 AND slug = $2
-	`, ownerSub, slug, name)
+		   AND ($8::timestamptz IS NULL OR updated_at = $8)
+	`, ownerSub, slug, ch.Name, ch.StartDate, ch.EndDate, ch.RunRetention, tags, ifUpdatedAt)
 	if err != nil {
-		return fmt.Errorf("updating portfolio name: %w", err)
+		return fmt.Errorf("updating portfolio: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
+	return notFoundOrStale(tag.RowsAffected(), ifUpdatedAt)
+}
+
+// notFoundOrStale maps a write that touched no rows to ErrStale when it
+// was conditional on a version and to ErrNotFound otherwise.
+func notFoundOrStale(rows int64, ifUpdatedAt *time.Time) error {
+	switch {
+	case rows > 0:
+		return nil
+	case ifUpdatedAt != nil:
+		return ErrStale
+	default:
 		return ErrNotFound
 	}
-	return nil
 }
 
-// UpdateDates updates a portfolio's start_date and/or end_date.
-// Only non-nil values overwrite existing ones. Returns ErrNotFound if no row matched.
-// The caller must ensure end is not before start; no validation is performed here.
-func UpdateDates(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug string, startDate, endDate *time.Time) error {
-	tag, err := pool.Exec(ctx, `
-		UPDATE portfolios
-		   SET start_date = COALESCE($3, start_date),
-		       end_date   = COALESCE($4, end_date),
-		       updated_at = NOW()
-		 WHERE owner_sub = $1 AND slug = $2
-	`, ownerSub, slug, startDate, endDate)
-	if err != nil {
-		return fmt.Errorf("updating portfolio dates: %w", err)
+// tagsOrEmpty keeps a nil slice from being written as NULL.
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return tags
 }
 
-// UpdateRunRetention updates a portfolio's run_retention. Returns ErrNotFound
-// if the (ownerSub, slug) pair does not match any row.
-func UpdateRunRetention(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug string, value int) error {
+// UpdateOwner moves a portfolio to another owner, such as a workspace.
+// Returns ErrNotFound if the (ownerSub, slug) pair does not match any row
+// and ErrDuplicateSlug if the new owner already has the slug.
+func UpdateOwner(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug, newOwnerSub string) error {
 	tag, err := pool.Exec(ctx, `
 		UPDATE portfolios
-		   SET run_retention = $3, updated_at = NOW()
+		   SET owner_sub = $3, updated_at = NOW()
 		 WHERE owner_sub = $1 AND slug = $2
-	`, ownerSub, slug, value)
+	`, ownerSub, slug, newOwnerSub)
+	if uniqueViolation(err) {
+		return ErrDuplicateSlug
+	}
 	if err != nil {
-		return fmt.Errorf("updating run_retention: %w", err)
+		return fmt.Errorf("updating portfolio owner: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
 		return ErrNotFound
@@ -186,20 +253,21 @@ func UpdateRunRetention(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug
 	return nil
 }
 
-// Delete removes a portfolio by (ownerSub, slug). Returns ErrNotFound if
-// no row was deleted.
-func Delete(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug string) error {
-	tag, err := pool.Exec(ctx,
-		`DELETE FROM portfolios WHERE owner_sub = $1 AND slug = $2`,
-		ownerSub, slug,
+// Delete removes a portfolio by (ownerSub, slug). When ifUpdatedAt is set
+// the row is only deleted if its updated_at still equals it, and ErrStale
+// is returned otherwise; without it, ErrNotFound is returned if no row was
+// deleted.
+func Delete(ctx context.Context, pool *pgxpool.Pool, ownerSub, slug string, ifUpdatedAt *time.Time) error {
+	tag, err := pool.Exec(ctx, `
+		DELETE FROM portfolios
+		 WHERE owner_sub = $1 AND slug = $2
+		   AND ($3::timestamptz IS NULL OR updated_at = $3)`,
+		ownerSub, slug, ifUpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("deleting portfolio: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNotFound
-	}
-	return nil
+	return notFoundOrStale(tag.RowsAffected(), ifUpdatedAt)
 }
 
 // GetByID fetches a portfolio by id without owner scoping. Used by
@@ -577,7 +645,7 @@ func scan(r scanner) (Portfolio, error) {
 		&statusStr, &p.LastRunAt, &p.LastError, &p.SnapshotPath,
 		&p.CurrentValue, &p.YtdReturn, &p.MaxDrawdown, &p.Sharpe,
 		&p.CagrSinceInception, &p.InceptionDate,
-		&p.CreatedAt, &p.UpdatedAt, &p.RunRetention,
+		&p.CreatedAt, &p.UpdatedAt, &p.RunRetention, &p.Tags,
 	)
 	if err != nil {
 		return Portfolio{}, err
@@ -0,0 +1,173 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package portfolio_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// racingStore lets two requests read the same version before either
+// writes, the interleaving a version check alone cannot stop.
+type racingStore struct {
+	*fakeStore
+	mu    sync.Mutex
+	reads atomic.Int32
+	read  sync.WaitGroup
+}
+
+func newRacingStore(f *fakeStore) *racingStore {
+	s := &racingStore{fakeStore: f}
+	s.read.Add(2)
+	return s
+}
+
+func (s *racingStore) Get(ctx context.Context, ownerSub, slug string) (portfolio.Portfolio, error) {
+	s.mu.Lock()
+	p, err := s.fakeStore.Get(ctx, ownerSub, slug)
+	s.mu.Unlock()
+	if s.reads.Add(1) <= 2 {
+		s.read.Done()
+		s.read.Wait()
+	}
+	return p, err
+}
+
+func (s *racingStore) Update(ctx context.Context, ownerSub, slug string, ch portfolio.Changes, ifUpdatedAt *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fakeStore.Update(ctx, ownerSub, slug, ch, ifUpdatedAt)
+}
+
+var _ = Describe("ETags", func() {
+	var (
+		app   *fiber.App
+		store *fakeStore
+		runID uuid.UUID
+	)
+
+	BeforeEach(func() {
+		runID = uuid.Must(uuid.NewV7())
+		path := "/snapshots/p/" + runID.String() + ".sqlite"
+		store = &fakeStore{rows: []portfolio.Portfolio{{
+			ID: uuid.Must(uuid.NewV7()), OwnerSub: "auth0|user-1", Slug: "mine", Name: "Mine",
+			Status: portfolio.StatusReady, SnapshotPath: &path,
+			UpdatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		}}}
+		opener := &fakeSnapshotOpener{readers: map[string]portfolio.SnapshotReader{
+			path: &fakeSnapshotReader{summary: &openapi.PortfolioSummary{CurrentValue: 100}},
+		}}
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, opener, nil, nil, nil, strategy.EphemeralOptions{})
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|user-1")
+			return c.Next()
+		})
+		app.Get("/portfolios/:slug", h.Get)
+		app.Patch("/portfolios/:slug", h.Patch)
+		app.Delete("/portfolios/:slug", h.Delete)
+		app.Get("/portfolios/:slug/summary", h.Summary)
+	})
+
+	do := func(method, target, body string, header map[string]string) (int, string) {
+		req := httptest.NewRequest(method, target, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode, resp.Header.Get("ETag")
+	}
+
+	It("tags a portfolio with its version and answers 304 when it is unchanged", func() {
+		status, tag := do("GET", "/portfolios/mine", "", nil)
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(tag).NotTo(BeEmpty())
+
+		status, _ = do("GET", "/portfolios/mine", "", map[string]string{"If-None-Match": tag})
+		Expect(status).To(Equal(fiber.StatusNotModified))
+	})
+
+	It("refuses an edit or delete based on a stale version with 412", func() {
+		_, tag := do("GET", "/portfolios/mine", "", nil)
+		store.rows[0].UpdatedAt = store.rows[0].UpdatedAt.Add(time.Second)
+
+		status, _ := do("PATCH", "/portfolios/mine", `{"name":"Theirs"}`, map[string]string{"If-Match": tag})
+		Expect(status).To(Equal(fiber.StatusPreconditionFailed))
+		Expect(store.rows[0].Name).To(Equal("Mine"))
+
+		status, _ = do("DELETE", "/portfolios/mine", "", map[string]string{"If-Match": tag})
+		Expect(status).To(Equal(fiber.StatusPreconditionFailed))
+		Expect(store.rows).To(HaveLen(1))
+	})
+
+	It("applies an edit whose If-Match is current", func() {
+		_, tag := do("GET", "/portfolios/mine", "", nil)
+		status, _ := do("PATCH", "/portfolios/mine", `{"name":"Ours"}`, map[string]string{"If-Match": tag})
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(store.rows[0].Name).To(Equal("Ours"))
+	})
+
+	It("lets only one of two concurrent edits with the same If-Match through", func() {
+		_, tag := do("GET", "/portfolios/mine", "", nil)
+
+		racing := newRacingStore(store)
+		h := portfolio.NewHandler(racing, &fakeStrategyStore{}, nil, nil, nil, nil, strategy.EphemeralOptions{})
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|user-1")
+			return c.Next()
+		})
+		app.Patch("/portfolios/:slug", h.Patch)
+
+		statuses := make(chan int, 2)
+		for _, name := range []string{"Ours", "Theirs"} {
+			go func() {
+				defer GinkgoRecover()
+				status, _ := do("PATCH", "/portfolios/mine", `{"name":"`+name+`"}`, map[string]string{"If-Match": tag})
+				statuses <- status
+			}()
+		}
+		Expect([]int{<-statuses, <-statuses}).To(ConsistOf(fiber.StatusOK, fiber.StatusPreconditionFailed))
+	})
+
+	It("keys snapshot reads by the active run", func() {
+		status, tag := do("GET", "/portfolios/mine/summary", "", nil)
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(tag).To(ContainSubstring(runID.String()))
+
+		status, _ = do("GET", "/portfolios/mine/summary", "", map[string]string{"If-None-Match": tag})
+		Expect(status).To(Equal(fiber.StatusNotModified))
+
+		status, _ = do("GET", "/portfolios/mine/summary", "", map[string]string{"If-None-Match": `W/"` + uuid.NewString() + `"`})
+		Expect(status).To(Equal(fiber.StatusOK))
+	})
+})
@@ -0,0 +1,127 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrFillNotFound is returned when a fill does not exist on the portfolio.
+var ErrFillNotFound = errors.New("fill not found")
+
+// Fill is a trade the user executed at their broker to mirror one of the
+// strategy's trades.
+type Fill struct {
+	ID          uuid.UUID
+	PortfolioID uuid.UUID
+	Date        time.Time // the strategy's trade date
+	Type        string    // buy | sell
+	Ticker      string
+	Figi        *string
+	Quantity    float64
+	Price       float64
+	// PredictedQuantity and PredictedPrice are the prediction's figures
+	// when the fill was recorded, nil if the trade was not predicted.
+	PredictedQuantity *float64
+	PredictedPrice    *float64
+	ExecutedAt        time.Time
+	Note              string
+	CreatedAt         time.Time
+}
+
+// FillStore persists manual trade fills.
+type FillStore interface {
+	ListFills(ctx context.Context, portfolioID uuid.UUID) ([]Fill, error)
+	// SaveFill records f, replacing any earlier fill of the same trade. The
+	// earlier fill's predicted figures are kept when f has none, since the
+	// prediction is gone once the backtest reaches the trade date.
+	SaveFill(ctx context.Context, f Fill) (Fill, error)
+	DeleteFill(ctx context.Context, portfolioID, fillID uuid.UUID) error
+}
+
+// PoolFillStore is the pgxpool-backed FillStore.
+type PoolFillStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolFillStore(pool *pgxpool.Pool) *PoolFillStore { return &PoolFillStore{pool: pool} }
+
+const fillColumns = `id, portfolio_id, trade_date, type, ticker, figi, quantity, price,
+	predicted_quantity, predicted_price, executed_at, note, created_at`
+
+func scanFill(row pgx.Row) (Fill, error) {
+	var f Fill
+	err := row.Scan(&f.ID, &f.PortfolioID, &f.Date, &f.Type, &f.Ticker, &f.Figi, &f.Quantity, &f.Price,
+		&f.PredictedQuantity, &f.PredictedPrice, &f.ExecutedAt, &f.Note, &f.CreatedAt)
+	return f, err
+}
+
+func (s *PoolFillStore) ListFills(ctx context.Context, portfolioID uuid.UUID) ([]Fill, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT `+fillColumns+` FROM portfolio_fills WHERE portfolio_id = $1 ORDER BY trade_date, created_at`,
+		portfolioID)
+	if err != nil {
+		return nil, fmt.Errorf("list fills: %w", err)
+	}
+	defer rows.Close()
+	out := []Fill{}
+	for rows.Next() {
+		f, err := scanFill(rows)
+		if err != nil {
+			return nil, fmt.Errorf("list fills: %w", err)
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+func (s *PoolFillStore) SaveFill(ctx context.Context, in Fill) (Fill, error) {
+	f, err := scanFill(s.pool.QueryRow(ctx, `
+		INSERT INTO portfolio_fills (portfolio_id, trade_date, type, ticker, figi, quantity, price,
+		                             predicted_quantity, predicted_price, executed_at, note)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (portfolio_id, trade_date, type, ticker) DO UPDATE
+		   SET figi = EXCLUDED.figi, quantity = EXCLUDED.quantity, price = EXCLUDED.price,
+		       predicted_quantity = COALESCE(EXCLUDED.predicted_quantity, portfolio_fills.predicted_quantity),
+		       predicted_price = COALESCE(EXCLUDED.predicted_price, portfolio_fills.predicted_price),
+		       executed_at = EXCLUDED.executed_at, note = EXCLUDED.note
+		RETURNING `+fillColumns,
+		in.PortfolioID, in.Date, in.Type, in.Ticker, in.Figi, in.Quantity, in.Price,
+		in.PredictedQuantity, in.PredictedPrice, in.ExecutedAt, in.Note))
+	if err != nil {
+		return Fill{}, fmt.Errorf("save fill: %w", err)
+	}
+	return f, nil
+}
+
+func (s *PoolFillStore) DeleteFill(ctx context.Context, portfolioID, fillID uuid.UUID) error {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM portfolio_fills WHERE portfolio_id = $1 AND id = $2`, portfolioID, fillID)
+	if err != nil {
+		return fmt.Errorf("delete fill: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrFillNotFound
+	}
+	return nil
+}
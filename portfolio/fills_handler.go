@@ -0,0 +1,495 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// Fill bases: what a fill's model figures were taken from.
+const (
+	FillBasisBacktest   = "backtest"
+	FillBasisPrediction = "prediction"
+	FillBasisUnmatched  = "unmatched"
+)
+
+// WithFills sets the store for manual trade fills. Without one the fill,
+// checklist and tracking endpoints answer 503.
+func (h *Handler) WithFills(s FillStore) *Handler {
+	h.fills = s
+	return h
+}
+
+// tradeKey identifies a trade by date, side and ticker.
+func tradeKey(day time.Time, typ, ticker string) string {
+	return day.Format("2006-01-02") + "|" + typ + "|" + strings.ToUpper(ticker)
+}
+
+// isOrder reports whether a transaction type is one a user places at their
+// broker; dividends, deposits and the like are not checklist items.
+func isOrder(typ string) bool { return typ == "buy" || typ == "sell" }
+
+func (h *Handler) fillsUnavailable(c fiber.Ctx) error {
+	return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable",
+		"trade fills are not configured on this server")
+}
+
+// Checklist implements GET /portfolios/{slug}/checklist: the predicted
+// trades for the next rebalance, each with the fill recorded for it, if any.
+func (h *Handler) Checklist(c fiber.Ctx) error {
+	if h.fills == nil {
+		return h.fillsUnavailable(c)
+	}
+	return h.readPortfolioSnapshot(c, func(p Portfolio, r SnapshotReader) (any, error) {
+		pred, err := r.Prediction(c.Context())
+		if errors.Is(err, ErrSnapshotNotFound) {
+			return nil, errNotFoundSentinel
+		}
+		if err != nil {
+			return nil, err
+		}
+		fills, err := h.fills.ListFills(c.Context(), p.ID)
+		if err != nil {
+			return nil, err
+		}
+		byKey := make(map[string]Fill, len(fills))
+		for _, f := range fills {
+			byKey[tradeKey(f.Date, f.Type, f.Ticker)] = f
+		}
+		out := checklistView{Date: pred.Date.Format("2006-01-02"), Items: []checklistItem{}}
+		for _, tx := range pred.Transactions {
+			if !isOrder(tx.Type) || tx.Ticker == nil {
+				continue
+			}
+			item := checklistItem{
+				Type:          tx.Type,
+				Ticker:        *tx.Ticker,
+				Figi:          tx.Figi,
+				Quantity:      tx.Quantity,
+				Price:         tx.Price,
+				Amount:        tx.Amount,
+				Justification: tx.Justification,
+			}
+			if f, ok := byKey[tradeKey(pred.Date.Time, tx.Type, *tx.Ticker)]; ok {
+				v := toFillView(f)
+				item.Executed = true
+				item.Fill = &v
+			}
+			out.Items = append(out.Items, item)
+		}
+		return out, nil
+	})
+}
+
+// ListFills implements GET /portfolios/{slug}/fills.
+func (h *Handler) ListFills(c fiber.Ctx) error {
+	if h.fills == nil {
+		return h.fillsUnavailable(c)
+	}
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
+	}
+	slug := string([]byte(c.Params("slug")))
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	fills, err := h.fills.ListFills(c.Context(), p.ID)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	out := make([]fillView, 0, len(fills))
+	for _, f := range fills {
+		out = append(out, toFillView(f))
+	}
+	return writeJSON(c, fiber.StatusOK, map[string]any{"items": out})
+}
+
+type fillBody struct {
+	Date       string  `json:"date"`
+	Type       string  `json:"type"`
+	Ticker     string  `json:"ticker"`
+	Quantity   float64 `json:"quantity"`
+	Price      float64 `json:"price"`
+	ExecutedAt *string `json:"executedAt"`
+	Note       string  `json:"note"`
+}
+
+// CreateFill implements POST /portfolios/{slug}/fills: it marks one of the
+// strategy's trades as executed at the given price and quantity. The trade
+// must be in the prediction or the backtest's transactions; date defaults
+// to the prediction's date. Marking a trade again replaces its fill.
+func (h *Handler) CreateFill(c fiber.Ctx) error {
+	if h.fills == nil {
+		return h.fillsUnavailable(c)
+	}
+	ownerSub, ok, err := h.owner(c, workspace.RoleEditor)
+	if !ok {
+		return err
+	}
+	var body fillBody
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid body", err.Error())
+	}
+	body.Type = strings.ToLower(strings.TrimSpace(body.Type))
+	body.Ticker = strings.ToUpper(strings.TrimSpace(body.Ticker))
+	switch {
+	case !isOrder(body.Type):
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid type", "type must be buy or sell")
+	case body.Ticker == "":
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "ticker required", "ticker is required")
+	case body.Quantity <= 0:
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid quantity", "quantity must be positive")
+	case body.Price <= 0:
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid price", "price must be positive")
+	}
+	var date *time.Time
+	if body.Date != "" {
+		if date, err = parseDate(body.Date); err != nil {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid date", err.Error())
+		}
+	}
+	executedAt := time.Now()
+	if body.ExecutedAt != nil {
+		if executedAt, err = time.Parse(time.RFC3339, *body.ExecutedAt); err != nil {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid executedAt",
+				"executedAt must be an RFC 3339 timestamp")
+		}
+	}
+
+	slug := string([]byte(c.Params("slug")))
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if p.Status != StatusReady || p.SnapshotPath == nil || *p.SnapshotPath == "" {
+		return h.respondRecalculating(c, p, slug)
+	}
+	reader, err := h.opener.Open(*p.SnapshotPath)
+	if err != nil {
+		return h.respondRecalculating(c, p, slug)
+	}
+	defer func() { _ = reader.Close() }()
+
+	f := Fill{
+		PortfolioID: p.ID,
+		Type:        body.Type,
+		Ticker:      body.Ticker,
+		Quantity:    body.Quantity,
+		Price:       body.Price,
+		ExecutedAt:  executedAt,
+		Note:        body.Note,
+	}
+	matched, err := matchTrade(c, reader, date, &f)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if !matched {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "unknown trade",
+			"the strategy has no predicted or backtest "+f.Type+" of "+f.Ticker+" on that date")
+	}
+	saved, err := h.fills.SaveFill(c.Context(), f)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return writeJSON(c, fiber.StatusCreated, toFillView(saved))
+}
+
+// matchTrade finds the strategy trade f mirrors and fills in its date, FIGI
+// and predicted figures. It looks in the prediction first, then in the
+// backtest's transactions on date.
+func matchTrade(c fiber.Ctx, r SnapshotReader, date *time.Time, f *Fill) (bool, error) {
+	pred, err := r.Prediction(c.Context())
+	if err != nil && !errors.Is(err, ErrSnapshotNotFound) {
+		return false, err
+	}
+	if pred != nil {
+		predDay := calendarDay(pred.Date.Time)
+		if date == nil {
+			date = &predDay
+		}
+		if predDay.Equal(calendarDay(*date)) {
+			for _, tx := range pred.Transactions {
+				if tx.Type == f.Type && tx.Ticker != nil && strings.EqualFold(*tx.Ticker, f.Ticker) {
+					f.Date = predDay
+					f.Figi = tx.Figi
+					f.PredictedQuantity = tx.Quantity
+					f.PredictedPrice = tx.Price
+					return true, nil
+				}
+			}
+		}
+	}
+	if date == nil {
+		return false, nil
+	}
+	day := calendarDay(*date)
+	txs, err := r.Transactions(c.Context(), SnapshotTxFilter{From: &day, To: &day, Types: []string{f.Type}})
+	if err != nil {
+		return false, err
+	}
+	if txs == nil {
+		return false, nil
+	}
+	for _, tx := range txs.Items {
+		if string(tx.Type) == f.Type && tx.Ticker != nil && strings.EqualFold(*tx.Ticker, f.Ticker) &&
+			calendarDay(tx.Date.Time).Equal(day) {
+			f.Date = day
+			f.Figi = tx.Figi
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteFill implements DELETE /portfolios/{slug}/fills/{fillId}.
+func (h *Handler) DeleteFill(c fiber.Ctx) error {
+	if h.fills == nil {
+		return h.fillsUnavailable(c)
+	}
+	ownerSub, ok, err := h.owner(c, workspace.RoleEditor)
+	if !ok {
+		return err
+	}
+	slug := string([]byte(c.Params("slug")))
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	fillID, err := uuid.Parse(c.Params("fillId"))
+	if err != nil {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "fill not found")
+	}
+	err = h.fills.DeleteFill(c.Context(), p.ID, fillID)
+	if errors.Is(err, ErrFillNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "fill not found")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Tracking implements GET /portfolios/{slug}/tracking: how far the user's
+// fills have drifted from the model. Each fill is compared with the
+// backtest's trades on its date, or with the prediction it was recorded
+// against when the backtest has not reached that date. Backtest trades on
+// or after the first fill that have no fill are listed as missed.
+func (h *Handler) Tracking(c fiber.Ctx) error {
+	if h.fills == nil {
+		return h.fillsUnavailable(c)
+	}
+	return h.readPortfolioSnapshot(c, func(p Portfolio, r SnapshotReader) (any, error) {
+		fills, err := h.fills.ListFills(c.Context(), p.ID)
+		if err != nil {
+			return nil, err
+		}
+		out := trackingView{Items: []trackingRow{}, MissedTrades: []missedTrade{}}
+		if len(fills) == 0 {
+			return out, nil
+		}
+		first := fills[0].Date
+		for _, f := range fills[1:] {
+			if f.Date.Before(first) {
+				first = f.Date
+			}
+		}
+		txs, err := r.Transactions(c.Context(), SnapshotTxFilter{From: &first, Types: []string{"buy", "sell"}})
+		if err != nil {
+			return nil, err
+		}
+		model := map[string]*modelTrade{}
+		var order []string
+		if txs != nil {
+			for _, tx := range txs.Items {
+				if !isOrder(string(tx.Type)) || tx.Ticker == nil || tx.Date.Time.Before(first) {
+					continue
+				}
+				key := tradeKey(tx.Date.Time, string(tx.Type), *tx.Ticker)
+				m, ok := model[key]
+				if !ok {
+					m = &modelTrade{date: calendarDay(tx.Date.Time), typ: string(tx.Type), ticker: *tx.Ticker}
+					model[key] = m
+					order = append(order, key)
+				}
+				if tx.Quantity != nil && tx.Price != nil {
+					m.quantity += *tx.Quantity
+					m.notional += *tx.Quantity * *tx.Price
+				}
+			}
+		}
+
+		filled := make(map[string]bool, len(fills))
+		var notional float64
+		for _, f := range fills {
+			key := tradeKey(f.Date, f.Type, f.Ticker)
+			filled[key] = true
+			row := trackingRow{Fill: toFillView(f), Basis: FillBasisUnmatched}
+			var qty, price float64
+			switch m := model[key]; {
+			case m != nil && m.quantity > 0:
+				row.Basis = FillBasisBacktest
+				qty, price = m.quantity, m.notional/m.quantity
+			case f.PredictedQuantity != nil && f.PredictedPrice != nil && *f.PredictedQuantity > 0:
+				row.Basis = FillBasisPrediction
+				qty, price = *f.PredictedQuantity, *f.PredictedPrice
+			}
+			if row.Basis != FillBasisUnmatched {
+				out.Matched++
+				impact := (f.Price - price) * f.Quantity
+				if f.Type == "sell" {
+					impact = -impact
+				}
+				qtyDiff, priceDiff := f.Quantity-qty, f.Price-price
+				row.ModelQuantity, row.ModelPrice = &qty, &price
+				row.QuantityDiff, row.PriceDiff = &qtyDiff, &priceDiff
+				row.CostImpact = &impact
+				if n := price * f.Quantity; n > 0 {
+					bps := impact / n * 1e4
+					row.CostImpactBps = &bps
+					notional += n
+				}
+				out.TotalCostImpact += impact
+			}
+			out.Items = append(out.Items, row)
+		}
+		out.Fills = len(fills)
+		if notional > 0 {
+			bps := out.TotalCostImpact / notional * 1e4
+			out.CostImpactBps = &bps
+		}
+		for _, key := range order {
+			if filled[key] {
+				continue
+			}
+			m := model[key]
+			mt := missedTrade{Date: m.date.Format("2006-01-02"), Type: m.typ, Ticker: m.ticker}
+			if m.quantity > 0 {
+				qty, price := m.quantity, m.notional/m.quantity
+				mt.Quantity, mt.Price = &qty, &price
+			}
+			out.MissedTrades = append(out.MissedTrades, mt)
+		}
+		slices.SortStableFunc(out.MissedTrades, func(a, b missedTrade) int { return strings.Compare(a.Date, b.Date) })
+		return out, nil
+	})
+}
+
+// modelTrade aggregates the backtest's trades of one ticker and side on one
+// day, which a strategy may split across several transactions.
+type modelTrade struct {
+	date     time.Time
+	typ      string
+	ticker   string
+	quantity float64
+	notional float64
+}
+
+type fillView struct {
+	ID                string   `json:"id"`
+	Date              string   `json:"date"`
+	Type              string   `json:"type"`
+	Ticker            string   `json:"ticker"`
+	Figi              *string  `json:"figi"`
+	Quantity          float64  `json:"quantity"`
+	Price             float64  `json:"price"`
+	PredictedQuantity *float64 `json:"predictedQuantity"`
+	PredictedPrice    *float64 `json:"predictedPrice"`
+	ExecutedAt        string   `json:"executedAt"`
+	Note              string   `json:"note"`
+	CreatedAt         string   `json:"createdAt"`
+}
+
+func toFillView(f Fill) fillView {
+	return fillView{
+		ID:                f.ID.String(),
+		Date:              f.Date.Format("2006-01-02"),
+		Type:              f.Type,
+		Ticker:            f.Ticker,
+		Figi:              f.Figi,
+		Quantity:          f.Quantity,
+		Price:             f.Price,
+		PredictedQuantity: f.PredictedQuantity,
+		PredictedPrice:    f.PredictedPrice,
+		ExecutedAt:        f.ExecutedAt.UTC().Format(time.RFC3339),
+		Note:              f.Note,
+		CreatedAt:         f.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+type checklistItem struct {
+	Type          string    `json:"type"`
+	Ticker        string    `json:"ticker"`
+	Figi          *string   `json:"figi"`
+	Quantity      *float64  `json:"quantity"`
+	Price         *float64  `json:"price"`
+	Amount        *float64  `json:"amount"`
+	Justification *string   `json:"justification"`
+	Executed      bool      `json:"executed"`
+	Fill          *fillView `json:"fill"`
+}
+
+type checklistView struct {
+	Date  string          `json:"date"`
+	Items []checklistItem `json:"items"`
+}
+
+type trackingRow struct {
+	Fill          fillView `json:"fill"`
+	Basis         string   `json:"basis"`
+	ModelQuantity *float64 `json:"modelQuantity"`
+	ModelPrice    *float64 `json:"modelPrice"`
+	QuantityDiff  *float64 `json:"quantityDiff"`
+	PriceDiff     *float64 `json:"priceDiff"`
+	CostImpact    *float64 `json:"costImpact"`
+	CostImpactBps *float64 `json:"costImpactBps"`
+}
+
+type missedTrade struct {
+	Date     string   `json:"date"`
+	Type     string   `json:"type"`
+	Ticker   string   `json:"ticker"`
+	Quantity *float64 `json:"quantity"`
+	Price    *float64 `json:"price"`
+}
+
+type trackingView struct {
+	Fills           int           `json:"fills"`
+	Matched         int           `json:"matched"`
+	TotalCostImpact float64       `json:"totalCostImpact"`
+	CostImpactBps   *float64      `json:"costImpactBps"`
+	Items           []trackingRow `json:"items"`
+	MissedTrades    []missedTrade `json:"missedTrades"`
+}
@@ -0,0 +1,247 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// fakeFillStore is an in-memory portfolio.FillStore keyed like the table's
+// unique constraint.
+type fakeFillStore struct {
+	fills []portfolio.Fill
+}
+
+func (f *fakeFillStore) ListFills(_ context.Context, portfolioID uuid.UUID) ([]portfolio.Fill, error) {
+	out := []portfolio.Fill{}
+	for _, fl := range f.fills {
+		if fl.PortfolioID == portfolioID {
+			out = append(out, fl)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeFillStore) SaveFill(_ context.Context, in portfolio.Fill) (portfolio.Fill, error) {
+	for i, fl := range f.fills {
+		if fl.PortfolioID == in.PortfolioID && fl.Date.Equal(in.Date) && fl.Type == in.Type && fl.Ticker == in.Ticker {
+			in.ID, in.CreatedAt = fl.ID, fl.CreatedAt
+			if in.PredictedQuantity == nil {
+				in.PredictedQuantity, in.PredictedPrice = fl.PredictedQuantity, fl.PredictedPrice
+			}
+			f.fills[i] = in
+			return in, nil
+		}
+	}
+	in.ID = uuid.Must(uuid.NewV7())
+	in.CreatedAt = time.Now()
+	f.fills = append(f.fills, in)
+	return in, nil
+}
+
+func (f *fakeFillStore) DeleteFill(_ context.Context, portfolioID, fillID uuid.UUID) error {
+	for i, fl := range f.fills {
+		if fl.PortfolioID == portfolioID && fl.ID == fillID {
+			f.fills = append(f.fills[:i], f.fills[i+1:]...)
+			return nil
+		}
+	}
+	return portfolio.ErrFillNotFound
+}
+
+var _ = Describe("Trade fills", func() {
+	var (
+		app    *fiber.App
+		store  *fakeStore
+		fills  *fakeFillStore
+		reader *fakeSnapshotReader
+		sub    = "auth0|owner"
+		path   = "/fake/snap.sqlite"
+	)
+
+	day := func(s string) time.Time {
+		t, err := time.Parse("2006-01-02", s)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+	ptr := func(v float64) *float64 { return &v }
+	str := func(s string) *string { return &s }
+
+	do := func(method, target, body string) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		out, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(out)
+	}
+
+	BeforeEach(func() {
+		store = &fakeStore{}
+		fills = &fakeFillStore{}
+		reader = &fakeSnapshotReader{
+			prediction: &openapi.PredictionResponse{
+				Date: openapi_types.Date{Time: day("2025-07-01")},
+				Transactions: []openapi.PredictedTransaction{
+					{Type: "sell", Ticker: str("SPY"), Quantity: ptr(10), Price: ptr(500)},
+					{Type: "buy", Ticker: str("TLT"), Quantity: ptr(50), Price: ptr(90)},
+				},
+			},
+			transactions: []openapi.Transaction{
+				{Date: openapi_types.Date{Time: day("2025-06-02")}, Type: "buy", Ticker: str("SPY"), Quantity: ptr(6), Price: ptr(480)},
+				{Date: openapi_types.Date{Time: day("2025-06-02")}, Type: "buy", Ticker: str("SPY"), Quantity: ptr(4), Price: ptr(485)},
+				{Date: openapi_types.Date{Time: day("2025-06-02")}, Type: "sell", Ticker: str("IEF"), Quantity: ptr(20), Price: ptr(95)},
+				{Date: openapi_types.Date{Time: day("2025-06-02")}, Type: "dividend", Ticker: str("IEF")},
+			},
+		}
+		opener := &fakeSnapshotOpener{readers: map[string]portfolio.SnapshotReader{path: reader}}
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, opener, nil, nil, nil, strategy.EphemeralOptions{}).
+			WithFills(fills)
+
+		app = fiber.New(fiber.Config{JSONEncoder: sonic.Marshal, JSONDecoder: sonic.Unmarshal})
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, sub)
+			return c.Next()
+		})
+		app.Get("/portfolios/:slug/checklist", h.Checklist)
+		app.Get("/portfolios/:slug/fills", h.ListFills)
+		app.Post("/portfolios/:slug/fills", h.CreateFill)
+		app.Delete("/portfolios/:slug/fills/:fillId", h.DeleteFill)
+		app.Get("/portfolios/:slug/tracking", h.Tracking)
+
+		store.rows = []portfolio.Portfolio{{
+			ID: uuid.Must(uuid.NewV7()), OwnerSub: sub, Slug: "s1", Name: "Core",
+			Status: portfolio.StatusReady, SnapshotPath: &path,
+		}}
+	})
+
+	It("marks predicted trades executed on the checklist", func() {
+		status, body := do("POST", "/portfolios/s1/fills", `{"type":"buy","ticker":"tlt","quantity":49,"price":90.5}`)
+		Expect(status).To(Equal(fiber.StatusCreated), body)
+		Expect(body).To(ContainSubstring(`"date":"2025-07-01"`))
+		Expect(body).To(ContainSubstring(`"predictedQuantity":50`))
+
+		status, body = do("GET", "/portfolios/s1/checklist", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		var list struct {
+			Date  string `json:"date"`
+			Items []struct {
+				Ticker   string `json:"ticker"`
+				Executed bool   `json:"executed"`
+			} `json:"items"`
+		}
+		Expect(sonic.Unmarshal([]byte(body), &list)).To(Succeed())
+		Expect(list.Date).To(Equal("2025-07-01"))
+		Expect(list.Items).To(HaveLen(2))
+		Expect(list.Items[0].Executed).To(BeFalse())
+		Expect(list.Items[1].Ticker).To(Equal("TLT"))
+		Expect(list.Items[1].Executed).To(BeTrue())
+	})
+
+	It("replaces the fill when a trade is marked again", func() {
+		status, _ := do("POST", "/portfolios/s1/fills", `{"type":"sell","ticker":"SPY","quantity":10,"price":499}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+		status, _ = do("POST", "/portfolios/s1/fills", `{"type":"sell","ticker":"SPY","quantity":10,"price":501}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+		Expect(fills.fills).To(HaveLen(1))
+		Expect(fills.fills[0].Price).To(Equal(501.0))
+	})
+
+	It("rejects fills that match no strategy trade", func() {
+		status, _ := do("POST", "/portfolios/s1/fills", `{"type":"buy","ticker":"GLD","quantity":1,"price":200}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		status, _ = do("POST", "/portfolios/s1/fills", `{"date":"2025-06-03","type":"buy","ticker":"SPY","quantity":1,"price":480}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		status, _ = do("POST", "/portfolios/s1/fills", `{"type":"hold","ticker":"SPY","quantity":1,"price":480}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		status, _ = do("POST", "/portfolios/s1/fills", `{"type":"buy","ticker":"TLT","quantity":0,"price":90}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		Expect(fills.fills).To(BeEmpty())
+	})
+
+	It("reports the tracking difference against the backtest and prediction", func() {
+		status, _ := do("POST", "/portfolios/s1/fills", `{"date":"2025-06-02","type":"buy","ticker":"SPY","quantity":10,"price":484}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+		status, _ = do("POST", "/portfolios/s1/fills", `{"type":"sell","ticker":"SPY","quantity":10,"price":499}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+
+		status, body := do("GET", "/portfolios/s1/tracking", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		var tr struct {
+			Fills           int     `json:"fills"`
+			Matched         int     `json:"matched"`
+			TotalCostImpact float64 `json:"totalCostImpact"`
+			Items           []struct {
+				Basis         string   `json:"basis"`
+				ModelQuantity *float64 `json:"modelQuantity"`
+				ModelPrice    *float64 `json:"modelPrice"`
+				CostImpact    *float64 `json:"costImpact"`
+			} `json:"items"`
+			MissedTrades []struct {
+				Ticker string `json:"ticker"`
+				Type   string `json:"type"`
+			} `json:"missedTrades"`
+		}
+		Expect(sonic.Unmarshal([]byte(body), &tr)).To(Succeed())
+		Expect(tr.Fills).To(Equal(2))
+		Expect(tr.Matched).To(Equal(2))
+
+		// Backtest bought 6 @ 480 and 4 @ 485: 10 @ 482. Paying 484 cost $20.
+		Expect(tr.Items[0].Basis).To(Equal(portfolio.FillBasisBacktest))
+		Expect(*tr.Items[0].ModelQuantity).To(Equal(10.0))
+		Expect(*tr.Items[0].ModelPrice).To(BeNumerically("~", 482, 1e-9))
+		Expect(*tr.Items[0].CostImpact).To(BeNumerically("~", 20, 1e-9))
+
+		// Predicted sell at 500, sold at 499: another $10.
+		Expect(tr.Items[1].Basis).To(Equal(portfolio.FillBasisPrediction))
+		Expect(*tr.Items[1].CostImpact).To(BeNumerically("~", 10, 1e-9))
+		Expect(tr.TotalCostImpact).To(BeNumerically("~", 30, 1e-9))
+
+		Expect(tr.MissedTrades).To(HaveLen(1))
+		Expect(tr.MissedTrades[0].Ticker).To(Equal("IEF"))
+		Expect(tr.MissedTrades[0].Type).To(Equal("sell"))
+	})
+
+	It("deletes fills", func() {
+		status, _ := do("POST", "/portfolios/s1/fills", `{"type":"buy","ticker":"TLT","quantity":50,"price":90}`)
+		Expect(status).To(Equal(fiber.StatusCreated))
+		id := fills.fills[0].ID.String()
+
+		status, _ = do("DELETE", "/portfolios/s1/fills/"+id, "")
+		Expect(status).To(Equal(fiber.StatusNoContent))
+		status, _ = do("DELETE", "/portfolios/s1/fills/"+id, "")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+		status, body := do("GET", "/portfolios/s1/fills", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(Equal(`{"items":[]}`))
+	})
+})
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
@@ -31,10 +32,14 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/rs/zerolog/log"
 
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/etag"
 	"github.com/penny-vault/pv-api/openapi"
 	"github.com/penny-vault/pv-api/progress"
+	"github.com/penny-vault/pv-api/quota"
 	"github.com/penny-vault/pv-api/strategy"
 	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
 )
 
 // ErrNoSubject is returned by subject() when the Auth0 sub is not present
@@ -51,6 +56,23 @@ type Handler struct {
 	dispatcher   Dispatcher
 	hub          *progress.Hub
 	snapshotsDir string
+	// calendarTokens stores per-user calendar feed tokens, hashed with
+	// calendarSecret; nil disables the per-user feed.
+	calendarTokens CalendarTokenStore
+	calendarSecret string
+	// fills stores manual trade fills; nil disables the fill endpoints.
+	fills FillStore
+	// shares stores public share links; nil disables sharing.
+	shares ShareStore
+	// workspaces looks up workspace roles; nil limits callers to their own
+	// portfolios.
+	workspaces workspace.Roles
+	// limits caps creates and runs per owner; usage counts toward them.
+	// A nil usage disables the checks.
+	limits quota.Limits
+	usage  quota.Counter
+	// audit records changes; nil records nothing.
+	audit audit.Recorder
 
 	ephemeralBuilder strategy.BuilderFunc
 	urlValidator     strategy.URLValidatorFunc
@@ -85,28 +107,86 @@ func NewHandler(
 	}
 }
 
-// List implements GET /portfolios.
+// WithWorkspaces lets requests act on a workspace's portfolios by naming it
+// in the X-Workspace header, subject to the caller's role there.
+func (h *Handler) WithWorkspaces(r workspace.Roles) *Handler {
+	h.workspaces = r
+	return h
+}
+
+// WithQuotas enforces limits on portfolio creates and manual runs.
+func (h *Handler) WithQuotas(limits quota.Limits, usage quota.Counter) *Handler {
+	h.limits = limits
+	h.usage = usage
+	return h
+}
+
+// checkQuota loads ownerSub's usage and runs check on it. When check
+// fails, it writes a 403 or 429 problem and returns ok=false.
+func (h *Handler) checkQuota(c fiber.Ctx, ownerSub string, check func(quota.Usage) error) (bool, error) {
+	if h.usage == nil {
+		return true, nil
+	}
+	u, err := h.usage.Usage(c.Context(), ownerSub)
+	if err != nil {
+		return false, writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if err := check(u); err != nil {
+		status := quota.Status(err)
+		return false, writeProblem(c, status, http.StatusText(status), err.Error())
+	}
+	return true, nil
+}
+
+// owner returns the owner_sub whose portfolios the request addresses: the
+// caller's own, or the selected workspace's when the caller's role there
+// allows need. On failure it writes the problem response and returns
+// ok=false.
+func (h *Handler) owner(c fiber.Ctx, need workspace.Role) (string, bool, error) {
+	sub, err := subject(c)
+	if err != nil {
+		return "", false, writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	ownerSub, err := workspace.Resolve(c, h.workspaces, sub, need)
+	if err != nil {
+		status := workspace.Status(err)
+		return "", false, writeProblem(c, status, http.StatusText(status), err.Error())
+	}
+	return ownerSub, true, nil
+}
+
+// List implements GET /portfolios. The query string filters, searches,
+// sorts, and pages the list (see parseListQuery); when more rows remain the
+// next page's cursor is returned in the X-Next-Cursor header.
 func (h *Handler) List(c fiber.Ctx) error {
-	ownerSub, err := subject(c)
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
+	}
+	q, err := parseListQuery(func(key string) string { return string([]byte(c.Query(key))) })
 	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
 	}
-	rows, err := h.store.List(c.Context(), ownerSub)
+	rows, err := h.store.ListPage(c.Context(), ownerSub, q)
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
-	out := make([]portfolioView, 0, len(rows))
-	for _, r := range rows {
+	page, next := q.page(rows)
+	out := make([]portfolioView, 0, len(page))
+	for _, r := range page {
 		out = append(out, toView(r))
 	}
+	if next != "" {
+		c.Set(HeaderNextCursor, next)
+	}
 	return writeJSON(c, fiber.StatusOK, out)
 }
 
 // Get implements GET /portfolios/{slug} (config only).
 func (h *Handler) Get(c fiber.Ctx) error {
-	ownerSub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
 	}
 	slug := c.Params("slug")
 	p, err := h.store.Get(c.Context(), ownerSub, slug)
@@ -116,6 +196,9 @@ func (h *Handler) Get(c fiber.Ctx) error {
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
+	if etag.NotModified(c, etag.FromTime(p.UpdatedAt)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 	return writeJSON(c, fiber.StatusOK, toView(p))
 }
 
@@ -123,9 +206,9 @@ func (h *Handler) Get(c fiber.Ctx) error {
 // createUnofficial depending on which of strategyCode / strategyCloneUrl
 // is set. Both set or neither set yields 422.
 func (h *Handler) Create(c fiber.Ctx) error {
-	ownerSub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleEditor)
+	if !ok {
+		return err
 	}
 
 	var body createBody
@@ -150,6 +233,19 @@ func (h *Handler) Create(c fiber.Ctx) error {
 	case req.StrategyCode == "" && req.StrategyCloneURL == "":
 		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity",
 			"one of strategyCode or strategyCloneUrl is required")
+	}
+
+	// Creating a portfolio also starts its first run.
+	if ok, err := h.checkQuota(c, ownerSub, func(u quota.Usage) error {
+		if err := h.limits.CheckPortfolio(u, req.StrategyCloneURL); err != nil {
+			return err
+		}
+		return h.limits.CheckRun(u)
+	}); !ok {
+		return err
+	}
+
+	switch {
 	case req.StrategyCloneURL != "":
 		return h.createUnofficial(c, ownerSub, req)
 	default:
@@ -240,12 +336,16 @@ func (h *Handler) insertAndDispatch(c fiber.Ctx, ownerSub string, norm CreateReq
 	runID, status, pb := h.autoTriggerOrProblem(c, created)
 	if status != 0 {
 		// Rollback the portfolio row because we could not queue its first run.
-		if delErr := h.store.Delete(c.Context(), ownerSub, created.Slug); delErr != nil {
+		if delErr := h.store.Delete(c.Context(), ownerSub, created.Slug, nil); delErr != nil {
 			log.Warn().Err(delErr).Stringer("portfolio_id", created.ID).Msg("rollback delete failed")
 		}
 		return writeProblem(c, status, pb.title, pb.detail)
 	}
 
+	e := audit.FromRequest(c, audit.ActionPortfolioCreate)
+	e.Changes = audit.Diff(nil, auditState(created))
+	h.record(c.Context(), e, created)
+
 	v := toView(created)
 	if runID != (uuid.UUID{}) {
 		s := runID.String()
@@ -281,6 +381,7 @@ func (h *Handler) buildPortfolio(ownerSub string, norm CreateRequest, describe s
 		EndDate:              norm.EndDate,
 		Status:               StatusPending,
 		RunRetention:         retention,
+		Tags:                 norm.Tags,
 	}
 	if norm.StrategyVer != "" {
 		v := norm.StrategyVer
@@ -322,6 +423,8 @@ type patchBody struct {
 	StartDate    string `json:"startDate"`
 	EndDate      string `json:"endDate"`
 	RunRetention *int   `json:"runRetention"`
+	// Tags replaces the tag list when present; [] clears it.
+	Tags *[]string `json:"tags"`
 }
 
 // parsePatchBody validates that the request contains only allowed fields and
@@ -331,7 +434,7 @@ func parsePatchBody(data []byte) (patchBody, *time.Time, *time.Time, error) {
 	if err := sonic.Unmarshal(data, &raw); err != nil {
 		return patchBody{}, nil, nil, fmt.Errorf("body is not valid JSON: %w", err)
 	}
-	allowed := map[string]bool{"name": true, "startDate": true, "endDate": true, "runRetention": true}
+	allowed := map[string]bool{"name": true, "startDate": true, "endDate": true, "runRetention": true, "tags": true}
 	for k := range raw {
 		if !allowed[k] {
 			return patchBody{}, nil, nil, fmt.Errorf("rejected field %q: %w", k, ErrImmutableField)
@@ -355,26 +458,23 @@ func parsePatchBody(data []byte) (patchBody, *time.Time, *time.Time, error) {
 	if err := validateRunRetention(body.RunRetention); err != nil {
 		return patchBody{}, nil, nil, err
 	}
-	return body, startDate, endDate, nil
-}
-
-// applyStoreUpdate calls fn and maps ErrNotFound to a 404 problem response.
-func applyStoreUpdate(c fiber.Ctx, slug string, fn func() error) error {
-	if err := fn(); err != nil {
-		if errors.Is(err, ErrNotFound) {
-			return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	if body.Tags != nil {
+		tags, err := normalizeTags(*body.Tags)
+		if err != nil {
+			return patchBody{}, nil, nil, err
 		}
-		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		tags = tagsOrEmpty(tags)
+		body.Tags = &tags
 	}
-	return nil
+	return body, startDate, endDate, nil
 }
 
 // Patch implements PATCH /portfolios/{slug}.
-// Allows updating: name, startDate, endDate, runRetention.
+// Allows updating: name, startDate, endDate, runRetention, tags.
 func (h *Handler) Patch(c fiber.Ctx) error {
-	ownerSub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleEditor)
+	if !ok {
+		return err
 	}
 	slug := string([]byte(c.Params("slug")))
 
@@ -382,26 +482,35 @@ func (h *Handler) Patch(c fiber.Ctx) error {
 	if err != nil {
 		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
 	}
-
-	if body.Name != "" {
-		if err := applyStoreUpdate(c, slug, func() error {
-			return h.store.UpdateName(c.Context(), ownerSub, slug, body.Name)
-		}); err != nil {
-			return err
+	before, beforeErr := h.store.Get(c.Context(), ownerSub, slug)
+	var guard *time.Time
+	if beforeErr == nil {
+		if !etag.Match(c, etag.FromTime(before.UpdatedAt)) {
+			return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", ErrStale.Error())
 		}
-	}
-	if startDate != nil || endDate != nil {
-		if err := applyStoreUpdate(c, slug, func() error {
-			return h.store.UpdateDates(c.Context(), ownerSub, slug, startDate, endDate)
-		}); err != nil {
-			return err
+		if etag.Conditional(c) {
+			guard = &before.UpdatedAt
 		}
 	}
-	if body.RunRetention != nil {
-		if err := applyStoreUpdate(c, slug, func() error {
-			return h.store.UpdateRunRetention(c.Context(), ownerSub, slug, *body.RunRetention)
-		}); err != nil {
-			return err
+
+	ch := Changes{
+		StartDate:    startDate,
+		EndDate:      endDate,
+		RunRetention: body.RunRetention,
+		Tags:         body.Tags,
+	}
+	if body.Name != "" {
+		ch.Name = &body.Name
+	}
+	if !ch.Empty() {
+		err := h.store.Update(c.Context(), ownerSub, slug, ch, guard)
+		switch {
+		case errors.Is(err, ErrStale):
+			return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", err.Error())
+		case errors.Is(err, ErrNotFound):
+			return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+		case err != nil:
+			return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 		}
 	}
 
@@ -409,14 +518,22 @@ func (h *Handler) Patch(c fiber.Ctx) error {
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
+	if beforeErr == nil {
+		if changes := audit.Diff(auditState(before), auditState(p)); len(changes) > 0 {
+			e := audit.FromRequest(c, audit.ActionPortfolioUpdate)
+			e.Changes = changes
+			h.record(c.Context(), e, p)
+		}
+	}
+	c.Set(fiber.HeaderETag, etag.FromTime(p.UpdatedAt))
 	return writeJSON(c, fiber.StatusOK, toView(p))
 }
 
 // Delete implements DELETE /portfolios/{slug}.
 func (h *Handler) Delete(c fiber.Ctx) error {
-	ownerSub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleOwner)
+	if !ok {
+		return err
 	}
 	slug := c.Params("slug")
 
@@ -425,12 +542,29 @@ func (h *Handler) Delete(c fiber.Ctx) error {
 	// surface whatever error it returns; the dir will be reaped by the
 	// scheduled orphan sweep.
 	p, lookupErr := h.store.Get(c.Context(), ownerSub, slug)
-	if err := h.store.Delete(c.Context(), ownerSub, slug); err != nil {
+	var guard *time.Time
+	if lookupErr == nil {
+		if !etag.Match(c, etag.FromTime(p.UpdatedAt)) {
+			return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", ErrStale.Error())
+		}
+		if etag.Conditional(c) {
+			guard = &p.UpdatedAt
+		}
+	}
+	if err := h.store.Delete(c.Context(), ownerSub, slug, guard); err != nil {
+		if errors.Is(err, ErrStale) {
+			return writeProblem(c, fiber.StatusPreconditionFailed, "Precondition Failed", err.Error())
+		}
 		if errors.Is(err, ErrNotFound) {
 			return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
 		}
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
+	if lookupErr == nil {
+		e := audit.FromRequest(c, audit.ActionPortfolioDelete)
+		e.Changes = audit.Diff(auditState(p), nil)
+		h.record(c.Context(), e, p)
+	}
 	if lookupErr == nil && h.snapshotsDir != "" {
 		dir := filepath.Join(h.snapshotsDir, p.ID.String())
 		if rmErr := os.RemoveAll(dir); rmErr != nil {
@@ -451,6 +585,7 @@ type createBody struct {
 	StartDate        string         `json:"startDate,omitempty"`
 	EndDate          string         `json:"endDate,omitempty"`
 	RunRetention     *int           `json:"runRetention"`
+	Tags             []string       `json:"tags,omitempty"`
 }
 
 func (b createBody) toRequest(startDate, endDate *time.Time) CreateRequest {
@@ -464,6 +599,7 @@ func (b createBody) toRequest(startDate, endDate *time.Time) CreateRequest {
 		StartDate:        startDate,
 		EndDate:          endDate,
 		RunRetention:     b.RunRetention,
+		Tags:             b.Tags,
 	}
 }
 
@@ -501,12 +637,14 @@ type portfolioView struct {
 	LastRunAt          *string        `json:"lastRunAt"`
 	LastError          *string        `json:"lastError"`
 	RunRetention       int            `json:"runRetention"`
+	Tags               []string       `json:"tags"`
 	CurrentValue       *float64       `json:"currentValue"`
 	YtdReturn          *float64       `json:"ytdReturn"`
 	MaxDrawDown        *float64       `json:"maxDrawDown"`
 	Sharpe             *float64       `json:"sharpe"`
 	CagrSinceInception *float64       `json:"cagrSinceInception"`
 	InceptionDate      *string        `json:"inceptionDate"`
+	WorkspaceID        *string        `json:"workspaceId,omitempty"`
 }
 
 func toView(p Portfolio) portfolioView {
@@ -524,6 +662,7 @@ func toView(p Portfolio) portfolioView {
 		UpdatedAt:          p.UpdatedAt.UTC().Format("2006-01-02T15:04:05Z"),
 		LastError:          p.LastError,
 		RunRetention:       p.RunRetention,
+		Tags:               tagsOrEmpty(p.Tags),
 		CurrentValue:       p.CurrentValue,
 		YtdReturn:          p.YtdReturn,
 		MaxDrawDown:        p.MaxDrawdown,
@@ -546,6 +685,10 @@ func toView(p Portfolio) portfolioView {
 		d := p.InceptionDate.Format("2006-01-02")
 		v.InceptionDate = &d
 	}
+	if id, ok := workspace.ParseOwnerSub(p.OwnerSub); ok {
+		s := id.String()
+		v.WorkspaceID = &s
+	}
 	return v
 }
 
@@ -605,14 +748,27 @@ func (h *Handler) Summary(c fiber.Ctx) error {
 	})
 }
 
-// readSnapshot is the shared skeleton for all derived-data endpoints.
+// readSnapshot is the shared skeleton for all derived-data endpoints. What
+// it serves depends on nothing but the snapshot, so it is tagged with the
+// active run and answers If-None-Match with 304.
 func (h *Handler) readSnapshot(c fiber.Ctx, fn func(SnapshotReader) (any, error)) error {
-	sub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	return h.serveSnapshot(c, true, func(_ Portfolio, r SnapshotReader) (any, error) { return fn(r) })
+}
+
+// readPortfolioSnapshot is readSnapshot for reads that also need the
+// portfolio row. Those reads mix in other data, such as fills, so they are
+// not tagged.
+func (h *Handler) readPortfolioSnapshot(c fiber.Ctx, fn func(Portfolio, SnapshotReader) (any, error)) error {
+	return h.serveSnapshot(c, false, fn)
+}
+
+func (h *Handler) serveSnapshot(c fiber.Ctx, tagByRun bool, fn func(Portfolio, SnapshotReader) (any, error)) error {
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
 	}
 	slug := string([]byte(c.Params("slug")))
-	p, err := h.store.Get(c.Context(), sub, slug)
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
 	if errors.Is(err, ErrNotFound) {
 		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
 	}
@@ -622,6 +778,9 @@ func (h *Handler) readSnapshot(c fiber.Ctx, fn func(SnapshotReader) (any, error)
 	if p.Status != StatusReady || p.SnapshotPath == nil || *p.SnapshotPath == "" {
 		return h.respondRecalculating(c, p, slug)
 	}
+	if runID, ok := snapshotRunID(*p.SnapshotPath); tagByRun && ok && etag.NotModified(c, etag.FromRun(runID)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 	reader, err := h.opener.Open(*p.SnapshotPath)
 	if err != nil {
 		// Snapshot file is missing or unreadable (evicted by retention sweep,
@@ -629,7 +788,7 @@ func (h *Handler) readSnapshot(c fiber.Ctx, fn func(SnapshotReader) (any, error)
 		return h.respondRecalculating(c, p, slug)
 	}
 	defer func() { _ = reader.Close() }()
-	out, err := fn(reader)
+	out, err := fn(p, reader)
 	if errors.Is(err, errNotFoundSentinel) {
 		return writeProblem(c, fiber.StatusNotFound, "Not Found", "not found")
 	}
@@ -639,6 +798,13 @@ func (h *Handler) readSnapshot(c fiber.Ctx, fn func(SnapshotReader) (any, error)
 	return c.JSON(out)
 }
 
+// snapshotRunID returns the run whose snapshot is at path, which the
+// backtest runner names <runID>.sqlite. Legacy paths yield false.
+func snapshotRunID(path string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(strings.TrimSuffix(filepath.Base(path), ".sqlite"))
+	return id, err == nil
+}
+
 // respondRecalculating returns 202 Accepted with the id of an in-flight or
 // freshly queued backtest run. Callers reach this branch when the portfolio's
 // snapshot is missing; the body tells the client to poll the run resource and
@@ -876,12 +1042,12 @@ func parseFromTo(c fiber.Ctx) (*time.Time, *time.Time, error) {
 
 // GET /portfolios/{slug}/runs
 func (h *Handler) ListRuns(c fiber.Ctx) error {
-	sub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
 	}
 	slug := string([]byte(c.Params("slug")))
-	p, err := h.store.Get(c.Context(), sub, slug)
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
 	if errors.Is(err, ErrNotFound) {
 		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
 	}
@@ -901,12 +1067,12 @@ func (h *Handler) ListRuns(c fiber.Ctx) error {
 
 // GET /portfolios/{slug}/runs/{runId}
 func (h *Handler) GetRun(c fiber.Ctx) error {
-	sub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
 	}
 	slug := string([]byte(c.Params("slug")))
-	p, err := h.store.Get(c.Context(), sub, slug)
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
 	if errors.Is(err, ErrNotFound) {
 		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
 	}
@@ -994,12 +1160,12 @@ func toAPIProgress(msg progress.ProgressMessage) *openapi.RunProgress {
 // CreateRun implements POST /portfolios/{slug}/runs. It creates a queued
 // backtest run and submits it to the dispatcher.
 func (h *Handler) CreateRun(c fiber.Ctx) error {
-	sub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleEditor)
+	if !ok {
+		return err
 	}
 	slug := string([]byte(c.Params("slug")))
-	p, err := h.store.Get(c.Context(), sub, slug)
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
 	if errors.Is(err, ErrNotFound) {
 		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
 	}
@@ -1009,6 +1175,9 @@ func (h *Handler) CreateRun(c fiber.Ctx) error {
 	if p.Status == StatusRunning {
 		return writeProblem(c, fiber.StatusConflict, "Conflict", "portfolio is already running")
 	}
+	if ok, err := h.checkQuota(c, ownerSub, h.limits.CheckRun); !ok {
+		return err
+	}
 	if h.dispatcher == nil {
 		return writeProblem(c, fiber.StatusNotImplemented, "Not Implemented", "backtest dispatcher not configured")
 	}
@@ -1022,6 +1191,9 @@ func (h *Handler) CreateRun(c fiber.Ctx) error {
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
+	e := audit.FromRequest(c, audit.ActionRunTrigger)
+	e.Target = runID.String()
+	h.record(c.Context(), e, p)
 	return c.Status(fiber.StatusAccepted).JSON(openapi.BacktestRun{
 		Id:            runID,
 		PortfolioSlug: slug,
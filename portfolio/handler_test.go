@@ -25,6 +25,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -75,6 +76,9 @@ type fakeStore struct {
 	rows []portfolio.Portfolio
 	runs map[uuid.UUID][]portfolio.Run
 
+	// listQueries records every ListPage query.
+	listQueries []portfolio.ListQuery
+
 	// ApplyUpgrade call recording.
 	ApplyUpgradeCalls []applyUpgradeCall
 	ApplyUpgradeErr   error // returned to caller; nil means success
@@ -90,6 +94,13 @@ func (f *fakeStore) List(_ context.Context, ownerSub string) ([]portfolio.Portfo
 	return out, nil
 }
 
+// ListPage records q and returns the owner's rows as stored; the filtering,
+// ordering and keyset SQL is covered by the Postgres smoke test.
+func (f *fakeStore) ListPage(ctx context.Context, ownerSub string, q portfolio.ListQuery) ([]portfolio.Portfolio, error) {
+	f.listQueries = append(f.listQueries, q)
+	return f.List(ctx, ownerSub)
+}
+
 func (f *fakeStore) Get(_ context.Context, ownerSub, slug string) (portfolio.Portfolio, error) {
 	for _, p := range f.rows {
 		if p.OwnerSub == ownerSub && p.Slug == slug {
@@ -112,24 +123,52 @@ func (f *fakeStore) Insert(_ context.Context, p portfolio.Portfolio) error {
 	return nil
 }
 
-func (f *fakeStore) UpdateName(_ context.Context, ownerSub, slug, name string) error {
+func (f *fakeStore) Update(_ context.Context, ownerSub, slug string, ch portfolio.Changes, ifUpdatedAt *time.Time) error {
 	for i, p := range f.rows {
-		if p.OwnerSub == ownerSub && p.Slug == slug {
-			f.rows[i].Name = name
-			f.rows[i].UpdatedAt = time.Now().UTC()
-			return nil
+		if p.OwnerSub != ownerSub || p.Slug != slug {
+			continue
+		}
+		if ifUpdatedAt != nil && !p.UpdatedAt.Equal(*ifUpdatedAt) {
+			return portfolio.ErrStale
+		}
+		r := &f.rows[i]
+		if ch.Name != nil {
+			r.Name = *ch.Name
+		}
+		if ch.StartDate != nil {
+			r.StartDate = ch.StartDate
 		}
+		if ch.EndDate != nil {
+			r.EndDate = ch.EndDate
+		}
+		if ch.RunRetention != nil {
+			r.RunRetention = *ch.RunRetention
+		}
+		if ch.Tags != nil {
+			r.Tags = *ch.Tags
+		}
+		r.UpdatedAt = time.Now().UTC()
+		return nil
+	}
+	if ifUpdatedAt != nil {
+		return portfolio.ErrStale
 	}
 	return portfolio.ErrNotFound
 }
 
-func (f *fakeStore) Delete(_ context.Context, ownerSub, slug string) error {
+func (f *fakeStore) Delete(_ context.Context, ownerSub, slug string, ifUpdatedAt *time.Time) error {
 	for i, p := range f.rows {
 		if p.OwnerSub == ownerSub && p.Slug == slug {
+			if ifUpdatedAt != nil && !p.UpdatedAt.Equal(*ifUpdatedAt) {
+				return portfolio.ErrStale
+			}
 			f.rows = append(f.rows[:i], f.rows[i+1:]...)
 			return nil
 		}
 	}
+	if ifUpdatedAt != nil {
+		return portfolio.ErrStale
+	}
 	return portfolio.ErrNotFound
 }
 
@@ -162,26 +201,15 @@ func (f *fakeStore) ClaimDue(_ context.Context, _ int) ([]uuid.UUID, error) {
 	return nil, nil
 }
 
-func (f *fakeStore) UpdateDates(_ context.Context, ownerSub, slug string, startDate, endDate *time.Time) error {
-	for i, p := range f.rows {
-		if p.OwnerSub == ownerSub && p.Slug == slug {
-			if startDate != nil {
-				f.rows[i].StartDate = startDate
-			}
-			if endDate != nil {
-				f.rows[i].EndDate = endDate
-			}
-			f.rows[i].UpdatedAt = time.Now().UTC()
-			return nil
+func (f *fakeStore) UpdateOwner(_ context.Context, ownerSub, slug, newOwnerSub string) error {
+	for _, p := range f.rows {
+		if p.OwnerSub == newOwnerSub && p.Slug == slug {
+			return portfolio.ErrDuplicateSlug
 		}
 	}
-	return portfolio.ErrNotFound
-}
-
-func (f *fakeStore) UpdateRunRetention(_ context.Context, ownerSub, slug string, value int) error {
 	for i, p := range f.rows {
 		if p.OwnerSub == ownerSub && p.Slug == slug {
-			f.rows[i].RunRetention = value
+			f.rows[i].OwnerSub = newOwnerSub
 			f.rows[i].UpdatedAt = time.Now().UTC()
 			return nil
 		}
@@ -693,6 +721,9 @@ type fakeSnapshotReader struct {
 	summary          *openapi.PortfolioSummary
 	metrics          *openapi.PortfolioMetrics
 	prediction       *openapi.PredictionResponse
+	transactions     []openapi.Transaction
+	performance      *openapi.PortfolioPerformance
+	holdings         *openapi.HoldingsResponse
 	holdingsImpactFn func(ctx context.Context, slug string, topN int) (*openapi.HoldingsImpactResponse, error)
 }
 
@@ -710,7 +741,7 @@ func (f *fakeSnapshotReader) TrailingReturns(_ context.Context) ([]openapi.Trail
 	return nil, nil
 }
 func (f *fakeSnapshotReader) CurrentHoldings(_ context.Context) (*openapi.HoldingsResponse, error) {
-	return nil, nil
+	return f.holdings, nil
 }
 func (f *fakeSnapshotReader) HoldingsAsOf(_ context.Context, _ time.Time) (*openapi.HoldingsAsOfResponse, error) {
 	return nil, nil
@@ -718,11 +749,30 @@ func (f *fakeSnapshotReader) HoldingsAsOf(_ context.Context, _ time.Time) (*open
 func (f *fakeSnapshotReader) HoldingsHistory(_ context.Context, _, _ *time.Time) (*openapi.HoldingsHistoryResponse, error) {
 	return nil, nil
 }
-func (f *fakeSnapshotReader) Performance(_ context.Context, _ string, _, _ *time.Time) (*openapi.PortfolioPerformance, error) {
-	return nil, nil
+func (f *fakeSnapshotReader) Performance(_ context.Context, slug string, _, _ *time.Time) (*openapi.PortfolioPerformance, error) {
+	if f.performance == nil {
+		return nil, nil
+	}
+	out := *f.performance
+	out.PortfolioSlug = slug
+	out.Points = slices.Clone(f.performance.Points)
+	return &out, nil
 }
-func (f *fakeSnapshotReader) Transactions(_ context.Context, _ portfolio.SnapshotTxFilter) (*openapi.TransactionsResponse, error) {
-	return nil, nil
+func (f *fakeSnapshotReader) Transactions(_ context.Context, filter portfolio.SnapshotTxFilter) (*openapi.TransactionsResponse, error) {
+	if f.transactions == nil {
+		return nil, nil
+	}
+	out := &openapi.TransactionsResponse{Items: []openapi.Transaction{}}
+	for _, tx := range f.transactions {
+		if filter.From != nil && tx.Date.Before(*filter.From) || filter.To != nil && tx.Date.After(*filter.To) {
+			continue
+		}
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, string(tx.Type)) {
+			continue
+		}
+		out.Items = append(out.Items, tx)
+	}
+	return out, nil
 }
 func (f *fakeSnapshotReader) Metrics(_ context.Context, _, _ []string) (*openapi.PortfolioMetrics, error) {
 	return f.metrics, nil
@@ -0,0 +1,259 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/penny-vault/pv-api/keyset"
+)
+
+// HeaderNextCursor carries the opaque cursor for the next page of
+// GET /portfolios.
+const HeaderNextCursor = keyset.HeaderNextCursor
+
+// maxListLimit caps the GET /portfolios page size.
+const maxListLimit = 200
+
+// Errors returned by parseListQuery. The handler maps them to 422.
+var (
+	ErrInvalidSort   = errors.New("invalid sort")
+	ErrInvalidLimit  = errors.New("limit must be an integer between 1 and 200")
+	ErrInvalidCursor = keyset.ErrInvalidCursor
+	ErrInvalidStatus = errors.New("invalid status")
+)
+
+// sortField is one sortable GET /portfolios field: the column it orders
+// by, how a cursor value binds against it, and the same value read from a
+// row, used to build the next cursor.
+type sortField struct {
+	column string
+	bind   func(float64) any
+	value  func(Portfolio) *float64
+}
+
+// sortFields maps each sortable field (its JSON name in portfolioView) to
+// its column. Each has an (owner_sub, column, slug) index (migration 36).
+var sortFields = map[string]sortField{
+	"createdAt": {"created_at", bindMicros, func(p Portfolio) *float64 {
+		f := float64(p.CreatedAt.UnixMicro())
+		return &f
+	}},
+	"currentValue":       {"current_value", nil, func(p Portfolio) *float64 { return p.CurrentValue }},
+	"ytdReturn":          {"ytd_return", nil, func(p Portfolio) *float64 { return p.YtdReturn }},
+	"sharpe":             {"sharpe", nil, func(p Portfolio) *float64 { return p.Sharpe }},
+	"maxDrawDown":        {"max_drawdown", nil, func(p Portfolio) *float64 { return p.MaxDrawdown }},
+	"cagrSinceInception": {"cagr_since_inception", nil, func(p Portfolio) *float64 { return p.CagrSinceInception }},
+}
+
+// bindMicros turns a createdAt cursor value back into a timestamp.
+func bindMicros(f float64) any { return time.UnixMicro(int64(f)) }
+
+// nameSearch is the text-search document for a portfolio's name. It must
+// match the expression indexed by migration 36.
+const nameSearch = "to_tsvector('simple', name)"
+
+// ListQuery is the parsed form of the GET /portfolios query string. The
+// store turns it into SQL scoped to one owner.
+type ListQuery struct {
+	Strategies  []string // match any; empty means all
+	Statuses    []Status // match any; empty means all
+	Benchmarks  []string // match any, ignoring case; empty means all
+	Tags        []string // match any; empty means all
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time // inclusive
+	LastRunFrom *time.Time
+	LastRunTo   *time.Time // inclusive
+	Terms       []string   // lower-cased words of the name search
+	SortField   string     // a sortFields key; default createdAt
+	Descending  bool
+	Limit       int // 0 means no limit
+	Cursor      *keyset.Cursor
+}
+
+// parseListQuery reads strategy, status, benchmark, tag, createdFrom,
+// createdTo, lastRunFrom, lastRunTo, q, sort, limit and cursor.
+// Multi-valued filters are comma-separated and dates are YYYY-MM-DD. sort
+// names a field, prefixed with "-" for descending order; the default,
+// -createdAt, keeps the newest-first order of the unfiltered list.
+func parseListQuery(get func(key string) string) (ListQuery, error) {
+	q := ListQuery{SortField: "createdAt", Descending: true}
+	q.Strategies = splitList(get("strategy"))
+	q.Benchmarks = splitList(get("benchmark"))
+	for _, t := range splitList(get("tag")) {
+		q.Tags = append(q.Tags, strings.ToLower(t))
+	}
+	for _, st := range splitList(get("status")) {
+		switch Status(st) {
+		case StatusPending, StatusRunning, StatusReady, StatusFailed:
+			q.Statuses = append(q.Statuses, Status(st))
+		default:
+			return ListQuery{}, fmt.Errorf("%w: %q", ErrInvalidStatus, st)
+		}
+	}
+	for _, d := range []struct {
+		key string
+		dst **time.Time
+	}{
+		{"createdFrom", &q.CreatedFrom},
+		{"createdTo", &q.CreatedTo},
+		{"lastRunFrom", &q.LastRunFrom},
+		{"lastRunTo", &q.LastRunTo},
+	} {
+		t, err := parseDate(get(d.key))
+		if err != nil {
+			return ListQuery{}, fmt.Errorf("%s: %w", d.key, err)
+		}
+		*d.dst = t
+	}
+	if terms := words(get("q")); len(terms) > 0 {
+		q.Terms = terms
+	}
+	if v := get("sort"); v != "" {
+		field, desc := strings.CutPrefix(v, "-")
+		if _, ok := sortFields[field]; !ok {
+			return ListQuery{}, fmt.Errorf("%w: %q", ErrInvalidSort, field)
+		}
+		q.SortField, q.Descending = field, desc
+	}
+	if v := get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxListLimit {
+			return ListQuery{}, ErrInvalidLimit
+		}
+		q.Limit = n
+	}
+	if v := get("cursor"); v != "" {
+		cur, err := keyset.Decode(v, q.sortToken())
+		if err != nil {
+			return ListQuery{}, err
+		}
+		q.Cursor = &cur
+	}
+	return q, nil
+}
+
+// order returns the keyset ordering for the query's sort.
+func (q ListQuery) order() keyset.Order {
+	f := sortFields[q.SortField]
+	return keyset.Order{Column: f.column, Key: "slug", Descending: q.Descending, Bind: f.bind}
+}
+
+// where returns the WHERE predicate for ownerSub's portfolios matching the
+// query's filters and cursor, appending its arguments to args.
+func (q ListQuery) where(ownerSub string, args *keyset.Args) string {
+	conds := []string{"owner_sub = " + args.Add(ownerSub)}
+	if len(q.Strategies) > 0 {
+		conds = append(conds, "strategy_code = ANY("+args.Add(q.Strategies)+"::text[])")
+	}
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, 0, len(q.Statuses))
+		for _, st := range q.Statuses {
+			statuses = append(statuses, string(st))
+		}
+		conds = append(conds, "status::text = ANY("+args.Add(statuses)+"::text[])")
+	}
+	if len(q.Benchmarks) > 0 {
+		benchmarks := make([]string, 0, len(q.Benchmarks))
+		for _, b := range q.Benchmarks {
+			benchmarks = append(benchmarks, strings.ToUpper(b))
+		}
+		conds = append(conds, "upper(benchmark) = ANY("+args.Add(benchmarks)+"::text[])")
+	}
+	if len(q.Tags) > 0 {
+		conds = append(conds, "tags && "+args.Add(q.Tags)+"::text[]")
+	}
+	conds = append(conds, dateRange("created_at", q.CreatedFrom, q.CreatedTo, args)...)
+	conds = append(conds, dateRange("last_run_at", q.LastRunFrom, q.LastRunTo, args)...)
+	if len(q.Terms) > 0 {
+		prefixes := make([]string, 0, len(q.Terms))
+		for _, t := range q.Terms {
+			prefixes = append(prefixes, t+":*")
+		}
+		conds = append(conds, nameSearch+" @@ to_tsquery('simple', "+args.Add(strings.Join(prefixes, " & "))+")")
+	}
+	if q.Cursor != nil {
+		conds = append(conds, q.order().After(*q.Cursor, args))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// dateRange returns the predicates keeping column on or after the from
+// date and on or before the to date. Rows where column is NULL fall
+// outside any range.
+func dateRange(column string, from, to *time.Time, args *keyset.Args) []string {
+	var conds []string
+	if from != nil {
+		conds = append(conds, column+" >= "+args.Add(*from))
+	}
+	if to != nil {
+		conds = append(conds, column+" < "+args.Add(to.AddDate(0, 0, 1)))
+	}
+	return conds
+}
+
+// fetchLimit is how many rows the store should read: one past the page so
+// page can tell whether another page follows. 0 means no limit.
+func (q ListQuery) fetchLimit() int {
+	if q.Limit == 0 {
+		return 0
+	}
+	return q.Limit + 1
+}
+
+// page trims rows read with fetchLimit to the page size and returns the
+// cursor for the next page, which is empty on the last page.
+func (q ListQuery) page(rows []Portfolio) ([]Portfolio, string) {
+	if q.Limit == 0 || len(rows) <= q.Limit {
+		return rows, ""
+	}
+	rows = rows[:q.Limit]
+	last := rows[len(rows)-1]
+	v := sortFields[q.SortField].value(last)
+	return rows, keyset.Encode(keyset.Cursor{Sort: q.sortToken(), Value: v, Key: last.Slug})
+}
+
+func (q ListQuery) sortToken() string {
+	if q.Descending {
+		return "-" + q.SortField
+	}
+	return q.SortField
+}
+
+// splitList splits a comma-separated query value, dropping blanks.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// words lower-cases s and splits it into runs of letters and digits. Each
+// becomes a prefix term of the name search, so only these characters reach
+// to_tsquery.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
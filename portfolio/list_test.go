@@ -0,0 +1,265 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// listSlugs fetches GET /portfolios with query and returns the status,
+// the slugs in order and the next-page cursor.
+func listSlugs(app *fiber.App, query string) (int, []string, string) {
+	resp, err := app.Test(httptest.NewRequest("GET", "/portfolios"+query, nil))
+	Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	Expect(err).NotTo(HaveOccurred())
+	if resp.StatusCode != 200 {
+		return resp.StatusCode, nil, ""
+	}
+	var out []map[string]any
+	Expect(sonic.Unmarshal(body, &out)).To(Succeed())
+	slugs := make([]string, 0, len(out))
+	for _, p := range out {
+		slugs = append(slugs, p["slug"].(string))
+	}
+	return resp.StatusCode, slugs, resp.Header.Get(portfolio.HeaderNextCursor)
+}
+
+var _ = Describe("GET /portfolios query", func() {
+	var (
+		app   *fiber.App
+		store *fakeStore
+		day   = func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	)
+
+	BeforeEach(func() {
+		f := func(v float64) *float64 { return &v }
+		store = &fakeStore{rows: []portfolio.Portfolio{
+			{OwnerSub: "auth0|user-1", Slug: "adm-growth", Sharpe: f(0.9), CreatedAt: day(1)},
+			{OwnerSub: "auth0|user-1", Slug: "adm-income", Sharpe: f(1.3), CreatedAt: day(2)},
+			{OwnerSub: "auth0|user-1", Slug: "daa-kids", CreatedAt: day(3)},
+			{OwnerSub: "auth0|user-2", Slug: "theirs", CreatedAt: day(4)},
+		}}
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, nil, nil, nil, nil, strategy.EphemeralOptions{})
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|user-1")
+			return c.Next()
+		})
+		app.Get("/portfolios", h.List)
+		app.Patch("/portfolios/:slug", h.Patch)
+	})
+
+	list := func(query string) (int, []string, string) { return listSlugs(app, query) }
+
+	lastQuery := func() portfolio.ListQuery {
+		Expect(store.listQueries).NotTo(BeEmpty())
+		return store.listQueries[len(store.listQueries)-1]
+	}
+
+	It("defaults to every portfolio, newest first", func() {
+		_, slugs, next := list("")
+		Expect(slugs).To(Equal([]string{"adm-growth", "adm-income", "daa-kids"}))
+		Expect(next).To(BeEmpty())
+		Expect(lastQuery()).To(Equal(portfolio.ListQuery{SortField: "createdAt", Descending: true}))
+	})
+
+	It("passes filters, search and sort to the store", func() {
+		status, _, _ := list("?strategy=adm,kel&status=failed,pending&benchmark=SPY&tag=Income" +
+			"&createdFrom=2026-03-02&lastRunTo=2026-03-10&q=ADM+gro&sort=sharpe")
+		Expect(status).To(Equal(200))
+		q := lastQuery()
+		Expect(q.Strategies).To(Equal([]string{"adm", "kel"}))
+		Expect(q.Statuses).To(Equal([]portfolio.Status{portfolio.StatusFailed, portfolio.StatusPending}))
+		Expect(q.Benchmarks).To(Equal([]string{"SPY"}))
+		Expect(q.Tags).To(Equal([]string{"income"}))
+		Expect(q.CreatedFrom).To(HaveValue(Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))))
+		Expect(q.CreatedTo).To(BeNil())
+		Expect(q.LastRunTo).To(HaveValue(Equal(time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))))
+		Expect(q.Terms).To(Equal([]string{"adm", "gro"}))
+		Expect(q.SortField).To(Equal("sharpe"))
+		Expect(q.Descending).To(BeFalse())
+	})
+
+	It("trims the extra row into a cursor that resumes after the page", func() {
+		_, slugs, next := list("?sort=-sharpe&limit=2")
+		Expect(slugs).To(Equal([]string{"adm-growth", "adm-income"}))
+		Expect(next).NotTo(BeEmpty())
+
+		status, _, _ := list("?sort=-sharpe&limit=2&cursor=" + next)
+		Expect(status).To(Equal(200))
+		q := lastQuery()
+		Expect(q.Limit).To(Equal(2))
+		Expect(q.Cursor).NotTo(BeNil())
+		Expect(q.Cursor.Key).To(Equal("adm-income"))
+		Expect(q.Cursor.Value).To(HaveValue(Equal(1.3)))
+
+		_, _, next = list("?limit=1")
+		list("?limit=1&cursor=" + next)
+		Expect(lastQuery().Cursor.Value).To(HaveValue(Equal(float64(day(1).UnixMicro()))))
+	})
+
+	It("omits the cursor on the last page", func() {
+		_, slugs, next := list("?limit=3")
+		Expect(slugs).To(HaveLen(3))
+		Expect(next).To(BeEmpty())
+	})
+
+	It("rejects bad parameters with 422", func() {
+		_, _, next := list("?limit=1&sort=-sharpe")
+		for _, q := range []string{
+			"?sort=name", "?limit=0", "?limit=500", "?status=archived",
+			"?createdFrom=March", "?cursor=%%%", "?sort=sharpe&cursor=" + next,
+		} {
+			status, _, _ := list(q)
+			Expect(status).To(Equal(422), q)
+		}
+	})
+
+	It("patches tags, normalised", func() {
+		patch := func(body string) int {
+			req := httptest.NewRequest("PATCH", "/portfolios/daa-kids", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+		Expect(patch(`{"tags": [" Income ", "income", "taxable"]}`)).To(Equal(200))
+		Expect(store.rows[2].Tags).To(Equal([]string{"income", "taxable"}))
+
+		Expect(patch(`{"tags": ["a,b"]}`)).To(Equal(422))
+		Expect(patch(`{"tags": []}`)).To(Equal(200))
+		Expect(store.rows[2].Tags).To(BeEmpty())
+	})
+})
+
+// These specs run GET /portfolios against Postgres so the filter, search,
+// sort and keyset SQL is exercised for real. The rows belong to an owner
+// made up for the run, so other rows in the database never leak in.
+var _ = Describe("PoolStore.ListPage", Ordered, func() {
+	var (
+		pool  *pgxpool.Pool
+		app   *fiber.App
+		ctx   = context.Background()
+		owner = "smoke|list-" + uuid.NewString()[:8]
+	)
+
+	BeforeAll(func() {
+		dbURL := os.Getenv("PVAPI_SMOKE_DB_URL")
+		if dbURL == "" {
+			Skip("PVAPI_SMOKE_DB_URL not set; skipping portfolio list smoke test")
+		}
+		var err error
+		pool, err = pgxpool.New(ctx, dbURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO strategies (short_code, repo_owner, repo_name, clone_url, is_official)
+			VALUES ('__lp_adm', 'smoke', 'adm', '', true),
+			       ('__lp_daa', 'smoke', 'daa', '', true),
+			       ('__lp_kel', 'smoke', 'kel', '', true)
+			ON CONFLICT (short_code) DO NOTHING
+		`)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = pool.Exec(ctx, `
+			INSERT INTO portfolios (owner_sub, slug, name, strategy_code, strategy_ver, strategy_describe_json,
+				parameters, benchmark, status, tags, current_value, sharpe, created_at, last_run_at)
+			VALUES
+				($1, 'adm-growth', 'ADM Growth',          '__lp_adm', 'v1', '{}', '{}', 'SPY', 'ready',   '{retirement}',        120,  0.9,  '2026-03-01T12:00:00Z', '2026-03-10T12:00:00Z'),
+				($1, 'adm-income', 'ADM Income',          '__lp_adm', 'v1', '{}', '{}', 'AGG', 'ready',   '{income,retirement}', 90,   1.3,  '2026-03-02T12:00:00Z', '2026-03-10T12:00:00Z'),
+				($1, 'daa-kids',   'Kids'' college (DAA)', '__lp_daa', 'v1', '{}', '{}', 'SPY', 'failed',  '{}',                  NULL, NULL, '2026-03-03T12:00:00Z', NULL),
+				($1, 'kel-new',    'Keller growth',       '__lp_kel', 'v1', '{}', '{}', 'spy', 'pending', '{}',                  NULL, NULL, '2026-03-04T12:00:00Z', NULL)
+		`, owner)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() {
+			_, _ = pool.Exec(ctx, `DELETE FROM portfolios WHERE owner_sub = $1`, owner)
+			pool.Close()
+		})
+
+		h := portfolio.NewHandler(portfolio.NewPoolStore(pool), &fakeStrategyStore{}, nil, nil, nil, nil, strategy.EphemeralOptions{})
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, owner)
+			return c.Next()
+		})
+		app.Get("/portfolios", h.List)
+	})
+
+	list := func(query string) []string {
+		status, slugs, _ := listSlugs(app, query)
+		Expect(status).To(Equal(200), query)
+		return slugs
+	}
+
+	It("defaults to every portfolio, newest first", func() {
+		Expect(list("")).To(Equal([]string{"kel-new", "daa-kids", "adm-income", "adm-growth"}))
+	})
+
+	It("filters by strategy, status, benchmark, tag and dates", func() {
+		Expect(list("?strategy=__lp_adm,__lp_kel")).To(Equal([]string{"kel-new", "adm-income", "adm-growth"}))
+		Expect(list("?status=failed,pending")).To(Equal([]string{"kel-new", "daa-kids"}))
+		Expect(list("?benchmark=SPY")).To(Equal([]string{"kel-new", "daa-kids", "adm-growth"}))
+		Expect(list("?tag=Income")).To(Equal([]string{"adm-income"}))
+		Expect(list("?createdFrom=2026-03-02&createdTo=2026-03-03")).To(Equal([]string{"daa-kids", "adm-income"}))
+		Expect(list("?lastRunFrom=2026-03-10")).To(Equal([]string{"adm-income", "adm-growth"}))
+	})
+
+	It("searches names by word prefix", func() {
+		Expect(list("?q=growth")).To(Equal([]string{"kel-new", "adm-growth"}))
+		Expect(list("?q=adm+gro")).To(Equal([]string{"adm-growth"}))
+		Expect(list("?q=coll")).To(Equal([]string{"daa-kids"}))
+		Expect(list("?q=rowth")).To(BeEmpty())
+	})
+
+	It("sorts on a KPI column with missing values last", func() {
+		Expect(list("?sort=-sharpe")).To(Equal([]string{"adm-income", "adm-growth", "daa-kids", "kel-new"}))
+		Expect(list("?sort=currentValue")).To(Equal([]string{"adm-income", "adm-growth", "daa-kids", "kel-new"}))
+	})
+
+	It("pages with a keyset cursor through NULL sort keys", func() {
+		for _, sort := range []string{"createdAt", "-createdAt", "-sharpe", "currentValue"} {
+			var all []string
+			query := "?limit=3&sort=" + sort
+			for range 5 {
+				_, slugs, next := listSlugs(app, query)
+				all = append(all, slugs...)
+				if next == "" {
+					break
+				}
+				query = "?limit=3&sort=" + sort + "&cursor=" + next
+			}
+			Expect(all).To(Equal(list("?sort="+sort)), sort)
+		}
+	})
+})
@@ -0,0 +1,222 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/penny-vault/pv-api/strategy"
+)
+
+// stepTolerance absorbs float rounding when checking a value against a
+// declared step grid (e.g. 0.1 steps).
+const stepTolerance = 1e-9
+
+// checkParameterValue wraps constraintViolation in ErrInvalidParameter.
+func checkParameterValue(p strategy.DescribeParameter, v any) error {
+	if reason := constraintViolation(p, v); reason != "" {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidParameter, p.Name, reason)
+	}
+	return nil
+}
+
+// constraintViolation checks v against the type and constraint metadata on
+// p and returns the failed constraint, or "" when v is acceptable. Types
+// pvapi does not recognise are accepted as-is so new pvbt types do not
+// block portfolio creation.
+func constraintViolation(p strategy.DescribeParameter, v any) string {
+	switch strings.ToLower(p.Type) {
+	case "int", "int64", "integer":
+		f, ok := toFloat(v)
+		if !ok || f != math.Trunc(f) {
+			return "must be an integer"
+		}
+	case "float", "float64", "number":
+		if _, ok := toFloat(v); !ok {
+			return "must be a number"
+		}
+	case "bool", "boolean":
+		if _, ok := v.(bool); !ok {
+			return "must be a boolean"
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return "must be a string"
+		}
+	case "universe":
+		tickers, ok := universeTickers(v)
+		if !ok {
+			return "must be a comma-separated ticker list or an array of tickers"
+		}
+		if reason := checkUniverse(p.Universe, tickers); reason != "" {
+			return reason
+		}
+	}
+
+	if len(p.Enum) > 0 && !enumContains(p.Enum, v) {
+		return fmt.Sprintf("must be one of %s", formatEnum(p.Enum))
+	}
+
+	if p.Min == nil && p.Max == nil && p.Step == nil {
+		return ""
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return "must be a number"
+	}
+	if p.Min != nil && f < *p.Min {
+		return fmt.Sprintf("must be >= %g", *p.Min)
+	}
+	if p.Max != nil && f > *p.Max {
+		return fmt.Sprintf("must be <= %g", *p.Max)
+	}
+	if p.Step != nil && *p.Step > 0 {
+		base := 0.0
+		if p.Min != nil {
+			base = *p.Min
+		}
+		k := (f - base) / *p.Step
+		if math.Abs(k-math.Round(k)) > stepTolerance {
+			return fmt.Sprintf("must be a multiple of %g from %g", *p.Step, base)
+		}
+	}
+	return ""
+}
+
+// checkUniverse returns a human-readable reason when tickers violate c, or
+// "" when they satisfy it (or c is nil).
+func checkUniverse(c *strategy.UniverseConstraint, tickers []string) string {
+	if c == nil {
+		return ""
+	}
+	if c.MinSize > 0 && len(tickers) < c.MinSize {
+		return fmt.Sprintf("must contain at least %d tickers", c.MinSize)
+	}
+	if c.MaxSize > 0 && len(tickers) > c.MaxSize {
+		return fmt.Sprintf("must contain at most %d tickers", c.MaxSize)
+	}
+	if len(c.Allowed) > 0 {
+		allowed := make(map[string]struct{}, len(c.Allowed))
+		for _, t := range c.Allowed {
+			allowed[strings.ToUpper(t)] = struct{}{}
+		}
+		for _, t := range tickers {
+			if _, ok := allowed[strings.ToUpper(t)]; !ok {
+				return fmt.Sprintf("ticker %s is not in the allowed universe", t)
+			}
+		}
+	}
+	return ""
+}
+
+// universeTickers accepts the two shapes backtest.BuildArgs knows how to
+// pass on the command line: a comma-separated string or an array of strings.
+func universeTickers(v any) ([]string, bool) {
+	var raw []string
+	switch vv := v.(type) {
+	case string:
+		raw = strings.Split(vv, ",")
+	case []string:
+		raw = vv
+	case []any:
+		for _, e := range vv {
+			s, ok := e.(string)
+			if !ok {
+				return nil, false
+			}
+			raw = append(raw, s)
+		}
+	default:
+		return nil, false
+	}
+	out := make([]string, 0, len(raw))
+	for _, t := range raw {
+		if t = strings.TrimSpace(t); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out, true
+}
+
+// toFloat converts the numeric shapes a decoded JSON body or a Go test
+// literal can carry into float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// enumContains compares numerically when both sides are numbers so that
+// 5 and 5.0 match, and by deep equality otherwise.
+func enumContains(enum []any, v any) bool {
+	vf, vNum := toFloat(v)
+	for _, e := range enum {
+		if ef, ok := toFloat(e); ok && vNum {
+			if ef == vf {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		parts[i] = fmt.Sprintf("%v", e)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// sameConstraints reports whether two declarations of a parameter carry the
+// same constraint metadata. Group is a UI hint and is ignored.
+func sameConstraints(a, b strategy.DescribeParameter) bool {
+	return reflect.DeepEqual(a.Enum, b.Enum) &&
+		floatPtrEqual(a.Min, b.Min) &&
+		floatPtrEqual(a.Max, b.Max) &&
+		floatPtrEqual(a.Step, b.Step) &&
+		a.IsRequired() == b.IsRequired() &&
+		reflect.DeepEqual(a.Universe, b.Universe)
+}
+
+func floatPtrEqual(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/penny-vault/pv-api/progress"
+	"github.com/penny-vault/pv-api/workspace"
 )
 
 // WithHub attaches a progress Hub for the SSE progress streaming endpoint.
@@ -49,14 +50,14 @@ func (h *Handler) StreamRunProgress(c fiber.Ctx) error {
 		return writeProblem(c, fiber.StatusNotImplemented, "Not Implemented", "progress streaming not configured")
 	}
 
-	sub, err := subject(c)
-	if err != nil {
-		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleViewer)
+	if !ok {
+		return err
 	}
 
 	// Copy params off the Fiber context before any goroutine boundary.
 	slug := string([]byte(c.Params("slug")))
-	p, err := h.store.Get(c.Context(), sub, slug)
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
 	if errors.Is(err, ErrNotFound) {
 		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
 	}
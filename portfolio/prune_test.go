@@ -128,7 +128,8 @@ var _ = Describe("PruneRuns", Ordered, func() {
 	It("honors a per-portfolio override", func() {
 		store, runStore, portID, ownerSub, slug := seedPrunePortfolio(ctx, pool)
 
-		Expect(store.UpdateRunRetention(ctx, ownerSub, slug, 1)).To(Succeed())
+		one := 1
+		Expect(store.Update(ctx, ownerSub, slug, portfolio.Changes{RunRetention: &one}, nil)).To(Succeed())
 
 		for i := 0; i < 3; i++ {
 			run, err := runStore.CreateRun(ctx, portID, "queued", "scheduled")
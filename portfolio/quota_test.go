@@ -0,0 +1,97 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/quota"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// fixedUsage is a quota.Counter that reports the same usage for everyone.
+type fixedUsage quota.Usage
+
+func (f fixedUsage) Usage(context.Context, string) (quota.Usage, error) { return quota.Usage(f), nil }
+
+var _ = Describe("Quotas", func() {
+	installedVer := "v1.0.0"
+	describeJSON := []byte(`{"shortCode":"adm","name":"ADM","description":"","parameters":[{"name":"riskOn","type":"universe"}],"presets":[],"schedule":"@monthend","benchmark":"SPY"}`)
+	limits := quota.Limits{Portfolios: 2, RunsPerDay: 3}
+
+	var (
+		disp *countingDispatcher
+		app  *fiber.App
+	)
+
+	setup := func(usage quota.Usage) {
+		store := &fakeStore{rows: []portfolio.Portfolio{
+			{ID: uuid.Must(uuid.NewV7()), OwnerSub: "auth0|user-1", Slug: "mine", Status: portfolio.StatusReady},
+		}}
+		strategies := &fakeStrategyStore{row: strategy.Strategy{
+			ShortCode: "adm", IsOfficial: true, InstalledVer: &installedVer, DescribeJSON: describeJSON,
+		}}
+		disp = &countingDispatcher{runID: uuid.Must(uuid.NewV7())}
+		h := portfolio.NewHandler(store, strategies, nil, disp, nil, nil, strategy.EphemeralOptions{}).
+			WithQuotas(limits, fixedUsage(usage))
+		app = fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|user-1")
+			return c.Next()
+		})
+		app.Post("/portfolios", h.Create)
+		app.Post("/portfolios/:slug/run", h.CreateRun)
+	}
+
+	post := func(target, body string) int {
+		req := httptest.NewRequest("POST", target, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	const createBody = `{"name":"test","strategyCode":"adm","parameters":{"riskOn":"SPY"}}`
+
+	It("allows creates and runs under the limits", func() {
+		setup(quota.Usage{Portfolios: 1, RunsToday: 2})
+		Expect(post("/portfolios", createBody)).To(Equal(fiber.StatusCreated))
+		Expect(post("/portfolios/mine/run", "")).To(Equal(fiber.StatusAccepted))
+	})
+
+	It("refuses a portfolio over the limit with 403", func() {
+		setup(quota.Usage{Portfolios: 2})
+		Expect(post("/portfolios", createBody)).To(Equal(fiber.StatusForbidden))
+		Expect(disp.calls.Load()).To(BeZero())
+	})
+
+	It("refuses runs over the daily limit with 429", func() {
+		setup(quota.Usage{Portfolios: 1, RunsToday: 3})
+		Expect(post("/portfolios/mine/run", "")).To(Equal(fiber.StatusTooManyRequests))
+		Expect(post("/portfolios", createBody)).To(Equal(fiber.StatusTooManyRequests))
+		Expect(disp.calls.Load()).To(BeZero())
+	})
+})
@@ -0,0 +1,157 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrShareNotFound is returned when a portfolio is not shared or a share
+// reference does not resolve.
+var ErrShareNotFound = errors.New("share not found")
+
+// ErrHandleTaken is returned when another portfolio already uses a handle.
+var ErrHandleTaken = errors.New("share handle already taken")
+
+// handleRe is the shape of a public handle: 3-40 lowercase letters, digits
+// and inner hyphens. Tokens are 43 characters, so the two never collide.
+var handleRe = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,38}[a-z0-9]$`)
+
+// ValidHandle reports whether s can be used as a public share handle.
+func ValidHandle(s string) bool { return handleRe.MatchString(s) }
+
+// Share publishes a read-only view of one portfolio.
+type Share struct {
+	ID          uuid.UUID
+	PortfolioID uuid.UUID
+	Token       string
+	Handle      *string
+	// HideValues rebases dollar figures so only returns are visible.
+	HideValues bool
+	// ShowHoldings exposes the current holdings.
+	ShowHoldings bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+
+	// OwnerSub and Slug locate the shared portfolio; filled on reads.
+	OwnerSub string
+	Slug     string
+}
+
+// NewShareToken returns a random, URL-safe share token.
+func NewShareToken() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// ShareStore persists public share links.
+type ShareStore interface {
+	GetShare(ctx context.Context, portfolioID uuid.UUID) (Share, error)
+	// SaveShare creates or replaces the portfolio's share. Returns
+	// ErrHandleTaken when the handle belongs to another portfolio.
+	SaveShare(ctx context.Context, s Share) (Share, error)
+	DeleteShare(ctx context.Context, portfolioID uuid.UUID) error
+	// ResolveShare looks a share up by its token or handle.
+	ResolveShare(ctx context.Context, ref string) (Share, error)
+}
+
+// PoolShareStore is the pgxpool-backed ShareStore.
+type PoolShareStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolShareStore(pool *pgxpool.Pool) *PoolShareStore { return &PoolShareStore{pool: pool} }
+
+const shareColumns = `s.id, s.portfolio_id, s.token, s.handle, s.hide_values, s.show_holdings,
+	s.created_at, s.updated_at, p.owner_sub, p.slug`
+
+func scanShare(row pgx.Row) (Share, error) {
+	var s Share
+	err := row.Scan(&s.ID, &s.PortfolioID, &s.Token, &s.Handle, &s.HideValues, &s.ShowHoldings,
+		&s.CreatedAt, &s.UpdatedAt, &s.OwnerSub, &s.Slug)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Share{}, ErrShareNotFound
+	}
+	return s, err
+}
+
+func (s *PoolShareStore) GetShare(ctx context.Context, portfolioID uuid.UUID) (Share, error) {
+	sh, err := scanShare(s.pool.QueryRow(ctx, `
+		SELECT `+shareColumns+`
+		  FROM portfolio_shares s JOIN portfolios p ON p.id = s.portfolio_id
+		 WHERE s.portfolio_id = $1`, portfolioID))
+	if err != nil && !errors.Is(err, ErrShareNotFound) {
+		return Share{}, fmt.Errorf("get share: %w", err)
+	}
+	return sh, err
+}
+
+func (s *PoolShareStore) SaveShare(ctx context.Context, in Share) (Share, error) {
+	sh, err := scanShare(s.pool.QueryRow(ctx, `
+		WITH s AS (
+			INSERT INTO portfolio_shares (portfolio_id, token, handle, hide_values, show_holdings)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (portfolio_id) DO UPDATE
+			   SET token = EXCLUDED.token, handle = EXCLUDED.handle,
+			       hide_values = EXCLUDED.hide_values, show_holdings = EXCLUDED.show_holdings,
+			       updated_at = now()
+			RETURNING *
+		)
+		SELECT `+shareColumns+` FROM s JOIN portfolios p ON p.id = s.portfolio_id`,
+		in.PortfolioID, in.Token, in.Handle, in.HideValues, in.ShowHoldings))
+	if uniqueViolation(err) {
+		return Share{}, ErrHandleTaken
+	}
+	if err != nil {
+		return Share{}, fmt.Errorf("save share: %w", err)
+	}
+	return sh, nil
+}
+
+func (s *PoolShareStore) DeleteShare(ctx context.Context, portfolioID uuid.UUID) error {
+	tag, err := s.pool.Exec(ctx, `DELETE FROM portfolio_shares WHERE portfolio_id = $1`, portfolioID)
+	if err != nil {
+		return fmt.Errorf("delete share: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
+
+func (s *PoolShareStore) ResolveShare(ctx context.Context, ref string) (Share, error) {
+	sh, err := scanShare(s.pool.QueryRow(ctx, `
+		SELECT `+shareColumns+`
+		  FROM portfolio_shares s JOIN portfolios p ON p.id = s.portfolio_id
+		 WHERE s.token = $1 OR s.handle = $1`, ref))
+	if err != nil && !errors.Is(err, ErrShareNotFound) {
+		return Share{}, fmt.Errorf("resolve share: %w", err)
+	}
+	return sh, err
+}
@@ -0,0 +1,399 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// sharedGrowthBase is the value hidden-value performance series start at,
+// so a shared chart reads as "growth of 10,000".
+const sharedGrowthBase = 10000.0
+
+// sharedCacheControl lets browsers and CDNs cache public reads briefly;
+// snapshots change at most once per run.
+const sharedCacheControl = "public, max-age=300"
+
+// WithShares sets the store for public share links. Without one the share
+// endpoints answer 503 and shared links do not resolve.
+func (h *Handler) WithShares(s ShareStore) *Handler {
+	h.shares = s
+	return h
+}
+
+func (h *Handler) sharesUnavailable(c fiber.Ctx) error {
+	return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable",
+		"portfolio sharing is not configured on this server")
+}
+
+// ownedPortfolio looks up the portfolio named by the :slug param, which the
+// caller must be able to act on with need, writing the problem response
+// itself when it cannot.
+func (h *Handler) ownedPortfolio(c fiber.Ctx, need workspace.Role) (Portfolio, bool, error) {
+	ownerSub, ok, err := h.owner(c, need)
+	if !ok {
+		return Portfolio{}, false, err
+	}
+	slug := string([]byte(c.Params("slug")))
+	p, err := h.store.Get(c.Context(), ownerSub, slug)
+	if errors.Is(err, ErrNotFound) {
+		return Portfolio{}, false, writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	}
+	if err != nil {
+		return Portfolio{}, false, writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return p, true, nil
+}
+
+// GetShare implements GET /portfolios/{slug}/share.
+func (h *Handler) GetShare(c fiber.Ctx) error {
+	if h.shares == nil {
+		return h.sharesUnavailable(c)
+	}
+	p, ok, err := h.ownedPortfolio(c, workspace.RoleViewer)
+	if !ok {
+		return err
+	}
+	sh, err := h.shares.GetShare(c.Context(), p.ID)
+	if errors.Is(err, ErrShareNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio is not shared: "+p.Slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return writeJSON(c, fiber.StatusOK, toShareView(c, sh))
+}
+
+type shareBody struct {
+	Handle       *string `json:"handle"`
+	HideValues   bool    `json:"hideValues"`
+	ShowHoldings bool    `json:"showHoldings"`
+	RotateToken  bool    `json:"rotateToken"`
+}
+
+// PutShare implements PUT /portfolios/{slug}/share: it publishes the
+// portfolio, or replaces the options of an existing share. The token is
+// kept unless rotateToken is set, which revokes the old link.
+func (h *Handler) PutShare(c fiber.Ctx) error {
+	if h.shares == nil {
+		return h.sharesUnavailable(c)
+	}
+	var body shareBody
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid body", err.Error())
+	}
+	if body.Handle != nil {
+		handle := strings.ToLower(strings.TrimSpace(*body.Handle))
+		switch {
+		case handle == "":
+			body.Handle = nil
+		case !ValidHandle(handle):
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "invalid handle",
+				"handle must be 3-40 lowercase letters, digits or hyphens, starting and ending with a letter or digit")
+		default:
+			body.Handle = &handle
+		}
+	}
+	p, ok, err := h.ownedPortfolio(c, workspace.RoleOwner)
+	if !ok {
+		return err
+	}
+
+	existing, err := h.shares.GetShare(c.Context(), p.ID)
+	created := errors.Is(err, ErrShareNotFound)
+	if err != nil && !created {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	token := existing.Token
+	if created || body.RotateToken {
+		if token, err = NewShareToken(); err != nil {
+			return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+	}
+	saved, err := h.shares.SaveShare(c.Context(), Share{
+		PortfolioID:  p.ID,
+		Token:        token,
+		Handle:       body.Handle,
+		HideValues:   body.HideValues,
+		ShowHoldings: body.ShowHoldings,
+	})
+	if errors.Is(err, ErrHandleTaken) {
+		return writeProblem(c, fiber.StatusConflict, "Conflict", "handle is already taken: "+*body.Handle)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	status := fiber.StatusOK
+	if created {
+		status = fiber.StatusCreated
+	}
+	return writeJSON(c, status, toShareView(c, saved))
+}
+
+// DeleteShare implements DELETE /portfolios/{slug}/share: it unpublishes
+// the portfolio; its token and handle stop resolving immediately.
+func (h *Handler) DeleteShare(c fiber.Ctx) error {
+	if h.shares == nil {
+		return h.sharesUnavailable(c)
+	}
+	p, ok, err := h.ownedPortfolio(c, workspace.RoleOwner)
+	if !ok {
+		return err
+	}
+	err = h.shares.DeleteShare(c.Context(), p.ID)
+	if errors.Is(err, ErrShareNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio is not shared: "+p.Slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// readShared is the skeleton of the public /api/shared/{ref} reads. Unlike
+// readSnapshot it never queues a run: anonymous traffic must not trigger
+// backtests, so a portfolio without results answers 503.
+func (h *Handler) readShared(c fiber.Ctx, fn func(Share, Portfolio, SnapshotReader) (any, error)) error {
+	if h.shares == nil {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio sharing is not configured")
+	}
+	ref := string([]byte(c.Params("ref")))
+	sh, err := h.shares.ResolveShare(c.Context(), ref)
+	if errors.Is(err, ErrShareNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "shared portfolio not found")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	p, err := h.store.Get(c.Context(), sh.OwnerSub, sh.Slug)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "shared portfolio not found")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if p.SnapshotPath == nil || *p.SnapshotPath == "" {
+		return writeProblem(c, fiber.StatusServiceUnavailable, "Snapshot Unavailable",
+			"the shared portfolio has no results yet")
+	}
+	reader, err := h.opener.Open(*p.SnapshotPath)
+	if err != nil {
+		return writeProblem(c, fiber.StatusServiceUnavailable, "Snapshot Unavailable",
+			"the shared portfolio's results are being recomputed")
+	}
+	defer func() { _ = reader.Close() }()
+	out, err := fn(sh, p, reader)
+	if errors.Is(err, errNotFoundSentinel) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "not found")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderCacheControl, sharedCacheControl)
+	return c.JSON(out)
+}
+
+// SharedPortfolio implements GET /api/shared/{ref}: what the share exposes.
+func (h *Handler) SharedPortfolio(c fiber.Ctx) error {
+	return h.readShared(c, func(sh Share, p Portfolio, _ SnapshotReader) (any, error) {
+		v := sharedPortfolioView{
+			Name:         p.Name,
+			StrategyCode: p.StrategyCode,
+			Benchmark:    p.Benchmark,
+			Handle:       sh.Handle,
+			HideValues:   sh.HideValues,
+			ShowHoldings: sh.ShowHoldings,
+		}
+		if p.InceptionDate != nil {
+			d := p.InceptionDate.Format("2006-01-02")
+			v.InceptionDate = &d
+		}
+		if p.LastRunAt != nil {
+			t := p.LastRunAt.UTC().Format(time.RFC3339)
+			v.LastRunAt = &t
+		}
+		return v, nil
+	})
+}
+
+// SharedSummary implements GET /api/shared/{ref}/summary.
+func (h *Handler) SharedSummary(c fiber.Ctx) error {
+	return h.readShared(c, func(sh Share, _ Portfolio, r SnapshotReader) (any, error) {
+		s, err := r.Summary(c.Context())
+		if err != nil || s == nil {
+			return s, err
+		}
+		v := sharedSummaryView{PortfolioSummary: *s, CurrentValue: &s.CurrentValue}
+		if sh.HideValues {
+			v.CurrentValue = nil
+		}
+		return v, nil
+	})
+}
+
+// SharedPerformance implements GET /api/shared/{ref}/performance. With
+// hideValues the series is rebased to a growth of sharedGrowthBase.
+func (h *Handler) SharedPerformance(c fiber.Ctx) error {
+	from, to, perr := parseFromTo(c)
+	if perr != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", perr.Error())
+	}
+	return h.readShared(c, func(sh Share, _ Portfolio, r SnapshotReader) (any, error) {
+		perf, err := r.Performance(c.Context(), "", from, to)
+		if err != nil || perf == nil {
+			return perf, err
+		}
+		perf.PortfolioSlug = ""
+		if sh.Handle != nil {
+			perf.PortfolioSlug = *sh.Handle
+		}
+		if sh.HideValues {
+			rebasePerformance(perf.Points)
+		}
+		return perf, nil
+	})
+}
+
+// rebasePerformance scales points so the portfolio starts at
+// sharedGrowthBase. The benchmark is normalized to the portfolio's opening
+// value, so the same factor applies to it.
+func rebasePerformance(points []openapi.PerformancePoint) {
+	if len(points) == 0 || points[0].PortfolioValue == 0 {
+		return
+	}
+	k := sharedGrowthBase / points[0].PortfolioValue
+	for i := range points {
+		points[i].PortfolioValue *= k
+		points[i].BenchmarkValue *= k
+	}
+}
+
+// SharedDrawdowns implements GET /api/shared/{ref}/drawdowns.
+func (h *Handler) SharedDrawdowns(c fiber.Ctx) error {
+	return h.readShared(c, func(_ Share, _ Portfolio, r SnapshotReader) (any, error) {
+		return r.Drawdowns(c.Context())
+	})
+}
+
+// SharedTrailingReturns implements GET /api/shared/{ref}/trailing-returns.
+func (h *Handler) SharedTrailingReturns(c fiber.Ctx) error {
+	return h.readShared(c, func(_ Share, _ Portfolio, r SnapshotReader) (any, error) {
+		return r.TrailingReturns(c.Context())
+	})
+}
+
+// SharedHoldings implements GET /api/shared/{ref}/holdings. It answers 404
+// unless the owner opted in with showHoldings; with hideValues only weights
+// are returned.
+func (h *Handler) SharedHoldings(c fiber.Ctx) error {
+	return h.readShared(c, func(sh Share, _ Portfolio, r SnapshotReader) (any, error) {
+		if !sh.ShowHoldings {
+			return nil, errNotFoundSentinel
+		}
+		hr, err := r.CurrentHoldings(c.Context())
+		if err != nil || hr == nil {
+			return hr, err
+		}
+		out := sharedHoldingsView{Date: hr.Date.Format("2006-01-02"), Items: make([]sharedHolding, 0, len(hr.Items))}
+		if !sh.HideValues {
+			out.TotalMarketValue = &hr.TotalMarketValue
+		}
+		for _, it := range hr.Items {
+			item := sharedHolding{Ticker: it.Ticker, Figi: it.Figi, DayChange: it.DayChange}
+			if hr.TotalMarketValue != 0 {
+				item.Weight = it.MarketValue / hr.TotalMarketValue
+			}
+			if !sh.HideValues {
+				item.Quantity = &it.Quantity
+				item.MarketValue = &it.MarketValue
+				item.AvgCost = &it.AvgCost
+			}
+			out.Items = append(out.Items, item)
+		}
+		return out, nil
+	})
+}
+
+type shareView struct {
+	Token        string  `json:"token"`
+	Handle       *string `json:"handle"`
+	HideValues   bool    `json:"hideValues"`
+	ShowHoldings bool    `json:"showHoldings"`
+	URL          string  `json:"url"`
+	HandleURL    *string `json:"handleUrl,omitempty"`
+	CreatedAt    string  `json:"createdAt"`
+	UpdatedAt    string  `json:"updatedAt"`
+}
+
+func toShareView(c fiber.Ctx, sh Share) shareView {
+	base := c.BaseURL() + "/api/shared/"
+	v := shareView{
+		Token:        sh.Token,
+		Handle:       sh.Handle,
+		HideValues:   sh.HideValues,
+		ShowHoldings: sh.ShowHoldings,
+		URL:          base + sh.Token,
+		CreatedAt:    sh.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:    sh.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if sh.Handle != nil {
+		u := base + *sh.Handle
+		v.HandleURL = &u
+	}
+	return v
+}
+
+type sharedPortfolioView struct {
+	Name          string  `json:"name"`
+	StrategyCode  string  `json:"strategyCode"`
+	Benchmark     string  `json:"benchmark"`
+	Handle        *string `json:"handle"`
+	InceptionDate *string `json:"inceptionDate"`
+	LastRunAt     *string `json:"lastRunAt"`
+	HideValues    bool    `json:"hideValues"`
+	ShowHoldings  bool    `json:"showHoldings"`
+}
+
+// sharedSummaryView is the summary with currentValue made optional; the
+// outer field shadows the embedded one when marshaling.
+type sharedSummaryView struct {
+	openapi.PortfolioSummary
+	CurrentValue *float64 `json:"currentValue,omitempty"`
+}
+
+type sharedHoldingsView struct {
+	Date             string          `json:"date"`
+	Items            []sharedHolding `json:"items"`
+	TotalMarketValue *float64        `json:"totalMarketValue,omitempty"`
+}
+
+type sharedHolding struct {
+	Ticker      string   `json:"ticker"`
+	Figi        *string  `json:"figi,omitempty"`
+	Weight      float64  `json:"weight"`
+	DayChange   *float64 `json:"dayChange,omitempty"`
+	Quantity    *float64 `json:"quantity,omitempty"`
+	MarketValue *float64 `json:"marketValue,omitempty"`
+	AvgCost     *float64 `json:"avgCost,omitempty"`
+}
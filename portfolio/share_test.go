@@ -0,0 +1,291 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/openapi"
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+)
+
+// fakeShareStore is an in-memory portfolio.ShareStore. It resolves owner
+// and slug through the portfolio fake, as the real store's join does.
+type fakeShareStore struct {
+	store  *fakeStore
+	shares []portfolio.Share
+}
+
+func (f *fakeShareStore) fill(s portfolio.Share) portfolio.Share {
+	for _, p := range f.store.rows {
+		if p.ID == s.PortfolioID {
+			s.OwnerSub, s.Slug = p.OwnerSub, p.Slug
+		}
+	}
+	return s
+}
+
+func (f *fakeShareStore) GetShare(_ context.Context, portfolioID uuid.UUID) (portfolio.Share, error) {
+	for _, s := range f.shares {
+		if s.PortfolioID == portfolioID {
+			return f.fill(s), nil
+		}
+	}
+	return portfolio.Share{}, portfolio.ErrShareNotFound
+}
+
+func (f *fakeShareStore) SaveShare(_ context.Context, in portfolio.Share) (portfolio.Share, error) {
+	for _, s := range f.shares {
+		if s.PortfolioID != in.PortfolioID && s.Handle != nil && in.Handle != nil && *s.Handle == *in.Handle {
+			return portfolio.Share{}, portfolio.ErrHandleTaken
+		}
+	}
+	in.UpdatedAt = time.Now()
+	for i, s := range f.shares {
+		if s.PortfolioID == in.PortfolioID {
+			in.ID, in.CreatedAt = s.ID, s.CreatedAt
+			f.shares[i] = in
+			return f.fill(in), nil
+		}
+	}
+	in.ID = uuid.Must(uuid.NewV7())
+	in.CreatedAt = in.UpdatedAt
+	f.shares = append(f.shares, in)
+	return f.fill(in), nil
+}
+
+func (f *fakeShareStore) DeleteShare(_ context.Context, portfolioID uuid.UUID) error {
+	for i, s := range f.shares {
+		if s.PortfolioID == portfolioID {
+			f.shares = append(f.shares[:i], f.shares[i+1:]...)
+			return nil
+		}
+	}
+	return portfolio.ErrShareNotFound
+}
+
+func (f *fakeShareStore) ResolveShare(_ context.Context, ref string) (portfolio.Share, error) {
+	for _, s := range f.shares {
+		if s.Token == ref || s.Handle != nil && *s.Handle == ref {
+			return f.fill(s), nil
+		}
+	}
+	return portfolio.Share{}, portfolio.ErrShareNotFound
+}
+
+var _ = Describe("Portfolio sharing", func() {
+	var (
+		app    *fiber.App
+		store  *fakeStore
+		shares *fakeShareStore
+		sub    = "auth0|owner"
+		path   = "/fake/snap.sqlite"
+	)
+
+	day := func(s string) openapi_types.Date {
+		t, err := time.Parse("2006-01-02", s)
+		Expect(err).NotTo(HaveOccurred())
+		return openapi_types.Date{Time: t}
+	}
+	ptr := func(v float64) *float64 { return &v }
+
+	do := func(method, target, body string) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		out, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(out)
+	}
+
+	share := func(body string) (token string) {
+		status, out := do("PUT", "/api/v3/portfolios/s1/share", body)
+		Expect(status).To(BeElementOf(fiber.StatusOK, fiber.StatusCreated), out)
+		var v struct {
+			Token string `json:"token"`
+		}
+		Expect(sonic.Unmarshal([]byte(out), &v)).To(Succeed())
+		return v.Token
+	}
+
+	BeforeEach(func() {
+		store = &fakeStore{}
+		shares = &fakeShareStore{store: store}
+		reader := &fakeSnapshotReader{
+			summary: &openapi.PortfolioSummary{CurrentValue: 123456.78, Sharpe: ptr(1.2)},
+			performance: &openapi.PortfolioPerformance{
+				From: day("2025-01-02"), To: day("2025-01-03"),
+				Points: []openapi.PerformancePoint{
+					{Date: day("2025-01-02"), PortfolioValue: 50000, BenchmarkValue: 50000},
+					{Date: day("2025-01-03"), PortfolioValue: 55000, BenchmarkValue: 51000},
+				},
+			},
+			holdings: &openapi.HoldingsResponse{
+				Date:             day("2025-01-03"),
+				TotalMarketValue: 55000,
+				Items: []openapi.Holding{
+					{Ticker: "SPY", Quantity: 50, MarketValue: 27500, AvgCost: 500},
+					{Ticker: "TLT", Quantity: 300, MarketValue: 27500, AvgCost: 90},
+				},
+			},
+		}
+		opener := &fakeSnapshotOpener{readers: map[string]portfolio.SnapshotReader{path: reader}}
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, opener, nil, nil, nil, strategy.EphemeralOptions{}).
+			WithShares(shares)
+
+		app = fiber.New(fiber.Config{JSONEncoder: sonic.Marshal, JSONDecoder: sonic.Unmarshal})
+		protected := app.Group("/api/v3", func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, sub)
+			return c.Next()
+		})
+		protected.Get("/portfolios/:slug/share", h.GetShare)
+		protected.Put("/portfolios/:slug/share", h.PutShare)
+		protected.Delete("/portfolios/:slug/share", h.DeleteShare)
+		app.Get("/api/shared/:ref", h.SharedPortfolio)
+		app.Get("/api/shared/:ref/summary", h.SharedSummary)
+		app.Get("/api/shared/:ref/performance", h.SharedPerformance)
+		app.Get("/api/shared/:ref/holdings", h.SharedHoldings)
+
+		store.rows = []portfolio.Portfolio{{
+			ID: uuid.Must(uuid.NewV7()), OwnerSub: sub, Slug: "s1", Name: "Core",
+			Status: portfolio.StatusReady, SnapshotPath: &path,
+		}}
+	})
+
+	It("publishes a portfolio under a token and a handle", func() {
+		status, out := do("PUT", "/api/v3/portfolios/s1/share", `{"handle":"My-Core"}`)
+		Expect(status).To(Equal(fiber.StatusCreated), out)
+		var v struct {
+			Token     string `json:"token"`
+			Handle    string `json:"handle"`
+			URL       string `json:"url"`
+			HandleURL string `json:"handleUrl"`
+		}
+		Expect(sonic.Unmarshal([]byte(out), &v)).To(Succeed())
+		Expect(v.Token).To(HaveLen(43))
+		Expect(v.Handle).To(Equal("my-core"))
+		Expect(v.URL).To(HaveSuffix("/api/shared/" + v.Token))
+		Expect(v.HandleURL).To(HaveSuffix("/api/shared/my-core"))
+
+		for _, ref := range []string{v.Token, "my-core"} {
+			status, out = do("GET", "/api/shared/"+ref, "")
+			Expect(status).To(Equal(fiber.StatusOK), out)
+			Expect(out).To(ContainSubstring(`"name":"Core"`))
+		}
+	})
+
+	It("keeps the token across updates unless asked to rotate it", func() {
+		first := share(`{}`)
+		Expect(share(`{"hideValues":true}`)).To(Equal(first))
+		rotated := share(`{"rotateToken":true}`)
+		Expect(rotated).NotTo(Equal(first))
+
+		status, _ := do("GET", "/api/shared/"+first, "")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("rejects malformed and taken handles", func() {
+		status, _ := do("PUT", "/api/v3/portfolios/s1/share", `{"handle":"-x"}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+
+		shares.shares = append(shares.shares, portfolio.Share{
+			PortfolioID: uuid.Must(uuid.NewV7()), Token: "other", Handle: func(s string) *string { return &s }("taken"),
+		})
+		status, _ = do("PUT", "/api/v3/portfolios/s1/share", `{"handle":"taken"}`)
+		Expect(status).To(Equal(fiber.StatusConflict))
+	})
+
+	It("stops resolving once unshared", func() {
+		token := share(`{}`)
+		status, _ := do("DELETE", "/api/v3/portfolios/s1/share", "")
+		Expect(status).To(Equal(fiber.StatusNoContent))
+		status, _ = do("GET", "/api/shared/"+token, "")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+		status, _ = do("GET", "/api/v3/portfolios/s1/share", "")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("serves dollar values when they are not hidden", func() {
+		token := share(`{"showHoldings":true}`)
+		status, out := do("GET", "/api/shared/"+token+"/summary", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out).To(ContainSubstring(`"currentValue":123456.78`))
+
+		status, out = do("GET", "/api/shared/"+token+"/holdings", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out).To(ContainSubstring(`"marketValue":27500`))
+		Expect(out).To(ContainSubstring(`"weight":0.5`))
+	})
+
+	It("redacts dollar values when hideValues is set", func() {
+		token := share(`{"hideValues":true,"showHoldings":true}`)
+
+		status, out := do("GET", "/api/shared/"+token+"/summary", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out).NotTo(ContainSubstring("currentValue"))
+		Expect(out).To(ContainSubstring(`"sharpe":1.2`))
+
+		status, out = do("GET", "/api/shared/"+token+"/performance", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		var perf openapi.PortfolioPerformance
+		Expect(sonic.Unmarshal([]byte(out), &perf)).To(Succeed())
+		Expect(perf.PortfolioSlug).To(BeEmpty())
+		Expect(perf.Points[0].PortfolioValue).To(BeNumerically("~", 10000))
+		Expect(perf.Points[1].PortfolioValue).To(BeNumerically("~", 11000))
+		Expect(perf.Points[1].BenchmarkValue).To(BeNumerically("~", 10200))
+
+		status, out = do("GET", "/api/shared/"+token+"/holdings", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(out).To(ContainSubstring(`"weight":0.5`))
+		Expect(out).NotTo(ContainSubstring("marketValue"))
+		Expect(out).NotTo(ContainSubstring("quantity"))
+	})
+
+	It("hides holdings unless the owner opted in", func() {
+		token := share(`{}`)
+		status, _ := do("GET", "/api/shared/"+token+"/holdings", "")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("answers 503 instead of queueing a run when there are no results", func() {
+		token := share(`{}`)
+		store.rows[0].SnapshotPath = nil
+		store.rows[0].Status = portfolio.StatusPending
+		status, _ := do("GET", "/api/shared/"+token+"/summary", "")
+		Expect(status).To(Equal(fiber.StatusServiceUnavailable))
+	})
+
+	It("sets a public cache header on shared reads", func() {
+		token := share(`{}`)
+		req := httptest.NewRequest("GET", "/api/shared/"+token+"/summary", nil)
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Header.Get("Cache-Control")).To(Equal("public, max-age=300"))
+	})
+})
@@ -28,13 +28,17 @@ import (
 type Store interface {
 	RunStore
 	List(ctx context.Context, ownerSub string) ([]Portfolio, error)
+	// ListPage returns one page of ownerSub's portfolios for q, reading one
+	// row past the page when q has a limit.
+	ListPage(ctx context.Context, ownerSub string, q ListQuery) ([]Portfolio, error)
 	Get(ctx context.Context, ownerSub, slug string) (Portfolio, error)
 	Insert(ctx context.Context, p Portfolio) error
-	UpdateName(ctx context.Context, ownerSub, slug, name string) error
-	UpdateDates(ctx context.Context, ownerSub, slug string, startDate, endDate *time.Time) error
-	UpdateRunRetention(ctx context.Context, ownerSub, slug string, value int) error
+	// Update and Delete take the updated_at the caller last read, if any,
+	// and return ErrStale when the row no longer carries it.
+	Update(ctx context.Context, ownerSub, slug string, ch Changes, ifUpdatedAt *time.Time) error
+	UpdateOwner(ctx context.Context, ownerSub, slug, newOwnerSub string) error
 	PruneRuns(ctx context.Context, portfolioID uuid.UUID) ([]string, error)
-	Delete(ctx context.Context, ownerSub, slug string) error
+	Delete(ctx context.Context, ownerSub, slug string, ifUpdatedAt *time.Time) error
 	ClaimDue(ctx context.Context, batchSize int) ([]uuid.UUID, error)
 	ApplyUpgrade(ctx context.Context, portfolioID uuid.UUID, newVer string,
 		newDescribe json.RawMessage, newParams json.RawMessage,
@@ -57,6 +61,10 @@ func (p PoolStore) List(ctx context.Context, ownerSub string) ([]Portfolio, erro
 	return List(ctx, p.Pool, ownerSub)
 }
 
+func (p PoolStore) ListPage(ctx context.Context, ownerSub string, q ListQuery) ([]Portfolio, error) {
+	return ListPage(ctx, p.Pool, ownerSub, q)
+}
+
 func (p PoolStore) Get(ctx context.Context, ownerSub, slug string) (Portfolio, error) {
 	return Get(ctx, p.Pool, ownerSub, slug)
 }
@@ -65,12 +73,12 @@ func (p PoolStore) Insert(ctx context.Context, port Portfolio) error {
 	return Insert(ctx, p.Pool, port)
 }
 
-func (p PoolStore) UpdateName(ctx context.Context, ownerSub, slug, name string) error {
-	return UpdateName(ctx, p.Pool, ownerSub, slug, name)
+func (p PoolStore) Update(ctx context.Context, ownerSub, slug string, ch Changes, ifUpdatedAt *time.Time) error {
+	return Update(ctx, p.Pool, ownerSub, slug, ch, ifUpdatedAt)
 }
 
-func (p PoolStore) Delete(ctx context.Context, ownerSub, slug string) error {
-	return Delete(ctx, p.Pool, ownerSub, slug)
+func (p PoolStore) Delete(ctx context.Context, ownerSub, slug string, ifUpdatedAt *time.Time) error {
+	return Delete(ctx, p.Pool, ownerSub, slug, ifUpdatedAt)
 }
 
 // GetByID fetches a portfolio by id without owner scoping (backtest path).
@@ -132,13 +140,8 @@ func (p PoolStore) MarkFailedTx(ctx context.Context, portfolioID, runID uuid.UUI
 	return MarkFailedTx(ctx, p.Pool, portfolioID, runID, errMsg, durationMs)
 }
 
-// UpdateDates updates a portfolio's start_date and/or end_date.
-func (p PoolStore) UpdateDates(ctx context.Context, ownerSub, slug string, startDate, endDate *time.Time) error {
-	return UpdateDates(ctx, p.Pool, ownerSub, slug, startDate, endDate)
-}
-
-func (p PoolStore) UpdateRunRetention(ctx context.Context, ownerSub, slug string, value int) error {
-	return UpdateRunRetention(ctx, p.Pool, ownerSub, slug, value)
+func (p PoolStore) UpdateOwner(ctx context.Context, ownerSub, slug, newOwnerSub string) error {
+	return UpdateOwner(ctx, p.Pool, ownerSub, slug, newOwnerSub)
 }
 
 func (p PoolStore) PruneRuns(ctx context.Context, portfolioID uuid.UUID) ([]string, error) {
@@ -0,0 +1,120 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio
+
+import (
+	"errors"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/audit"
+	"github.com/penny-vault/pv-api/quota"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// Transfer implements POST /portfolios/{slug}/transfer: it moves the
+// portfolio into a workspace, or back to the caller with a null
+// workspaceId. The caller must own the portfolio where it is and be at
+// least an editor where it goes, and the destination must have room for
+// it under its quotas. Runs, alerts, fills and share links go with it.
+func (h *Handler) Transfer(c fiber.Ctx) error {
+	var body struct {
+		WorkspaceID *string `json:"workspaceId"`
+	}
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	ownerSub, ok, err := h.owner(c, workspace.RoleOwner)
+	if !ok {
+		return err
+	}
+	sub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	target := sub
+	if body.WorkspaceID != nil {
+		id, err := uuid.Parse(*body.WorkspaceID)
+		if err != nil {
+			return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "workspaceId must be a UUID")
+		}
+		if h.workspaces == nil {
+			return writeProblem(c, fiber.StatusNotFound, "Not Found", "workspace not found")
+		}
+		role, err := h.workspaces.Role(c.Context(), id, sub)
+		if errors.Is(err, workspace.ErrNotFound) {
+			return writeProblem(c, fiber.StatusNotFound, "Not Found", "workspace not found")
+		}
+		if err != nil {
+			return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+		if !role.Allows(workspace.RoleEditor) {
+			return writeProblem(c, fiber.StatusForbidden, "Forbidden",
+				"moving a portfolio into a workspace requires the editor role there")
+		}
+		target = workspace.OwnerSub(id)
+	}
+
+	slug := string([]byte(c.Params("slug")))
+	if target != ownerSub {
+		before, err := h.store.Get(c.Context(), ownerSub, slug)
+		if errors.Is(err, ErrNotFound) {
+			return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+		}
+		if err != nil {
+			return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+		// The portfolio counts against its new owner from here on, so it
+		// must fit within the destination's limits like a create would.
+		cloneURL := ""
+		if before.StrategyVer == nil {
+			cloneURL = before.StrategyCloneURL
+		}
+		if ok, err := h.checkQuota(c, target, func(u quota.Usage) error {
+			if err := h.limits.CheckPortfolio(u, cloneURL); err != nil {
+				return err
+			}
+			return h.limits.CheckRun(u)
+		}); !ok {
+			return err
+		}
+		err = h.store.UpdateOwner(c.Context(), ownerSub, slug, target)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+		case errors.Is(err, ErrDuplicateSlug):
+			return writeProblem(c, fiber.StatusConflict, "Conflict",
+				"the destination already has a portfolio with slug "+slug)
+		case err != nil:
+			return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+	}
+	p, err := h.store.Get(c.Context(), target, slug)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "portfolio not found: "+slug)
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if target != ownerSub {
+		e := audit.FromRequest(c, audit.ActionPortfolioTransfer)
+		e.Changes = map[string]audit.Change{"owner": {Before: ownerSub, After: target}}
+		h.record(c.Context(), e, p)
+	}
+	return writeJSON(c, fiber.StatusOK, toView(p))
+}
@@ -59,6 +59,7 @@ type Portfolio struct {
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
 	RunRetention         int `json:"run_retention"`
+	Tags                 []string
 }
 
 // CreateRequest is what the POST /portfolios handler passes to the domain layer.
@@ -72,6 +73,7 @@ type CreateRequest struct {
 	StartDate        *time.Time
 	EndDate          *time.Time
 	RunRetention     *int
+	Tags             []string
 }
 
 // UpdateRequest is what PATCH /portfolios/{slug} passes to the domain layer.
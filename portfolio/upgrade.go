@@ -22,7 +22,9 @@ import (
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v3"
 
+	"github.com/penny-vault/pv-api/audit"
 	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/workspace"
 )
 
 // Upgrade implements POST /portfolios/{slug}/upgrade.
@@ -50,6 +52,9 @@ func (h *Handler) Upgrade(c fiber.Ctx) error {
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
+	if res.Outcome == UpgradeOutcomeApplied {
+		h.recordUpgrade(c.Context(), audit.FromRequest(c, audit.ActionPortfolioUpgrade), p)
+	}
 	return writeUpgradeResponse(c, p, s, res)
 }
 
@@ -59,9 +64,9 @@ func (h *Handler) Upgrade(c fiber.Ctx) error {
 // — which is whatever the response writer returned, typically nil — and
 // stop processing.
 func (h *Handler) loadUpgradeTargets(c fiber.Ctx) (Portfolio, strategy.Strategy, bool, error) {
-	ownerSub, err := subject(c)
-	if err != nil {
-		return Portfolio{}, strategy.Strategy{}, false, writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	ownerSub, ok, err := h.owner(c, workspace.RoleEditor)
+	if !ok {
+		return Portfolio{}, strategy.Strategy{}, false, err
 	}
 	slug := c.Params("slug")
 	p, err := h.store.Get(c.Context(), ownerSub, slug)
@@ -142,7 +147,7 @@ func writeUpgradeResponse(c fiber.Ctx, p Portfolio, s strategy.Strategy, res Upg
 
 // writeIncompatibleParametersResponse emits the 409 body that asks the
 // caller to resubmit with explicit parameters when a parameter rename or
-// retype is detected.
+// retype is detected, or a kept value violates a changed constraint.
 func writeIncompatibleParametersResponse(c fiber.Ctx, p Portfolio, s strategy.Strategy, diff ParameterDiff) error {
 	removed := diff.Removed
 	if removed == nil {
@@ -156,6 +161,10 @@ func writeIncompatibleParametersResponse(c fiber.Ctx, p Portfolio, s strategy.St
 	if retyped == nil {
 		retyped = []ParameterRetype{}
 	}
+	violations := diff.Violations
+	if violations == nil {
+		violations = []ParameterViolation{}
+	}
 	return writeJSON(c, fiber.StatusConflict, fiber.Map{
 		"error":        "parameters_incompatible",
 		"from_version": deref(p.StrategyVer),
@@ -164,6 +173,7 @@ func writeIncompatibleParametersResponse(c fiber.Ctx, p Portfolio, s strategy.St
 			"removed":               removed,
 			"added_without_default": addedNoDefault,
 			"retyped":               retyped,
+			"violations":            violations,
 		},
 		"current_parameters": p.Parameters,
 		"new_describe":       json.RawMessage(s.DescribeJSON),
@@ -102,7 +102,7 @@ func (h *Handler) doUpgrade(
 		return res, err
 	}
 
-	diff := DiffParameters(oldDescribe, newDescribe)
+	diff := DiffParameters(oldDescribe, newDescribe).CheckValues(p.Parameters, newDescribe)
 	res.Diff = diff
 	res.NewDescribe = json.RawMessage(s.DescribeJSON)
 
@@ -19,6 +19,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/penny-vault/pv-api/strategy"
@@ -29,11 +31,19 @@ var (
 	ErrStrategyVersionMismatch = errors.New("strategy version not installed")
 	ErrUnknownParameter        = errors.New("unknown parameter")
 	ErrMissingParameter        = errors.New("missing required parameter")
+	ErrInvalidParameter        = errors.New("invalid parameter value")
 	ErrInvalidStrategyDescribe = errors.New("strategy describe JSON is malformed")
 	ErrInvalidDate             = errors.New("invalid date")
 	ErrEndBeforeStart          = errors.New("endDate must be on or after startDate")
-	ErrImmutableField          = errors.New("field is not updatable; only `name`, `startDate`, `endDate`, `runRetention` may be patched")
+	ErrImmutableField          = errors.New("field is not updatable; only `name`, `startDate`, `endDate`, `runRetention`, `tags` may be patched")
 	ErrInvalidRunRetention     = errors.New("run_retention must be >= 1")
+	ErrInvalidTags             = errors.New("invalid tags")
+)
+
+// Tag limits. Tags are short labels for filtering, not free text.
+const (
+	maxTags      = 20
+	maxTagLength = 40
 )
 
 // validateRunRetention returns ErrInvalidRunRetention when v is non-nil and < 1.
@@ -47,6 +57,34 @@ func validateRunRetention(v *int) error {
 	return nil
 }
 
+// normalizeTags trims, lower-cases and de-duplicates tags, keeping their
+// order. It rejects empty tags, tags containing commas (the list filter's
+// separator) and more than maxTags.
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		switch {
+		case t == "":
+			return nil, fmt.Errorf("%w: tags must not be empty", ErrInvalidTags)
+		case len(t) > maxTagLength:
+			return nil, fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTags, t, maxTagLength)
+		case strings.Contains(t, ","):
+			return nil, fmt.Errorf("%w: %q contains a comma", ErrInvalidTags, t)
+		}
+		if !slices.Contains(out, t) {
+			out = append(out, t)
+		}
+	}
+	if len(out) > maxTags {
+		return nil, fmt.Errorf("%w: at most %d tags", ErrInvalidTags, maxTags)
+	}
+	return out, nil
+}
+
 // ValidateCreate validates and normalises an official-strategy create request.
 func ValidateCreate(req CreateRequest, s strategy.Strategy) (CreateRequest, error) {
 	norm := req
@@ -54,6 +92,11 @@ func ValidateCreate(req CreateRequest, s strategy.Strategy) (CreateRequest, erro
 	if err := validateRunRetention(req.RunRetention); err != nil {
 		return CreateRequest{}, err
 	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return CreateRequest{}, err
+	}
+	norm.Tags = tags
 
 	if s.InstalledVer == nil || len(s.DescribeJSON) == 0 {
 		return norm, fmt.Errorf("%w: %s is still installing — try again shortly", ErrStrategyNotReady, s.ShortCode)
@@ -87,6 +130,11 @@ func ValidateCreateUnofficial(req CreateRequest, d strategy.Describe) (CreateReq
 	if err := validateRunRetention(req.RunRetention); err != nil {
 		return CreateRequest{}, err
 	}
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return CreateRequest{}, err
+	}
+	norm.Tags = tags
 	if err := validateParameters(norm.Parameters, d); err != nil {
 		return norm, err
 	}
@@ -124,20 +172,62 @@ type ParameterDiff struct {
 	AddedWithoutDefault []string          `json:"added_without_default"`
 	Removed             []string          `json:"removed"`
 	Retyped             []ParameterRetype `json:"retyped"`
+	// AddedOptional lists new parameters declared required:false with no
+	// default; they can be left unset and the strategy applies its own.
+	AddedOptional []string `json:"added_optional"`
+	// Reconstrained lists Kept parameters whose enum, range, step,
+	// required flag, or universe constraint changed. Whether the change
+	// matters depends on the portfolio's values; see CheckValues.
+	Reconstrained []string             `json:"reconstrained"`
+	Violations    []ParameterViolation `json:"violations"`
+}
+
+// ParameterViolation describes a kept parameter whose current value no
+// longer satisfies the new describe's constraints.
+type ParameterViolation struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
 }
 
 // Compatible reports whether the diff can be applied automatically:
-// no Removed, no Retyped, no AddedWithoutDefault.
+// no Removed, no Retyped, no AddedWithoutDefault, no Violations.
 func (d ParameterDiff) Compatible() bool {
-	return len(d.Removed) == 0 && len(d.Retyped) == 0 && len(d.AddedWithoutDefault) == 0
+	return len(d.Removed) == 0 && len(d.Retyped) == 0 && len(d.AddedWithoutDefault) == 0 &&
+		len(d.Violations) == 0
+}
+
+// CheckValues re-validates the current value of every Reconstrained
+// parameter against newDescribe and records failures in Violations.
+func (d ParameterDiff) CheckValues(current map[string]any, newDescribe strategy.Describe) ParameterDiff {
+	byName := make(map[string]strategy.DescribeParameter, len(newDescribe.Parameters))
+	for _, p := range newDescribe.Parameters {
+		byName[p.Name] = p
+	}
+	d.Violations = nil
+	for _, name := range d.Reconstrained {
+		p := byName[name]
+		v, present := current[name]
+		if !present {
+			if p.IsRequired() {
+				d.Violations = append(d.Violations, ParameterViolation{Name: name, Reason: "now required"})
+			}
+			continue
+		}
+		if reason := constraintViolation(p, v); reason != "" {
+			d.Violations = append(d.Violations, ParameterViolation{Name: name, Reason: reason})
+		}
+	}
+	return d
 }
 
 // DiffParameters classifies every parameter declared on either describe.
 //   - Kept: same Name and same Type on both describes.
 //   - Retyped: same Name on both describes, different Type.
+//   - Reconstrained: Kept, but with different constraint metadata.
 //   - Removed: declared on old, absent on new.
 //   - AddedWithDefault / AddedWithoutDefault: absent on old, declared on new
 //     (split by whether new declaration has a non-nil Default).
+//   - AddedOptional: absent on old, declared optional on new with no Default.
 func DiffParameters(oldDescribe, newDescribe strategy.Describe) ParameterDiff {
 	oldByName := make(map[string]strategy.DescribeParameter, len(oldDescribe.Parameters))
 	for _, p := range oldDescribe.Parameters {
@@ -160,14 +250,20 @@ func DiffParameters(oldDescribe, newDescribe strategy.Describe) ParameterDiff {
 			continue
 		}
 		d.Kept = append(d.Kept, name)
+		if !sameConstraints(oldP, newP) {
+			d.Reconstrained = append(d.Reconstrained, name)
+		}
 	}
 	for name, newP := range newByName {
 		if _, present := oldByName[name]; present {
 			continue
 		}
-		if newP.Default != nil {
+		switch {
+		case newP.Default != nil:
 			d.AddedWithDefault = append(d.AddedWithDefault, name)
-		} else {
+		case !newP.IsRequired():
+			d.AddedOptional = append(d.AddedOptional, name)
+		default:
 			d.AddedWithoutDefault = append(d.AddedWithoutDefault, name)
 		}
 	}
@@ -188,12 +284,13 @@ func MatchPresetName(current map[string]any, newDescribe strategy.Describe) *str
 	return nil
 }
 
-// validateParameters enforces that every declared parameter is present and no
-// unknown parameters are supplied.
+// validateParameters enforces that every required parameter is present, no
+// unknown parameters are supplied, and each supplied value satisfies the
+// constraints declared on the describe.
 func validateParameters(params map[string]any, d strategy.Describe) error {
-	declared := make(map[string]struct{}, len(d.Parameters))
+	declared := make(map[string]strategy.DescribeParameter, len(d.Parameters))
 	for _, p := range d.Parameters {
-		declared[p.Name] = struct{}{}
+		declared[p.Name] = p
 	}
 	for k := range params {
 		if _, ok := declared[k]; !ok {
@@ -201,8 +298,15 @@ func validateParameters(params map[string]any, d strategy.Describe) error {
 		}
 	}
 	for _, p := range d.Parameters {
-		if _, present := params[p.Name]; !present {
-			return fmt.Errorf("%w: %s", ErrMissingParameter, p.Name)
+		v, present := params[p.Name]
+		if !present {
+			if p.IsRequired() {
+				return fmt.Errorf("%w: %s", ErrMissingParameter, p.Name)
+			}
+			continue
+		}
+		if err := checkParameterValue(p, v); err != nil {
+			return err
 		}
 	}
 	return nil
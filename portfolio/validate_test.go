@@ -54,6 +54,25 @@ var _ = Describe("ValidateCreate", func() {
 		Expect(norm.Benchmark).To(Equal("SPY"))
 	})
 
+	It("normalises tags and rejects too many", func() {
+		req := portfolio.CreateRequest{
+			Name:         "foo",
+			StrategyCode: "adm",
+			Parameters:   map[string]any{"riskOn": "SPY"},
+			Tags:         []string{"Roth ", "roth", "kids"},
+		}
+		norm, err := portfolio.ValidateCreate(req, makeStrategy())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(norm.Tags).To(Equal([]string{"roth", "kids"}))
+
+		req.Tags = make([]string, 21)
+		for i := range req.Tags {
+			req.Tags[i] = string(rune('a' + i))
+		}
+		_, err = portfolio.ValidateCreate(req, makeStrategy())
+		Expect(errors.Is(err, portfolio.ErrInvalidTags)).To(BeTrue())
+	})
+
 	It("defaults benchmark to strategy describe benchmark when blank", func() {
 		req := portfolio.CreateRequest{
 			Name:         "foo",
@@ -300,3 +319,79 @@ var _ = Describe("MatchPresetName", func() {
 		Expect(n).To(BeNil())
 	})
 })
+
+var _ = Describe("parameter constraints", func() {
+	f := func(v float64) *float64 { return &v }
+	no := false
+
+	d := strategy.Describe{
+		ShortCode: "fake",
+		Parameters: []strategy.DescribeParameter{
+			{Name: "lookback", Type: "int", Min: f(1), Max: f(24)},
+			{Name: "weight", Type: "float", Min: f(0), Step: f(0.25)},
+			{Name: "mode", Type: "string", Enum: []any{"fast", "slow"}},
+			{Name: "riskOn", Type: "universe", Universe: &strategy.UniverseConstraint{MaxSize: 2, Allowed: []string{"SPY", "QQQ", "IWM"}}},
+			{Name: "verbose", Type: "bool", Required: &no},
+		},
+	}
+	valid := func() map[string]any {
+		return map[string]any{"lookback": 12.0, "weight": 0.5, "mode": "fast", "riskOn": "SPY,QQQ"}
+	}
+
+	It("accepts values inside every constraint and allows omitting optional parameters", func() {
+		_, err := portfolio.ValidateCreateUnofficial(portfolio.CreateRequest{Name: "p", Parameters: valid()}, d)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	DescribeTable("rejects values outside the declared constraints",
+		func(name string, value any, reason string) {
+			params := valid()
+			params[name] = value
+			_, err := portfolio.ValidateCreateUnofficial(portfolio.CreateRequest{Name: "p", Parameters: params}, d)
+			Expect(errors.Is(err, portfolio.ErrInvalidParameter)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(name))
+			Expect(err.Error()).To(ContainSubstring(reason))
+		},
+		Entry("below min", "lookback", 0.0, ">= 1"),
+		Entry("above max", "lookback", 240.0, "<= 24"),
+		Entry("not an integer", "lookback", 1.5, "integer"),
+		Entry("off the step grid", "weight", 0.3, "multiple of 0.25"),
+		Entry("not in enum", "mode", "medium", "one of [fast, slow]"),
+		Entry("too many tickers", "riskOn", []any{"SPY", "QQQ", "IWM"}, "at most 2"),
+		Entry("ticker outside the universe", "riskOn", "SPY,TLT", "TLT"),
+		Entry("wrong type", "verbose", "yes", "boolean"),
+	)
+
+	It("enforces constraints in ValidateCreate", func() {
+		installed := "v1.0.0"
+		raw := []byte(`{"shortcode":"adm","parameters":[{"name":"lookback","type":"int","min":1,"max":24}]}`)
+		s := strategy.Strategy{ShortCode: "adm", InstalledVer: &installed, DescribeJSON: raw}
+		_, err := portfolio.ValidateCreate(portfolio.CreateRequest{Name: "p", Parameters: map[string]any{"lookback": 400.0}}, s)
+		Expect(errors.Is(err, portfolio.ErrInvalidParameter)).To(BeTrue())
+	})
+
+	It("flags kept parameters whose constraints changed and checks current values", func() {
+		old := strategy.Describe{Parameters: []strategy.DescribeParameter{{Name: "lookback", Type: "int", Max: f(36)}}}
+		newDesc := strategy.Describe{Parameters: []strategy.DescribeParameter{{Name: "lookback", Type: "int", Max: f(24)}}}
+
+		diff := portfolio.DiffParameters(old, newDesc)
+		Expect(diff.Kept).To(ConsistOf("lookback"))
+		Expect(diff.Reconstrained).To(ConsistOf("lookback"))
+
+		ok := diff.CheckValues(map[string]any{"lookback": 12.0}, newDesc)
+		Expect(ok.Violations).To(BeEmpty())
+		Expect(ok.Compatible()).To(BeTrue())
+
+		bad := diff.CheckValues(map[string]any{"lookback": 30.0}, newDesc)
+		Expect(bad.Violations).To(ConsistOf(portfolio.ParameterViolation{Name: "lookback", Reason: "must be <= 24"}))
+		Expect(bad.Compatible()).To(BeFalse())
+	})
+
+	It("treats a new optional parameter without default as compatible", func() {
+		newDesc := strategy.Describe{Parameters: []strategy.DescribeParameter{{Name: "verbose", Type: "bool", Required: &no}}}
+		diff := portfolio.DiffParameters(strategy.Describe{}, newDesc)
+		Expect(diff.AddedOptional).To(ConsistOf("verbose"))
+		Expect(diff.AddedWithoutDefault).To(BeEmpty())
+		Expect(diff.Compatible()).To(BeTrue())
+	})
+})
@@ -0,0 +1,173 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portfolio_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/portfolio"
+	"github.com/penny-vault/pv-api/quota"
+	"github.com/penny-vault/pv-api/strategy"
+	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// fakeRoles is a fixed workspace.Roles keyed by workspace then subject.
+type fakeRoles map[uuid.UUID]map[string]workspace.Role
+
+func (f fakeRoles) Role(_ context.Context, id uuid.UUID, sub string) (workspace.Role, error) {
+	if r, ok := f[id][sub]; ok {
+		return r, nil
+	}
+	return "", workspace.ErrNotFound
+}
+
+var _ = Describe("Workspace portfolios", func() {
+	var (
+		app    *fiber.App
+		store  *fakeStore
+		roles  fakeRoles
+		wsID   uuid.UUID
+		caller string
+	)
+
+	do := func(method, target, body string, inWorkspace bool) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if inWorkspace {
+			req.Header.Set(workspace.HeaderWorkspace, wsID.String())
+		}
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		out, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(out)
+	}
+
+	BeforeEach(func() {
+		store = &fakeStore{}
+		wsID = uuid.Must(uuid.NewV7())
+		roles = fakeRoles{wsID: {
+			"auth0|owner":  workspace.RoleOwner,
+			"auth0|editor": workspace.RoleEditor,
+			"auth0|viewer": workspace.RoleViewer,
+		}}
+		caller = "auth0|owner"
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, nil, nil, nil, nil, strategy.EphemeralOptions{}).
+			WithWorkspaces(roles)
+
+		app = fiber.New(fiber.Config{JSONEncoder: sonic.Marshal, JSONDecoder: sonic.Unmarshal})
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, caller)
+			return c.Next()
+		})
+		app.Get("/portfolios", h.List)
+		app.Get("/portfolios/:slug", h.Get)
+		app.Patch("/portfolios/:slug", h.Patch)
+		app.Delete("/portfolios/:slug", h.Delete)
+		app.Post("/portfolios/:slug/transfer", h.Transfer)
+
+		store.rows = []portfolio.Portfolio{
+			{ID: uuid.Must(uuid.NewV7()), OwnerSub: workspace.OwnerSub(wsID), Slug: "book", Name: "Book"},
+			{ID: uuid.Must(uuid.NewV7()), OwnerSub: "auth0|owner", Slug: "mine", Name: "Mine"},
+		}
+	})
+
+	It("lists the workspace's portfolios for any member", func() {
+		caller = "auth0|viewer"
+		status, body := do("GET", "/portfolios", "", true)
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(ContainSubstring(`"slug":"book"`))
+		Expect(body).To(ContainSubstring(`"workspaceId":"` + wsID.String() + `"`))
+		Expect(body).NotTo(ContainSubstring(`"slug":"mine"`))
+
+		status, body = do("GET", "/portfolios", "", false)
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(Equal("[]"))
+	})
+
+	It("enforces roles on changes", func() {
+		caller = "auth0|viewer"
+		status, _ := do("PATCH", "/portfolios/book", `{"name":"Renamed"}`, true)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+
+		caller = "auth0|editor"
+		status, _ = do("PATCH", "/portfolios/book", `{"name":"Renamed"}`, true)
+		Expect(status).To(Equal(fiber.StatusOK))
+		status, _ = do("DELETE", "/portfolios/book", "", true)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+
+		caller = "auth0|owner"
+		status, _ = do("DELETE", "/portfolios/book", "", true)
+		Expect(status).To(Equal(fiber.StatusNoContent))
+	})
+
+	It("hides the workspace from non-members", func() {
+		caller = "auth0|stranger"
+		status, _ := do("GET", "/portfolios/book", "", true)
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("moves a personal portfolio into the workspace and back", func() {
+		status, body := do("POST", "/portfolios/mine/transfer", `{"workspaceId":"`+wsID.String()+`"}`, false)
+		Expect(status).To(Equal(fiber.StatusOK), body)
+		Expect(store.rows[1].OwnerSub).To(Equal(workspace.OwnerSub(wsID)))
+
+		status, _ = do("POST", "/portfolios/mine/transfer", `{"workspaceId":null}`, true)
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(store.rows[1].OwnerSub).To(Equal("auth0|owner"))
+	})
+
+	It("requires editor access to the destination and ownership of the source", func() {
+		caller = "auth0|viewer"
+		store.rows[1].OwnerSub = caller
+		status, _ := do("POST", "/portfolios/mine/transfer", `{"workspaceId":"`+wsID.String()+`"}`, false)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+
+		caller = "auth0|editor"
+		status, _ = do("POST", "/portfolios/book/transfer", `{"workspaceId":null}`, true)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+	})
+
+	It("refuses a transfer the destination has no room for", func() {
+		h := portfolio.NewHandler(store, &fakeStrategyStore{}, nil, nil, nil, nil, strategy.EphemeralOptions{}).
+			WithWorkspaces(roles).
+			WithQuotas(quota.Limits{Portfolios: 1, RunsPerDay: 3}, fixedUsage(quota.Usage{Portfolios: 1}))
+		app.Post("/limited/portfolios/:slug/transfer", h.Transfer)
+		status, body := do("POST", "/limited/portfolios/mine/transfer", `{"workspaceId":"`+wsID.String()+`"}`, false)
+		Expect(status).To(Equal(fiber.StatusForbidden), body)
+		Expect(store.rows[1].OwnerSub).To(Equal("auth0|owner"))
+
+		h.WithQuotas(quota.Limits{Portfolios: 5, RunsPerDay: 3}, fixedUsage(quota.Usage{Portfolios: 1, RunsToday: 3}))
+		status, _ = do("POST", "/limited/portfolios/mine/transfer", `{"workspaceId":"`+wsID.String()+`"}`, false)
+		Expect(status).To(Equal(fiber.StatusTooManyRequests))
+		Expect(store.rows[1].OwnerSub).To(Equal("auth0|owner"))
+	})
+
+	It("refuses a transfer onto an existing slug", func() {
+		store.rows[1].Slug = "book"
+		status, _ := do("POST", "/portfolios/book/transfer", `{"workspaceId":"`+wsID.String()+`"}`, false)
+		Expect(status).To(Equal(fiber.StatusConflict))
+	})
+})
@@ -0,0 +1,118 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+var errNoSubject = errors.New("missing authenticated subject")
+
+// Handler serves GET /me/usage.
+type Handler struct {
+	limits     Limits
+	counter    Counter
+	workspaces workspace.Roles
+}
+
+func NewHandler(limits Limits, counter Counter) *Handler {
+	return &Handler{limits: limits, counter: counter}
+}
+
+// WithWorkspaces lets members read a workspace's usage with X-Workspace.
+func (h *Handler) WithWorkspaces(r workspace.Roles) *Handler {
+	h.workspaces = r
+	return h
+}
+
+type meter struct {
+	Used  *int `json:"used,omitempty"`
+	Limit *int `json:"limit,omitempty"`
+}
+
+type usageView struct {
+	Portfolios           meter `json:"portfolios"`
+	UnofficialStrategies meter `json:"unofficialStrategies"`
+	RunsPerDay           meter `json:"runsPerDay"`
+	AlertRecipients      meter `json:"alertRecipients"`
+	RequestsPerMinute    meter `json:"requestsPerMinute"`
+}
+
+// newMeter leaves Limit unset for unlimited (zero) limits.
+func newMeter(used *int, limit int) meter {
+	m := meter{Used: used}
+	if limit > 0 {
+		m.Limit = &limit
+	}
+	return m
+}
+
+// Usage implements GET /me/usage: the caller's (or workspace's) usage
+// against each limit.
+func (h *Handler) Usage(c fiber.Ctx) error {
+	sub, ok := c.Locals(types.AuthSubjectKey{}).(string)
+	if !ok || sub == "" {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", errNoSubject.Error())
+	}
+	ownerSub, err := workspace.Resolve(c, h.workspaces, sub, workspace.RoleViewer)
+	if err != nil {
+		status := workspace.Status(err)
+		return writeProblem(c, status, http.StatusText(status), err.Error())
+	}
+	u, err := h.counter.Usage(c.Context(), ownerSub)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	unofficial := len(u.UnofficialCloneURLs)
+	return writeJSON(c, fiber.StatusOK, usageView{
+		Portfolios:           newMeter(&u.Portfolios, h.limits.Portfolios),
+		UnofficialStrategies: newMeter(&unofficial, h.limits.UnofficialStrategies),
+		RunsPerDay:           newMeter(&u.RunsToday, h.limits.RunsPerDay),
+		AlertRecipients:      newMeter(nil, h.limits.AlertRecipients),
+		RequestsPerMinute:    newMeter(nil, h.limits.RequestsPerMinute),
+	})
+}
+
+func writeJSON(c fiber.Ctx, status int, v any) error {
+	body, err := sonic.Marshal(v)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/json")
+	return c.Status(status).Send(body)
+}
+
+func writeProblem(c fiber.Ctx, status int, title, detail string) error {
+	type problem struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+	body, _ := sonic.Marshal(problem{
+		Type: "about:blank", Title: title, Status: status, Detail: detail, Instance: c.Path(),
+	})
+	c.Set(fiber.HeaderContentType, "application/problem+json")
+	return c.Status(status).Send(body)
+}
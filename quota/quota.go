@@ -0,0 +1,151 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quota caps what one owner may use so a single account cannot
+// crowd out everyone else. Limits count against the owner a request acts
+// for: the caller's own subject, or a workspace, which has its own
+// allowance. A zero limit means unlimited.
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrExceeded is returned when a create would go over a count limit.
+	// It maps to 403.
+	ErrExceeded = errors.New("quota exceeded")
+	// ErrRateLimited is returned when an owner has used up a limit that
+	// refills over time. It maps to 429.
+	ErrRateLimited = errors.New("rate limit exceeded")
+)
+
+// Status maps a quota error to its HTTP status.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrExceeded):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Limits caps what one owner may use. Zero means unlimited.
+type Limits struct {
+	// Portfolios caps how many portfolios an owner may have.
+	Portfolios int
+	// UnofficialStrategies caps how many distinct clone-URL strategies an
+	// owner's portfolios may use.
+	UnofficialStrategies int
+	// RunsPerDay caps the backtests an owner may start in 24 hours. Every
+	// run a person starts counts: Run now, creating a portfolio and
+	// upgrading one. Scheduled runs do not.
+	RunsPerDay int
+	// AlertRecipients caps the email recipients on one alert.
+	AlertRecipients int
+	// Workspaces caps how many workspaces one user may create. Each
+	// workspace has its own allowance under the limits above.
+	Workspaces int
+	// RequestsPerMinute caps API requests per subject, per server.
+	RequestsPerMinute int
+}
+
+// Usage is what an owner is using now.
+type Usage struct {
+	Portfolios int
+	// UnofficialCloneURLs are the distinct clone-URL strategies in use.
+	UnofficialCloneURLs []string
+	// RunsToday counts the runs started by hand in the last 24 hours.
+	RunsToday int
+}
+
+// Counter reports an owner's usage.
+type Counter interface {
+	Usage(ctx context.Context, ownerSub string) (Usage, error)
+}
+
+// CheckPortfolio returns ErrExceeded if creating a portfolio would go over
+// l. cloneURL is the new portfolio's strategy if it is unofficial, and ""
+// otherwise; reusing a strategy already in use costs nothing.
+func (l Limits) CheckPortfolio(u Usage, cloneURL string) error {
+	if l.Portfolios > 0 && u.Portfolios >= l.Portfolios {
+		return fmt.Errorf("%w: at most %d portfolios", ErrExceeded, l.Portfolios)
+	}
+	if cloneURL != "" && l.UnofficialStrategies > 0 &&
+		!slices.Contains(u.UnofficialCloneURLs, cloneURL) &&
+		len(u.UnofficialCloneURLs) >= l.UnofficialStrategies {
+		return fmt.Errorf("%w: at most %d unofficial strategies", ErrExceeded, l.UnofficialStrategies)
+	}
+	return nil
+}
+
+// CheckRun returns ErrRateLimited if starting a run would go over l.
+func (l Limits) CheckRun(u Usage) error {
+	if l.RunsPerDay > 0 && u.RunsToday >= l.RunsPerDay {
+		return fmt.Errorf("%w: at most %d runs in 24 hours", ErrRateLimited, l.RunsPerDay)
+	}
+	return nil
+}
+
+// CheckRecipients returns ErrExceeded if an alert with n email recipients
+// would go over l.
+func (l Limits) CheckRecipients(n int) error {
+	if l.AlertRecipients > 0 && n > l.AlertRecipients {
+		return fmt.Errorf("%w: at most %d recipients per alert", ErrExceeded, l.AlertRecipients)
+	}
+	return nil
+}
+
+// PoolCounter is the pgxpool-backed Counter.
+type PoolCounter struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolCounter(pool *pgxpool.Pool) *PoolCounter { return &PoolCounter{pool: pool} }
+
+// Usage counts from the portfolios and backtest_runs tables. Unofficial
+// portfolios are the ones without a pinned strategy version. Runs carry no
+// creation time, but their uuidv7 IDs do.
+func (p *PoolCounter) Usage(ctx context.Context, ownerSub string) (Usage, error) {
+	var u Usage
+	err := p.pool.QueryRow(ctx, `
+		SELECT count(*),
+		       COALESCE(array_agg(DISTINCT strategy_clone_url)
+		                FILTER (WHERE strategy_ver IS NULL), '{}')
+		  FROM portfolios
+		 WHERE owner_sub = $1`, ownerSub).Scan(&u.Portfolios, &u.UnofficialCloneURLs)
+	if err != nil {
+		return Usage{}, fmt.Errorf("count portfolios: %w", err)
+	}
+	err = p.pool.QueryRow(ctx, `
+		SELECT count(*)
+		  FROM backtest_runs r
+		  JOIN portfolios p ON p.id = r.portfolio_id
+		 WHERE p.owner_sub = $1
+		   AND r.triggered_by = 'manual'
+		   AND uuid_extract_timestamp(r.id) > now() - interval '24 hours'`, ownerSub).Scan(&u.RunsToday)
+	if err != nil {
+		return Usage{}, fmt.Errorf("count runs: %w", err)
+	}
+	return u, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quota_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/quota"
+	"github.com/penny-vault/pv-api/types"
+)
+
+type fixedUsage map[string]quota.Usage
+
+func (f fixedUsage) Usage(_ context.Context, ownerSub string) (quota.Usage, error) {
+	return f[ownerSub], nil
+}
+
+var _ = Describe("Limits", func() {
+	limits := quota.Limits{Portfolios: 3, UnofficialStrategies: 1, RunsPerDay: 5, AlertRecipients: 2}
+
+	It("caps portfolios", func() {
+		Expect(limits.CheckPortfolio(quota.Usage{Portfolios: 2}, "")).To(Succeed())
+		Expect(limits.CheckPortfolio(quota.Usage{Portfolios: 3}, "")).To(MatchError(quota.ErrExceeded))
+	})
+
+	It("caps distinct unofficial strategies but allows reusing one", func() {
+		u := quota.Usage{Portfolios: 1, UnofficialCloneURLs: []string{"https://github.com/a/b"}}
+		Expect(limits.CheckPortfolio(u, "https://github.com/a/b")).To(Succeed())
+		Expect(limits.CheckPortfolio(u, "https://github.com/c/d")).To(MatchError(quota.ErrExceeded))
+		Expect(limits.CheckPortfolio(u, "")).To(Succeed())
+	})
+
+	It("rate-limits runs", func() {
+		Expect(limits.CheckRun(quota.Usage{RunsToday: 4})).To(Succeed())
+		err := limits.CheckRun(quota.Usage{RunsToday: 5})
+		Expect(err).To(MatchError(quota.ErrRateLimited))
+		Expect(quota.Status(err)).To(Equal(fiber.StatusTooManyRequests))
+	})
+
+	It("caps alert recipients", func() {
+		Expect(limits.CheckRecipients(2)).To(Succeed())
+		err := limits.CheckRecipients(3)
+		Expect(err).To(MatchError(quota.ErrExceeded))
+		Expect(quota.Status(err)).To(Equal(fiber.StatusForbidden))
+	})
+
+	It("treats zero as unlimited", func() {
+		var none quota.Limits
+		Expect(none.CheckPortfolio(quota.Usage{Portfolios: 1000, UnofficialCloneURLs: []string{"x"}}, "y")).To(Succeed())
+		Expect(none.CheckRun(quota.Usage{RunsToday: 1000})).To(Succeed())
+		Expect(none.CheckRecipients(1000)).To(Succeed())
+	})
+})
+
+var _ = Describe("GET /me/usage", func() {
+	It("reports usage against each limit, omitting unlimited ones", func() {
+		h := quota.NewHandler(quota.Limits{Portfolios: 10, RunsPerDay: 20, RequestsPerMinute: 120},
+			fixedUsage{"auth0|user-1": {Portfolios: 3, UnofficialCloneURLs: []string{"u"}, RunsToday: 4}})
+		app := fiber.New()
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, "auth0|user-1")
+			return c.Next()
+		})
+		app.Get("/me/usage", h.Usage)
+
+		resp, err := app.Test(httptest.NewRequest("GET", "/me/usage", nil))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(fiber.StatusOK))
+		body, _ := io.ReadAll(resp.Body)
+		Expect(body).To(MatchJSON(`{
+			"portfolios": {"used": 3, "limit": 10},
+			"unofficialStrategies": {"used": 1},
+			"runsPerDay": {"used": 4, "limit": 20},
+			"alertRecipients": {},
+			"requestsPerMinute": {"limit": 120}
+		}`))
+	})
+})
@@ -59,6 +59,26 @@ func (r *Reader) Drawdowns(ctx context.Context) ([]openapi.Drawdown, error) {
 	return dds, nil
 }
 
+// CurrentDrawdown returns how far the latest portfolio value sits below its
+// running peak, as a non-positive fraction (-0.12 = 12% under the high-water
+// mark). Zero means the portfolio is at a new high.
+func (r *Reader) CurrentDrawdown(ctx context.Context) (float64, error) {
+	var peak, latest float64
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(value), 0) FROM perf_data WHERE `+portfolioValueClause).Scan(&peak)
+	if err != nil {
+		return 0, fmt.Errorf("current drawdown peak: %w", err)
+	}
+	latest, err = r.latestPerf(ctx, portfolioValueClause)
+	if err != nil {
+		return 0, err
+	}
+	if peak <= 0 {
+		return 0, nil
+	}
+	return latest/peak - 1, nil
+}
+
 type perfPoint struct {
 	d time.Time
 	v float64
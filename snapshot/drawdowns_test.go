@@ -17,6 +17,7 @@ package snapshot_test
 
 import (
 	"context"
+	"database/sql"
 	"path/filepath"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -41,3 +42,30 @@ var _ = Describe("Drawdowns", func() {
 		Expect(dds[0].Depth).To(BeNumerically("~", -0.060, 0.001))
 	})
 })
+
+var _ = Describe("CurrentDrawdown", func() {
+	It("is zero at a new high and negative below the peak", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "f.sqlite")
+		Expect(snapshot.BuildTestSnapshot(path)).To(Succeed())
+
+		r, err := snapshot.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		dd, err := r.CurrentDrawdown(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dd).To(BeNumerically("~", 0, 1e-9))
+		Expect(r.Close()).To(Succeed())
+
+		db, err := sql.Open("sqlite", "file:"+path)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = db.Exec(`INSERT INTO perf_data VALUES ('2024-01-09', 'portfolio_value', 92700)`)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(db.Close()).To(Succeed())
+
+		r, err = snapshot.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer r.Close()
+		dd, err = r.CurrentDrawdown(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dd).To(BeNumerically("~", -0.10, 1e-9))
+	})
+})
@@ -0,0 +1,217 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// MinSchemaVersion is the oldest pvbt snapshot schema Reader understands.
+// Schema 6 introduced the prediction tables; schema 7 added
+// predicted_annotations, which Reader treats as optional.
+const MinSchemaVersion = 6
+
+// SchemaIssue describes one way a snapshot deviates from the layout Reader
+// expects. Table and Column are set for table-level problems, Key for
+// metadata problems.
+type SchemaIssue struct {
+	Table   string
+	Column  string
+	Key     string
+	Message string
+}
+
+// requiredTables lists every table Reader queries unconditionally, with the
+// columns those queries touch. The prediction tables are omitted because
+// Reader checks sqlite_master before reading them.
+var requiredTables = []struct {
+	name    string
+	columns []string
+}{
+	{"metadata", []string{"key", "value"}},
+	{"perf_data", []string{"date", "metric", "value"}},
+	{"metrics", []string{"date", "name", "window", "value"}},
+	{"holdings", []string{"asset_ticker", "asset_figi", "quantity", "avg_cost", "market_value"}},
+	{"transactions", []string{"batch_id", "date", "type", "ticker", "figi", "quantity", "price", "amount", "justification"}},
+	{"batches", []string{"batch_id", "timestamp"}},
+	{"annotations", []string{"batch_id", "key", "value"}},
+	{"positions_daily", []string{"date", "ticker", "figi", "market_value", "quantity"}},
+}
+
+// CheckSchema opens the snapshot at path and reports every missing table,
+// missing column, and missing or malformed metadata key that would make a
+// Reader accessor fail. An empty slice means the snapshot is servable. The
+// returned error is non-nil only when the file cannot be opened at all.
+func CheckSchema(ctx context.Context, path string) ([]SchemaIssue, error) {
+	r, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+
+	issues := []SchemaIssue{}
+	present := map[string]bool{}
+	for _, t := range requiredTables {
+		cols, err := r.tableColumns(ctx, t.name)
+		if err != nil {
+			return nil, err
+		}
+		if len(cols) == 0 {
+			issues = append(issues, SchemaIssue{
+				Table:   t.name,
+				Message: fmt.Sprintf("required table %q is missing", t.name),
+			})
+			continue
+		}
+		present[t.name] = true
+		for _, col := range t.columns {
+			if !cols[col] {
+				issues = append(issues, SchemaIssue{
+					Table:   t.name,
+					Column:  col,
+					Message: fmt.Sprintf("table %q is missing column %q", t.name, col),
+				})
+			}
+		}
+	}
+
+	if present["metadata"] {
+		metaIssues, err := r.checkMetadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, metaIssues...)
+	}
+
+	if present["perf_data"] {
+		var n int
+		err := r.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM perf_data WHERE metric = 'portfolio_value'`).Scan(&n)
+		if err != nil {
+			return nil, fmt.Errorf("check perf_data: %w", err)
+		}
+		if n == 0 {
+			issues = append(issues, SchemaIssue{
+				Table:   "perf_data",
+				Message: "perf_data has no portfolio_value rows",
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+// tableColumns returns the set of column names on table, or an empty set
+// when the table does not exist.
+func (r *Reader) tableColumns(ctx context.Context, table string) (map[string]bool, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("table info %s: %w", table, err)
+	}
+	defer func() { _ = rows.Close() }()
+	cols := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("table info %s scan: %w", table, err)
+		}
+		cols[name] = true
+	}
+	return cols, rows.Err()
+}
+
+// checkMetadata validates schema_version and the run window keys. Either
+// the pvbt run.start/run.end pair or the legacy start_date/end_date pair
+// satisfies the window check.
+func (r *Reader) checkMetadata(ctx context.Context) ([]SchemaIssue, error) {
+	var issues []SchemaIssue
+
+	var version string
+	err := r.db.QueryRowContext(ctx,
+		`SELECT value FROM metadata WHERE key = 'schema_version'`).Scan(&version)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		issues = append(issues, SchemaIssue{
+			Table:   "metadata",
+			Key:     "schema_version",
+			Message: "metadata key \"schema_version\" is missing",
+		})
+	case err != nil:
+		return nil, fmt.Errorf("read schema_version: %w", err)
+	default:
+		v, perr := strconv.Atoi(version)
+		if perr != nil {
+			issues = append(issues, SchemaIssue{
+				Table:   "metadata",
+				Key:     "schema_version",
+				Message: fmt.Sprintf("schema_version %q is not an integer", version),
+			})
+		} else if v < MinSchemaVersion {
+			issues = append(issues, SchemaIssue{
+				Table:   "metadata",
+				Key:     "schema_version",
+				Message: fmt.Sprintf("schema_version %d is older than the minimum supported version %d", v, MinSchemaVersion),
+			})
+		}
+	}
+
+	window := map[string]string{}
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT key, value FROM metadata WHERE key IN ('start_date','end_date','run.start','run.end')`)
+	if err != nil {
+		return nil, fmt.Errorf("read window: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, fmt.Errorf("read window scan: %w", err)
+		}
+		window[k] = v
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read window iterate: %w", err)
+	}
+
+	for _, pair := range [][2]string{{"run.start", "start_date"}, {"run.end", "end_date"}} {
+		key, legacy := pair[0], pair[1]
+		val, ok := window[key]
+		if !ok {
+			val, ok = window[legacy]
+		}
+		if !ok {
+			issues = append(issues, SchemaIssue{
+				Table:   "metadata",
+				Key:     key,
+				Message: fmt.Sprintf("metadata key %q is missing", key),
+			})
+			continue
+		}
+		if _, perr := parseMetadataDate(val); perr != nil {
+			issues = append(issues, SchemaIssue{
+				Table:   "metadata",
+				Key:     key,
+				Message: fmt.Sprintf("metadata key %q has unparseable value %q", key, val),
+			})
+		}
+	}
+
+	return issues, nil
+}
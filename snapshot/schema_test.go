@@ -0,0 +1,110 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/snapshot"
+)
+
+var _ = Describe("CheckSchema", func() {
+	var path string
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "f.sqlite")
+		Expect(snapshot.BuildTestSnapshot(path)).To(Succeed())
+	})
+
+	exec := func(stmt string) {
+		db, err := sql.Open("sqlite", "file:"+path)
+		Expect(err).NotTo(HaveOccurred())
+		defer db.Close()
+		_, err = db.Exec(stmt)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("reports no issues for the reference fixture", func() {
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("reports a missing positions_daily table", func() {
+		exec(`DROP TABLE positions_daily`)
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Table).To(Equal("positions_daily"))
+		Expect(issues[0].Message).To(ContainSubstring("missing"))
+	})
+
+	It("reports missing columns", func() {
+		exec(`ALTER TABLE holdings DROP COLUMN avg_cost`)
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(HaveField("Column", "avg_cost")))
+	})
+
+	It("reports every metadata problem at once", func() {
+		exec(`DELETE FROM metadata WHERE key IN ('schema_version','run.end')`)
+		exec(`UPDATE metadata SET value = 'yesterday' WHERE key = 'run.start'`)
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(
+			HaveField("Key", "schema_version"),
+			HaveField("Key", "run.start"),
+			HaveField("Key", "run.end"),
+		))
+	})
+
+	It("accepts the legacy start_date/end_date keys", func() {
+		exec(`DELETE FROM metadata WHERE key IN ('run.start','run.end')`)
+		exec(`INSERT INTO metadata VALUES ('start_date', '2024-01-02'), ('end_date', '2024-01-08')`)
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("rejects schema versions older than the minimum", func() {
+		exec(`UPDATE metadata SET value = '5' WHERE key = 'schema_version'`)
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(HaveLen(1))
+		Expect(issues[0].Message).To(ContainSubstring("older than"))
+	})
+
+	It("accepts the minimum schema version", func() {
+		Expect(snapshot.MinSchemaVersion).To(Equal(6))
+		exec(`UPDATE metadata SET value = '6' WHERE key = 'schema_version'`)
+		exec(`DROP TABLE IF EXISTS predicted_annotations`)
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(BeEmpty())
+	})
+
+	It("flags a snapshot with no portfolio values", func() {
+		exec(`DELETE FROM perf_data`)
+		issues, err := snapshot.CheckSchema(context.Background(), path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues).To(ConsistOf(HaveField("Table", "perf_data")))
+	})
+})
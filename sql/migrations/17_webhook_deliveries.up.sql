@@ -0,0 +1,13 @@
+-- Delivery IDs seen by the strategy-sync webhooks. GitHub retries a
+-- delivery (same X-GitHub-Delivery) when it does not get a timely 2xx, and
+-- operators can redeliver from the UI; recording the ID lets every pvapi
+-- replica drop repeats instead of queueing the same install twice. Rows are
+-- pruned after seven days, well past GitHub's redelivery window.
+CREATE TABLE webhook_deliveries (
+    source       TEXT        NOT NULL,
+    delivery_id  TEXT        NOT NULL,
+    received_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, delivery_id)
+);
+
+CREATE INDEX webhook_deliveries_received_at_idx ON webhook_deliveries (received_at);
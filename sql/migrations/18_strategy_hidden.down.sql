@@ -0,0 +1 @@
+ALTER TABLE strategies DROP COLUMN IF EXISTS hidden;
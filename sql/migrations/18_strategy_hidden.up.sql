@@ -0,0 +1,4 @@
+-- Lets an operator pull a strategy from the GET /strategies catalog without
+-- deleting its row. Hidden strategies keep syncing and remain reachable by
+-- short code so existing portfolios are unaffected.
+ALTER TABLE strategies ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1 @@
+ALTER TABLE strategies DROP COLUMN risk_adjusted_score;
@@ -0,0 +1,4 @@
+-- Composite score the strategy leaderboard sorts on by default. Written by
+-- the StatsRefresher alongside the metrics it is derived from (Sortino,
+-- Sharpe, and CAGR over max drawdown); NULL until the next stats refresh.
+ALTER TABLE strategies ADD COLUMN risk_adjusted_score DOUBLE PRECISION;
@@ -0,0 +1,9 @@
+DELETE FROM portfolio_alerts WHERE frequency = 'on_condition';
+
+ALTER TABLE portfolio_alerts
+    DROP COLUMN condition_state,
+    DROP COLUMN conditions;
+
+ALTER TABLE portfolio_alerts DROP CONSTRAINT portfolio_alerts_frequency_check;
+ALTER TABLE portfolio_alerts ADD CONSTRAINT portfolio_alerts_frequency_check
+    CHECK (frequency IN ('scheduled_run','daily','weekly','monthly'));
@@ -0,0 +1,11 @@
+-- Condition-based alerts: an alert with frequency 'on_condition' is evaluated
+-- against the fresh snapshot after every successful run and sends only when
+-- one of its conditions newly fires. condition_state remembers what each
+-- condition last saw so a drawdown that persists for weeks alerts once.
+ALTER TABLE portfolio_alerts DROP CONSTRAINT portfolio_alerts_frequency_check;
+ALTER TABLE portfolio_alerts ADD CONSTRAINT portfolio_alerts_frequency_check
+    CHECK (frequency IN ('scheduled_run','daily','weekly','monthly','on_condition'));
+
+ALTER TABLE portfolio_alerts
+    ADD COLUMN conditions      JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN condition_state JSONB NOT NULL DEFAULT '{}';
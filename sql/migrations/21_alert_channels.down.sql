@@ -0,0 +1,3 @@
+DELETE FROM portfolio_alerts WHERE array_length(recipients, 1) IS NULL;
+
+ALTER TABLE portfolio_alerts DROP COLUMN channels;
@@ -0,0 +1,6 @@
+-- Non-email delivery targets for an alert (signed webhooks, Slack, Discord,
+-- Telegram, ntfy), as a JSON array of {type, url, secret, chatId, topic}.
+-- recipients keeps the email addresses and may now be empty when the alert
+-- has at least one channel.
+ALTER TABLE portfolio_alerts
+    ADD COLUMN channels JSONB NOT NULL DEFAULT '[]';
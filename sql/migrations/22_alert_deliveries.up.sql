@@ -0,0 +1,29 @@
+-- Outbox for alert notifications. The checker renders each message and
+-- inserts one row per email recipient or channel target in the same
+-- transaction that records the alert as sent; a worker then delivers due
+-- rows, retrying with backoff until they are sent or dead-lettered. Rows
+-- double as the delivery history shown to users.
+CREATE TABLE alert_deliveries (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    alert_id            UUID NOT NULL REFERENCES portfolio_alerts(id) ON DELETE CASCADE,
+    channel             TEXT NOT NULL,
+    destination         TEXT NOT NULL,
+    target              JSONB,
+    event               TEXT NOT NULL,
+    subject             TEXT NOT NULL,
+    html_body           TEXT NOT NULL DEFAULT '',
+    text_body           TEXT NOT NULL,
+    payload             JSONB NOT NULL DEFAULT '{}',
+    status              TEXT NOT NULL DEFAULT 'pending'
+                        CHECK (status IN ('pending','sending','sent','dead')),
+    attempts            INT NOT NULL DEFAULT 0,
+    next_attempt_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error          TEXT,
+    provider_message_id TEXT,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at             TIMESTAMPTZ
+);
+
+CREATE INDEX idx_alert_deliveries_due ON alert_deliveries(next_attempt_at)
+    WHERE status IN ('pending','sending');
+CREATE INDEX idx_alert_deliveries_alert ON alert_deliveries(alert_id, created_at DESC);
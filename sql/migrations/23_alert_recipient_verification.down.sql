@@ -0,0 +1 @@
+ALTER TABLE portfolio_alerts DROP COLUMN verified_recipients;
@@ -0,0 +1,8 @@
+-- Double opt-in for alert emails. A recipient is mailed only once they
+-- appear in verified_recipients, which happens when they follow the signed
+-- confirmation link. Recipients already on an alert before this migration
+-- are grandfathered in as verified.
+ALTER TABLE portfolio_alerts
+    ADD COLUMN verified_recipients TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE portfolio_alerts SET verified_recipients = recipients;
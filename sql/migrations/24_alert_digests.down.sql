@@ -0,0 +1,4 @@
+DELETE FROM alert_deliveries WHERE alert_id IS NULL;
+ALTER TABLE alert_deliveries DROP COLUMN digest_id;
+ALTER TABLE alert_deliveries ALTER COLUMN alert_id SET NOT NULL;
+DROP TABLE IF EXISTS alert_digests;
@@ -0,0 +1,23 @@
+-- Per-user digest: one email on a daily, weekly or monthly cadence that
+-- summarizes every portfolio the user owns, instead of an alert per
+-- portfolio. Recipients go through the same double opt-in as alert
+-- recipients. Digest emails share the alert delivery outbox, so a delivery
+-- now belongs to either an alert or a digest.
+CREATE TABLE alert_digests (
+    id                  UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_sub           TEXT NOT NULL UNIQUE,
+    frequency           TEXT NOT NULL CHECK (frequency IN ('daily','weekly','monthly')),
+    recipients          TEXT[] NOT NULL DEFAULT '{}',
+    verified_recipients TEXT[] NOT NULL DEFAULT '{}',
+    last_sent_at        TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE alert_deliveries
+    ALTER COLUMN alert_id DROP NOT NULL,
+    ADD COLUMN digest_id UUID REFERENCES alert_digests(id) ON DELETE CASCADE,
+    ADD CONSTRAINT alert_deliveries_owner CHECK ((alert_id IS NULL) <> (digest_id IS NULL));
+
+CREATE INDEX idx_alert_deliveries_digest ON alert_deliveries(digest_id, created_at DESC)
+    WHERE digest_id IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE alert_digests DROP COLUMN recipient_locales;
+ALTER TABLE portfolio_alerts DROP COLUMN recipient_locales;
@@ -0,0 +1,5 @@
+-- Each alert and digest recipient can read email in their own locale, stored
+-- as recipient -> BCP 47 tag. Recipients without an entry get the server's
+-- default locale.
+ALTER TABLE portfolio_alerts ADD COLUMN recipient_locales JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE alert_digests ADD COLUMN recipient_locales JSONB NOT NULL DEFAULT '{}';
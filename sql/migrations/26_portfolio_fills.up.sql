@@ -0,0 +1,21 @@
+-- Manual trade fills: users who mirror a strategy at their own broker mark
+-- each predicted trade as executed with the price and quantity they actually
+-- got. A trade (portfolio, date, side, ticker) has at most one fill; marking
+-- it again replaces the fill. predicted_* keep what the prediction said at
+-- the time, so drift can be measured before the backtest reaches that date.
+CREATE TABLE portfolio_fills (
+    id                 UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    portfolio_id       UUID NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+    trade_date         DATE NOT NULL,
+    type               TEXT NOT NULL CHECK (type IN ('buy','sell')),
+    ticker             TEXT NOT NULL,
+    figi               TEXT,
+    quantity           DOUBLE PRECISION NOT NULL CHECK (quantity > 0),
+    price              DOUBLE PRECISION NOT NULL CHECK (price > 0),
+    predicted_quantity DOUBLE PRECISION,
+    predicted_price    DOUBLE PRECISION,
+    executed_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    note               TEXT NOT NULL DEFAULT '',
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (portfolio_id, trade_date, type, ticker)
+);
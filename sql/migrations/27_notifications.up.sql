@@ -0,0 +1,20 @@
+-- In-app notification inbox: one row per event a user should hear about
+-- (run finished or failed, portfolio auto-upgraded, alert condition fired).
+-- Ids are UUIDv7 so they sort by creation time; the inbox pages on id and
+-- the SSE stream replays from a Last-Event-ID.
+CREATE TABLE notifications (
+    id             UUID PRIMARY KEY DEFAULT uuidv7(),
+    owner_sub      TEXT NOT NULL,
+    kind           TEXT NOT NULL,
+    portfolio_id   UUID REFERENCES portfolios(id) ON DELETE CASCADE,
+    portfolio_slug TEXT,
+    run_id         UUID,
+    title          TEXT NOT NULL,
+    body           TEXT NOT NULL DEFAULT '',
+    data           JSONB NOT NULL DEFAULT '{}',
+    read_at        TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_notifications_owner ON notifications (owner_sub, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications (owner_sub) WHERE read_at IS NULL;
@@ -0,0 +1,15 @@
+-- Public share links: an owner can publish a read-only view of one
+-- portfolio. The token is an unguessable random string; the handle is an
+-- optional vanity name chosen by the owner. Either resolves the share.
+-- hide_values rebases dollar figures away; show_holdings opts in to
+-- exposing current positions.
+CREATE TABLE portfolio_shares (
+    id            UUID PRIMARY KEY DEFAULT uuidv7(),
+    portfolio_id  UUID NOT NULL UNIQUE REFERENCES portfolios(id) ON DELETE CASCADE,
+    token         TEXT NOT NULL UNIQUE,
+    handle        TEXT UNIQUE,
+    hide_values   BOOLEAN NOT NULL DEFAULT false,
+    show_holdings BOOLEAN NOT NULL DEFAULT false,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
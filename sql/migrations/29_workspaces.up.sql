@@ -0,0 +1,23 @@
+-- Workspaces let a team share one book of portfolios. Each member has a
+-- role: viewers read, editors also create, change and run portfolios,
+-- owners also delete them and manage the workspace and its members.
+-- A portfolio that belongs to a workspace has owner_sub
+-- 'workspace|<workspace id>' instead of a user's subject, so slugs are
+-- unique per workspace and every portfolio query keeps keying on owner_sub.
+CREATE TABLE workspaces (
+    id         UUID PRIMARY KEY DEFAULT uuidv7(),
+    name       TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    sub          TEXT NOT NULL,
+    role         TEXT NOT NULL CHECK (role IN ('viewer','editor','owner')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (workspace_id, sub)
+);
+
+CREATE INDEX idx_workspace_members_sub ON workspace_members (sub);
@@ -0,0 +1,17 @@
+-- Personal API keys let scripts and notebooks call the API without an
+-- interactive login. Only a SHA-256 hash of each key is kept; the key
+-- itself is shown once, when it is created. prefix is the first few
+-- characters of the key so users can tell their keys apart.
+CREATE TABLE api_keys (
+    id           UUID PRIMARY KEY DEFAULT uuidv7(),
+    owner_sub    TEXT NOT NULL,
+    name         TEXT NOT NULL,
+    prefix       TEXT NOT NULL,
+    key_hash     BYTEA NOT NULL UNIQUE,
+    read_only    BOOLEAN NOT NULL DEFAULT TRUE,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_api_keys_owner ON api_keys (owner_sub);
@@ -0,0 +1,34 @@
+-- An append-only trail of who changed what: portfolio creates, edits,
+-- deletes, transfers and upgrades (by hand and automatic), run triggers,
+-- alert changes, unsubscribes and admin actions. actor is who acted and
+-- subject whose authority they used; they differ only when an admin acts
+-- as a user. owner_sub is the owner of what changed, so users can read
+-- the trail of their own portfolios. portfolio_id is deliberately not a
+-- foreign key: entries outlive the portfolios they describe. changes maps
+-- each changed field to {"before": ..., "after": ...}.
+CREATE TABLE audit_log (
+    id             UUID PRIMARY KEY DEFAULT uuidv7(),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    action         TEXT NOT NULL,
+    actor          TEXT NOT NULL,
+    subject        TEXT NOT NULL,
+    owner_sub      TEXT,
+    portfolio_id   UUID,
+    portfolio_slug TEXT,
+    target         TEXT,
+    request_id     TEXT,
+    changes        JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX idx_audit_log_owner ON audit_log (owner_sub, id DESC);
+CREATE INDEX idx_audit_log_portfolio ON audit_log (portfolio_id, id DESC);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,17 @@
+-- Responses to POSTs that carried an Idempotency-Key header, kept so a
+-- retry replays the first response instead of repeating the change. Keys
+-- are per subject. fingerprint is a SHA-256 of the method, path, workspace
+-- and body, so a key reused for a different request is refused. status is
+-- NULL while the first request is still being handled.
+CREATE TABLE idempotency_keys (
+    subject      TEXT NOT NULL,
+    key          TEXT NOT NULL,
+    fingerprint  BYTEA NOT NULL,
+    status       INTEGER,
+    content_type TEXT,
+    body         BYTEA,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subject, key)
+);
+
+CREATE INDEX idx_idempotency_keys_created ON idempotency_keys (created_at);
@@ -0,0 +1,3 @@
+-- Free-form labels users put on portfolios so they can filter long lists
+-- (GET /portfolios?tag=...). Stored lower-cased and de-duplicated.
+ALTER TABLE portfolios ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
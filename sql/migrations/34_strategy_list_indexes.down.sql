@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_strategies_risk_adjusted_score;
+DROP INDEX IF EXISTS idx_strategies_benchmark_ytd_return;
+DROP INDEX IF EXISTS idx_strategies_ytd_return;
+DROP INDEX IF EXISTS idx_strategies_one_year_return;
+DROP INDEX IF EXISTS idx_strategies_tax_cost_ratio;
+DROP INDEX IF EXISTS idx_strategies_std_dev;
+DROP INDEX IF EXISTS idx_strategies_alpha;
+DROP INDEX IF EXISTS idx_strategies_beta;
+DROP INDEX IF EXISTS idx_strategies_ulcer_index;
+DROP INDEX IF EXISTS idx_strategies_sortino;
+DROP INDEX IF EXISTS idx_strategies_sharpe;
+DROP INDEX IF EXISTS idx_strategies_max_drawdown;
+DROP INDEX IF EXISTS idx_strategies_cagr;
+DROP INDEX IF EXISTS idx_strategies_stars;
+DROP INDEX IF EXISTS idx_strategies_categories;
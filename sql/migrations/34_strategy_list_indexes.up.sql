@@ -0,0 +1,18 @@
+-- GET /strategies filters and sorts in SQL. Category filters use array
+-- overlap (GIN), and each sortable stats column gets a (column,
+-- short_code) index matching the keyset ORDER BY's tie-breaker.
+CREATE INDEX idx_strategies_categories ON strategies USING GIN (categories);
+CREATE INDEX idx_strategies_stars ON strategies (stars, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_cagr ON strategies (cagr, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_max_drawdown ON strategies (max_drawdown, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_sharpe ON strategies (sharpe, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_sortino ON strategies (sortino, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_ulcer_index ON strategies (ulcer_index, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_beta ON strategies (beta, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_alpha ON strategies (alpha, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_std_dev ON strategies (std_dev, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_tax_cost_ratio ON strategies (tax_cost_ratio, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_one_year_return ON strategies (one_year_return, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_ytd_return ON strategies (ytd_return, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_benchmark_ytd_return ON strategies (benchmark_ytd_return, short_code) WHERE NOT hidden;
+CREATE INDEX idx_strategies_risk_adjusted_score ON strategies (risk_adjusted_score, short_code) WHERE NOT hidden;
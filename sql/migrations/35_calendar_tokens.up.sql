@@ -0,0 +1,8 @@
+-- Per-user calendar feed tokens. Only an HMAC of each token, keyed with the
+-- server's calendar_secret, is stored. Issuing a new token replaces the
+-- user's old one; deleting the row revokes the feed.
+CREATE TABLE calendar_tokens (
+    owner_sub  TEXT PRIMARY KEY,
+    token_hash BYTEA NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
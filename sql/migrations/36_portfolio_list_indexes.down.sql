@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_portfolios_owner_cagr_since_inception;
+DROP INDEX IF EXISTS idx_portfolios_owner_max_drawdown;
+DROP INDEX IF EXISTS idx_portfolios_owner_sharpe;
+DROP INDEX IF EXISTS idx_portfolios_owner_ytd_return;
+DROP INDEX IF EXISTS idx_portfolios_owner_current_value;
+DROP INDEX IF EXISTS idx_portfolios_owner_created_at;
+DROP INDEX IF EXISTS idx_portfolios_tags;
+DROP INDEX IF EXISTS idx_portfolios_name_search;
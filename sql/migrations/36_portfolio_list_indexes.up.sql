@@ -0,0 +1,12 @@
+-- Indexes for GET /portfolios, which filters, searches, sorts and pages in
+-- SQL. The name search matches prefixes against to_tsvector('simple', name)
+-- and tag filters use tags && $n; each sort field is ordered by
+-- (column, slug) within one owner, which is also the keyset order.
+CREATE INDEX idx_portfolios_name_search ON portfolios USING GIN (to_tsvector('simple', name));
+CREATE INDEX idx_portfolios_tags ON portfolios USING GIN (tags);
+CREATE INDEX idx_portfolios_owner_created_at ON portfolios (owner_sub, created_at, slug);
+CREATE INDEX idx_portfolios_owner_current_value ON portfolios (owner_sub, current_value, slug);
+CREATE INDEX idx_portfolios_owner_ytd_return ON portfolios (owner_sub, ytd_return, slug);
+CREATE INDEX idx_portfolios_owner_sharpe ON portfolios (owner_sub, sharpe, slug);
+CREATE INDEX idx_portfolios_owner_max_drawdown ON portfolios (owner_sub, max_drawdown, slug);
+CREATE INDEX idx_portfolios_owner_cagr_since_inception ON portfolios (owner_sub, cagr_since_inception, slug);
@@ -0,0 +1,163 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// AdminStore is the persistence contract for the registry admin routes.
+type AdminStore interface {
+	Get(ctx context.Context, shortCode string) (Strategy, error)
+	ClearInstallError(ctx context.Context, shortCode string) error
+	SetHidden(ctx context.Context, shortCode string, hidden bool) error
+}
+
+// AdminSyncer is the subset of *Syncer the admin routes drive.
+type AdminSyncer interface {
+	Tick(ctx context.Context) error
+	Reinstall(ctx context.Context, shortCode, version string) error
+}
+
+// AdminHandler serves the operator-only registry routes: forcing a sync
+// tick, reinstalling a version, clearing an install error, hiding a
+// strategy from the catalog, and refreshing its stats. Long-running work
+// (sync, install, stats) is started in the background and answered with
+// 202; the outcome lands on the strategy row as usual. The caller is
+// responsible for mounting these behind an admin scope check.
+type AdminHandler struct {
+	Store  AdminStore
+	Syncer AdminSyncer
+	Stats  StatsRunner
+}
+
+// adminReinstallRequest is the optional body of the reinstall route.
+type adminReinstallRequest struct {
+	Version string `json:"version"`
+}
+
+// Sync implements POST /admin/strategies/sync.
+func (h *AdminHandler) Sync(c fiber.Ctx) error {
+	ctx := context.WithoutCancel(c.Context())
+	go func() {
+		if err := h.Syncer.Tick(ctx); err != nil {
+			log.Warn().Err(err).Msg("admin-triggered strategy sync tick failed")
+		}
+	}()
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"status": "accepted"})
+}
+
+// Reinstall implements POST /admin/strategies/{shortCode}/reinstall. The
+// body's version defaults to the installed version, or the latest tag when
+// nothing is installed yet.
+func (h *AdminHandler) Reinstall(c fiber.Ctx) error {
+	var req adminReinstallRequest
+	if body := c.Body(); len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid JSON body: "+err.Error())
+		}
+	}
+	if req.Version != "" && !refRe.MatchString(req.Version) {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", ErrInvalidRef.Error())
+	}
+	s, ok, err := h.load(c)
+	if !ok {
+		return err
+	}
+	version := req.Version
+	if version == "" && s.InstalledVer != nil {
+		version = *s.InstalledVer
+	}
+
+	log.Info().Str("short_code", s.ShortCode).Str("version", version).Msg("admin-triggered strategy reinstall")
+	ctx := context.WithoutCancel(c.Context())
+	go func() {
+		if err := h.Syncer.Reinstall(ctx, s.ShortCode, version); err != nil {
+			log.Warn().Err(err).Str("short_code", s.ShortCode).Str("version", version).Msg("admin reinstall failed")
+		}
+	}()
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"status":    "accepted",
+		"shortCode": s.ShortCode,
+		"version":   version,
+	})
+}
+
+// ClearInstallError implements DELETE /admin/strategies/{shortCode}/install-error.
+func (h *AdminHandler) ClearInstallError(c fiber.Ctx) error {
+	shortCode := c.Params("shortCode")
+	if err := h.Store.ClearInstallError(c.Context(), shortCode); err != nil {
+		return adminStoreProblem(c, shortCode, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Hide implements POST /admin/strategies/{shortCode}/hide.
+func (h *AdminHandler) Hide(c fiber.Ctx) error { return h.setHidden(c, true) }
+
+// Unhide implements POST /admin/strategies/{shortCode}/unhide.
+func (h *AdminHandler) Unhide(c fiber.Ctx) error { return h.setHidden(c, false) }
+
+func (h *AdminHandler) setHidden(c fiber.Ctx, hidden bool) error {
+	shortCode := c.Params("shortCode")
+	if err := h.Store.SetHidden(c.Context(), shortCode, hidden); err != nil {
+		return adminStoreProblem(c, shortCode, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RefreshStats implements POST /admin/strategies/{shortCode}/stats.
+func (h *AdminHandler) RefreshStats(c fiber.Ctx) error {
+	s, ok, err := h.load(c)
+	if !ok {
+		return err
+	}
+	if s.ArtifactRef == nil {
+		return writeProblem(c, fiber.StatusConflict, "Conflict", ErrNoInstalledArtifact.Error())
+	}
+
+	ctx := context.WithoutCancel(c.Context())
+	go func() {
+		if err := h.Stats.RunOne(ctx, s.ShortCode); err != nil {
+			log.Warn().Err(err).Str("short_code", s.ShortCode).Msg("admin stats refresh failed")
+		}
+	}()
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"status": "accepted", "shortCode": s.ShortCode})
+}
+
+// load fetches the strategy named by the shortCode path parameter. When ok
+// is false the problem response has already been written and err is the
+// value the handler should return.
+func (h *AdminHandler) load(c fiber.Ctx) (Strategy, bool, error) {
+	shortCode := c.Params("shortCode")
+	s, err := h.Store.Get(c.Context(), shortCode)
+	if err != nil {
+		return Strategy{}, false, adminStoreProblem(c, shortCode, err)
+	}
+	return s, true, nil
+}
+
+func adminStoreProblem(c fiber.Ctx, shortCode string, err error) error {
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "strategy not found: "+shortCode)
+	}
+	return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+}
@@ -0,0 +1,160 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy_test
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/gofiber/fiber/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/strategy"
+)
+
+// fakeAdminSyncer records the admin-triggered Syncer calls.
+type fakeAdminSyncer struct {
+	mu         sync.Mutex
+	ticks      int
+	reinstalls []syncCall
+}
+
+func (f *fakeAdminSyncer) Tick(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ticks++
+	return nil
+}
+
+func (f *fakeAdminSyncer) Reinstall(_ context.Context, shortCode, version string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reinstalls = append(f.reinstalls, syncCall{shortCode, version})
+	return nil
+}
+
+var _ = Describe("AdminHandler", func() {
+	var (
+		app    *fiber.App
+		store  *fakeStore
+		syncer *fakeAdminSyncer
+		stats  *fakeStatsRefresher
+	)
+
+	BeforeEach(func() {
+		store = newFakeStore()
+		ver := "v1.0.0"
+		attempted := "v1.1.0"
+		ref := "/srv/official/adm.bin"
+		failure := "go build failed"
+		store.rows["adm"] = strategy.Strategy{
+			ShortCode:        "adm",
+			InstalledVer:     &ver,
+			LastAttemptedVer: &attempted,
+			InstallError:     &failure,
+			ArtifactRef:      &ref,
+		}
+		store.rows["new"] = strategy.Strategy{ShortCode: "new"}
+		syncer = &fakeAdminSyncer{}
+		stats = &fakeStatsRefresher{}
+
+		h := &strategy.AdminHandler{Store: store, Syncer: syncer, Stats: stats}
+		app = fiber.New()
+		app.Post("/admin/strategies/sync", h.Sync)
+		app.Post("/admin/strategies/:shortCode/reinstall", h.Reinstall)
+		app.Delete("/admin/strategies/:shortCode/install-error", h.ClearInstallError)
+		app.Post("/admin/strategies/:shortCode/hide", h.Hide)
+		app.Post("/admin/strategies/:shortCode/unhide", h.Unhide)
+		app.Post("/admin/strategies/:shortCode/stats", h.RefreshStats)
+	})
+
+	do := func(method, path, body string) int {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	It("starts a sync tick in the background", func() {
+		Expect(do("POST", "/admin/strategies/sync", "")).To(Equal(fiber.StatusAccepted))
+		Eventually(func() int {
+			syncer.mu.Lock()
+			defer syncer.mu.Unlock()
+			return syncer.ticks
+		}).Should(Equal(1))
+	})
+
+	It("reinstalls the requested version", func() {
+		Expect(do("POST", "/admin/strategies/adm/reinstall", `{"version":"v1.1.0"}`)).To(Equal(fiber.StatusAccepted))
+		Eventually(func() []syncCall {
+			syncer.mu.Lock()
+			defer syncer.mu.Unlock()
+			return append([]syncCall(nil), syncer.reinstalls...)
+		}).Should(Equal([]syncCall{{"adm", "v1.1.0"}}))
+	})
+
+	It("defaults the reinstall version to the installed version", func() {
+		Expect(do("POST", "/admin/strategies/adm/reinstall", "")).To(Equal(fiber.StatusAccepted))
+		Eventually(func() []syncCall {
+			syncer.mu.Lock()
+			defer syncer.mu.Unlock()
+			return append([]syncCall(nil), syncer.reinstalls...)
+		}).Should(Equal([]syncCall{{"adm", "v1.0.0"}}))
+	})
+
+	It("rejects a malformed reinstall version", func() {
+		Expect(do("POST", "/admin/strategies/adm/reinstall", `{"version":"--upload-pack=x"}`)).To(Equal(fiber.StatusBadRequest))
+	})
+
+	It("404s a reinstall of an unknown strategy", func() {
+		Expect(do("POST", "/admin/strategies/nope/reinstall", "")).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("clears the install error and rewinds the attempted version", func() {
+		Expect(do("DELETE", "/admin/strategies/adm/install-error", "")).To(Equal(fiber.StatusNoContent))
+		row := store.rows["adm"]
+		Expect(row.InstallError).To(BeNil())
+		Expect(*row.LastAttemptedVer).To(Equal("v1.0.0"))
+	})
+
+	It("hides and unhides a strategy", func() {
+		Expect(do("POST", "/admin/strategies/adm/hide", "")).To(Equal(fiber.StatusNoContent))
+		Expect(store.rows["adm"].Hidden).To(BeTrue())
+		Expect(do("POST", "/admin/strategies/adm/unhide", "")).To(Equal(fiber.StatusNoContent))
+		Expect(store.rows["adm"].Hidden).To(BeFalse())
+		Expect(do("POST", "/admin/strategies/nope/hide", "")).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("refreshes stats for an installed strategy", func() {
+		Expect(do("POST", "/admin/strategies/adm/stats", "")).To(Equal(fiber.StatusAccepted))
+		Eventually(func() []string {
+			stats.mu.Lock()
+			defer stats.mu.Unlock()
+			return append([]string(nil), stats.runOneCalls...)
+		}).Should(Equal([]string{"adm"}))
+	})
+
+	It("refuses a stats refresh when nothing is installed", func() {
+		Expect(do("POST", "/admin/strategies/new/stats", "")).To(Equal(fiber.StatusConflict))
+	})
+})
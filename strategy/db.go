@@ -22,6 +22,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/penny-vault/pv-api/keyset"
 )
 
 // ErrNotFound is returned when a requested strategy row does not exist.
@@ -34,8 +36,8 @@ const strategyColumns = `
 	artifact_kind, artifact_ref, describe_json,
 	cagr, max_drawdown, sharpe, sortino,
 	ulcer_index, beta, alpha, std_dev, tax_cost_ratio,
-	one_year_return, ytd_return, benchmark_ytd_return,
-	stats_as_of,
+	one_year_return, ytd_return, benchmark_ytd_return, risk_adjusted_score,
+	stats_as_of, hidden,
 	discovered_at, updated_at
 `
 
@@ -61,6 +63,36 @@ func List(ctx context.Context, pool *pgxpool.Pool) ([]Strategy, error) {
 	return out, nil
 }
 
+// ListPage returns the visible strategies matching q, filtered, ordered
+// and resumed after q.Cursor in SQL, reading one row past q.Limit so the
+// caller can tell whether another page follows.
+func ListPage(ctx context.Context, pool *pgxpool.Pool, q ListQuery) ([]Strategy, error) {
+	var args keyset.Args
+	sql := `SELECT ` + strategyColumns + ` FROM strategies WHERE ` + q.where(&args) +
+		` ORDER BY ` + q.order().OrderBy()
+	if n := q.fetchLimit(); n > 0 {
+		sql += ` LIMIT ` + args.Add(n)
+	}
+	rows, err := pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying strategy page: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Strategy
+	for rows.Next() {
+		s, scanErr := scan(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating strategy page: %w", err)
+	}
+	return out, nil
+}
+
 // Get returns one strategy by short code.
 func Get(ctx context.Context, pool *pgxpool.Pool, shortCode string) (Strategy, error) {
 	row := pool.QueryRow(ctx,
@@ -168,6 +200,41 @@ func MarkFailure(ctx context.Context, pool *pgxpool.Pool, shortCode, version, er
 	return nil
 }
 
+// ClearInstallError drops a recorded install failure and rewinds
+// last_attempted_ver to the installed version, so the next sync tick retries
+// the failed version instead of skipping it as already attempted.
+func ClearInstallError(ctx context.Context, pool *pgxpool.Pool, shortCode string) error {
+	tag, err := pool.Exec(ctx, `
+		UPDATE strategies
+		   SET install_error      = NULL,
+		       last_attempted_ver = installed_ver,
+		       updated_at         = NOW()
+		 WHERE short_code = $1
+	`, shortCode)
+	if err != nil {
+		return fmt.Errorf("clear install error %s: %w", shortCode, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// SetHidden toggles whether a strategy appears in the GET /strategies
+// catalog.
+func SetHidden(ctx context.Context, pool *pgxpool.Pool, shortCode string, hidden bool) error {
+	tag, err := pool.Exec(ctx,
+		`UPDATE strategies SET hidden = $2, updated_at = NOW() WHERE short_code = $1`,
+		shortCode, hidden)
+	if err != nil {
+		return fmt.Errorf("set hidden %s: %w", shortCode, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // ListInstalled returns all strategies that have a non-NULL artifact_ref.
 func ListInstalled(ctx context.Context, pool *pgxpool.Pool) ([]Strategy, error) {
 	rows, err := pool.Query(ctx,
@@ -198,11 +265,13 @@ func UpdateStats(ctx context.Context, pool *pgxpool.Pool, shortCode string, r St
 		    SET cagr=$2, max_drawdown=$3, sharpe=$4, sortino=$5,
 		        ulcer_index=$6, beta=$7, alpha=$8, std_dev=$9, tax_cost_ratio=$10,
 		        one_year_return=$11, ytd_return=$12, benchmark_ytd_return=$13,
-		        stats_as_of=$14, stats_error=NULL, updated_at=NOW()
+		        risk_adjusted_score=$14,
+		        stats_as_of=$15, stats_error=NULL, updated_at=NOW()
 		  WHERE short_code=$1`,
 		shortCode, r.CAGR, r.MaxDrawdown, r.Sharpe, r.Sortino,
 		r.UlcerIndex, r.Beta, r.Alpha, r.StdDev, r.TaxCostRatio,
 		r.OneYearReturn, r.YtdReturn, r.BenchmarkYtdReturn,
+		r.RiskAdjustedScore,
 		r.AsOf)
 	if err != nil {
 		return fmt.Errorf("update stats %s: %w", shortCode, err)
@@ -255,8 +324,8 @@ func scan(r scanner) (Strategy, error) {
 		&s.ArtifactKind, &s.ArtifactRef, &s.DescribeJSON,
 		&s.CAGR, &s.MaxDrawdown, &s.Sharpe, &s.Sortino,
 		&s.UlcerIndex, &s.Beta, &s.Alpha, &s.StdDev, &s.TaxCostRatio,
-		&s.OneYearReturn, &s.YtdReturn, &s.BenchmarkYtdReturn,
-		&s.StatsAsOf,
+		&s.OneYearReturn, &s.YtdReturn, &s.BenchmarkYtdReturn, &s.RiskAdjustedScore,
+		&s.StatsAsOf, &s.Hidden,
 		&s.DiscoveredAt, &s.UpdatedAt,
 	)
 	if err != nil {
@@ -264,3 +333,32 @@ func scan(r scanner) (Strategy, error) {
 	}
 	return s, nil
 }
+
+// RecordWebhookDelivery inserts (source, deliveryID) and reports whether it
+// was new. A false return means the delivery was already processed. Rows
+// older than seven days are pruned on the way in.
+func RecordWebhookDelivery(ctx context.Context, pool *pgxpool.Pool, source, deliveryID string) (bool, error) {
+	if _, err := pool.Exec(ctx,
+		`DELETE FROM webhook_deliveries WHERE received_at < NOW() - INTERVAL '7 days'`); err != nil {
+		return false, fmt.Errorf("prune webhook deliveries: %w", err)
+	}
+	tag, err := pool.Exec(ctx, `
+		INSERT INTO webhook_deliveries (source, delivery_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, source, deliveryID)
+	if err != nil {
+		return false, fmt.Errorf("record webhook delivery %s/%s: %w", source, deliveryID, err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ForgetWebhookDelivery deletes a recorded delivery so a redelivery of the
+// same ID is processed instead of dropped as a duplicate.
+func ForgetWebhookDelivery(ctx context.Context, pool *pgxpool.Pool, source, deliveryID string) error {
+	if _, err := pool.Exec(ctx,
+		`DELETE FROM webhook_deliveries WHERE source = $1 AND delivery_id = $2`, source, deliveryID); err != nil {
+		return fmt.Errorf("forget webhook delivery %s/%s: %w", source, deliveryID, err)
+	}
+	return nil
+}
@@ -201,7 +201,7 @@ func buildDockerImage(ctx context.Context, c dockercli.Client, dir, tag string)
 // describeDockerImage runs `<image> describe --json` in a disposable
 // container and returns the raw JSON plus the parsed Describe struct.
 func describeDockerImage(ctx context.Context, c dockercli.Client, tag string) ([]byte, Describe, error) {
-	describeJSON, err := runDescribeInContainer(ctx, c, tag)
+	describeJSON, err := runDescribeInContainer(ctx, c, tag, "")
 	if err != nil {
 		return nil, Describe{}, fmt.Errorf("describe after build: %w", err)
 	}
@@ -283,17 +283,28 @@ func drainBuildStream(r io.Reader) (string, error) {
 	return out.String(), nil
 }
 
+// DescribeImage returns a DescribeFunc that runs describe in a disposable
+// container attached to network ("" = docker's default).
+func DescribeImage(c dockercli.Client, network string) DescribeFunc {
+	return func(ctx context.Context, image string) ([]byte, error) {
+		return runDescribeInContainer(ctx, c, image, network)
+	}
+}
+
 // runDescribeInContainer creates a disposable container from the given image
 // with cmd = ["describe", "--json"], starts it, waits for exit 0, and
 // returns stdout. AutoRemove=true cleans up the container on exit.
-func runDescribeInContainer(ctx context.Context, c dockercli.Client, image string) ([]byte, error) {
+func runDescribeInContainer(ctx context.Context, c dockercli.Client, image, network string) ([]byte, error) {
 	resp, err := c.ContainerCreate(ctx, client.ContainerCreateOptions{
 		Config: &container.Config{
 			Image: image,
 			Cmd:   []string{"describe", "--json"},
 			Tty:   false,
 		},
-		HostConfig: &container.HostConfig{AutoRemove: true},
+		HostConfig: &container.HostConfig{
+			AutoRemove:  true,
+			NetworkMode: container.NetworkMode(network),
+		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("container create: %w", err)
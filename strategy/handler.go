@@ -22,35 +22,60 @@ import (
 
 	"github.com/bytedance/sonic"
 	"github.com/gofiber/fiber/v3"
+
+	"github.com/penny-vault/pv-api/keyset"
 )
 
+// HeaderNextCursor carries the opaque cursor for the next page of a
+// paginated list response.
+const HeaderNextCursor = keyset.HeaderNextCursor
+
 // ReadStore is the subset of Store operations the handler uses.
 type ReadStore interface {
 	List(ctx context.Context) ([]Strategy, error)
 	Get(ctx context.Context, shortCode string) (Strategy, error)
 }
 
+// ListStore adds the filtered, paged catalog query behind GET /strategies.
+type ListStore interface {
+	ReadStore
+	// ListPage returns the visible strategies matching q in q's order,
+	// starting after q.Cursor and reading at most q.Limit+1 rows.
+	ListPage(ctx context.Context, q ListQuery) ([]Strategy, error)
+}
+
 // Handler serves the GET /strategies endpoints.
 type Handler struct {
-	store ReadStore
+	store ListStore
 }
 
 // NewHandler constructs a handler backed by the given read-only store.
-func NewHandler(store ReadStore) *Handler {
+func NewHandler(store ListStore) *Handler {
 	return &Handler{store: store}
 }
 
-// List implements GET /strategies.
+// List implements GET /strategies. Hidden strategies are omitted. The
+// query string filters, sorts, and pages the catalog (see parseListQuery);
+// when more rows remain the next page's cursor is returned in the
+// X-Next-Cursor header.
 func (h *Handler) List(c fiber.Ctx) error {
-	rows, err := h.store.List(c.Context())
+	q, err := parseListQuery(func(key string) string { return string([]byte(c.Query(key))) })
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	rows, err := h.store.ListPage(c.Context(), q)
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
 	}
 
-	out := make([]strategyView, 0, len(rows))
-	for _, r := range rows {
+	page, next := q.page(rows)
+	out := make([]strategyView, 0, len(page))
+	for _, r := range page {
 		out = append(out, toView(r))
 	}
+	if next != "" {
+		c.Set(HeaderNextCursor, next)
+	}
 	body, err := sonic.Marshal(out)
 	if err != nil {
 		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
@@ -109,6 +134,7 @@ type strategyView struct {
 	OneYearReturn      *float64  `json:"oneYearReturn,omitempty"`
 	YtdReturn          *float64  `json:"ytdReturn,omitempty"`
 	BenchmarkYtdReturn *float64  `json:"benchmarkYtdReturn,omitempty"`
+	RiskAdjustedScore  *float64  `json:"riskAdjustedScore,omitempty"`
 }
 
 func toView(s Strategy) strategyView {
@@ -138,6 +164,7 @@ func toView(s Strategy) strategyView {
 		OneYearReturn:      s.OneYearReturn,
 		YtdReturn:          s.YtdReturn,
 		BenchmarkYtdReturn: s.BenchmarkYtdReturn,
+		RiskAdjustedScore:  s.RiskAdjustedScore,
 	}
 	if s.InstalledAt != nil {
 		t := s.InstalledAt.UTC().Format("2006-01-02T15:04:05Z")
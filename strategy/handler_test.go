@@ -83,6 +83,22 @@ var _ = Describe("strategy.Handler", func() {
 		Expect(out).To(HaveLen(2))
 	})
 
+	It("omits hidden strategies from the list but still serves them by short code", func() {
+		row := store.rows["bogus"]
+		row.Hidden = true
+		store.rows["bogus"] = row
+
+		status, body, _ := run("GET", "/strategies")
+		Expect(status).To(Equal(200))
+		var out []map[string]any
+		Expect(sonic.Unmarshal(body, &out)).To(Succeed())
+		Expect(out).To(HaveLen(1))
+		Expect(out[0]["shortCode"]).To(Equal("adm"))
+
+		status, _, _ = run("GET", "/strategies/bogus")
+		Expect(status).To(Equal(200))
+	})
+
 	It("returns install state on a ready strategy", func() {
 		status, body, _ := run("GET", "/strategies/adm")
 		Expect(status).To(Equal(200))
@@ -110,3 +126,109 @@ var _ = Describe("strategy.Handler", func() {
 		Expect(ct).To(Equal("application/problem+json"))
 	})
 })
+
+var _ = Describe("GET /strategies query", func() {
+	var (
+		app   *fiber.App
+		store *fakeStore
+	)
+
+	BeforeEach(func() {
+		store = newFakeStore()
+		f := func(v float64) *float64 { return &v }
+		for _, s := range []strategy.Strategy{
+			{ShortCode: "adm", CAGR: f(0.12)},
+			{ShortCode: "daa", CAGR: f(0.09)},
+			{ShortCode: "kel"},
+		} {
+			store.rows[s.ShortCode] = s
+		}
+		app = fiber.New()
+		app.Get("/strategies", strategy.NewHandler(store).List)
+	})
+
+	list := func(query string) (int, []string, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/strategies"+query, nil))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		if resp.StatusCode != 200 {
+			return resp.StatusCode, nil, ""
+		}
+		var out []map[string]any
+		Expect(sonic.Unmarshal(body, &out)).To(Succeed())
+		codes := make([]string, 0, len(out))
+		for _, s := range out {
+			codes = append(codes, s["shortCode"].(string))
+		}
+		return resp.StatusCode, codes, resp.Header.Get(strategy.HeaderNextCursor)
+	}
+
+	lastQuery := func() strategy.ListQuery {
+		Expect(store.listQueries).NotTo(BeEmpty())
+		return store.listQueries[len(store.listQueries)-1]
+	}
+
+	It("defaults to every visible strategy by short code", func() {
+		_, codes, next := list("")
+		Expect(codes).To(Equal([]string{"adm", "daa", "kel"}))
+		Expect(next).To(BeEmpty())
+		Expect(lastQuery()).To(Equal(strategy.ListQuery{SortField: "shortCode"}))
+	})
+
+	It("passes filters and sort to the store", func() {
+		status, _, _ := list("?category=momentum,tactical&installState=ready,failed&official=true&sort=-cagr")
+		Expect(status).To(Equal(200))
+		q := lastQuery()
+		Expect(q.Categories).To(Equal([]string{"momentum", "tactical"}))
+		Expect(q.InstallStates).To(Equal([]strategy.InstallState{strategy.InstallStateReady, strategy.InstallStateFailed}))
+		Expect(q.Official).To(HaveValue(BeTrue()))
+		Expect(q.SortField).To(Equal("cagr"))
+		Expect(q.Descending).To(BeTrue())
+	})
+
+	It("trims the extra row into a cursor that resumes after the page", func() {
+		_, codes, next := list("?sort=-cagr&limit=2")
+		Expect(codes).To(Equal([]string{"adm", "daa"}))
+		Expect(next).NotTo(BeEmpty())
+
+		status, _, _ := list("?sort=-cagr&limit=2&cursor=" + next)
+		Expect(status).To(Equal(200))
+		q := lastQuery()
+		Expect(q.Limit).To(Equal(2))
+		Expect(q.Cursor).NotTo(BeNil())
+		Expect(q.Cursor.Key).To(Equal("daa"))
+		Expect(q.Cursor.Value).To(HaveValue(Equal(0.09)))
+	})
+
+	It("omits the cursor on the last page", func() {
+		_, codes, next := list("?limit=3")
+		Expect(codes).To(HaveLen(3))
+		Expect(next).To(BeEmpty())
+	})
+
+	It("rejects bad parameters with 422", func() {
+		for _, q := range []string{
+			"?sort=favourite", "?limit=0", "?limit=500", "?installState=gone",
+			"?official=maybe", "?cursor=%%%", "?sort=cagr&cursor=" + cursorFor("-cagr"),
+		} {
+			status, _, _ := list(q)
+			Expect(status).To(Equal(422), q)
+		}
+	})
+})
+
+// cursorFor pages once under sort so the returned cursor can be replayed
+// against a different sort.
+func cursorFor(sort string) string {
+	store := newFakeStore()
+	store.rows["a"] = strategy.Strategy{ShortCode: "a"}
+	store.rows["b"] = strategy.Strategy{ShortCode: "b"}
+	app := fiber.New()
+	app.Get("/strategies", strategy.NewHandler(store).List)
+	resp, err := app.Test(httptest.NewRequest("GET", "/strategies?limit=1&sort="+sort, nil))
+	Expect(err).NotTo(HaveOccurred())
+	defer resp.Body.Close()
+	return resp.Header.Get(strategy.HeaderNextCursor)
+}
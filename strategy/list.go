@@ -0,0 +1,203 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/penny-vault/pv-api/keyset"
+)
+
+// maxListLimit caps the GET /strategies page size.
+const maxListLimit = 200
+
+// Errors returned by parseListQuery. The handler maps them to 422.
+var (
+	ErrInvalidSort         = errors.New("invalid sort")
+	ErrInvalidLimit        = errors.New("limit must be an integer between 1 and 200")
+	ErrInvalidCursor       = keyset.ErrInvalidCursor
+	ErrInvalidInstallState = errors.New("invalid installState")
+	ErrInvalidOfficial     = errors.New("official must be true or false")
+)
+
+// sortField is one sortable GET /strategies field: the column it orders by
+// and the same value read from a row, used to build the next cursor.
+type sortField struct {
+	column string
+	value  func(Strategy) *float64
+}
+
+// sortFields maps each sortable field (its JSON name in strategyView) to
+// its column. shortCode orders by the short code alone. Every stats column
+// has an index (migration 34).
+var sortFields = map[string]sortField{
+	"shortCode":          {},
+	"stars":              {"stars", func(s Strategy) *float64 { return intToFloat(s.Stars) }},
+	"cagr":               {"cagr", func(s Strategy) *float64 { return s.CAGR }},
+	"maxDrawDown":        {"max_drawdown", func(s Strategy) *float64 { return s.MaxDrawdown }},
+	"sharpe":             {"sharpe", func(s Strategy) *float64 { return s.Sharpe }},
+	"sortino":            {"sortino", func(s Strategy) *float64 { return s.Sortino }},
+	"ulcerIndex":         {"ulcer_index", func(s Strategy) *float64 { return s.UlcerIndex }},
+	"beta":               {"beta", func(s Strategy) *float64 { return s.Beta }},
+	"alpha":              {"alpha", func(s Strategy) *float64 { return s.Alpha }},
+	"stdDev":             {"std_dev", func(s Strategy) *float64 { return s.StdDev }},
+	"taxCostRatio":       {"tax_cost_ratio", func(s Strategy) *float64 { return s.TaxCostRatio }},
+	"oneYearReturn":      {"one_year_return", func(s Strategy) *float64 { return s.OneYearReturn }},
+	"ytdReturn":          {"ytd_return", func(s Strategy) *float64 { return s.YtdReturn }},
+	"benchmarkYtdReturn": {"benchmark_ytd_return", func(s Strategy) *float64 { return s.BenchmarkYtdReturn }},
+	"riskAdjustedScore":  {"risk_adjusted_score", func(s Strategy) *float64 { return s.RiskAdjustedScore }},
+}
+
+// installStateSQL is the predicate for each InstallState, mirroring
+// Strategy.DeriveInstallState.
+var installStateSQL = map[InstallState]string{
+	InstallStateReady:      "(installed_ver IS NOT NULL AND install_error IS NULL)",
+	InstallStateFailed:     "install_error IS NOT NULL",
+	InstallStateInstalling: "(installed_ver IS NULL AND install_error IS NULL AND last_attempted_ver IS NOT NULL)",
+	InstallStatePending:    "(installed_ver IS NULL AND install_error IS NULL AND last_attempted_ver IS NULL)",
+}
+
+// ListQuery is the parsed form of the GET /strategies query string. The
+// store turns it into SQL; hidden strategies are never listed.
+type ListQuery struct {
+	Categories    []string       // match any; empty means all
+	InstallStates []InstallState // match any; empty means all
+	Official      *bool          // nil means both
+	SortField     string         // a sortFields key; default shortCode
+	Descending    bool
+	Limit         int // 0 means no limit
+	Cursor        *keyset.Cursor
+}
+
+// parseListQuery reads category, installState, official, sort, limit and
+// cursor. Multi-valued filters are comma-separated. sort names a field,
+// prefixed with "-" for descending order.
+func parseListQuery(get func(key string) string) (ListQuery, error) {
+	q := ListQuery{SortField: "shortCode"}
+	for _, c := range strings.Split(get("category"), ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			q.Categories = append(q.Categories, c)
+		}
+	}
+	for _, st := range strings.Split(get("installState"), ",") {
+		switch st = strings.TrimSpace(st); InstallState(st) {
+		case "":
+		case InstallStatePending, InstallStateInstalling, InstallStateReady, InstallStateFailed:
+			q.InstallStates = append(q.InstallStates, InstallState(st))
+		default:
+			return ListQuery{}, fmt.Errorf("%w: %q", ErrInvalidInstallState, st)
+		}
+	}
+	if v := get("official"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return ListQuery{}, ErrInvalidOfficial
+		}
+		q.Official = &b
+	}
+	if v := get("sort"); v != "" {
+		field, desc := strings.CutPrefix(v, "-")
+		if _, ok := sortFields[field]; !ok {
+			return ListQuery{}, fmt.Errorf("%w: %q", ErrInvalidSort, field)
+		}
+		q.SortField, q.Descending = field, desc
+	}
+	if v := get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxListLimit {
+			return ListQuery{}, ErrInvalidLimit
+		}
+		q.Limit = n
+	}
+	if v := get("cursor"); v != "" {
+		cur, err := keyset.Decode(v, q.sortToken())
+		if err != nil {
+			return ListQuery{}, err
+		}
+		q.Cursor = &cur
+	}
+	return q, nil
+}
+
+// order returns the keyset ordering for the query's sort.
+func (q ListQuery) order() keyset.Order {
+	return keyset.Order{Column: sortFields[q.SortField].column, Key: "short_code", Descending: q.Descending}
+}
+
+// where returns the WHERE predicate for the query's filters and cursor,
+// appending its arguments to args.
+func (q ListQuery) where(args *keyset.Args) string {
+	conds := []string{"NOT hidden"}
+	if len(q.Categories) > 0 {
+		conds = append(conds, "categories && "+args.Add(q.Categories)+"::text[]")
+	}
+	if len(q.InstallStates) > 0 {
+		ors := make([]string, 0, len(q.InstallStates))
+		for _, st := range q.InstallStates {
+			ors = append(ors, installStateSQL[st])
+		}
+		conds = append(conds, "("+strings.Join(ors, " OR ")+")")
+	}
+	if q.Official != nil {
+		conds = append(conds, "is_official = "+args.Add(*q.Official))
+	}
+	if q.Cursor != nil {
+		conds = append(conds, q.order().After(*q.Cursor, args))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// fetchLimit is how many rows the store should read: one past the page so
+// page can tell whether another page follows. 0 means no limit.
+func (q ListQuery) fetchLimit() int {
+	if q.Limit == 0 {
+		return 0
+	}
+	return q.Limit + 1
+}
+
+// page trims rows read with fetchLimit to the page size and returns the
+// cursor for the next page, which is empty on the last page.
+func (q ListQuery) page(rows []Strategy) ([]Strategy, string) {
+	if q.Limit == 0 || len(rows) <= q.Limit {
+		return rows, ""
+	}
+	rows = rows[:q.Limit]
+	last := rows[len(rows)-1]
+	var v *float64
+	if f := sortFields[q.SortField].value; f != nil {
+		v = f(last)
+	}
+	return rows, keyset.Encode(keyset.Cursor{Sort: q.sortToken(), Value: v, Key: last.ShortCode})
+}
+
+func (q ListQuery) sortToken() string {
+	if q.Descending {
+		return "-" + q.SortField
+	}
+	return q.SortField
+}
+
+func intToFloat(p *int) *float64 {
+	if p == nil {
+		return nil
+	}
+	f := float64(*p)
+	return &f
+}
@@ -0,0 +1,132 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/jackc/pgx/v5/pgxpool"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/strategy"
+)
+
+// These specs run GET /strategies against Postgres so the filter, sort and
+// keyset SQL is exercised for real. Every row carries the __ls category and
+// every query filters on it, so other rows in the database never leak in.
+var _ = Describe("PoolStore.ListPage", Ordered, func() {
+	var (
+		pool *pgxpool.Pool
+		app  *fiber.App
+		ctx  = context.Background()
+	)
+
+	BeforeAll(func() {
+		dbURL := os.Getenv("PVAPI_SMOKE_DB_URL")
+		if dbURL == "" {
+			Skip("PVAPI_SMOKE_DB_URL not set; skipping strategy list smoke test")
+		}
+		var err error
+		pool, err = pgxpool.New(ctx, dbURL)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO strategies (short_code, repo_owner, repo_name, clone_url, is_official, owner_sub,
+				categories, installed_ver, last_attempted_ver, install_error, cagr, sharpe, risk_adjusted_score, hidden)
+			VALUES
+				('__ls_adm',  'smoke', 'adm',  'https://x/adm',  true,  NULL, '{__ls,__ls_momentum}',               'v1', 'v1', NULL,   0.12, 0.9, 1.1,  false),
+				('__ls_daa',  'smoke', 'daa',  'https://x/daa',  true,  NULL, '{__ls,__ls_tactical}',               'v1', 'v1', NULL,   0.09, 1.2, 1.4,  false),
+				('__ls_kel',  'smoke', 'kel',  'https://x/kel',  true,  NULL, '{__ls,__ls_momentum,__ls_tactical}', 'v1', 'v1', NULL,   0.15, 0.7, NULL, false),
+				('__ls_bad',  'smoke', 'bad',  'https://x/bad',  true,  NULL, '{__ls}',                             NULL, 'v1', 'boom', NULL, NULL, NULL, false),
+				('__ls_mine', 'smoke', 'mine', 'https://x/mine', false, 'smoke|user', '{__ls}',                     'v1', 'v1', NULL,   0.2,  NULL, NULL, false),
+				('__ls_gone', 'smoke', 'gone', 'https://x/gone', true,  NULL, '{__ls}',                             'v1', 'v1', NULL,   0.5,  NULL, NULL, true)
+		`)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() {
+			_, _ = pool.Exec(ctx, `DELETE FROM strategies WHERE short_code LIKE '\_\_ls\_%'`)
+			pool.Close()
+		})
+
+		app = fiber.New()
+		app.Get("/strategies", strategy.NewHandler(strategy.PoolStore{Pool: pool}).List)
+	})
+
+	list := func(query string) ([]string, string) {
+		resp, err := app.Test(httptest.NewRequest("GET", "/strategies?"+query, nil))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(200), string(body))
+		var out []map[string]any
+		Expect(sonic.Unmarshal(body, &out)).To(Succeed())
+		codes := make([]string, 0, len(out))
+		for _, s := range out {
+			codes = append(codes, s["shortCode"].(string)[len("__ls_"):])
+		}
+		return codes, resp.Header.Get(strategy.HeaderNextCursor)
+	}
+
+	It("omits hidden strategies and orders by short code", func() {
+		codes, _ := list("category=__ls")
+		Expect(codes).To(Equal([]string{"adm", "bad", "daa", "kel", "mine"}))
+		codes, _ = list("category=__ls&sort=-shortCode")
+		Expect(codes).To(Equal([]string{"mine", "kel", "daa", "bad", "adm"}))
+	})
+
+	It("filters by category, install state, and officialness", func() {
+		codes, _ := list("category=__ls_tactical")
+		Expect(codes).To(Equal([]string{"daa", "kel"}))
+		codes, _ = list("category=__ls&installState=failed")
+		Expect(codes).To(Equal([]string{"bad"}))
+		codes, _ = list("category=__ls&official=false")
+		Expect(codes).To(Equal([]string{"mine"}))
+		codes, _ = list("category=__ls_momentum&installState=ready&official=true")
+		Expect(codes).To(Equal([]string{"adm", "kel"}))
+	})
+
+	It("sorts on a stats column with missing values last", func() {
+		codes, _ := list("category=__ls&sort=-cagr")
+		Expect(codes).To(Equal([]string{"mine", "kel", "adm", "daa", "bad"}))
+		codes, _ = list("category=__ls&sort=sharpe")
+		Expect(codes).To(Equal([]string{"kel", "adm", "daa", "bad", "mine"}))
+		codes, _ = list("category=__ls&sort=-riskAdjustedScore")
+		Expect(codes).To(Equal([]string{"daa", "adm", "bad", "kel", "mine"}))
+	})
+
+	It("pages with a keyset cursor through NULL sort keys", func() {
+		for _, sort := range []string{"-cagr", "sharpe", "-shortCode"} {
+			var all []string
+			query := "category=__ls&limit=2&sort=" + sort
+			for range 5 {
+				codes, next := list(query)
+				all = append(all, codes...)
+				if next == "" {
+					break
+				}
+				query = "category=__ls&limit=2&sort=" + sort + "&cursor=" + next
+			}
+			full, _ := list("category=__ls&sort=" + sort)
+			Expect(all).To(Equal(full), sort)
+		}
+	})
+})
@@ -19,6 +19,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"time"
@@ -45,7 +46,8 @@ type StatRunner interface {
 // StatRunRequest carries the inputs needed to run a single-strategy backtest for
 // stats purposes. It is intentionally minimal to avoid coupling to backtest types.
 type StatRunRequest struct {
-	// Artifact is the absolute filesystem path to the strategy binary.
+	// Artifact is the absolute filesystem path to the strategy binary, or an
+	// image reference when the runner is container-based.
 	Artifact string
 	// Args are the CLI flags to pass to the strategy binary (benchmark, start date, etc.).
 	Args []string
@@ -235,6 +237,7 @@ func (r *StatsRefresher) RunOne(ctx context.Context, shortCode string) error {
 		OneYearReturn:      kpis.OneYearReturn,
 		YtdReturn:          kpis.YtdReturn,
 		BenchmarkYtdReturn: kpis.BenchmarkYtdReturn,
+		RiskAdjustedScore:  riskAdjustedScore(kpis),
 		AsOf:               time.Now().UTC(),
 	}
 	if err := r.store.UpdateStats(ctx, shortCode, result); err != nil {
@@ -257,6 +260,7 @@ func (r *StatsRefresher) RunOne(ctx context.Context, shortCode string) error {
 		{"one_year_return", result.OneYearReturn},
 		{"ytd_return", result.YtdReturn},
 		{"benchmark_ytd_return", result.BenchmarkYtdReturn},
+		{"risk_adjusted_score", result.RiskAdjustedScore},
 	} {
 		if f.val != nil {
 			ev = ev.Float64(f.name, *f.val)
@@ -266,6 +270,43 @@ func (r *StatsRefresher) RunOne(ctx context.Context, shortCode string) error {
 	return nil
 }
 
+// Risk-adjusted score weights and the range each component is clamped to
+// before weighting, so a near-zero drawdown or volatility cannot let one
+// ratio dominate the blend.
+const (
+	scoreWeightSortino = 0.4
+	scoreWeightSharpe  = 0.3
+	scoreWeightCalmar  = 0.3
+	scoreComponentMin  = -3.0
+	scoreComponentMax  = 5.0
+)
+
+// riskAdjustedScore blends Sortino, Sharpe, and Calmar (CAGR over the
+// magnitude of max drawdown) into the single number the strategy
+// leaderboard sorts on. Missing components are dropped and the remaining
+// weights rescaled; nil means none of them were available.
+func riskAdjustedScore(k StatKpis) *float64 {
+	var sum, weight float64
+	add := func(v *float64, w float64) {
+		if v == nil || math.IsNaN(*v) {
+			return
+		}
+		sum += w * math.Max(scoreComponentMin, math.Min(scoreComponentMax, *v))
+		weight += w
+	}
+	add(k.Sortino, scoreWeightSortino)
+	add(k.Sharpe, scoreWeightSharpe)
+	if k.CAGR != nil && k.MaxDrawdown != nil && *k.MaxDrawdown != 0 {
+		calmar := *k.CAGR / math.Abs(*k.MaxDrawdown)
+		add(&calmar, scoreWeightCalmar)
+	}
+	if weight == 0 {
+		return nil
+	}
+	score := sum / weight
+	return &score
+}
+
 // buildArgs builds CLI args for a stats-only backtest (no user parameters).
 func buildArgs(benchmark string, startDate *time.Time) []string {
 	var args []string
@@ -122,6 +122,31 @@ var _ = Describe("StatsRefresher.RunOne", func() {
 		Expect(store.statsErrors).To(BeEmpty())
 	})
 
+	It("blends Sortino, Sharpe, and Calmar into a risk-adjusted score", func() {
+		kpis := func(sortino, sharpe, cagr, maxDD *float64) strategy.SnapshotKpisFunc {
+			return func(context.Context, string) (strategy.StatKpis, error) {
+				return strategy.StatKpis{Sortino: sortino, Sharpe: sharpe, CAGR: cagr, MaxDrawdown: maxDD}, nil
+			}
+		}
+		f := func(v float64) *float64 { return &v }
+		score := func(fn strategy.SnapshotKpisFunc) *float64 {
+			store.statsUpdates = nil
+			refresh, err := strategy.NewStatsRefresher(store, runner, fn, strategy.StatsRefresherConfig{RefreshTime: "17:00"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refresh.RunOne(context.Background(), "fake")).To(Succeed())
+			return store.statsUpdates[0].result.RiskAdjustedScore
+		}
+
+		// 0.4*1.5 + 0.3*1.0 + 0.3*(0.10/0.20)
+		Expect(*score(kpis(f(1.5), f(1.0), f(0.10), f(-0.20)))).To(BeNumerically("~", 1.05, 1e-9))
+		// Missing Calmar: weights rescale over Sortino and Sharpe.
+		Expect(*score(kpis(f(1.5), f(1.0), nil, nil))).To(BeNumerically("~", (0.6+0.3)/0.7, 1e-9))
+		// A near-zero drawdown is clamped rather than dominating.
+		Expect(*score(kpis(nil, nil, f(0.10), f(-0.0001)))).To(BeNumerically("~", 5.0, 1e-9))
+		Expect(score(kpis(nil, nil, nil, nil))).To(BeNil())
+		Expect(store.rows["fake"].RiskAdjustedScore).To(BeNil())
+	})
+
 	It("calls MarkStatsError and returns error when runner fails", func() {
 		runner.err = errors.New("exec failed")
 		refresh := newTestRefresher(store, runner)
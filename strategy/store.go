@@ -30,6 +30,10 @@ func (p PoolStore) List(ctx context.Context) ([]Strategy, error) {
 	return List(ctx, p.Pool)
 }
 
+func (p PoolStore) ListPage(ctx context.Context, q ListQuery) ([]Strategy, error) {
+	return ListPage(ctx, p.Pool, q)
+}
+
 func (p PoolStore) Get(ctx context.Context, shortCode string) (Strategy, error) {
 	return Get(ctx, p.Pool, shortCode)
 }
@@ -74,5 +78,23 @@ func (p PoolStore) MarkStatsError(ctx context.Context, shortCode, errText string
 	return MarkStatsError(ctx, p.Pool, shortCode, errText)
 }
 
+func (p PoolStore) RecordWebhookDelivery(ctx context.Context, source, deliveryID string) (bool, error) {
+	return RecordWebhookDelivery(ctx, p.Pool, source, deliveryID)
+}
+
+func (p PoolStore) ForgetWebhookDelivery(ctx context.Context, source, deliveryID string) error {
+	return ForgetWebhookDelivery(ctx, p.Pool, source, deliveryID)
+}
+
+func (p PoolStore) ClearInstallError(ctx context.Context, shortCode string) error {
+	return ClearInstallError(ctx, p.Pool, shortCode)
+}
+
+func (p PoolStore) SetHidden(ctx context.Context, shortCode string, hidden bool) error {
+	return SetHidden(ctx, p.Pool, shortCode, hidden)
+}
+
 var _ StatsStore = PoolStore{}
+var _ AdminStore = PoolStore{}
+var _ DeliveryStore = PoolStore{}
 var _ Store = PoolStore{}
@@ -20,12 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/rs/zerolog/log"
 )
 
@@ -33,6 +35,9 @@ import (
 var (
 	ErrRunInterval = errors.New("Syncer.Run requires SyncerOptions.Interval > 0")
 	ErrNoTagsFound = errors.New("no tags found")
+	// ErrInstallInFlight is returned by SyncOne when the repository is
+	// already being installed by a Tick or an earlier SyncOne.
+	ErrInstallInFlight = errors.New("strategy install already in flight")
 )
 
 // StatsRunner is the interface the Syncer uses to trigger a post-install stats
@@ -100,6 +105,11 @@ func expectedArtifactKind(mode string) string {
 type Syncer struct {
 	store Store
 	opts  SyncerOptions
+
+	// inflight holds the clone URLs with an install running, so a webhook
+	// SyncOne and a periodic Tick never build the same repo concurrently.
+	mu       sync.Mutex
+	inflight map[string]struct{}
 }
 
 // NewSyncer constructs a Syncer. Install concurrency of 0 is replaced with 1.
@@ -107,7 +117,7 @@ func NewSyncer(store Store, opts SyncerOptions) *Syncer {
 	if opts.Concurrency < 1 {
 		opts.Concurrency = 1
 	}
-	return &Syncer{store: store, opts: opts}
+	return &Syncer{store: store, opts: opts, inflight: map[string]struct{}{}}
 }
 
 // Run ticks on the configured Interval until ctx is cancelled. The first
@@ -163,22 +173,8 @@ func (s *Syncer) Tick(ctx context.Context) error {
 			continue
 		}
 
-		if existing.LastAttemptedVer != nil && *existing.LastAttemptedVer == remote {
-			// ArtifactKind nil means the row pre-dates kind tracking; treat as
-			// matching so we don't re-install rows that were never stamped.
-			kindMatches := existing.ArtifactKind == nil || *existing.ArtifactKind == expectedArtifactKind(s.opts.RunnerMode)
-			if kindMatches {
-				log.Debug().
-					Str("repo", l.Owner+"/"+l.Name).
-					Str("version", remote).
-					Msg("strategy up to date; skipping")
-				continue
-			}
-			log.Info().
-				Str("short_code", existing.ShortCode).
-				Str("existing_kind", ptrStr(existing.ArtifactKind)).
-				Str("expected_kind", expectedArtifactKind(s.opts.RunnerMode)).
-				Msg("artifact kind mismatch; scheduling reinstall")
+		if s.upToDate(existing, l, remote) {
+			continue
 		}
 
 		log.Info().
@@ -200,6 +196,11 @@ func (s *Syncer) Tick(ctx context.Context) error {
 		go func() {
 			defer wg.Done()
 			defer func() { <-sem }()
+			if !s.claim(j.listing.CloneURL) {
+				log.Info().Str("clone_url", j.listing.CloneURL).Msg("install already in flight; skipping")
+				return
+			}
+			defer s.release(j.listing.CloneURL)
 			s.runInstall(ctx, j.listing, j.version, j.dest)
 		}()
 	}
@@ -207,6 +208,146 @@ func (s *Syncer) Tick(ctx context.Context) error {
 	return nil
 }
 
+// SyncOne reconciles a single already-registered strategy, identified by
+// clone URL, without waiting for the next Tick. version is the tag to
+// install; empty resolves the latest tag via ResolveVer. A version that is
+// not semver-greater than the installed one is a no-op, so pushing a
+// backport tag never downgrades a strategy. Returns ErrNotFound when no
+// strategy row has cloneURL and ErrInstallInFlight when the repo is already
+// being installed.
+func (s *Syncer) SyncOne(ctx context.Context, cloneURL, version string) error {
+	existing, err := s.store.GetByCloneURL(ctx, cloneURL)
+	if err != nil {
+		return err
+	}
+	if version != "" && !newerThanInstalled(existing, version) {
+		log.Debug().
+			Str("short_code", existing.ShortCode).
+			Str("installed", ptrStr(existing.InstalledVer)).
+			Str("version", version).
+			Msg("pushed version is not newer than installed; skipping")
+		return nil
+	}
+	return s.install(ctx, existing, version, false, "webhook")
+}
+
+// newerThanInstalled reports whether version is semver-greater than
+// existing's installed version. Nothing installed yet counts as newer; a
+// version that does not parse does not.
+func newerThanInstalled(existing Strategy, version string) bool {
+	if existing.InstalledVer == nil {
+		return true
+	}
+	next, err := semver.NewVersion(version)
+	if err != nil {
+		return false
+	}
+	cur, err := semver.NewVersion(*existing.InstalledVer)
+	if err != nil {
+		return true
+	}
+	return next.GreaterThan(cur)
+}
+
+// Reinstall clones and builds version of the strategy with shortCode even
+// when that version was already attempted. Empty version resolves the latest
+// tag via ResolveVer. A version that is already on disk is rebuilt into a
+// fresh directory so the live artifact keeps serving until MarkSuccess
+// swaps the row over. Returns ErrNotFound for an unknown short code and
+// ErrInstallInFlight when the repo is already being installed.
+func (s *Syncer) Reinstall(ctx context.Context, shortCode, version string) error {
+	existing, err := s.store.Get(ctx, shortCode)
+	if err != nil {
+		return err
+	}
+	return s.install(ctx, existing, version, true, "admin")
+}
+
+// install runs one install of existing at version. Unless force is set, a
+// version that upToDate reports as already attempted is a no-op. trigger
+// only labels the log line.
+func (s *Syncer) install(ctx context.Context, existing Strategy, version string, force bool, trigger string) error {
+	var err error
+	if version == "" {
+		version, err = s.opts.ResolveVer(ctx, existing.CloneURL)
+		if err != nil {
+			return fmt.Errorf("resolve remote version: %w", err)
+		}
+	}
+	l := Listing{
+		Name:        existing.RepoName,
+		Owner:       existing.RepoOwner,
+		Description: ptrStr(existing.Description),
+		Categories:  existing.Categories,
+		CloneURL:    existing.CloneURL,
+	}
+	if existing.Stars != nil {
+		l.Stars = *existing.Stars
+	}
+	if !force && s.upToDate(existing, l, version) {
+		return nil
+	}
+	if !s.claim(existing.CloneURL) {
+		return ErrInstallInFlight
+	}
+	defer s.release(existing.CloneURL)
+
+	dest := filepath.Join(s.opts.OfficialDir, l.Owner, l.Name, version)
+	if force {
+		if _, statErr := os.Stat(dest); statErr == nil {
+			dest = fmt.Sprintf("%s-%d", dest, time.Now().Unix())
+		}
+	}
+	log.Info().
+		Str("repo", l.Owner+"/"+l.Name).
+		Str("version", version).
+		Str("trigger", trigger).
+		Msg("strategy queued for install")
+	s.runInstall(ctx, l, version, dest)
+	return nil
+}
+
+// upToDate reports whether existing already records an install attempt of
+// remote with the artifact kind the current runner mode produces.
+func (s *Syncer) upToDate(existing Strategy, l Listing, remote string) bool {
+	if existing.LastAttemptedVer == nil || *existing.LastAttemptedVer != remote {
+		return false
+	}
+	// ArtifactKind nil means the row pre-dates kind tracking; treat as
+	// matching so we don't re-install rows that were never stamped.
+	kindMatches := existing.ArtifactKind == nil || *existing.ArtifactKind == expectedArtifactKind(s.opts.RunnerMode)
+	if kindMatches {
+		log.Debug().
+			Str("repo", l.Owner+"/"+l.Name).
+			Str("version", remote).
+			Msg("strategy up to date; skipping")
+		return true
+	}
+	log.Info().
+		Str("short_code", existing.ShortCode).
+		Str("existing_kind", ptrStr(existing.ArtifactKind)).
+		Str("expected_kind", expectedArtifactKind(s.opts.RunnerMode)).
+		Msg("artifact kind mismatch; scheduling reinstall")
+	return false
+}
+
+// claim marks cloneURL as being installed. Returns false if it already is.
+func (s *Syncer) claim(cloneURL string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, busy := s.inflight[cloneURL]; busy {
+		return false
+	}
+	s.inflight[cloneURL] = struct{}{}
+	return true
+}
+
+func (s *Syncer) release(cloneURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.inflight, cloneURL)
+}
+
 func (s *Syncer) runInstall(ctx context.Context, l Listing, version, dest string) {
 	installer := s.opts.Installer
 	kind := artifactKindBinary
@@ -18,6 +18,9 @@ package strategy_test
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -36,6 +39,7 @@ type fakeStore struct {
 	failures     []failureCall
 	statsUpdates []statsUpdateCall
 	statsErrors  []statsErrorCall
+	listQueries  []strategy.ListQuery
 }
 
 type successCall struct {
@@ -61,6 +65,20 @@ func (f *fakeStore) List(_ context.Context) ([]strategy.Strategy, error) {
 	return out, nil
 }
 
+// ListPage records q and returns every visible row by short code; the
+// filtering, ordering and keyset SQL is covered by the Postgres smoke test.
+func (f *fakeStore) ListPage(_ context.Context, q strategy.ListQuery) ([]strategy.Strategy, error) {
+	f.listQueries = append(f.listQueries, q)
+	out := make([]strategy.Strategy, 0, len(f.rows))
+	for _, v := range f.rows {
+		if !v.Hidden {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ShortCode < out[j].ShortCode })
+	return out, nil
+}
+
 func (f *fakeStore) Get(_ context.Context, sc string) (strategy.Strategy, error) {
 	v, ok := f.rows[sc]
 	if !ok {
@@ -159,6 +177,7 @@ func (f *fakeStore) UpdateStats(_ context.Context, shortCode string, r strategy.
 	row.OneYearReturn = r.OneYearReturn
 	row.YtdReturn = r.YtdReturn
 	row.BenchmarkYtdReturn = r.BenchmarkYtdReturn
+	row.RiskAdjustedScore = r.RiskAdjustedScore
 	now := r.AsOf
 	row.StatsAsOf = &now
 	f.rows[shortCode] = row
@@ -173,6 +192,27 @@ func (f *fakeStore) MarkStatsError(_ context.Context, shortCode, errText string)
 	return nil
 }
 
+func (f *fakeStore) ClearInstallError(_ context.Context, shortCode string) error {
+	row, ok := f.rows[shortCode]
+	if !ok {
+		return strategy.ErrNotFound
+	}
+	row.InstallError = nil
+	row.LastAttemptedVer = row.InstalledVer
+	f.rows[shortCode] = row
+	return nil
+}
+
+func (f *fakeStore) SetHidden(_ context.Context, shortCode string, hidden bool) error {
+	row, ok := f.rows[shortCode]
+	if !ok {
+		return strategy.ErrNotFound
+	}
+	row.Hidden = hidden
+	f.rows[shortCode] = row
+	return nil
+}
+
 type fakeStatsRefresher struct {
 	mu          sync.Mutex
 	runOneCalls []string
@@ -433,3 +473,171 @@ var _ = Describe("Syncer.Tick", func() {
 		Expect(store.successes[0].ref).To(Equal("pvapi-strategy/penny-vault/adm:v1.0.0"))
 	})
 })
+
+var _ = Describe("Syncer.SyncOne", func() {
+	installer := func(calls *[]strategy.InstallRequest) strategy.InstallerFunc {
+		return func(_ context.Context, req strategy.InstallRequest) (*strategy.InstallResult, error) {
+			*calls = append(*calls, req)
+			return &strategy.InstallResult{
+				ArtifactRef:  req.DestDir + "/fake.bin",
+				DescribeJSON: []byte(`{"shortcode":"fake"}`),
+				ShortCode:    "fake",
+			}, nil
+		}
+	}
+	seed := func(store *fakeStore, lastAttempted string) {
+		store.rows["fake"] = strategy.Strategy{
+			ShortCode:        "fake",
+			RepoOwner:        "penny-vault",
+			RepoName:         "fake",
+			CloneURL:         "https://github.com/penny-vault/fake.git",
+			IsOfficial:       true,
+			LastAttemptedVer: &lastAttempted,
+		}
+	}
+
+	It("installs the pushed version of a registered strategy without discovery", func() {
+		store := newFakeStore()
+		seed(store, "v1.0.0")
+		var calls []strategy.InstallRequest
+		s := strategy.NewSyncer(store, strategy.SyncerOptions{
+			Discovery: func(context.Context) ([]strategy.Listing, error) {
+				Fail("discovery should not run")
+				return nil, nil
+			},
+			ResolveVer: func(context.Context, string) (string, error) {
+				Fail("resolve should not run when a version is given")
+				return "", nil
+			},
+			Installer:   installer(&calls),
+			OfficialDir: "/srv/official",
+		})
+
+		Expect(s.SyncOne(context.Background(), "https://github.com/penny-vault/fake.git", "v1.1.0")).To(Succeed())
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].Version).To(Equal("v1.1.0"))
+		Expect(calls[0].DestDir).To(Equal("/srv/official/penny-vault/fake/v1.1.0"))
+		Expect(store.successes).To(HaveLen(1))
+	})
+
+	It("is a no-op when the version was already attempted", func() {
+		store := newFakeStore()
+		seed(store, "v1.1.0")
+		var calls []strategy.InstallRequest
+		s := strategy.NewSyncer(store, strategy.SyncerOptions{Installer: installer(&calls)})
+
+		Expect(s.SyncOne(context.Background(), "https://github.com/penny-vault/fake.git", "v1.1.0")).To(Succeed())
+		Expect(calls).To(BeEmpty())
+	})
+
+	It("does not reinstall an older tag pushed after a newer one", func() {
+		store := newFakeStore()
+		seed(store, "v1.3.0")
+		installed := "v1.3.0"
+		row := store.rows["fake"]
+		row.InstalledVer = &installed
+		store.rows["fake"] = row
+		var calls []strategy.InstallRequest
+		s := strategy.NewSyncer(store, strategy.SyncerOptions{Installer: installer(&calls)})
+
+		Expect(s.SyncOne(context.Background(), "https://github.com/penny-vault/fake.git", "v1.2.9")).To(Succeed())
+		Expect(s.SyncOne(context.Background(), "https://github.com/penny-vault/fake.git", "v1.3.0")).To(Succeed())
+		Expect(calls).To(BeEmpty())
+
+		Expect(s.SyncOne(context.Background(), "https://github.com/penny-vault/fake.git", "v1.3.1")).To(Succeed())
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].Version).To(Equal("v1.3.1"))
+	})
+
+	It("returns ErrNotFound for an unregistered clone URL", func() {
+		s := strategy.NewSyncer(newFakeStore(), strategy.SyncerOptions{})
+		err := s.SyncOne(context.Background(), "https://github.com/someone/else.git", "v1.0.0")
+		Expect(errors.Is(err, strategy.ErrNotFound)).To(BeTrue())
+	})
+
+	It("refuses to start a second install of the same repo", func() {
+		store := newFakeStore()
+		seed(store, "v1.0.0")
+		started := make(chan struct{})
+		release := make(chan struct{})
+		s := strategy.NewSyncer(store, strategy.SyncerOptions{
+			Installer: func(context.Context, strategy.InstallRequest) (*strategy.InstallResult, error) {
+				close(started)
+				<-release
+				return nil, errors.New("stopped")
+			},
+		})
+
+		done := make(chan error, 1)
+		go func() { done <- s.SyncOne(context.Background(), "https://github.com/penny-vault/fake.git", "v1.1.0") }()
+		Eventually(started).Should(BeClosed())
+
+		err := s.SyncOne(context.Background(), "https://github.com/penny-vault/fake.git", "v1.1.0")
+		Expect(errors.Is(err, strategy.ErrInstallInFlight)).To(BeTrue())
+
+		close(release)
+		Eventually(done).Should(Receive(BeNil()))
+	})
+})
+
+var _ = Describe("Syncer.Reinstall", func() {
+	var (
+		store *fakeStore
+		calls []strategy.InstallRequest
+		dir   string
+	)
+
+	BeforeEach(func() {
+		store = newFakeStore()
+		ver := "v1.1.0"
+		store.rows["fake"] = strategy.Strategy{
+			ShortCode:        "fake",
+			RepoOwner:        "penny-vault",
+			RepoName:         "fake",
+			CloneURL:         "https://github.com/penny-vault/fake.git",
+			IsOfficial:       true,
+			InstalledVer:     &ver,
+			LastAttemptedVer: &ver,
+		}
+		calls = nil
+		dir = GinkgoT().TempDir()
+	})
+
+	newSyncer := func() *strategy.Syncer {
+		return strategy.NewSyncer(store, strategy.SyncerOptions{
+			ResolveVer: func(context.Context, string) (string, error) { return "v1.2.0", nil },
+			Installer: func(_ context.Context, req strategy.InstallRequest) (*strategy.InstallResult, error) {
+				calls = append(calls, req)
+				return &strategy.InstallResult{ArtifactRef: req.DestDir + "/fake.bin", ShortCode: "fake"}, nil
+			},
+			OfficialDir: dir,
+		})
+	}
+
+	It("rebuilds a version that was already attempted", func() {
+		Expect(newSyncer().Reinstall(context.Background(), "fake", "v1.1.0")).To(Succeed())
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].DestDir).To(Equal(filepath.Join(dir, "penny-vault", "fake", "v1.1.0")))
+		Expect(store.successes).To(HaveLen(1))
+	})
+
+	It("builds into a fresh directory when the version is already on disk", func() {
+		live := filepath.Join(dir, "penny-vault", "fake", "v1.1.0")
+		Expect(os.MkdirAll(live, 0o755)).To(Succeed())
+
+		Expect(newSyncer().Reinstall(context.Background(), "fake", "v1.1.0")).To(Succeed())
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].DestDir).To(HavePrefix(live + "-"))
+	})
+
+	It("resolves the latest tag when no version is given", func() {
+		Expect(newSyncer().Reinstall(context.Background(), "fake", "")).To(Succeed())
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].Version).To(Equal("v1.2.0"))
+	})
+
+	It("returns ErrNotFound for an unknown short code", func() {
+		err := newSyncer().Reinstall(context.Background(), "nope", "v1.0.0")
+		Expect(errors.Is(err, strategy.ErrNotFound)).To(BeTrue())
+	})
+})
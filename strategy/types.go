@@ -65,9 +65,13 @@ type Strategy struct {
 	OneYearReturn      *float64
 	YtdReturn          *float64
 	BenchmarkYtdReturn *float64
+	RiskAdjustedScore  *float64
 	StatsAsOf          *time.Time
 	// StatsError is write-only at the DB layer; not included in strategyColumns/scan.
-	StatsError   *string
+	StatsError *string
+	// Hidden strategies are omitted from the catalog listing but stay
+	// addressable by short code.
+	Hidden       bool
 	DiscoveredAt time.Time
 	UpdatedAt    time.Time
 }
@@ -89,6 +93,7 @@ type StatsResult struct {
 	OneYearReturn      *float64
 	YtdReturn          *float64
 	BenchmarkYtdReturn *float64
+	RiskAdjustedScore  *float64 // derived by the StatsRefresher; see riskAdjustedScore
 	AsOf               time.Time
 }
 
@@ -132,11 +137,44 @@ type Describe struct {
 }
 
 // DescribeParameter is one declared parameter in the describe output.
+// Everything after Description is optional constraint metadata emitted by
+// newer pvbt releases; older describes leave it zero and are validated on
+// name alone.
 type DescribeParameter struct {
 	Name        string      `json:"name"`
 	Type        string      `json:"type"`
 	Default     interface{} `json:"default,omitempty"`
 	Description string      `json:"description,omitempty"`
+
+	// Enum lists the allowed values. Empty means any value of Type.
+	Enum []interface{} `json:"enum,omitempty"`
+	// Min and Max bound numeric parameters (inclusive).
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Step requires numeric values to sit on the grid Min + k*Step
+	// (or k*Step when Min is unset).
+	Step *float64 `json:"step,omitempty"`
+	// Required is nil on describes that predate the field; such parameters
+	// are treated as required. An explicit false lets callers omit the
+	// parameter and leave the strategy to apply its own default.
+	Required *bool `json:"required,omitempty"`
+	// Universe constrains ticker-universe parameters.
+	Universe *UniverseConstraint `json:"universe,omitempty"`
+	// Group is a UI hint for grouping related parameters. Not validated.
+	Group string `json:"group,omitempty"`
+}
+
+// IsRequired reports whether callers must supply the parameter.
+func (p DescribeParameter) IsRequired() bool {
+	return p.Required == nil || *p.Required
+}
+
+// UniverseConstraint limits the tickers a universe parameter accepts.
+// Zero MinSize/MaxSize mean unbounded; empty Allowed means any ticker.
+type UniverseConstraint struct {
+	MinSize int      `json:"minSize,omitempty"`
+	MaxSize int      `json:"maxSize,omitempty"`
+	Allowed []string `json:"allowed,omitempty"`
 }
 
 // DescribePreset is one named parameter set from the describe output.
@@ -0,0 +1,325 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+)
+
+// Validation stages reported in ValidationIssue.Stage, in pipeline order.
+const (
+	StageBuild    = "build"
+	StageDescribe = "describe"
+	StageBacktest = "backtest"
+	StageSnapshot = "snapshot"
+)
+
+// defaultSmokeWindow is how much history the smoke backtest covers.
+const defaultSmokeWindow = 90 * 24 * time.Hour
+
+// defaultValidateConcurrency caps how many validations run at once when
+// ValidateHandler.MaxConcurrent is zero. Each one builds an image and runs
+// two containers, so the cap is deliberately small.
+const defaultValidateConcurrency = 2
+
+// validateRetryAfter is the Retry-After hint sent when every slot is busy.
+const validateRetryAfter = 30 * time.Second
+
+var (
+	ErrInvalidRef = errors.New("ref must be a branch or tag name")
+
+	refRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+)
+
+// ValidationIssue is one problem found while validating a strategy repo.
+// Table, Column, and Key are only set for snapshot-stage issues.
+type ValidationIssue struct {
+	Stage   string `json:"stage"`
+	Message string `json:"message"`
+	Table   string `json:"table,omitempty"`
+	Column  string `json:"column,omitempty"`
+	Key     string `json:"key,omitempty"`
+}
+
+// ValidationReport is the body returned by POST /strategies/validate.
+// Stages that could not run because an earlier stage failed are listed in
+// Skipped so authors can tell "passed" apart from "never checked".
+type ValidationReport struct {
+	CloneURL string            `json:"cloneUrl"`
+	Ref      string            `json:"ref,omitempty"`
+	Valid    bool              `json:"valid"`
+	Describe *Describe         `json:"describe,omitempty"`
+	Issues   []ValidationIssue `json:"issues"`
+	Skipped  []string          `json:"skipped"`
+}
+
+// SnapshotCheckFunc inspects the snapshot at path and returns every schema
+// problem found. Injected to break the import cycle between strategy and
+// snapshot; production wires an adapter over snapshot.CheckSchema.
+type SnapshotCheckFunc func(ctx context.Context, path string) ([]ValidationIssue, error)
+
+// DescribeFunc runs an artifact's describe command and returns its JSON.
+// RunDescribe is the host implementation; DescribeImage runs it in a
+// container.
+type DescribeFunc func(ctx context.Context, artifact string) ([]byte, error)
+
+// ValidateSandbox is the isolated toolchain the validate pipeline runs an
+// untrusted repo in. Build produces an artifact, Describe and Runner execute
+// it, and WorkDir is where smoke snapshots are written -- it must be visible
+// to Runner (for the docker runner, inside the bind-mounted snapshots dir).
+type ValidateSandbox struct {
+	Build    BuilderFunc
+	Describe DescribeFunc
+	Runner   StatRunner
+	WorkDir  string // "" = EphemeralOpts.Dir
+}
+
+// ValidateHandler serves POST /strategies/validate: build the repo at the
+// requested ref, run describe, run a short smoke backtest, and check the
+// resulting snapshot. The repo is arbitrary caller-supplied code, so every
+// step that executes it goes through Sandbox; with no sandbox configured
+// (runner.mode=host) the endpoint answers 503 rather than falling back to
+// running it on the API host.
+type ValidateHandler struct {
+	URLValidator  URLValidatorFunc
+	Sandbox       *ValidateSandbox
+	CheckSnapshot SnapshotCheckFunc
+	EphemeralOpts EphemeralOptions // only Dir and Timeout are read; CloneURL/Ver are set per request
+	SmokeWindow   time.Duration    // 0 = 90 days
+	MaxConcurrent int              // 0 = defaultValidateConcurrency
+
+	slotsOnce sync.Once
+	slots     chan struct{}
+}
+
+type validateRequest struct {
+	CloneURL string `json:"cloneUrl"`
+	Ref      string `json:"ref"`
+}
+
+// Validate implements POST /strategies/validate. Request-shape problems
+// (bad JSON, disallowed clone URL, malformed ref) are 400s; a missing
+// sandbox or a full set of validation slots is a 503; everything the
+// pipeline finds is reported in a 200 ValidationReport.
+func (h *ValidateHandler) Validate(c fiber.Ctx) error {
+	if h.Sandbox == nil {
+		return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable",
+			"strategy validation requires the docker runner (runner.mode=docker)")
+	}
+	var req validateRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid JSON body: "+err.Error())
+	}
+	if err := h.URLValidator(req.CloneURL); err != nil {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", err.Error())
+	}
+	if req.Ref != "" && !refRe.MatchString(req.Ref) {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", ErrInvalidRef.Error())
+	}
+
+	if !h.acquire() {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(validateRetryAfter.Seconds())))
+		return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable",
+			"too many strategy validations in progress; retry later")
+	}
+	defer h.release()
+
+	report := h.run(c.Context(), req)
+
+	body, err := sonic.Marshal(report)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/json")
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// acquire takes a validation slot without blocking and reports whether one
+// was free.
+func (h *ValidateHandler) acquire() bool {
+	h.slotsOnce.Do(func() {
+		n := h.MaxConcurrent
+		if n <= 0 {
+			n = defaultValidateConcurrency
+		}
+		h.slots = make(chan struct{}, n)
+	})
+	select {
+	case h.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (h *ValidateHandler) release() { <-h.slots }
+
+// stepTimeout bounds each sandboxed step that runs the strategy.
+func (h *ValidateHandler) stepTimeout() time.Duration {
+	if h.EphemeralOpts.Timeout > 0 {
+		return h.EphemeralOpts.Timeout
+	}
+	return defaultEphemeralTimeout
+}
+
+// run executes the validation pipeline. Each stage appends its issues to
+// the report; a stage whose output later stages depend on stops the
+// pipeline and marks the remaining stages skipped.
+func (h *ValidateHandler) run(ctx context.Context, req validateRequest) *ValidationReport {
+	report := &ValidationReport{
+		CloneURL: req.CloneURL,
+		Ref:      req.Ref,
+		Issues:   []ValidationIssue{},
+		Skipped:  []string{},
+	}
+	fail := func(stage, msg string, skipped ...string) *ValidationReport {
+		report.Issues = append(report.Issues, ValidationIssue{Stage: stage, Message: msg})
+		report.Skipped = append(report.Skipped, skipped...)
+		return report
+	}
+
+	opts := h.EphemeralOpts
+	opts.CloneURL = req.CloneURL
+	opts.Ver = req.Ref
+
+	sb := h.Sandbox
+	artifact, cleanup, err := sb.Build(ctx, opts)
+	if err != nil {
+		return fail(StageBuild, err.Error(), StageDescribe, StageBacktest, StageSnapshot)
+	}
+	defer cleanup()
+
+	describeCtx, cancelDescribe := context.WithTimeout(ctx, h.stepTimeout())
+	raw, err := sb.Describe(describeCtx, artifact)
+	cancelDescribe()
+	if err != nil {
+		return fail(StageDescribe, err.Error(), StageBacktest, StageSnapshot)
+	}
+	var d Describe
+	if err := json.Unmarshal(raw, &d); err != nil {
+		return fail(StageDescribe, "describe JSON malformed: "+err.Error(), StageBacktest, StageSnapshot)
+	}
+	report.Describe = &d
+	for _, msg := range checkDescribe(d) {
+		report.Issues = append(report.Issues, ValidationIssue{Stage: StageDescribe, Message: msg})
+	}
+
+	workDir := sb.WorkDir
+	if workDir == "" {
+		workDir = opts.Dir
+	}
+	tmpDir, err := os.MkdirTemp(workDir, "validate-*")
+	if err != nil {
+		return fail(StageBacktest, fmt.Sprintf("creating temp dir for smoke snapshot: %v", err), StageSnapshot)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	outPath := filepath.Join(tmpDir, "smoke.sqlite")
+
+	window := h.SmokeWindow
+	if window == 0 {
+		window = defaultSmokeWindow
+	}
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	start := end.Add(-window)
+	args := append(buildArgs(d.Benchmark, &start), "--end", end.Format("2006-01-02"))
+
+	runCtx, cancelRun := context.WithTimeout(ctx, h.stepTimeout())
+	err = sb.Runner.Run(runCtx, StatRunRequest{Artifact: artifact, Args: args, OutPath: outPath})
+	cancelRun()
+	if err != nil {
+		return fail(StageBacktest, err.Error(), StageSnapshot)
+	}
+	if _, err := os.Stat(outPath); err != nil {
+		return fail(StageBacktest, "smoke backtest exited cleanly but wrote no snapshot", StageSnapshot)
+	}
+
+	issues, err := h.CheckSnapshot(ctx, outPath)
+	if err != nil {
+		return fail(StageSnapshot, err.Error())
+	}
+	for _, is := range issues {
+		is.Stage = StageSnapshot
+		report.Issues = append(report.Issues, is)
+	}
+
+	report.Valid = len(report.Issues) == 0
+	return report
+}
+
+// checkDescribe reports describe-output problems that would break
+// registration or portfolio creation: missing identity fields, unnamed or
+// untyped parameters, and presets that reference undeclared parameters.
+func checkDescribe(d Describe) []string {
+	var out []string
+	if d.ShortCode == "" {
+		out = append(out, "shortcode is empty")
+	}
+	if d.Name == "" {
+		out = append(out, "name is empty")
+	}
+	if d.Schedule == "" {
+		out = append(out, "schedule is empty")
+	}
+	declared := make(map[string]bool, len(d.Parameters))
+	for i, p := range d.Parameters {
+		if p.Name == "" {
+			out = append(out, fmt.Sprintf("parameter %d has no name", i))
+			continue
+		}
+		if declared[p.Name] {
+			out = append(out, fmt.Sprintf("parameter %q is declared more than once", p.Name))
+		}
+		declared[p.Name] = true
+		if p.Type == "" {
+			out = append(out, fmt.Sprintf("parameter %q has no type", p.Name))
+		}
+		if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+			out = append(out, fmt.Sprintf("parameter %q has min greater than max", p.Name))
+		}
+		if p.Step != nil && *p.Step <= 0 {
+			out = append(out, fmt.Sprintf("parameter %q has a non-positive step", p.Name))
+		}
+	}
+	for _, preset := range d.Presets {
+		if preset.Name == "" {
+			out = append(out, "preset has no name")
+		}
+		keys := make([]string, 0, len(preset.Parameters))
+		for k := range preset.Parameters {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if !declared[k] {
+				out = append(out, fmt.Sprintf("preset %q sets undeclared parameter %q", preset.Name, k))
+			}
+		}
+	}
+	return out
+}
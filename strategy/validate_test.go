@@ -0,0 +1,258 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/snapshot"
+	"github.com/penny-vault/pv-api/strategy"
+)
+
+// fixtureStatRunner writes snapshot.BuildTestSnapshot to req.OutPath, then
+// applies mutate (if any) so tests can simulate a non-conforming strategy.
+type fixtureStatRunner struct {
+	err    error
+	mutate string
+	got    strategy.StatRunRequest
+}
+
+func (f *fixtureStatRunner) Run(_ context.Context, req strategy.StatRunRequest) error {
+	f.got = req
+	if f.err != nil {
+		return f.err
+	}
+	if err := snapshot.BuildTestSnapshot(req.OutPath); err != nil {
+		return err
+	}
+	if f.mutate == "" {
+		return nil
+	}
+	db, err := sql.Open("sqlite", "file:"+req.OutPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	_, err = db.Exec(f.mutate)
+	return err
+}
+
+// statRunnerFunc adapts a function to strategy.StatRunner.
+type statRunnerFunc func(ctx context.Context, req strategy.StatRunRequest) error
+
+func (f statRunnerFunc) Run(ctx context.Context, req strategy.StatRunRequest) error {
+	return f(ctx, req)
+}
+
+func checkSnapshot(ctx context.Context, path string) ([]strategy.ValidationIssue, error) {
+	issues, err := snapshot.CheckSchema(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]strategy.ValidationIssue, 0, len(issues))
+	for _, is := range issues {
+		out = append(out, strategy.ValidationIssue{Table: is.Table, Column: is.Column, Key: is.Key, Message: is.Message})
+	}
+	return out, nil
+}
+
+var _ = Describe("ValidateHandler", func() {
+	var (
+		runner  *fixtureStatRunner
+		builder strategy.BuilderFunc
+		gotVer  string
+	)
+
+	BeforeEach(func() {
+		runner = &fixtureStatRunner{}
+		gotVer = ""
+		builder = func(_ context.Context, opts strategy.EphemeralOptions) (string, func(), error) {
+			gotVer = opts.Ver
+			bin := buildFakeStrategy()
+			return bin, func() { removeAll(filepath.Dir(bin)) }, nil
+		}
+	})
+
+	post := func(body string) (int, strategy.ValidationReport) {
+		app := fiber.New()
+		app.Post("/strategies/validate", (&strategy.ValidateHandler{
+			URLValidator: strategy.ValidateCloneURL,
+			Sandbox: &strategy.ValidateSandbox{
+				Build:    builder,
+				Describe: strategy.RunDescribe,
+				Runner:   runner,
+			},
+			CheckSnapshot: checkSnapshot,
+			EphemeralOpts: strategy.EphemeralOptions{Timeout: 10 * time.Second},
+		}).Validate)
+
+		req := httptest.NewRequest("POST", "/strategies/validate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, fiber.TestConfig{Timeout: 30 * time.Second})
+		Expect(err).NotTo(HaveOccurred())
+		raw, _ := io.ReadAll(resp.Body)
+		var report strategy.ValidationReport
+		if resp.StatusCode == 200 {
+			Expect(sonic.Unmarshal(raw, &report)).To(Succeed(), string(raw))
+		}
+		return resp.StatusCode, report
+	}
+
+	It("reports a conforming strategy as valid", func() {
+		status, report := post(`{"cloneUrl":"https://github.com/foo/bar","ref":"v1.2.0"}`)
+		Expect(status).To(Equal(200))
+		Expect(report.Valid).To(BeTrue())
+		Expect(report.Issues).To(BeEmpty())
+		Expect(report.Skipped).To(BeEmpty())
+		Expect(report.Describe).NotTo(BeNil())
+		Expect(report.Describe.ShortCode).To(Equal("fake"))
+		Expect(gotVer).To(Equal("v1.2.0"))
+		Expect(runner.got.Args).To(ContainElements("--start", "--end", "--benchmark", "SPY"))
+	})
+
+	It("reports a missing positions_daily table", func() {
+		runner.mutate = `DROP TABLE positions_daily`
+		status, report := post(`{"cloneUrl":"https://github.com/foo/bar"}`)
+		Expect(status).To(Equal(200))
+		Expect(report.Valid).To(BeFalse())
+		Expect(report.Issues).To(ConsistOf(And(
+			HaveField("Stage", strategy.StageSnapshot),
+			HaveField("Table", "positions_daily"),
+		)))
+	})
+
+	It("skips the backtest and snapshot stages when the build fails", func() {
+		builder = func(context.Context, strategy.EphemeralOptions) (string, func(), error) {
+			return "", nil, errors.New("ephemeral: go build: exit status 1")
+		}
+		status, report := post(`{"cloneUrl":"https://github.com/foo/bar"}`)
+		Expect(status).To(Equal(200))
+		Expect(report.Valid).To(BeFalse())
+		Expect(report.Issues).To(ConsistOf(HaveField("Stage", strategy.StageBuild)))
+		Expect(report.Skipped).To(Equal([]string{strategy.StageDescribe, strategy.StageBacktest, strategy.StageSnapshot}))
+	})
+
+	It("reports a failed smoke backtest", func() {
+		runner.err = errors.New("runner failed: exit status 2")
+		status, report := post(`{"cloneUrl":"https://github.com/foo/bar"}`)
+		Expect(status).To(Equal(200))
+		Expect(report.Valid).To(BeFalse())
+		Expect(report.Issues).To(ConsistOf(HaveField("Stage", strategy.StageBacktest)))
+		Expect(report.Skipped).To(Equal([]string{strategy.StageSnapshot}))
+	})
+
+	It("bounds the smoke backtest by the ephemeral timeout", func() {
+		app := fiber.New()
+		app.Post("/strategies/validate", (&strategy.ValidateHandler{
+			URLValidator: strategy.ValidateCloneURL,
+			Sandbox: &strategy.ValidateSandbox{
+				Build:    builder,
+				Describe: strategy.RunDescribe,
+				Runner: statRunnerFunc(func(ctx context.Context, _ strategy.StatRunRequest) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}),
+			},
+			CheckSnapshot: checkSnapshot,
+			EphemeralOpts: strategy.EphemeralOptions{Timeout: 200 * time.Millisecond},
+		}).Validate)
+		req := httptest.NewRequest("POST", "/strategies/validate", strings.NewReader(`{"cloneUrl":"https://github.com/foo/bar"}`))
+		resp, err := app.Test(req, fiber.TestConfig{Timeout: 30 * time.Second})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(200))
+		var report strategy.ValidationReport
+		raw, _ := io.ReadAll(resp.Body)
+		Expect(sonic.Unmarshal(raw, &report)).To(Succeed())
+		Expect(report.Issues).To(ConsistOf(And(
+			HaveField("Stage", strategy.StageBacktest),
+			HaveField("Message", ContainSubstring("deadline exceeded")),
+		)))
+	})
+
+	It("503s when no sandbox is configured", func() {
+		app := fiber.New()
+		app.Post("/strategies/validate", (&strategy.ValidateHandler{
+			URLValidator:  strategy.ValidateCloneURL,
+			CheckSnapshot: checkSnapshot,
+		}).Validate)
+		req := httptest.NewRequest("POST", "/strategies/validate", strings.NewReader(`{"cloneUrl":"https://github.com/foo/bar"}`))
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(503))
+	})
+
+	It("503s with Retry-After when every validation slot is busy", func() {
+		started := make(chan struct{})
+		unblock := make(chan struct{})
+		h := &strategy.ValidateHandler{
+			URLValidator: strategy.ValidateCloneURL,
+			Sandbox: &strategy.ValidateSandbox{
+				Build: func(context.Context, strategy.EphemeralOptions) (string, func(), error) {
+					close(started)
+					<-unblock
+					return "", nil, errors.New("build aborted")
+				},
+				Describe: strategy.RunDescribe,
+				Runner:   runner,
+			},
+			CheckSnapshot: checkSnapshot,
+			MaxConcurrent: 1,
+		}
+		app := fiber.New()
+		app.Post("/strategies/validate", h.Validate)
+
+		done := make(chan int)
+		go func() {
+			defer GinkgoRecover()
+			req := httptest.NewRequest("POST", "/strategies/validate", strings.NewReader(`{"cloneUrl":"https://github.com/foo/bar"}`))
+			resp, err := app.Test(req, fiber.TestConfig{Timeout: 30 * time.Second})
+			Expect(err).NotTo(HaveOccurred())
+			done <- resp.StatusCode
+		}()
+		Eventually(started).Should(BeClosed())
+
+		req := httptest.NewRequest("POST", "/strategies/validate", strings.NewReader(`{"cloneUrl":"https://github.com/foo/bar"}`))
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(503))
+		Expect(resp.Header.Get("Retry-After")).NotTo(BeEmpty())
+
+		close(unblock)
+		Eventually(done).Should(Receive(Equal(200)))
+	})
+
+	It("400s on a disallowed clone URL", func() {
+		status, _ := post(`{"cloneUrl":"ssh://git@github.com/foo/bar"}`)
+		Expect(status).To(Equal(400))
+	})
+
+	It("400s on a ref that could be read as a git option", func() {
+		status, _ := post(`{"cloneUrl":"https://github.com/foo/bar","ref":"--upload-pack=evil"}`)
+		Expect(status).To(Equal(400))
+	})
+})
@@ -0,0 +1,289 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// Webhook delivery sources, recorded alongside delivery IDs so the two
+// endpoints cannot collide.
+const (
+	WebhookSourceGitHub = "github"
+	WebhookSourceGit    = "git"
+)
+
+const signaturePrefix = "sha256="
+
+// defaultAcceptWindow is how long dispatch waits for a sync to fail fast
+// (install in flight, unresolvable version) before answering 202.
+const defaultAcceptWindow = 2 * time.Second
+
+// webhookRetryAfter is the Retry-After hint when an install is in flight.
+const webhookRetryAfter = 60 * time.Second
+
+// DeliveryStore records webhook delivery IDs for de-duplication.
+type DeliveryStore interface {
+	// RecordWebhookDelivery returns false when the delivery was seen before.
+	RecordWebhookDelivery(ctx context.Context, source, deliveryID string) (bool, error)
+	// ForgetWebhookDelivery releases a recorded delivery whose sync never
+	// happened, so the sender's redelivery is processed.
+	ForgetWebhookDelivery(ctx context.Context, source, deliveryID string) error
+}
+
+// CloneURLLookup resolves a clone URL to its registered strategy row.
+type CloneURLLookup interface {
+	GetByCloneURL(ctx context.Context, cloneURL string) (Strategy, error)
+}
+
+// SyncOneFunc is the Syncer.SyncOne signature as a function type.
+type SyncOneFunc func(ctx context.Context, cloneURL, version string) error
+
+// WebhookHandler serves the signed strategy-sync webhooks. A tag push for a
+// registered clone URL triggers an immediate single-strategy sync instead of
+// waiting for the next registry tick. Secret must be non-empty; every
+// request is rejected with 401 unless its HMAC-SHA256 signature matches.
+type WebhookHandler struct {
+	Secret       []byte
+	Strategies   CloneURLLookup
+	Deliveries   DeliveryStore
+	Sync         SyncOneFunc
+	AcceptWindow time.Duration // 0 = defaultAcceptWindow
+}
+
+// githubPush is the subset of GitHub's push event payload we read.
+type githubPush struct {
+	Ref        string `json:"ref"`
+	Deleted    bool   `json:"deleted"`
+	Repository struct {
+		CloneURL string `json:"clone_url"`
+		HTMLURL  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// gitPush is the generic push payload for non-GitHub forges and CI jobs.
+type gitPush struct {
+	CloneURL string `json:"cloneUrl"`
+	Ref      string `json:"ref"`
+}
+
+// GitHub implements POST /api/webhooks/github. It verifies
+// X-Hub-Signature-256, answers ping events, de-duplicates on
+// X-GitHub-Delivery, and triggers a sync for tag pushes.
+func (h *WebhookHandler) GitHub(c fiber.Ctx) error {
+	if !h.verify(c.Body(), c.Get("X-Hub-Signature-256")) {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", "signature mismatch")
+	}
+	event := c.Get("X-GitHub-Event")
+	if event == "ping" {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "pong"})
+	}
+	if event != "push" {
+		return ignored(c, "event "+event+" is not handled")
+	}
+	deliveryID := string([]byte(c.Get("X-GitHub-Delivery")))
+	if deliveryID == "" {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "missing X-GitHub-Delivery header")
+	}
+
+	var body githubPush
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid push payload: "+err.Error())
+	}
+	if body.Deleted {
+		return ignored(c, "ref deleted")
+	}
+	return h.dispatch(c, WebhookSourceGitHub, deliveryID, body.Ref,
+		body.Repository.CloneURL, body.Repository.HTMLURL)
+}
+
+// GitPush implements POST /api/webhooks/git, a forge-neutral variant signed
+// with X-Signature-256 over the raw body. X-Delivery-Id is optional; when
+// absent the body digest is used, so an identical replay is still dropped.
+func (h *WebhookHandler) GitPush(c fiber.Ctx) error {
+	if !h.verify(c.Body(), c.Get("X-Signature-256")) {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", "signature mismatch")
+	}
+	deliveryID := string([]byte(c.Get("X-Delivery-Id")))
+	if deliveryID == "" {
+		sum := sha256.Sum256(c.Body())
+		deliveryID = hex.EncodeToString(sum[:])
+	}
+
+	var body gitPush
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "invalid push payload: "+err.Error())
+	}
+	if body.CloneURL == "" {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", "cloneUrl is required")
+	}
+	ref := body.Ref
+	if ref != "" && !strings.HasPrefix(ref, "refs/") {
+		ref = "refs/tags/" + ref
+	}
+	return h.dispatch(c, WebhookSourceGit, deliveryID, ref, body.CloneURL)
+}
+
+// dispatch records the delivery, matches the clone URL against the
+// registry, and starts the sync in the background so the sender gets its
+// 202 well inside GitHub's ten-second delivery timeout. The delivery ID
+// only stays recorded once the sync has been accepted: a failed lookup, or
+// a sync that fails inside AcceptWindow (for example ErrInstallInFlight),
+// releases it and answers 5xx so the sender's retry is not dropped as a
+// duplicate. A sync that fails after the 202 also releases it, so a manual
+// redelivery goes through.
+func (h *WebhookHandler) dispatch(c fiber.Ctx, source, deliveryID, ref string, cloneURLs ...string) error {
+	tag, ok := strings.CutPrefix(ref, "refs/tags/")
+	if !ok {
+		return ignored(c, "not a tag push")
+	}
+	if !refRe.MatchString(tag) {
+		return writeProblem(c, fiber.StatusBadRequest, "Bad Request", ErrInvalidRef.Error())
+	}
+
+	fresh, err := h.Deliveries.RecordWebhookDelivery(c.Context(), source, deliveryID)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if !fresh {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "duplicate"})
+	}
+
+	s, err := h.lookup(c.Context(), cloneURLs)
+	if errors.Is(err, ErrNotFound) {
+		return ignored(c, "repository is not a registered strategy")
+	}
+	if err != nil {
+		h.forget(c.Context(), source, deliveryID)
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+
+	log.Info().
+		Str("source", source).
+		Str("delivery_id", deliveryID).
+		Str("short_code", s.ShortCode).
+		Str("version", tag).
+		Msg("webhook triggered strategy sync")
+
+	// Detach from the request so the install outlives the response.
+	syncCtx := context.WithoutCancel(c.Context())
+	cloneURL := s.CloneURL
+	// done is unbuffered: a result is handed to dispatch only while it is
+	// still waiting; once accepted is closed the goroutine owns the result.
+	done := make(chan error)
+	accepted := make(chan struct{})
+	go func() {
+		err := h.Sync(syncCtx, cloneURL, tag)
+		select {
+		case done <- err:
+			return
+		case <-accepted:
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("clone_url", cloneURL).Str("version", tag).Msg("webhook sync failed")
+			h.forget(syncCtx, source, deliveryID)
+		}
+	}()
+
+	window := h.AcceptWindow
+	if window <= 0 {
+		window = defaultAcceptWindow
+	}
+	timer := time.NewTimer(window)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			log.Warn().Err(err).Str("clone_url", cloneURL).Str("version", tag).Msg("webhook sync failed")
+			h.forget(syncCtx, source, deliveryID)
+			if errors.Is(err, ErrInstallInFlight) {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(webhookRetryAfter.Seconds())))
+				return writeProblem(c, fiber.StatusServiceUnavailable, "Service Unavailable", err.Error())
+			}
+			return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+	case <-timer.C:
+		close(accepted)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"status":    "accepted",
+		"shortCode": s.ShortCode,
+		"version":   tag,
+	})
+}
+
+// forget releases a delivery ID; failures are logged since the caller is
+// already reporting a more important error.
+func (h *WebhookHandler) forget(ctx context.Context, source, deliveryID string) {
+	if err := h.Deliveries.ForgetWebhookDelivery(ctx, source, deliveryID); err != nil {
+		log.Error().Err(err).Str("source", source).Str("delivery_id", deliveryID).Msg("forget webhook delivery")
+	}
+}
+
+// lookup tries each candidate clone URL with and without a trailing .git,
+// since registry rows store GitHub's clone_url while forges and CI jobs
+// often send the bare repository URL.
+func (h *WebhookHandler) lookup(ctx context.Context, candidates []string) (Strategy, error) {
+	for _, u := range candidates {
+		if u == "" {
+			continue
+		}
+		for _, v := range []string{u, strings.TrimSuffix(u, ".git"), strings.TrimSuffix(u, ".git") + ".git"} {
+			s, err := h.Strategies.GetByCloneURL(ctx, v)
+			if err == nil {
+				return s, nil
+			}
+			if !errors.Is(err, ErrNotFound) {
+				return Strategy{}, err
+			}
+		}
+	}
+	return Strategy{}, ErrNotFound
+}
+
+// verify checks a "sha256=<hex>" signature over body in constant time.
+func (h *WebhookHandler) verify(body []byte, header string) bool {
+	if len(h.Secret) == 0 {
+		return false
+	}
+	sig, ok := strings.CutPrefix(header, signaturePrefix)
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.Secret)
+	_, _ = mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func ignored(c fiber.Ctx, reason string) error {
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"status": "ignored", "reason": reason})
+}
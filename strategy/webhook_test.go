@@ -0,0 +1,259 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strategy_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/strategy"
+)
+
+const webhookSecret = "s3cret"
+
+// memDeliveries is an in-memory strategy.DeliveryStore.
+type memDeliveries struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+func (m *memDeliveries) RecordWebhookDelivery(_ context.Context, source, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := source + "/" + id
+	if m.seen[key] {
+		return false, nil
+	}
+	m.seen[key] = true
+	return true, nil
+}
+
+func (m *memDeliveries) ForgetWebhookDelivery(_ context.Context, source, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.seen, source+"/"+id)
+	return nil
+}
+
+type syncCall struct{ cloneURL, version string }
+
+func sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(webhookSecret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func recordedPayload(name string) []byte {
+	b, err := os.ReadFile(filepath.Join("testdata", "webhooks", name))
+	Expect(err).NotTo(HaveOccurred())
+	return b
+}
+
+var _ = Describe("WebhookHandler", func() {
+	var (
+		app     *fiber.App
+		mu      sync.Mutex
+		calls   []syncCall
+		syncErr error
+		release chan struct{}
+	)
+
+	BeforeEach(func() {
+		store := newFakeStore()
+		store.rows["adm"] = strategy.Strategy{
+			ShortCode: "adm",
+			RepoOwner: "penny-vault",
+			RepoName:  "adm",
+			CloneURL:  "https://github.com/penny-vault/adm.git",
+		}
+		calls = nil
+		syncErr = nil
+		release = nil
+		h := &strategy.WebhookHandler{
+			Secret:     []byte(webhookSecret),
+			Strategies: store,
+			Deliveries: &memDeliveries{seen: map[string]bool{}},
+			Sync: func(_ context.Context, cloneURL, version string) error {
+				mu.Lock()
+				calls = append(calls, syncCall{cloneURL, version})
+				err, wait := syncErr, release
+				mu.Unlock()
+				if wait != nil {
+					<-wait
+				}
+				return err
+			},
+			AcceptWindow: 200 * time.Millisecond,
+		}
+		app = fiber.New()
+		app.Post("/api/webhooks/github", h.GitHub)
+		app.Post("/api/webhooks/git", h.GitPush)
+	})
+
+	syncCalls := func() []syncCall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]syncCall(nil), calls...)
+	}
+
+	github := func(event, delivery string, body []byte, signature string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/github", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-GitHub-Delivery", delivery)
+		req.Header.Set("X-Hub-Signature-256", signature)
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		raw, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(raw)
+	}
+
+	It("triggers a sync for a recorded tag push", func() {
+		body := recordedPayload("github_push_tag.json")
+		status, resp := github("push", "7f1c2a40-9e51-11f0-8d1e-3b6c2f1a9d11", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusAccepted))
+		Expect(resp).To(ContainSubstring(`"shortCode":"adm"`))
+		Eventually(syncCalls).Should(ConsistOf(syncCall{"https://github.com/penny-vault/adm.git", "v1.3.0"}))
+	})
+
+	It("drops a redelivery of the same delivery ID", func() {
+		body := recordedPayload("github_push_tag.json")
+		status, _ := github("push", "dup-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusAccepted))
+		status, resp := github("push", "dup-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(resp).To(ContainSubstring("duplicate"))
+		Eventually(syncCalls).Should(HaveLen(1))
+		Consistently(syncCalls).Should(HaveLen(1))
+	})
+
+	It("503s and releases the delivery ID when an install is already in flight", func() {
+		syncErr = strategy.ErrInstallInFlight
+		body := recordedPayload("github_push_tag.json")
+		status, _ := github("push", "busy-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusServiceUnavailable))
+
+		mu.Lock()
+		syncErr = nil
+		mu.Unlock()
+		status, _ = github("push", "busy-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusAccepted))
+		Eventually(syncCalls).Should(HaveLen(2))
+	})
+
+	It("releases the delivery ID when an accepted sync later fails", func() {
+		mu.Lock()
+		syncErr = errors.New("clone failed")
+		release = make(chan struct{})
+		mu.Unlock()
+		body := recordedPayload("github_push_tag.json")
+		status, _ := github("push", "late-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusAccepted))
+
+		mu.Lock()
+		wait := release
+		syncErr, release = nil, nil
+		mu.Unlock()
+		close(wait)
+
+		Eventually(func() int {
+			status, _ := github("push", "late-1", body, sign(body))
+			return status
+		}).Should(Equal(fiber.StatusAccepted))
+	})
+
+	It("rejects a bad signature", func() {
+		body := recordedPayload("github_push_tag.json")
+		status, _ := github("push", "bad-sig", body, sign([]byte("something else")))
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+		Expect(syncCalls()).To(BeEmpty())
+	})
+
+	It("rejects a missing signature", func() {
+		body := recordedPayload("github_push_tag.json")
+		status, _ := github("push", "no-sig", body, "")
+		Expect(status).To(Equal(fiber.StatusUnauthorized))
+	})
+
+	It("ignores branch pushes", func() {
+		body := recordedPayload("github_push_branch.json")
+		status, resp := github("push", "branch-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(resp).To(ContainSubstring("not a tag push"))
+		Consistently(syncCalls).Should(BeEmpty())
+	})
+
+	It("answers ping events", func() {
+		body := recordedPayload("github_ping.json")
+		status, resp := github("ping", "ping-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(resp).To(ContainSubstring("pong"))
+	})
+
+	It("ignores pushes for repositories that are not registered", func() {
+		body := bytes.ReplaceAll(recordedPayload("github_push_tag.json"), []byte("penny-vault/adm"), []byte("penny-vault/other"))
+		status, resp := github("push", "other-1", body, sign(body))
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(resp).To(ContainSubstring("not a registered strategy"))
+	})
+
+	Describe("generic git push", func() {
+		post := func(body []byte, delivery string) int {
+			req := httptest.NewRequest(http.MethodPost, "/api/webhooks/git", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Signature-256", sign(body))
+			if delivery != "" {
+				req.Header.Set("X-Delivery-Id", delivery)
+			}
+			resp, err := app.Test(req)
+			Expect(err).NotTo(HaveOccurred())
+			return resp.StatusCode
+		}
+
+		It("accepts a bare tag name and a clone URL without .git", func() {
+			body := []byte(`{"cloneUrl":"https://github.com/penny-vault/adm","ref":"v2.0.0"}`)
+			Expect(post(body, "ci-42")).To(Equal(fiber.StatusAccepted))
+			Eventually(syncCalls).Should(ConsistOf(syncCall{"https://github.com/penny-vault/adm.git", "v2.0.0"}))
+		})
+
+		It("de-duplicates on the body digest when no delivery ID is sent", func() {
+			body := []byte(`{"cloneUrl":"https://github.com/penny-vault/adm.git","ref":"refs/tags/v2.0.1"}`)
+			Expect(post(body, "")).To(Equal(fiber.StatusAccepted))
+			Expect(post(body, "")).To(Equal(fiber.StatusOK))
+			Eventually(syncCalls).Should(HaveLen(1))
+		})
+
+		It("rejects a tag that could be read as a git option", func() {
+			body := []byte(`{"cloneUrl":"https://github.com/penny-vault/adm.git","ref":"refs/tags/--upload-pack=x"}`)
+			Expect(post(body, "evil")).To(Equal(fiber.StatusBadRequest))
+		})
+	})
+})
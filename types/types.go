@@ -21,3 +21,16 @@ type RequestIDKey struct{}
 // AuthSubjectKey is the Fiber locals key for the authenticated user's
 // subject (Auth0 `sub` claim).
 type AuthSubjectKey struct{}
+
+// AuthScopesKey is the Fiber locals key for the authenticated caller's
+// granted scopes, permissions, and roles, merged into one []string.
+type AuthScopesKey struct{}
+
+// AuthAPIKeyKey is the Fiber locals key for the ID of the personal API key
+// a request authenticated with. It is unset for JWT-authenticated requests.
+type AuthAPIKeyKey struct{}
+
+// AuthActorKey is the Fiber locals key for the subject of an admin acting
+// as another user via X-Act-As. AuthSubjectKey then holds the user acted
+// for. It is unset when callers act as themselves.
+type AuthActorKey struct{}
@@ -0,0 +1,329 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+
+	"github.com/penny-vault/pv-api/types"
+)
+
+const maxNameLength = 100
+
+var errNoSubject = errors.New("missing authenticated subject")
+
+// Handler serves /workspaces and their members.
+type Handler struct {
+	store      Store
+	maxCreated int
+}
+
+func NewHandler(store Store) *Handler { return &Handler{store: store} }
+
+// WithCreateLimit caps the workspaces one user may have created and still
+// belong to. Each workspace has its own quota allowance, so without a cap
+// a user could multiply theirs by creating more. Zero means unlimited.
+func (h *Handler) WithCreateLimit(n int) *Handler {
+	h.maxCreated = n
+	return h
+}
+
+// List implements GET /workspaces: the workspaces the caller belongs to.
+func (h *Handler) List(c fiber.Ctx) error {
+	sub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	rows, err := h.store.List(c.Context(), sub)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	out := make([]workspaceView, 0, len(rows))
+	for _, w := range rows {
+		out = append(out, toWorkspaceView(w, nil))
+	}
+	return writeJSON(c, fiber.StatusOK, out)
+}
+
+type nameBody struct {
+	Name string `json:"name"`
+}
+
+func parseName(c fiber.Ctx) (string, error) {
+	var body nameBody
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(body.Name)
+	if name == "" || len(name) > maxNameLength {
+		return "", errors.New("name must be 1-100 characters")
+	}
+	return name, nil
+}
+
+// Create implements POST /workspaces. The caller becomes its owner.
+func (h *Handler) Create(c fiber.Ctx) error {
+	sub, err := subject(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	name, err := parseName(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	if h.maxCreated > 0 {
+		rows, err := h.store.List(c.Context(), sub)
+		if err != nil {
+			return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+		}
+		created := 0
+		for _, w := range rows {
+			if w.CreatedBy == sub {
+				created++
+			}
+		}
+		if created >= h.maxCreated {
+			return writeProblem(c, fiber.StatusForbidden, "Forbidden",
+				fmt.Sprintf("quota exceeded: at most %d workspaces", h.maxCreated))
+		}
+	}
+	w, err := h.store.Create(c.Context(), name, sub)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	members := []Member{{Sub: sub, Role: RoleOwner, CreatedAt: w.CreatedAt}}
+	return writeJSON(c, fiber.StatusCreated, toWorkspaceView(w, members))
+}
+
+// member checks that the caller belongs to the :id workspace with at least
+// need, writing the problem response itself when not.
+func (h *Handler) member(c fiber.Ctx, need Role) (uuid.UUID, Role, bool, error) {
+	sub, err := subject(c)
+	if err != nil {
+		return uuid.Nil, "", false, writeProblem(c, fiber.StatusUnauthorized, "Unauthorized", err.Error())
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, "", false, writeProblem(c, fiber.StatusNotFound, "Not Found", "workspace not found")
+	}
+	role, err := h.store.Role(c.Context(), id, sub)
+	if errors.Is(err, ErrNotFound) {
+		return uuid.Nil, "", false, writeProblem(c, fiber.StatusNotFound, "Not Found", "workspace not found")
+	}
+	if err != nil {
+		return uuid.Nil, "", false, writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	if !role.Allows(need) {
+		return uuid.Nil, "", false, writeProblem(c, fiber.StatusForbidden, "Forbidden",
+			"this requires the "+string(need)+" role in the workspace")
+	}
+	return id, role, true, nil
+}
+
+// Get implements GET /workspaces/{id}: the workspace and its members.
+func (h *Handler) Get(c fiber.Ctx) error {
+	id, role, ok, err := h.member(c, RoleViewer)
+	if !ok {
+		return err
+	}
+	w, err := h.store.Get(c.Context(), id)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	members, err := h.store.Members(c.Context(), id)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	w.Role = role
+	return writeJSON(c, fiber.StatusOK, toWorkspaceView(w, members))
+}
+
+// Patch implements PATCH /workspaces/{id}: rename. Owners only.
+func (h *Handler) Patch(c fiber.Ctx) error {
+	id, role, ok, err := h.member(c, RoleOwner)
+	if !ok {
+		return err
+	}
+	name, err := parseName(c)
+	if err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	w, err := h.store.Rename(c.Context(), id, name)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	w.Role = role
+	return writeJSON(c, fiber.StatusOK, toWorkspaceView(w, nil))
+}
+
+// Delete implements DELETE /workspaces/{id}. Owners only; the workspace
+// must not own portfolios.
+func (h *Handler) Delete(c fiber.Ctx) error {
+	id, _, ok, err := h.member(c, RoleOwner)
+	if !ok {
+		return err
+	}
+	err = h.store.Delete(c.Context(), id)
+	if errors.Is(err, ErrNotEmpty) {
+		return writeProblem(c, fiber.StatusConflict, "Conflict",
+			"delete or transfer the workspace's portfolios first")
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// memberSub is the :sub path param. Subjects contain '|', which clients
+// may or may not percent-encode.
+func memberSub(c fiber.Ctx) string {
+	raw := string([]byte(c.Params("sub")))
+	if s, err := url.PathUnescape(raw); err == nil {
+		return s
+	}
+	return raw
+}
+
+// PutMember implements PUT /workspaces/{id}/members/{sub}: add a member or
+// change their role. Owners only.
+func (h *Handler) PutMember(c fiber.Ctx) error {
+	id, _, ok, err := h.member(c, RoleOwner)
+	if !ok {
+		return err
+	}
+	var body struct {
+		Role Role `json:"role"`
+	}
+	if err := sonic.Unmarshal(c.Body(), &body); err != nil {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", err.Error())
+	}
+	if !body.Role.Valid() {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity",
+			"role must be one of: viewer, editor, owner")
+	}
+	sub := memberSub(c)
+	if sub == "" {
+		return writeProblem(c, fiber.StatusUnprocessableEntity, "Unprocessable Entity", "sub is required")
+	}
+	m, err := h.store.SetMember(c.Context(), id, sub, body.Role)
+	if errors.Is(err, ErrLastOwner) {
+		return writeProblem(c, fiber.StatusConflict, "Conflict", err.Error())
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return writeJSON(c, fiber.StatusOK, toMemberView(m))
+}
+
+// DeleteMember implements DELETE /workspaces/{id}/members/{sub}. Owners
+// may remove anyone; any member may remove themselves.
+func (h *Handler) DeleteMember(c fiber.Ctx) error {
+	sub := memberSub(c)
+	need := RoleOwner
+	if caller, err := subject(c); err == nil && caller == sub {
+		need = RoleViewer
+	}
+	id, _, ok, err := h.member(c, need)
+	if !ok {
+		return err
+	}
+	err = h.store.RemoveMember(c.Context(), id, sub)
+	if errors.Is(err, ErrNotFound) {
+		return writeProblem(c, fiber.StatusNotFound, "Not Found", "member not found")
+	}
+	if errors.Is(err, ErrLastOwner) {
+		return writeProblem(c, fiber.StatusConflict, "Conflict", err.Error())
+	}
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+type workspaceView struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Role      Role         `json:"role,omitempty"`
+	CreatedBy string       `json:"createdBy"`
+	CreatedAt string       `json:"createdAt"`
+	UpdatedAt string       `json:"updatedAt"`
+	Members   []memberView `json:"members,omitempty"`
+}
+
+type memberView struct {
+	Sub       string `json:"sub"`
+	Role      Role   `json:"role"`
+	CreatedAt string `json:"createdAt"`
+}
+
+func toWorkspaceView(w Workspace, members []Member) workspaceView {
+	v := workspaceView{
+		ID:        w.ID.String(),
+		Name:      w.Name,
+		Role:      w.Role,
+		CreatedBy: w.CreatedBy,
+		CreatedAt: w.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: w.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, m := range members {
+		v.Members = append(v.Members, toMemberView(m))
+	}
+	return v
+}
+
+func toMemberView(m Member) memberView {
+	return memberView{Sub: m.Sub, Role: m.Role, CreatedAt: m.CreatedAt.UTC().Format(time.RFC3339)}
+}
+
+func subject(c fiber.Ctx) (string, error) {
+	sub, ok := c.Locals(types.AuthSubjectKey{}).(string)
+	if !ok || sub == "" {
+		return "", errNoSubject
+	}
+	return string([]byte(sub)), nil
+}
+
+func writeJSON(c fiber.Ctx, status int, v any) error {
+	body, err := sonic.Marshal(v)
+	if err != nil {
+		return writeProblem(c, fiber.StatusInternalServerError, "Internal Server Error", err.Error())
+	}
+	c.Set(fiber.HeaderContentType, "application/json")
+	return c.Status(status).Send(body)
+}
+
+func writeProblem(c fiber.Ctx, status int, title, detail string) error {
+	type problem struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+	}
+	body, _ := sonic.Marshal(problem{
+		Type: "about:blank", Title: title, Status: status, Detail: detail, Instance: c.Path(),
+	})
+	c.Set(fiber.HeaderContentType, "application/problem+json")
+	return c.Status(status).Send(body)
+}
@@ -0,0 +1,355 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/penny-vault/pv-api/types"
+	"github.com/penny-vault/pv-api/workspace"
+)
+
+// fakeStore is an in-memory workspace.Store.
+type fakeStore struct {
+	mu         sync.Mutex
+	workspaces map[uuid.UUID]workspace.Workspace
+	members    map[uuid.UUID][]workspace.Member
+	// owning lists workspaces that still own portfolios.
+	owning map[uuid.UUID]bool
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		workspaces: map[uuid.UUID]workspace.Workspace{},
+		members:    map[uuid.UUID][]workspace.Member{},
+		owning:     map[uuid.UUID]bool{},
+	}
+}
+
+func (f *fakeStore) Role(_ context.Context, id uuid.UUID, sub string) (workspace.Role, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range f.members[id] {
+		if m.Sub == sub {
+			return m.Role, nil
+		}
+	}
+	return "", workspace.ErrNotFound
+}
+
+func (f *fakeStore) Create(_ context.Context, name, ownerSub string) (workspace.Workspace, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	w := workspace.Workspace{ID: uuid.Must(uuid.NewV7()), Name: name, CreatedBy: ownerSub, CreatedAt: now, UpdatedAt: now}
+	f.workspaces[w.ID] = w
+	f.members[w.ID] = []workspace.Member{{Sub: ownerSub, Role: workspace.RoleOwner, CreatedAt: now}}
+	w.Role = workspace.RoleOwner
+	return w, nil
+}
+
+func (f *fakeStore) List(_ context.Context, sub string) ([]workspace.Workspace, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := []workspace.Workspace{}
+	for id, ms := range f.members {
+		for _, m := range ms {
+			if m.Sub == sub {
+				w := f.workspaces[id]
+				w.Role = m.Role
+				out = append(out, w)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStore) Get(_ context.Context, id uuid.UUID) (workspace.Workspace, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w, ok := f.workspaces[id]
+	if !ok {
+		return workspace.Workspace{}, workspace.ErrNotFound
+	}
+	return w, nil
+}
+
+func (f *fakeStore) Rename(_ context.Context, id uuid.UUID, name string) (workspace.Workspace, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := f.workspaces[id]
+	w.Name = name
+	f.workspaces[id] = w
+	return w, nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.owning[id] {
+		return workspace.ErrNotEmpty
+	}
+	delete(f.workspaces, id)
+	delete(f.members, id)
+	return nil
+}
+
+func (f *fakeStore) Members(_ context.Context, id uuid.UUID) ([]workspace.Member, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]workspace.Member(nil), f.members[id]...), nil
+}
+
+// onlyOwner reports whether sub is the workspace's only owner.
+func (f *fakeStore) onlyOwner(id uuid.UUID, sub string) bool {
+	owners, isOwner := 0, false
+	for _, m := range f.members[id] {
+		if m.Role == workspace.RoleOwner {
+			owners++
+			isOwner = isOwner || m.Sub == sub
+		}
+	}
+	return isOwner && owners == 1
+}
+
+func (f *fakeStore) SetMember(_ context.Context, id uuid.UUID, sub string, role workspace.Role) (workspace.Member, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.onlyOwner(id, sub) && role != workspace.RoleOwner {
+		return workspace.Member{}, workspace.ErrLastOwner
+	}
+	for i, m := range f.members[id] {
+		if m.Sub == sub {
+			f.members[id][i].Role = role
+			return f.members[id][i], nil
+		}
+	}
+	m := workspace.Member{Sub: sub, Role: role, CreatedAt: time.Now()}
+	f.members[id] = append(f.members[id], m)
+	return m, nil
+}
+
+func (f *fakeStore) RemoveMember(_ context.Context, id uuid.UUID, sub string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.onlyOwner(id, sub) {
+		return workspace.ErrLastOwner
+	}
+	for i, m := range f.members[id] {
+		if m.Sub == sub {
+			f.members[id] = append(f.members[id][:i], f.members[id][i+1:]...)
+			return nil
+		}
+	}
+	return workspace.ErrNotFound
+}
+
+var _ = Describe("Workspaces", func() {
+	var (
+		app    *fiber.App
+		store  *fakeStore
+		caller string
+		owner  = "auth0|owner"
+		mate   = "auth0|mate"
+	)
+
+	do := func(method, target, body string) (int, string) {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		Expect(err).NotTo(HaveOccurred())
+		out, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(out)
+	}
+
+	create := func(name string) string {
+		status, body := do("POST", "/workspaces", `{"name":"`+name+`"}`)
+		Expect(status).To(Equal(fiber.StatusCreated), body)
+		var v struct {
+			ID string `json:"id"`
+		}
+		Expect(sonic.Unmarshal([]byte(body), &v)).To(Succeed())
+		return v.ID
+	}
+
+	BeforeEach(func() {
+		store = newFakeStore()
+		caller = owner
+		h := workspace.NewHandler(store)
+		app = fiber.New(fiber.Config{JSONEncoder: sonic.Marshal, JSONDecoder: sonic.Unmarshal})
+		app.Use(func(c fiber.Ctx) error {
+			c.Locals(types.AuthSubjectKey{}, caller)
+			return c.Next()
+		})
+		app.Get("/workspaces", h.List)
+		app.Post("/workspaces", h.Create)
+		app.Get("/workspaces/:id", h.Get)
+		app.Patch("/workspaces/:id", h.Patch)
+		app.Delete("/workspaces/:id", h.Delete)
+		app.Put("/workspaces/:id/members/:sub", h.PutMember)
+		app.Delete("/workspaces/:id/members/:sub", h.DeleteMember)
+		app.Get("/whoami", func(c fiber.Ctx) error {
+			ownerSub, err := workspace.Resolve(c, store, caller, workspace.RoleEditor)
+			if err != nil {
+				return c.SendStatus(workspace.Status(err))
+			}
+			return c.SendString(ownerSub)
+		})
+	})
+
+	It("caps the workspaces one user may create", func() {
+		h := workspace.NewHandler(store).WithCreateLimit(2)
+		app.Post("/limited/workspaces", h.Create)
+		post := func() int {
+			status, _ := do("POST", "/limited/workspaces", `{"name":"Team"}`)
+			return status
+		}
+		Expect(post()).To(Equal(fiber.StatusCreated))
+		Expect(post()).To(Equal(fiber.StatusCreated))
+		Expect(post()).To(Equal(fiber.StatusForbidden))
+
+		caller = mate
+		Expect(post()).To(Equal(fiber.StatusCreated))
+	})
+
+	It("makes the creator the owner and lets them add members", func() {
+		id := create("Family office")
+		status, body := do("PUT", "/workspaces/"+id+"/members/auth0%7Cmate", `{"role":"viewer"}`)
+		Expect(status).To(Equal(fiber.StatusOK), body)
+		Expect(body).To(ContainSubstring(`"sub":"auth0|mate"`))
+
+		status, body = do("GET", "/workspaces/"+id, "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(ContainSubstring(`"role":"owner"`))
+		Expect(body).To(ContainSubstring(`"sub":"auth0|mate"`))
+
+		caller = mate
+		status, body = do("GET", "/workspaces", "")
+		Expect(status).To(Equal(fiber.StatusOK))
+		Expect(body).To(ContainSubstring(`"role":"viewer"`))
+	})
+
+	It("limits membership changes to owners", func() {
+		id := create("Team")
+		_, _ = do("PUT", "/workspaces/"+id+"/members/"+mate, `{"role":"editor"}`)
+
+		caller = mate
+		status, _ := do("PUT", "/workspaces/"+id+"/members/auth0%7Cother", `{"role":"viewer"}`)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+		status, _ = do("PATCH", "/workspaces/"+id, `{"name":"Mine"}`)
+		Expect(status).To(Equal(fiber.StatusForbidden))
+
+		caller = "auth0|stranger"
+		status, _ = do("GET", "/workspaces/"+id, "")
+		Expect(status).To(Equal(fiber.StatusNotFound))
+	})
+
+	It("keeps at least one owner", func() {
+		id := create("Team")
+		status, _ := do("PUT", "/workspaces/"+id+"/members/"+owner, `{"role":"editor"}`)
+		Expect(status).To(Equal(fiber.StatusConflict))
+		status, _ = do("DELETE", "/workspaces/"+id+"/members/"+owner, "")
+		Expect(status).To(Equal(fiber.StatusConflict))
+	})
+
+	It("lets members leave on their own", func() {
+		id := create("Team")
+		_, _ = do("PUT", "/workspaces/"+id+"/members/"+mate, `{"role":"viewer"}`)
+		caller = mate
+		status, _ := do("DELETE", "/workspaces/"+id+"/members/"+mate, "")
+		Expect(status).To(Equal(fiber.StatusNoContent))
+		Expect(store.members[uuid.MustParse(id)]).To(HaveLen(1))
+	})
+
+	It("refuses to delete a workspace that owns portfolios", func() {
+		id := create("Team")
+		store.owning[uuid.MustParse(id)] = true
+		status, _ := do("DELETE", "/workspaces/"+id, "")
+		Expect(status).To(Equal(fiber.StatusConflict))
+		store.owning[uuid.MustParse(id)] = false
+		status, _ = do("DELETE", "/workspaces/"+id, "")
+		Expect(status).To(Equal(fiber.StatusNoContent))
+	})
+
+	It("rejects unknown roles and blank names", func() {
+		id := create("Team")
+		status, _ := do("PUT", "/workspaces/"+id+"/members/"+mate, `{"role":"admin"}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+		status, _ = do("POST", "/workspaces", `{"name":"  "}`)
+		Expect(status).To(Equal(fiber.StatusUnprocessableEntity))
+	})
+
+	Describe("Resolve", func() {
+		whoami := func(header, query string) (int, string) {
+			target := "/whoami"
+			if query != "" {
+				target += "?workspace=" + query
+			}
+			req := httptest.NewRequest("GET", target, nil)
+			if header != "" {
+				req.Header.Set(workspace.HeaderWorkspace, header)
+			}
+			resp, err := app.Test(req)
+			Expect(err).NotTo(HaveOccurred())
+			out, _ := io.ReadAll(resp.Body)
+			return resp.StatusCode, string(out)
+		}
+
+		It("addresses the caller's own portfolios without a selector", func() {
+			status, body := whoami("", "")
+			Expect(status).To(Equal(fiber.StatusOK))
+			Expect(body).To(Equal(owner))
+		})
+
+		It("addresses the workspace when the role allows it", func() {
+			id := create("Team")
+			_, _ = do("PUT", "/workspaces/"+id+"/members/"+mate, `{"role":"viewer"}`)
+
+			status, body := whoami(id, "")
+			Expect(status).To(Equal(fiber.StatusOK))
+			Expect(body).To(Equal(workspace.OwnerSub(uuid.MustParse(id))))
+			status, _ = whoami("", id)
+			Expect(status).To(Equal(fiber.StatusOK))
+
+			caller = mate
+			status, _ = whoami(id, "")
+			Expect(status).To(Equal(fiber.StatusForbidden))
+			caller = "auth0|stranger"
+			status, _ = whoami(id, "")
+			Expect(status).To(Equal(fiber.StatusNotFound))
+		})
+	})
+
+	It("round-trips owner subs", func() {
+		id := uuid.Must(uuid.NewV7())
+		got, ok := workspace.ParseOwnerSub(workspace.OwnerSub(id))
+		Expect(ok).To(BeTrue())
+		Expect(got).To(Equal(id))
+		_, ok = workspace.ParseOwnerSub("auth0|abc")
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,66 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+// HeaderWorkspace names the workspace a request acts in. EventSource
+// clients, which cannot set headers, use the workspace query parameter.
+const HeaderWorkspace = "X-Workspace"
+
+// Resolve returns the owner_sub the request addresses on behalf of sub.
+// Without a workspace selector that is sub itself, whose own portfolios
+// allow everything. With one, sub must be a member whose role allows need:
+// ErrNotFound when the workspace is unknown or sub is not a member (or
+// roles is nil), ErrForbidden when the role is too low.
+func Resolve(c fiber.Ctx, roles Roles, sub string, need Role) (string, error) {
+	sel := c.Get(HeaderWorkspace)
+	if sel == "" {
+		sel = c.Query("workspace")
+	}
+	if sel == "" {
+		return sub, nil
+	}
+	id, err := uuid.Parse(sel)
+	if err != nil || roles == nil {
+		return "", ErrNotFound
+	}
+	role, err := roles.Role(c.Context(), id, sub)
+	if err != nil {
+		return "", err
+	}
+	if !role.Allows(need) {
+		return "", ErrForbidden
+	}
+	return OwnerSub(id), nil
+}
+
+// Status maps a Resolve error to an HTTP status.
+func Status(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, ErrForbidden):
+		return fiber.StatusForbidden
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2021-2026
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package workspace lets a team share portfolios. A workspace has members,
+// each with a role, and owns portfolios the way a user does: their
+// owner_sub is the workspace's OwnerSub. Requests act in a workspace by
+// naming it in the X-Workspace header; Resolve turns that into the owner
+// key the portfolio store is queried with, after checking the caller's
+// role.
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Role is a member's level of access to a workspace.
+type Role string
+
+// Roles, from least to most access.
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+var roleRank = map[Role]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+// Valid reports whether r is a known role.
+func (r Role) Valid() bool { return roleRank[r] > 0 }
+
+// Allows reports whether r grants at least the access of need.
+func (r Role) Allows(need Role) bool { return r.Valid() && roleRank[r] >= roleRank[need] }
+
+var (
+	// ErrNotFound is returned when a workspace does not exist or the caller
+	// is not a member of it, and when a member does not exist.
+	ErrNotFound = errors.New("workspace not found")
+	// ErrForbidden is returned when the caller's role is too low.
+	ErrForbidden = errors.New("workspace role does not allow this")
+	// ErrLastOwner is returned when a change would leave a workspace
+	// without an owner.
+	ErrLastOwner = errors.New("workspace must keep at least one owner")
+	// ErrNotEmpty is returned when deleting a workspace that still owns
+	// portfolios.
+	ErrNotEmpty = errors.New("workspace still owns portfolios")
+)
+
+// ownerPrefix marks owner_sub values that name a workspace. Auth0 subjects
+// are "<connection>|<id>", so the prefix reads as one more connection.
+const ownerPrefix = "workspace|"
+
+// OwnerSub is the owner_sub of the portfolios workspace id owns.
+func OwnerSub(id uuid.UUID) string { return ownerPrefix + id.String() }
+
+// ParseOwnerSub returns the workspace an owner_sub names, if it names one.
+func ParseOwnerSub(s string) (uuid.UUID, bool) {
+	rest, ok := strings.CutPrefix(s, ownerPrefix)
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(rest)
+	return id, err == nil
+}
+
+// Workspace is a team that shares portfolios.
+type Workspace struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedBy string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Role is the caller's role; set by List.
+	Role Role
+}
+
+// Member is a user's membership in a workspace.
+type Member struct {
+	Sub       string
+	Role      Role
+	CreatedAt time.Time
+}
+
+// Roles looks up a user's role in a workspace. It returns ErrNotFound when
+// the user is not a member.
+type Roles interface {
+	Role(ctx context.Context, id uuid.UUID, sub string) (Role, error)
+}
+
+// Store persists workspaces and their members.
+type Store interface {
+	Roles
+	// Create makes a workspace with ownerSub as its first owner.
+	Create(ctx context.Context, name, ownerSub string) (Workspace, error)
+	// List returns the workspaces sub is a member of, with sub's role.
+	List(ctx context.Context, sub string) ([]Workspace, error)
+	Get(ctx context.Context, id uuid.UUID) (Workspace, error)
+	Rename(ctx context.Context, id uuid.UUID, name string) (Workspace, error)
+	// Delete removes an empty workspace; ErrNotEmpty if it owns portfolios.
+	Delete(ctx context.Context, id uuid.UUID) error
+	Members(ctx context.Context, id uuid.UUID) ([]Member, error)
+	// SetMember adds sub or changes their role. ErrLastOwner if it would
+	// demote the only owner.
+	SetMember(ctx context.Context, id uuid.UUID, sub string, role Role) (Member, error)
+	// RemoveMember removes sub. ErrLastOwner if sub is the only owner.
+	RemoveMember(ctx context.Context, id uuid.UUID, sub string) error
+}
+
+// PoolStore is the pgxpool-backed Store.
+type PoolStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPoolStore(pool *pgxpool.Pool) *PoolStore { return &PoolStore{pool: pool} }
+
+func (s *PoolStore) Role(ctx context.Context, id uuid.UUID, sub string) (Role, error) {
+	var role Role
+	err := s.pool.QueryRow(ctx,
+		`SELECT role FROM workspace_members WHERE workspace_id = $1 AND sub = $2`, id, sub).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("workspace role: %w", err)
+	}
+	return role, nil
+}
+
+func (s *PoolStore) Create(ctx context.Context, name, ownerSub string) (w Workspace, err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("create workspace: begin: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) && err == nil {
+			err = rbErr
+		}
+	}()
+	if err = tx.QueryRow(ctx, `
+		INSERT INTO workspaces (name, created_by) VALUES ($1, $2)
+		RETURNING id, name, created_by, created_at, updated_at`, name, ownerSub,
+	).Scan(&w.ID, &w.Name, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return Workspace{}, fmt.Errorf("create workspace: %w", err)
+	}
+	if _, err = tx.Exec(ctx,
+		`INSERT INTO workspace_members (workspace_id, sub, role) VALUES ($1, $2, $3)`,
+		w.ID, ownerSub, RoleOwner); err != nil {
+		return Workspace{}, fmt.Errorf("create workspace: add owner: %w", err)
+	}
+	w.Role = RoleOwner
+	return w, tx.Commit(ctx)
+}
+
+func (s *PoolStore) List(ctx context.Context, sub string) ([]Workspace, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT w.id, w.name, w.created_by, w.created_at, w.updated_at, m.role
+		  FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+		 WHERE m.sub = $1
+		 ORDER BY w.name, w.id`, sub)
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	defer rows.Close()
+	out := []Workspace{}
+	for rows.Next() {
+		var w Workspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt, &w.Role); err != nil {
+			return nil, fmt.Errorf("list workspaces: %w", err)
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (s *PoolStore) Get(ctx context.Context, id uuid.UUID) (Workspace, error) {
+	var w Workspace
+	err := s.pool.QueryRow(ctx,
+		`SELECT id, name, created_by, created_at, updated_at FROM workspaces WHERE id = $1`, id,
+	).Scan(&w.ID, &w.Name, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Workspace{}, ErrNotFound
+	}
+	if err != nil {
+		return Workspace{}, fmt.Errorf("get workspace: %w", err)
+	}
+	return w, nil
+}
+
+func (s *PoolStore) Rename(ctx context.Context, id uuid.UUID, name string) (Workspace, error) {
+	var w Workspace
+	err := s.pool.QueryRow(ctx, `
+		UPDATE workspaces SET name = $2, updated_at = now() WHERE id = $1
+		RETURNING id, name, created_by, created_at, updated_at`, id, name,
+	).Scan(&w.ID, &w.Name, &w.CreatedBy, &w.CreatedAt, &w.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Workspace{}, ErrNotFound
+	}
+	if err != nil {
+		return Workspace{}, fmt.Errorf("rename workspace: %w", err)
+	}
+	return w, nil
+}
+
+func (s *PoolStore) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM workspaces
+		 WHERE id = $1
+		   AND NOT EXISTS (SELECT 1 FROM portfolios WHERE owner_sub = $2)`,
+		id, OwnerSub(id))
+	if err != nil {
+		return fmt.Errorf("delete workspace: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := s.Get(ctx, id); err != nil {
+			return err
+		}
+		return ErrNotEmpty
+	}
+	return nil
+}
+
+func (s *PoolStore) Members(ctx context.Context, id uuid.UUID) ([]Member, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT sub, role, created_at FROM workspace_members
+		 WHERE workspace_id = $1 ORDER BY created_at, sub`, id)
+	if err != nil {
+		return nil, fmt.Errorf("list workspace members: %w", err)
+	}
+	defer rows.Close()
+	out := []Member{}
+	for rows.Next() {
+		var m Member
+		if err := rows.Scan(&m.Sub, &m.Role, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("list workspace members: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// lockOwners locks the workspace row, serializing membership changes, and
+// reports whether sub is currently its only owner.
+func lockOwners(ctx context.Context, tx pgx.Tx, id uuid.UUID, sub string) (bool, error) {
+	var locked uuid.UUID
+	err := tx.QueryRow(ctx, `SELECT id FROM workspaces WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, ErrNotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	var owners int
+	var isOwner bool
+	err = tx.QueryRow(ctx, `
+		SELECT count(*), coalesce(bool_or(sub = $2), false)
+		  FROM workspace_members WHERE workspace_id = $1 AND role = 'owner'`, id, sub,
+	).Scan(&owners, &isOwner)
+	return isOwner && owners == 1, err
+}
+
+func (s *PoolStore) SetMember(ctx context.Context, id uuid.UUID, sub string, role Role) (m Member, err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return Member{}, fmt.Errorf("set workspace member: begin: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) && err == nil {
+			err = rbErr
+		}
+	}()
+	lastOwner, err := lockOwners(ctx, tx, id, sub)
+	if errors.Is(err, ErrNotFound) {
+		return Member{}, err
+	}
+	if err != nil {
+		return Member{}, fmt.Errorf("set workspace member: %w", err)
+	}
+	if lastOwner && role != RoleOwner {
+		return Member{}, ErrLastOwner
+	}
+	if err = tx.QueryRow(ctx, `
+		INSERT INTO workspace_members (workspace_id, sub, role) VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id, sub) DO UPDATE SET role = EXCLUDED.role
+		RETURNING sub, role, created_at`, id, sub, role,
+	).Scan(&m.Sub, &m.Role, &m.CreatedAt); err != nil {
+		return Member{}, fmt.Errorf("set workspace member: %w", err)
+	}
+	return m, tx.Commit(ctx)
+}
+
+func (s *PoolStore) RemoveMember(ctx context.Context, id uuid.UUID, sub string) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("remove workspace member: begin: %w", err)
+	}
+	defer func() {
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) && err == nil {
+			err = rbErr
+		}
+	}()
+	lastOwner, err := lockOwners(ctx, tx, id, sub)
+	if errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("remove workspace member: %w", err)
+	}
+	if lastOwner {
+		return ErrLastOwner
+	}
+	tag, err := tx.Exec(ctx,
+		`DELETE FROM workspace_members WHERE workspace_id = $1 AND sub = $2`, id, sub)
+	if err != nil {
+		return fmt.Errorf("remove workspace member: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return tx.Commit(ctx)
+}